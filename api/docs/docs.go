@@ -68,17 +68,1074 @@ const docTemplate = `{
                         }
                     }
                 ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ItemA"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/a-items/batch": {
+            "put": {
+                "description": "Creates or replaces up to 1000 items by ID, items without ID are created with an ID generated by the server. An item with an ID that is not stored yet is created with it, so an export can be imported back, and one with the ID of a deleted item fails with 409 Conflict until the item is restored. In atomic mode either every item is applied or none, in best-effort mode each item succeeds or fails on its own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemA"
+                ],
+                "summary": "Upserts items in batch",
+                "parameters": [
+                    {
+                        "enum": [
+                            "atomic",
+                            "best-effort"
+                        ],
+                        "type": "string",
+                        "default": "atomic",
+                        "description": "Batch mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Items Properties",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ItemA"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/batch.Result"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/batch.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates up to 1000 items. In atomic mode either every item is created or none, in best-effort mode each item succeeds or fails on its own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemA"
+                ],
+                "summary": "Creates items in batch",
+                "parameters": [
+                    {
+                        "enum": [
+                            "atomic",
+                            "best-effort"
+                        ],
+                        "type": "string",
+                        "default": "atomic",
+                        "description": "Batch mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Items Properties",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ItemA"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/batch.Result"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/batch.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft deletes up to 1000 items by ID. In atomic mode either every item is deleted or none, in best-effort mode each item succeeds or fails on its own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemA"
+                ],
+                "summary": "Deletes items in batch",
+                "parameters": [
+                    {
+                        "enum": [
+                            "atomic",
+                            "best-effort"
+                        ],
+                        "type": "string",
+                        "default": "atomic",
+                        "description": "Batch mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Item IDs",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/batch.Result"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/batch.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/a-items/export": {
+            "get": {
+                "description": "Streams every item as newline delimited JSON or CSV, reading them from the database with a cursor",
+                "produces": [
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "itemA"
+                ],
+                "summary": "Exports items",
+                "parameters": [
+                    {
+                        "enum": [
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "ndjson",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/a-items/import": {
+            "post": {
+                "description": "Streams an NDJSON or CSV file, sent as the request body or as the file field of a multipart form, and upserts its rows in batches. Rows that fail are listed in the report while the others are imported. With the Prefer: respond-async header the file is imported by a background job instead, answered with 202 Accepted and the job status URL as Location, the report being the job result",
+                "consumes": [
+                    "application/x-ndjson",
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemA"
+                ],
+                "summary": "Imports items",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "NDJSON or CSV file",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond-async to import in the background",
+                        "name": "Prefer",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/transfer.ImportReport"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/job.Job"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the job status"
+                            }
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/transfer.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {}
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/a-items/stream": {
+            "get": {
+                "description": "Sends an event whenever an item is created, updated, deleted or restored, as Server-Sent Events or as WebSocket messages when the connection is upgraded. Events published after the Last-Event-ID header or the lastEventId parameter are replayed first",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "itemA"
+                ],
+                "summary": "Streams item changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "created,deleted",
+                        "description": "Comma separated event types",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated item IDs",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "lastEventId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/changefeed.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/a-items/{id}": {
+            "get": {
+                "description": "get item by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemA"
+                ],
+                "summary": "Show an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ItemA"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates an item with given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemA"
+                ],
+                "summary": "Updates an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item Properties",
+                        "name": "itemA",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ItemA"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes an item with given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemA"
+                ],
+                "summary": "Deletes an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "string",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ItemA"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Partially updates an item with given ID using a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) document",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemA"
+                ],
+                "summary": "Patches an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch document",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ItemA"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {}
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/a-items/{id}/purge": {
+            "delete": {
+                "description": "Permanently deletes an item with given ID, including soft deleted ones. Restricted to admins",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemA"
+                ],
+                "summary": "Purges an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/a-items/{id}/restore": {
+            "post": {
+                "description": "Restores a soft deleted item with given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemA"
+                ],
+                "summary": "Restores an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/b-items": {
+            "get": {
+                "description": "Return all stored items",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemB"
+                ],
+                "summary": "Show all items",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ItemB"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "description": "creates an item with given data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemB"
+                ],
+                "summary": "Creates an item",
+                "parameters": [
+                    {
+                        "description": "Item Properties",
+                        "name": "itemB",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ItemB"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ItemB"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the created item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/b-items/batch": {
+            "put": {
+                "description": "Creates or replaces up to 1000 items by ID, items without ID are created with an ID generated by the server. An item with an ID that is not stored yet is created with it, so an export can be imported back, and one with the ID of a deleted item fails with 409 Conflict until the item is restored. In atomic mode either every item is applied or none, in best-effort mode each item succeeds or fails on its own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemB"
+                ],
+                "summary": "Upserts items in batch",
+                "parameters": [
+                    {
+                        "enum": [
+                            "atomic",
+                            "best-effort"
+                        ],
+                        "type": "string",
+                        "default": "atomic",
+                        "description": "Batch mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Items Properties",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ItemB"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/batch.Result"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/batch.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates up to 1000 items. In atomic mode either every item is created or none, in best-effort mode each item succeeds or fails on its own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemB"
+                ],
+                "summary": "Creates items in batch",
+                "parameters": [
+                    {
+                        "enum": [
+                            "atomic",
+                            "best-effort"
+                        ],
+                        "type": "string",
+                        "default": "atomic",
+                        "description": "Batch mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Items Properties",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ItemB"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/batch.Result"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/batch.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft deletes up to 1000 items by ID. In atomic mode either every item is deleted or none, in best-effort mode each item succeeds or fails on its own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemB"
+                ],
+                "summary": "Deletes items in batch",
+                "parameters": [
+                    {
+                        "enum": [
+                            "atomic",
+                            "best-effort"
+                        ],
+                        "type": "string",
+                        "default": "atomic",
+                        "description": "Batch mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Item IDs",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/batch.Result"
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/batch.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/b-items/export": {
+            "get": {
+                "description": "Streams every item as newline delimited JSON or CSV, reading them from the database with a cursor",
+                "produces": [
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "itemB"
+                ],
+                "summary": "Exports items",
+                "parameters": [
+                    {
+                        "enum": [
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "ndjson",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/b-items/import": {
+            "post": {
+                "description": "Streams an NDJSON or CSV file, sent as the request body or as the file field of a multipart form, and upserts its rows in batches. Rows that fail are listed in the report while the others are imported. With the Prefer: respond-async header the file is imported by a background job instead, answered with 202 Accepted and the job status URL as Location, the report being the job result",
+                "consumes": [
+                    "application/x-ndjson",
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "itemB"
+                ],
+                "summary": "Imports items",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "NDJSON or CSV file",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond-async to import in the background",
+                        "name": "Prefer",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.ItemA"
+                            "$ref": "#/definitions/transfer.ImportReport"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/job.Job"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the job status"
+                            }
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/transfer.ImportReport"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {}
                     },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {}
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -86,7 +1143,62 @@ const docTemplate = `{
                 }
             }
         },
-        "/a-items/{id}": {
+        "/b-items/stream": {
+            "get": {
+                "description": "Sends an event whenever an item is created, updated, deleted or restored, as Server-Sent Events or as WebSocket messages when the connection is upgraded. Events published after the Last-Event-ID header or the lastEventId parameter are replayed first",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "itemB"
+                ],
+                "summary": "Streams item changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "created,deleted",
+                        "description": "Comma separated event types",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated item IDs",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "lastEventId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/changefeed.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/b-items/{id}": {
             "get": {
                 "description": "get item by ID",
                 "consumes": [
@@ -96,7 +1208,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "itemA"
+                    "itemB"
                 ],
                 "summary": "Show an item",
                 "parameters": [
@@ -112,7 +1224,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.ItemA"
+                            "$ref": "#/definitions/domain.ItemB"
                         }
                     },
                     "400": {
@@ -138,7 +1250,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "itemA"
+                    "itemB"
                 ],
                 "summary": "Updates an item",
                 "parameters": [
@@ -151,11 +1263,11 @@ const docTemplate = `{
                     },
                     {
                         "description": "Item Properties",
-                        "name": "itemA",
+                        "name": "itemB",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.ItemA"
+                            "$ref": "#/definitions/domain.ItemB"
                         }
                     }
                 ],
@@ -171,6 +1283,10 @@ const docTemplate = `{
                         "description": "Not Found",
                         "schema": {}
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -186,7 +1302,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "itemA"
+                    "itemB"
                 ],
                 "summary": "Deletes an item",
                 "parameters": [
@@ -202,7 +1318,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.ItemA"
+                            "$ref": "#/definitions/domain.ItemB"
                         }
                     },
                     "400": {
@@ -218,13 +1334,12 @@ const docTemplate = `{
                         "schema": {}
                     }
                 }
-            }
-        },
-        "/b-items": {
-            "get": {
-                "description": "Return all stored items",
+            },
+            "patch": {
+                "description": "Partially updates an item with given ID using a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) document",
                 "consumes": [
-                    "application/json"
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
                 ],
                 "produces": [
                     "application/json"
@@ -232,25 +1347,58 @@ const docTemplate = `{
                 "tags": [
                     "itemB"
                 ],
-                "summary": "Show all items",
+                "summary": "Patches an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch document",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.ItemB"
-                            }
+                            "$ref": "#/definitions/domain.ItemB"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {}
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
-            },
-            "post": {
-                "description": "creates an item with given data",
+            }
+        },
+        "/b-items/{id}/purge": {
+            "delete": {
+                "description": "Permanently deletes an item with given ID, including soft deleted ones. Restricted to admins",
                 "consumes": [
                     "application/json"
                 ],
@@ -260,29 +1408,32 @@ const docTemplate = `{
                 "tags": [
                     "itemB"
                 ],
-                "summary": "Creates an item",
+                "summary": "Purges an item",
                 "parameters": [
                     {
-                        "description": "Item Properties",
-                        "name": "itemB",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.ItemB"
-                        }
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.ItemB"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {}
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {}
@@ -294,9 +1445,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/b-items/{id}": {
-            "get": {
-                "description": "get item by ID",
+        "/b-items/{id}/restore": {
+            "post": {
+                "description": "Restores a soft deleted item with given ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -306,7 +1457,7 @@ const docTemplate = `{
                 "tags": [
                     "itemB"
                 ],
-                "summary": "Show an item",
+                "summary": "Restores an item",
                 "parameters": [
                     {
                         "type": "string",
@@ -317,11 +1468,8 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.ItemB"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -336,40 +1484,45 @@ const docTemplate = `{
                         "schema": {}
                     }
                 }
-            },
-            "put": {
-                "description": "Updates an item with given ID",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "description": "Returns the status of a background job. A queued or running job is answered with 202 Accepted and a Retry-After header telling when to poll again, a done job with 200 OK and its result or error. A job is only returned to the consumer who enqueued it and to the admins, the others getting 404 Not Found",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "itemB"
+                    "job"
                 ],
-                "summary": "Updates an item",
+                "summary": "Shows a job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item ID",
+                        "description": "Job ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Item Properties",
-                        "name": "itemB",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.ItemB"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/job.Job"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/job.Job"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "string",
+                                "description": "Seconds to wait before polling again"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -386,22 +1539,19 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Deletes an item with given ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Cancels a queued job right away, answering 200 OK, or asks the worker of a running job to stop it, answering 202 Accepted until the job is polled as cancelled. Only the consumer who enqueued the job and the admins can cancel it",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "itemB"
+                    "job"
                 ],
-                "summary": "Deletes an item",
+                "summary": "Cancels a job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item ID",
-                        "name": "string",
+                        "description": "Job ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -410,7 +1560,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.ItemB"
+                            "$ref": "#/definitions/job.Job"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/job.Job"
                         }
                     },
                     "400": {
@@ -421,6 +1577,10 @@ const docTemplate = `{
                         "description": "Not Found",
                         "schema": {}
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -430,19 +1590,293 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "batch.ItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validation.FieldError"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "batch.Mode": {
+            "type": "string",
+            "enum": [
+                "atomic",
+                "best-effort"
+            ],
+            "x-enum-varnames": [
+                "Atomic",
+                "BestEffort"
+            ]
+        },
+        "batch.Result": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/batch.ItemResult"
+                    }
+                },
+                "mode": {
+                    "$ref": "#/definitions/batch.Mode"
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "changefeed.Event": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "itemId": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/changefeed.EventType"
+                }
+            }
+        },
+        "changefeed.EventType": {
+            "type": "string",
+            "enum": [
+                "created",
+                "updated",
+                "deleted",
+                "restored"
+            ],
+            "x-enum-varnames": [
+                "Created",
+                "Updated",
+                "Deleted",
+                "Restored"
+            ]
+        },
         "domain.ItemA": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
                 "id": {
                     "type": "string"
+                },
+                "itemBId": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
         "domain.ItemB": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "job.Job": {
             "type": "object",
             "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
+                },
+                "maxAttempts": {
+                    "type": "integer"
+                },
+                "owner": {
+                    "description": "Owner is the consumer who enqueued the job, only them and the admins following it",
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "progress": {
+                    "description": "Progress is the percentage of the current attempt reported by the handler",
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "object"
+                },
+                "runAt": {
+                    "description": "RunAt is when the job is due, later than CreatedAt when an attempt is retried",
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/job.Status"
+                },
+                "timeout": {
+                    "description": "Timeout bounds every attempt, in nanoseconds",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "job.Status": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "running",
+                "succeeded",
+                "failed",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "Queued",
+                "Running",
+                "Succeeded",
+                "Failed",
+                "Cancelled"
+            ]
+        },
+        "transfer.ImportReport": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/transfer.RowError"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "imported": {
+                    "type": "integer"
+                }
+            }
+        },
+        "transfer.RowError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validation.FieldError"
+                    }
+                },
+                "line": {
+                    "type": "integer"
+                }
+            }
+        },
+        "validation.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
                 }
             }
         }
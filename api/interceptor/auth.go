@@ -0,0 +1,43 @@
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"app/internal/auth"
+)
+
+type authentication struct {
+	gateway auth.Gateway
+}
+
+// NewAuthInterceptor stores the consumer authenticated by Kong in the context, reading the same headers as the
+// REST API from the call metadata. The calls without the gateway secret are anonymous, like those reaching
+// GRPC_PORT directly
+func NewAuthInterceptor(gateway auth.Gateway) Interceptor {
+	return &authentication{
+		gateway: gateway,
+	}
+}
+
+func (i *authentication) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if principal, ok := i.gateway.Principal(metadataGetter(md)); ok {
+			ctx = auth.WithPrincipal(ctx, principal)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// metadataGetter returns the first value of a metadata key, keys are case insensitive like http headers
+func metadataGetter(md metadata.MD) func(key string) string {
+	return func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+}
@@ -0,0 +1,55 @@
+package interceptor
+
+import (
+	"context"
+	stdErrors "errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"app/internal/errors"
+	"app/internal/validation"
+)
+
+type errorMapping struct{}
+
+// NewErrorInterceptor turns the errors returned by the services into gRPC statuses, with the code matching the
+// http status of the REST API. Invalid fields are attached as BadRequest details
+func NewErrorInterceptor() Interceptor {
+	return &errorMapping{}
+}
+
+func (i *errorMapping) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, toStatus(err).Err()
+	}
+}
+
+func toStatus(err error) *status.Status {
+	st := status.New(errors.GetCode(err), err.Error())
+
+	var validationErr *validation.Error
+	if !stdErrors.As(err, &validationErr) {
+		return st
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(validationErr.Fields))
+	for _, field := range validationErr.Fields {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       field.Field,
+			Description: field.Message,
+		})
+	}
+	if detailed, detailsErr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); detailsErr == nil {
+		return detailed
+	}
+	return st
+}
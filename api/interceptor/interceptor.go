@@ -0,0 +1,8 @@
+package interceptor
+
+import "google.golang.org/grpc"
+
+// Interceptor is the gRPC counterpart of middleware.Middleware, wrapping every unary call
+type Interceptor interface {
+	Unary() grpc.UnaryServerInterceptor
+}
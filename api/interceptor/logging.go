@@ -0,0 +1,45 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"app/internal/logger"
+)
+
+const (
+	methodKey   = "method"
+	codeKey     = "code"
+	durationKey = "duration"
+
+	callHandled = "grpc call handled"
+)
+
+type logging struct {
+	log logger.Logger
+}
+
+// NewLoggingInterceptor logs the method, status code and duration of every call
+func NewLoggingInterceptor(log logger.Logger) Interceptor {
+	return &logging{
+		log: log,
+	}
+}
+
+func (i *logging) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		i.log.Info(ctx, callHandled, logrus.Fields{
+			methodKey:   info.FullMethod,
+			codeKey:     status.Code(err).String(),
+			durationKey: time.Since(start).String(),
+		})
+		return resp, err
+	}
+}
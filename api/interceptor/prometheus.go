@@ -0,0 +1,80 @@
+package interceptor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// standard counter metric
+	defaultGrpcCounterMetricName = "grpc_request_count"
+	defaultGrpcCounterMetricHelp = "Number of grpc request"
+
+	// standard latency metric
+	defaultGrpcLatencyMetricName = "grpc_request_latency_in_sec"
+	defaultGrpcLatencyMetricHelp = "Grpc request latency in sec"
+
+	// metric properties
+	codeProperty   = "code"
+	methodProperty = "method"
+
+	// errors
+	failedToRegisterStandardDurationMetric = "failed to register standard grpc duration metric"
+	failedToRegisterStandardCounterMetric  = "failed to register standard grpc counter metric"
+)
+
+type prometheus struct {
+	requestCounterMetric  *prom.CounterVec
+	requestDurationMetric *prom.HistogramVec
+}
+
+// NewPrometheusInterceptor counts the calls and measures their latency by method and status code,
+// the metrics are exposed on the /metrics route of the REST API
+func NewPrometheusInterceptor() Interceptor {
+	p := &prometheus{
+		requestCounterMetric: prom.NewCounterVec(
+			prom.CounterOpts{
+				Name: defaultGrpcCounterMetricName,
+				Help: defaultGrpcCounterMetricHelp,
+			},
+			[]string{codeProperty, methodProperty},
+		),
+		requestDurationMetric: prom.NewHistogramVec(
+			prom.HistogramOpts{
+				Name: defaultGrpcLatencyMetricName,
+				Help: defaultGrpcLatencyMetricHelp,
+			},
+			[]string{codeProperty, methodProperty},
+		),
+	}
+	p.registerMetrics()
+	return p
+}
+
+func (p *prometheus) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err).String()
+		p.requestDurationMetric.WithLabelValues(code, info.FullMethod).Observe(time.Since(start).Seconds())
+		p.requestCounterMetric.WithLabelValues(code, info.FullMethod).Inc()
+		return resp, err
+	}
+}
+
+func (p *prometheus) registerMetrics() {
+	err := prom.Register(p.requestCounterMetric)
+	if err != nil {
+		log.Println(failedToRegisterStandardCounterMetric)
+	}
+	err = prom.Register(p.requestDurationMetric)
+	if err != nil {
+		log.Println(failedToRegisterStandardDurationMetric)
+	}
+}
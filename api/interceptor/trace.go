@@ -0,0 +1,37 @@
+package interceptor
+
+import (
+	"context"
+
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"app/internal/trace"
+)
+
+type tracing struct{}
+
+// NewTraceInterceptor stores the request ID and trace context of every call in its context,
+// generating a request ID when the caller didn't send one and returning it in the response header
+func NewTraceInterceptor() Interceptor {
+	return &tracing{}
+}
+
+func (i *tracing) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		get := metadataGetter(md)
+		info := trace.Info{
+			RequestID:   get(trace.RequestIDHeader),
+			TraceParent: get(trace.TraceParentHeader),
+			TraceState:  get(trace.TraceStateHeader),
+		}
+		if info.RequestID == "" {
+			info.RequestID = uuid.NewV4().String()
+		}
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs(trace.RequestIDHeader, info.RequestID))
+		return handler(trace.WithInfo(ctx, info), req)
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"app/internal/auth"
+)
+
+type authentication struct {
+	gateway auth.Gateway
+}
+
+// NewAuthMiddleware stores the consumer of the requests forwarded by Kong in their context, the others being
+// anonymous
+func NewAuthMiddleware(gateway auth.Gateway) Middleware {
+	return &authentication{
+		gateway: gateway,
+	}
+}
+
+func (m *authentication) HandleFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if principal, ok := m.gateway.Principal(c.GetHeader); ok {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		}
+		c.Next()
+	}
+}
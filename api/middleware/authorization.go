@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"app/internal/auth"
+)
+
+type authorization struct {
+	group string
+}
+
+// NewAuthorizationMiddleware returns a middleware that only lets principals of the given group through
+func NewAuthorizationMiddleware(group string) Middleware {
+	return &authorization{
+		group: group,
+	}
+}
+
+func (m *authorization) HandleFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		if !principal.HasGroup(m.group) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+	}
+}
@@ -16,9 +16,9 @@ func NewCorsMiddleware() Middleware {
 func (m *cors) HandleFunc() gin.HandlerFunc {
 	return ginCors.Middleware(ginCors.Config{
 		Origins:         "*",
-		Methods:         "GET, PUT, POST, DELETE",
-		RequestHeaders:  "Origin, Authorization, Content-Type",
-		ExposedHeaders:  "",
+		Methods:         "GET, PUT, PATCH, POST, DELETE",
+		RequestHeaders:  "Origin, Authorization, Content-Type, X-Request-ID, traceparent, tracestate",
+		ExposedHeaders:  "Location, X-Request-ID",
 		MaxAge:          50 * time.Second,
 		Credentials:     false,
 		ValidateHeaders: false,
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"app/internal/errors"
+	"app/internal/validation"
+)
+
+type params struct {
+	rules map[string]string
+}
+
+// NewParamsMiddleware returns a middleware that validates the path params of a route, keyed by param name
+func NewParamsMiddleware(rules map[string]string) Middleware {
+	return &params{
+		rules: rules,
+	}
+}
+
+func (m *params) HandleFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var fields []validation.FieldError
+		for name, rules := range m.rules {
+			value, ok := c.Params.Get(name)
+			if !ok {
+				continue
+			}
+
+			err := validation.Param(name, value, rules)
+			if err == nil {
+				continue
+			}
+			if validationErr, ok := err.(*validation.Error); ok {
+				fields = append(fields, validationErr.Fields...)
+			}
+		}
+
+		if len(fields) > 0 {
+			err := validation.NewParamError(fields...)
+			c.AbortWithStatusJSON(errors.GetStatus(err), err)
+			return
+		}
+		c.Next()
+	}
+}
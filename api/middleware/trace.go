@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/trace"
+)
+
+type tracing struct{}
+
+// NewTraceMiddleware stores the request ID and trace context of every request in its context,
+// generating a request ID when the caller didn't send one and echoing it in the response
+func NewTraceMiddleware() Middleware {
+	return &tracing{}
+}
+
+func (m *tracing) HandleFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := trace.FromHeader(c.Request.Header)
+		if info.RequestID == "" {
+			info.RequestID = uuid.NewV4().String()
+		}
+
+		c.Header(trace.RequestIDHeader, info.RequestID)
+		c.Request = c.Request.WithContext(trace.WithInfo(c.Request.Context(), info))
+		c.Next()
+	}
+}
@@ -0,0 +1,286 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: serviceA/item_a.proto
+
+package serviceA
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ItemAServiceClient is the client API for ItemAService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ItemAServiceClient interface {
+	List(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error)
+	Get(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*ItemA, error)
+	Create(ctx context.Context, in *CreateItemRequest, opts ...grpc.CallOption) (*ItemA, error)
+	Update(ctx context.Context, in *UpdateItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Delete(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Restore(ctx context.Context, in *RestoreItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type itemAServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewItemAServiceClient(cc grpc.ClientConnInterface) ItemAServiceClient {
+	return &itemAServiceClient{cc}
+}
+
+func (c *itemAServiceClient) List(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error) {
+	out := new(ListItemsResponse)
+	err := c.cc.Invoke(ctx, "/servicea.v1.ItemAService/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemAServiceClient) Get(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*ItemA, error) {
+	out := new(ItemA)
+	err := c.cc.Invoke(ctx, "/servicea.v1.ItemAService/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemAServiceClient) Create(ctx context.Context, in *CreateItemRequest, opts ...grpc.CallOption) (*ItemA, error) {
+	out := new(ItemA)
+	err := c.cc.Invoke(ctx, "/servicea.v1.ItemAService/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemAServiceClient) Update(ctx context.Context, in *UpdateItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/servicea.v1.ItemAService/Update", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemAServiceClient) Delete(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/servicea.v1.ItemAService/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemAServiceClient) Restore(ctx context.Context, in *RestoreItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/servicea.v1.ItemAService/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ItemAServiceServer is the server API for ItemAService service.
+// All implementations must embed UnimplementedItemAServiceServer
+// for forward compatibility
+type ItemAServiceServer interface {
+	List(context.Context, *ListItemsRequest) (*ListItemsResponse, error)
+	Get(context.Context, *GetItemRequest) (*ItemA, error)
+	Create(context.Context, *CreateItemRequest) (*ItemA, error)
+	Update(context.Context, *UpdateItemRequest) (*emptypb.Empty, error)
+	Delete(context.Context, *DeleteItemRequest) (*emptypb.Empty, error)
+	Restore(context.Context, *RestoreItemRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedItemAServiceServer()
+}
+
+// UnimplementedItemAServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedItemAServiceServer struct {
+}
+
+func (UnimplementedItemAServiceServer) List(context.Context, *ListItemsRequest) (*ListItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedItemAServiceServer) Get(context.Context, *GetItemRequest) (*ItemA, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedItemAServiceServer) Create(context.Context, *CreateItemRequest) (*ItemA, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedItemAServiceServer) Update(context.Context, *UpdateItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedItemAServiceServer) Delete(context.Context, *DeleteItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedItemAServiceServer) Restore(context.Context, *RestoreItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedItemAServiceServer) mustEmbedUnimplementedItemAServiceServer() {}
+
+// UnsafeItemAServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ItemAServiceServer will
+// result in compilation errors.
+type UnsafeItemAServiceServer interface {
+	mustEmbedUnimplementedItemAServiceServer()
+}
+
+func RegisterItemAServiceServer(s grpc.ServiceRegistrar, srv ItemAServiceServer) {
+	s.RegisterService(&ItemAService_ServiceDesc, srv)
+}
+
+func _ItemAService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemAServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/servicea.v1.ItemAService/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemAServiceServer).List(ctx, req.(*ListItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemAService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemAServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/servicea.v1.ItemAService/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemAServiceServer).Get(ctx, req.(*GetItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemAService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemAServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/servicea.v1.ItemAService/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemAServiceServer).Create(ctx, req.(*CreateItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemAService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemAServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/servicea.v1.ItemAService/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemAServiceServer).Update(ctx, req.(*UpdateItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemAService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemAServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/servicea.v1.ItemAService/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemAServiceServer).Delete(ctx, req.(*DeleteItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemAService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemAServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/servicea.v1.ItemAService/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemAServiceServer).Restore(ctx, req.(*RestoreItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ItemAService_ServiceDesc is the grpc.ServiceDesc for ItemAService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ItemAService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "servicea.v1.ItemAService",
+	HandlerType: (*ItemAServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _ItemAService_List_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _ItemAService_Get_Handler,
+		},
+		{
+			MethodName: "Create",
+			Handler:    _ItemAService_Create_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _ItemAService_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _ItemAService_Delete_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _ItemAService_Restore_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "serviceA/item_a.proto",
+}
@@ -0,0 +1,286 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: serviceB/item_b.proto
+
+package serviceB
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ItemBServiceClient is the client API for ItemBService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ItemBServiceClient interface {
+	List(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error)
+	Get(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*ItemB, error)
+	Create(ctx context.Context, in *CreateItemRequest, opts ...grpc.CallOption) (*ItemB, error)
+	Update(ctx context.Context, in *UpdateItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Delete(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Restore(ctx context.Context, in *RestoreItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type itemBServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewItemBServiceClient(cc grpc.ClientConnInterface) ItemBServiceClient {
+	return &itemBServiceClient{cc}
+}
+
+func (c *itemBServiceClient) List(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error) {
+	out := new(ListItemsResponse)
+	err := c.cc.Invoke(ctx, "/serviceb.v1.ItemBService/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemBServiceClient) Get(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*ItemB, error) {
+	out := new(ItemB)
+	err := c.cc.Invoke(ctx, "/serviceb.v1.ItemBService/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemBServiceClient) Create(ctx context.Context, in *CreateItemRequest, opts ...grpc.CallOption) (*ItemB, error) {
+	out := new(ItemB)
+	err := c.cc.Invoke(ctx, "/serviceb.v1.ItemBService/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemBServiceClient) Update(ctx context.Context, in *UpdateItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/serviceb.v1.ItemBService/Update", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemBServiceClient) Delete(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/serviceb.v1.ItemBService/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemBServiceClient) Restore(ctx context.Context, in *RestoreItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/serviceb.v1.ItemBService/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ItemBServiceServer is the server API for ItemBService service.
+// All implementations must embed UnimplementedItemBServiceServer
+// for forward compatibility
+type ItemBServiceServer interface {
+	List(context.Context, *ListItemsRequest) (*ListItemsResponse, error)
+	Get(context.Context, *GetItemRequest) (*ItemB, error)
+	Create(context.Context, *CreateItemRequest) (*ItemB, error)
+	Update(context.Context, *UpdateItemRequest) (*emptypb.Empty, error)
+	Delete(context.Context, *DeleteItemRequest) (*emptypb.Empty, error)
+	Restore(context.Context, *RestoreItemRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedItemBServiceServer()
+}
+
+// UnimplementedItemBServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedItemBServiceServer struct {
+}
+
+func (UnimplementedItemBServiceServer) List(context.Context, *ListItemsRequest) (*ListItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedItemBServiceServer) Get(context.Context, *GetItemRequest) (*ItemB, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedItemBServiceServer) Create(context.Context, *CreateItemRequest) (*ItemB, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedItemBServiceServer) Update(context.Context, *UpdateItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedItemBServiceServer) Delete(context.Context, *DeleteItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedItemBServiceServer) Restore(context.Context, *RestoreItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedItemBServiceServer) mustEmbedUnimplementedItemBServiceServer() {}
+
+// UnsafeItemBServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ItemBServiceServer will
+// result in compilation errors.
+type UnsafeItemBServiceServer interface {
+	mustEmbedUnimplementedItemBServiceServer()
+}
+
+func RegisterItemBServiceServer(s grpc.ServiceRegistrar, srv ItemBServiceServer) {
+	s.RegisterService(&ItemBService_ServiceDesc, srv)
+}
+
+func _ItemBService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemBServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/serviceb.v1.ItemBService/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemBServiceServer).List(ctx, req.(*ListItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemBService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemBServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/serviceb.v1.ItemBService/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemBServiceServer).Get(ctx, req.(*GetItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemBService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemBServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/serviceb.v1.ItemBService/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemBServiceServer).Create(ctx, req.(*CreateItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemBService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemBServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/serviceb.v1.ItemBService/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemBServiceServer).Update(ctx, req.(*UpdateItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemBService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemBServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/serviceb.v1.ItemBService/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemBServiceServer).Delete(ctx, req.(*DeleteItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemBService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemBServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/serviceb.v1.ItemBService/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemBServiceServer).Restore(ctx, req.(*RestoreItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ItemBService_ServiceDesc is the grpc.ServiceDesc for ItemBService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ItemBService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "serviceb.v1.ItemBService",
+	HandlerType: (*ItemBServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _ItemBService_List_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _ItemBService_Get_Handler,
+		},
+		{
+			MethodName: "Create",
+			Handler:    _ItemBService_Create_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _ItemBService_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _ItemBService_Delete_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _ItemBService_Restore_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "serviceB/item_b.proto",
+}
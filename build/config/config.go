@@ -1,49 +1,522 @@
 package config
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
 	"app/build/env"
 	"app/build/flags"
 	"app/build/router"
+	"app/build/rpc"
+	"app/infra/cache/breaker"
+	cacheMemory "app/infra/cache/memory"
 	"app/infra/cache/redis"
+	"app/infra/cache/tiered"
+	changeFeedRedis "app/infra/changefeed/redis"
+	databaseMemory "app/infra/database/memory"
 	"app/infra/database/postgresql"
+	databaseSqlite "app/infra/database/sqlite"
+	jobMemory "app/infra/job/memory"
+	jobRedis "app/infra/job/redis"
+	lockMemory "app/infra/lock/memory"
+	lockPostgresql "app/infra/lock/postgresql"
+	lockRedis "app/infra/lock/redis"
+	"app/infra/messaging/kafka"
+	"app/infra/messaging/memory"
+	"app/infra/messaging/nats"
+	messagingRedis "app/infra/messaging/redis"
+	"app/internal/auth"
+	"app/internal/cacheaside"
+	"app/internal/changefeed"
+	"app/internal/consumer"
+	"app/internal/container"
+	"app/internal/health"
+	"app/internal/httpclient"
+	"app/internal/identifier"
+	"app/internal/job"
+	"app/internal/lock"
 	"app/internal/logger"
+	"app/internal/messaging"
+	"app/internal/outbox"
+	"app/internal/scheduler"
 	"app/internal/storage"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+)
+
+const (
+	// changeFeedChannel is the redis pub/sub channel the replicas of a service share their change events on,
+	// prefixed by the name of the service when it's hosted with others
+	changeFeedChannel   = "changefeed"
+	changeFeedSeparator = ":"
+
+	// cacheInvalidationChannel is the redis pub/sub channel the replicas share the keys to drop from their L1 on
+	cacheInvalidationChannel = "cache:invalidation"
+	// cacheProbe names the cache in the readiness report
+	cacheProbe = "cache"
+
+	memoryDriver   = "memory"
+	postgresDriver = "postgres"
+	sqliteDriver   = "sqlite"
+	redisDriver    = "redis"
+	natsDriver     = "nats"
+	kafkaDriver    = "kafka"
+
+	kafkaBrokersSeparator = ","
+
+	postgresLock = "postgres"
+	redisLock    = "redis"
+	memoryLock   = "memory"
+
+	unknownDBDriverErr        = "unknown database driver: %s"
+	unknownCacheDriverErr     = "unknown cache driver: %s"
+	unknownMessagingDriverErr = "unknown messaging driver: %s"
+	unknownLockErr            = "unknown lock: %s"
+	unknownJobStoreErr        = "unknown job store: %s"
+	outboxDriverErr           = "MESSAGING_DRIVER needs DB_DRIVER=postgres, the outbox relay running raw postgres SQL: %s"
 )
 
 type BuildArgs struct {
-	Env    env.Env
-	Flags  flags.Flags
+	Env   env.Env
+	Flags flags.Flags
+	// Router is the router of the service built by New, NewShared leaves the routers to NewService
 	Router *gin.Engine
 }
 
-type Config struct {
-	ServicePort string
-	Database    storage.Database
-	Cache       storage.Cache
-	Logger      logger.Logger
-	Router      *gin.Engine
-}
-
-func Build(args BuildArgs) Config {
-	return Config{
-		ServicePort: args.Env.ServiceEnv.Server.Port,
-		Database: postgresql.New(
-			args.Env.DBEnv.Server.Host,
-			args.Env.DBEnv.Server.Port,
-			args.Env.DBEnv.Credentials.Username,
-			args.Env.DBEnv.Credentials.Password,
-			args.Env.DBEnv.DatabaseName,
-		),
-		Cache: redis.New(
-			args.Env.CacheEnv.Server.Host,
-			args.Env.CacheEnv.Server.Port,
-		),
-		Logger: logger.NewLogger(
-			*args.Flags.Debug,
-		),
-		Router: router.New(
-			args.Router,
-		),
+// ServiceArgs are what a service hosted with others in a single process doesn't share with them
+type ServiceArgs struct {
+	// Name prefixes the change feed of the service, like serviceA
+	Name   string
+	Router *gin.Engine
+	Server ServerConfig
+}
+
+// The components provided by New, built the first time they're resolved. Server, Router and ChangeFeed belong to
+// a service, NewShared leaving them to NewService
+var (
+	Server      = container.NewKey[ServerConfig]("server")
+	Purge       = container.NewKey[PurgeConfig]("purge")
+	Logger      = container.NewKey[logger.Logger]("logger")
+	Database    = container.NewKey[storage.Database]("database")
+	Cache       = container.NewKey[storage.Cache]("cache")
+	Router      = container.NewKey[*gin.Engine]("router")
+	GRPCServer  = container.NewKey[*grpc.Server]("grpc-server")
+	IDGenerator = container.NewKey[identifier.Generator]("id-generator")
+	ChangeFeed  = container.NewKey[changefeed.Broker]("changefeed")
+	// ChangeFeedOrigins are the other origins whose pages may open the change feed WebSocket, set on CHANGEFEED_ORIGINS
+	ChangeFeedOrigins = container.NewKey[changefeed.Origins]("changefeed-origins")
+	// CacheAside tunes how the repositories reload the items missing from Cache
+	CacheAside = container.NewKey[cacheaside.Config]("cache-aside")
+	// RecomputeLocker lets a single replica at a time reload an item, it's nil unless CACHE_RECOMPUTE_LOCK is set
+	RecomputeLocker = container.NewKey[lock.Locker]("recompute-locker")
+	// Gateway trusts the consumers of the requests forwarded by Kong, set on GATEWAY_SECRET
+	Gateway = container.NewKey[auth.Gateway]("gateway")
+	// Readiness reports whether the replica is ready to serve, on the ReadyPath of the Router
+	Readiness = container.NewKey[health.Readiness]("readiness")
+	// Messaging is nil unless MESSAGING_DRIVER is set
+	Messaging = container.NewKey[messaging.Broker]("messaging")
+	// Outbox stores the domain events in the database for OutboxRelay to publish them on Messaging,
+	// both are nil when Messaging is and fail to resolve unless the database driver is postgres
+	Outbox      = container.NewKey[messaging.Publisher]("outbox")
+	OutboxRelay = container.NewKey[outbox.Relay]("outbox-relay")
+	// Consumer runs the handlers of the topics the service reads, it's nil when Messaging is
+	Consumer = container.NewKey[consumer.Consumer]("consumer")
+	// Jobs queues the background jobs JobPool runs, in the cache server
+	Jobs    = container.NewKey[job.Queue]("jobs")
+	JobPool = container.NewKey[job.Pool]("job-pool")
+	// Scheduler runs the scheduled tasks on a single replica at a time
+	Scheduler = container.NewKey[scheduler.Scheduler]("scheduler")
+	// ServiceBClient is nil unless SERVICE_B_URL is set
+	ServiceBClient = container.NewKey[*httpclient.Client]("service-b-client")
+
+	environment = container.NewKey[env.Env]("env")
+	jobStore    = container.NewKey[job.Store]("job-store")
+	// cacheBackend is the cache of the driver, Cache bypassing it while it fails
+	cacheBackend = container.NewKey[storage.Cache]("cache-backend")
+)
+
+type ServerConfig struct {
+	Port string
+	// GRPCPort is empty when gRPC shares Port with the REST API
+	GRPCPort string
+}
+
+type PurgeConfig struct {
+	Schedule  string
+	Retention time.Duration
+}
+
+// New returns the container providing the shared components of a service, the runners among them, like
+// the job pool, running from Start to Stop
+func New(args BuildArgs) *container.Container {
+	c := NewShared(args)
+	provideService(c, ServiceArgs{
+		Router: args.Router,
+		Server: ServerConfig{
+			Port:     args.Env.ServiceEnv.Server.Port,
+			GRPCPort: args.Env.ServiceEnv.Server.GRPCPort,
+		},
+	})
+	return c
+}
+
+// NewShared returns the container providing the components shared by the services hosted in a single process,
+// like the logger, the storage and the job pool, each service getting its own with NewService
+func NewShared(args BuildArgs) *container.Container {
+	c := container.New()
+
+	container.Value(c, environment, args.Env)
+	container.Value(c, Purge, PurgeConfig{
+		Schedule:  args.Env.ServiceEnv.Purge.Schedule,
+		Retention: time.Duration(args.Env.ServiceEnv.Purge.RetentionDays) * 24 * time.Hour,
+	})
+	container.Value(c, Gateway, auth.NewGateway(args.Env.ServiceEnv.Auth.GatewaySecret))
+	container.Value(c, ChangeFeedOrigins, changefeed.Origins(args.Env.ServiceEnv.ChangeFeed.Origins))
+	container.Provide(c, Logger, func(*container.Container) (logger.Logger, error) {
+		return logger.NewLogger(*args.Flags.Debug), nil
+	})
+	container.Provide(c, Database, func(*container.Container) (storage.Database, error) {
+		return newDatabase(args.Env.DBEnv)
+	}, container.Closer[storage.Database]())
+	container.Value(c, CacheAside, cacheaside.Config{
+		TTL:                  args.Env.CacheEnv.Aside.TTL,
+		EarlyExpiration:      args.Env.CacheEnv.Aside.EarlyExpiration,
+		StaleWhileRevalidate: args.Env.CacheEnv.Aside.StaleWhileRevalidate,
+		LockWait:             args.Env.CacheEnv.Aside.RecomputeLockWait,
+		LoadTimeout:          args.Env.CacheEnv.Aside.LoadTimeout,
+	})
+	container.Provide(c, RecomputeLocker, func(c *container.Container) (lock.Locker, error) {
+		properties := args.Env.CacheEnv.Aside
+		if properties.RecomputeLock == "" {
+			return nil, nil
+		}
+		return newLocker(c, args.Env.CacheEnv, properties.RecomputeLock, properties.RecomputeLockTTL)
+	})
+	container.Provide(c, cacheBackend, func(*container.Container) (storage.Cache, error) {
+		return newCache(args.Env.CacheEnv)
+	}, container.Closer[storage.Cache](), container.Background(func(ctx context.Context, cache storage.Cache) {
+		if twoTier, ok := cache.(tiered.Cache); ok {
+			twoTier.Run(ctx)
+		}
+	}))
+	container.Provide(c, Cache, func(c *container.Container) (storage.Cache, error) {
+		return breaker.New(
+			&breaker.DependenciesNode{Cache: container.MustResolve(c, cacheBackend)},
+			breaker.Config{
+				Name:          args.Env.CacheEnv.Driver,
+				RetryInterval: args.Env.CacheEnv.RetryInterval,
+				MaxTTL:        args.Env.CacheEnv.MaxTTL,
+				MaxPending:    args.Env.CacheEnv.MaxPendingRemovals,
+			},
+		), nil
+	})
+	container.Provide(c, Readiness, func(c *container.Container) (health.Readiness, error) {
+		probes := map[string]health.Probe{}
+		if probe, ok := container.MustResolve(c, Cache).(health.Probe); ok {
+			probes[cacheProbe] = probe
+		}
+		var required []string
+		if args.Env.CacheEnv.Required {
+			required = append(required, cacheProbe)
+		}
+		return health.New(&health.DependenciesNode{Probes: probes}, health.Config{Required: required}), nil
+	})
+	container.Provide(c, GRPCServer, func(c *container.Container) (*grpc.Server, error) {
+		return rpc.New(container.MustResolve(c, Logger), container.MustResolve(c, Gateway)), nil
+	})
+	container.Provide(c, IDGenerator, func(*container.Container) (identifier.Generator, error) {
+		return identifier.New(args.Env.ServiceEnv.IDStrategy)
+	})
+	container.Provide(c, Messaging, func(*container.Container) (messaging.Broker, error) {
+		return newMessaging(args.Env.ServiceEnv.Messaging, args.Env.CacheEnv)
+	})
+	container.Provide(c, Outbox, func(c *container.Container) (messaging.Publisher, error) {
+		if container.MustResolve(c, Messaging) == nil {
+			return nil, nil
+		}
+		if args.Env.DBEnv.Driver != postgresDriver {
+			return nil, fmt.Errorf(outboxDriverErr, args.Env.DBEnv.Driver)
+		}
+		return outbox.New(container.MustResolve(c, Database)), nil
+	})
+	container.Provide(c, OutboxRelay, func(c *container.Container) (outbox.Relay, error) {
+		// the relay runs along with the outbox, which fails on the databases other than postgres
+		if container.MustResolve(c, Outbox) == nil {
+			return nil, nil
+		}
+		return newOutboxRelay(
+			container.MustResolve(c, Database),
+			container.MustResolve(c, Messaging),
+			container.MustResolve(c, Logger),
+			args.Env.ServiceEnv.Outbox,
+		), nil
+	}, container.Background(func(ctx context.Context, relay outbox.Relay) {
+		relay.Run(ctx)
+	}))
+	container.Provide(c, Consumer, func(c *container.Container) (consumer.Consumer, error) {
+		return newConsumer(
+			container.MustResolve(c, Messaging),
+			container.MustResolve(c, Cache),
+			container.MustResolve(c, Logger),
+			args.Env.ServiceEnv.Consumer,
+		), nil
+	}, container.Background(func(ctx context.Context, consumer consumer.Consumer) {
+		if err := consumer.Run(ctx); err != nil {
+			log.Println(err)
+		}
+	}))
+	container.Provide(c, jobStore, func(*container.Container) (job.Store, error) {
+		return newJobStore(args.Env.CacheEnv.Server, args.Env.ServiceEnv.Jobs)
+	})
+	container.Provide(c, Jobs, func(c *container.Container) (job.Queue, error) {
+		return job.NewQueue(container.MustResolve(c, jobStore)), nil
+	})
+	container.Provide(c, JobPool, func(c *container.Container) (job.Pool, error) {
+		return newJobPool(
+			container.MustResolve(c, jobStore),
+			container.MustResolve(c, Logger),
+			args.Env.ServiceEnv.Jobs,
+		), nil
+	}, container.Background(func(ctx context.Context, pool job.Pool) {
+		pool.Run(ctx)
+	}))
+	container.Provide(c, Scheduler, func(c *container.Container) (scheduler.Scheduler, error) {
+		return newScheduler(c, args.Env.CacheEnv, args.Env.ServiceEnv.Scheduler)
+	}, container.Background(func(ctx context.Context, scheduler scheduler.Scheduler) {
+		scheduler.Run(ctx)
+	}))
+	container.Provide(c, ServiceBClient, func(*container.Container) (*httpclient.Client, error) {
+		return newClient(
+			args.Env.ServiceEnv.Clients.ServiceBURL,
+			args.Env.ServiceEnv.Clients.Timeout,
+		), nil
+	})
+
+	return c
+}
+
+// NewService returns the container of a service hosted with others in a single process, providing its own
+// Server, Router and ChangeFeed on top of the components of shared. It's started after shared and stopped before it
+func NewService(shared *container.Container, args ServiceArgs) *container.Container {
+	c := shared.Child()
+	provideService(c, args)
+	return c
+}
+
+func provideService(c *container.Container, args ServiceArgs) {
+	container.Value(c, Server, args.Server)
+	container.Provide(c, Router, func(c *container.Container) (*gin.Engine, error) {
+		engine := router.New(args.Router, container.MustResolve(c, Gateway))
+		engine.GET(health.ReadyPath, container.MustResolve(c, Readiness).Handle)
+		return engine, nil
+	})
+	container.Provide(c, ChangeFeed, func(c *container.Container) (changefeed.Broker, error) {
+		channel := changeFeedChannel
+		if args.Name != "" {
+			channel = args.Name + changeFeedSeparator + channel
+		}
+		return newChangeFeed(container.MustResolve(c, environment), channel), nil
+	}, container.Background(func(ctx context.Context, broker changefeed.Broker) {
+		broker.Run(ctx)
+	}))
+}
+
+// newLocker returns the lock.Locker of kind, on the cache server, the postgres server or the memory of the process
+func newLocker(c *container.Container, cacheEnv env.CacheEnv, kind string, ttl time.Duration) (lock.Locker, error) {
+	switch kind {
+	case redisLock:
+		return lockRedis.New(cacheEnv.Server.Host, cacheEnv.Server.Port, ttl), nil
+	case postgresLock:
+		dbEnv := container.MustResolve(c, environment).DBEnv
+		return lockPostgresql.New(
+			dbEnv.Server.Host,
+			dbEnv.Server.Port,
+			dbEnv.Credentials.Username,
+			dbEnv.Credentials.Password,
+			dbEnv.DatabaseName,
+			ttl,
+		)
+	case memoryLock:
+		return lockMemory.New(), nil
+	default:
+		return nil, fmt.Errorf(unknownLockErr, kind)
+	}
+}
+
+// newDatabase returns the database of the driver, the sqlite and memory databases being for a single replica
+func newDatabase(dbEnv env.DBEnv) (storage.Database, error) {
+	switch dbEnv.Driver {
+	case postgresDriver:
+		return postgresql.New(
+			dbEnv.Server.Host,
+			dbEnv.Server.Port,
+			dbEnv.Credentials.Username,
+			dbEnv.Credentials.Password,
+			dbEnv.DatabaseName,
+		), nil
+	case sqliteDriver:
+		return databaseSqlite.New(dbEnv.Path)
+	case memoryDriver:
+		return databaseMemory.New(), nil
+	default:
+		return nil, fmt.Errorf(unknownDBDriverErr, dbEnv.Driver)
+	}
+}
+
+// newCache returns the cache of the driver, the memory cache being for a single replica. The redis cache gets an
+// in-process L1 when its TTL is set, invalidated by the writes of the other replicas
+func newCache(cacheEnv env.CacheEnv) (storage.Cache, error) {
+	switch cacheEnv.Driver {
+	case redisDriver:
+		cache := redis.New(cacheEnv.Server.Host, cacheEnv.Server.Port)
+		if cacheEnv.L1.TTL <= 0 {
+			return cache, nil
+		}
+		return tiered.New(&tiered.DependenciesNode{
+			L2:        cache,
+			Transport: redis.NewInvalidation(cacheEnv.Server.Host, cacheEnv.Server.Port, cacheInvalidationChannel),
+		}, tiered.Config{
+			L1: cacheMemory.Config{
+				MaxEntries: cacheEnv.L1.MaxEntries,
+				MaxBytes:   cacheEnv.L1.MaxBytes,
+				TTL:        cacheEnv.L1.TTL,
+			},
+		}), nil
+	case memoryDriver:
+		return cacheMemory.New(cacheMemory.Config{
+			MaxEntries: cacheEnv.Memory.MaxEntries,
+			MaxBytes:   cacheEnv.Memory.MaxBytes,
+			TTL:        cacheEnv.Memory.TTL,
+		}), nil
+	default:
+		return nil, fmt.Errorf(unknownCacheDriverErr, cacheEnv.Driver)
+	}
+}
+
+// newJobStore keeps the jobs on the redis cache server, or in memory. There's no postgres store: the jobs kept in
+// memory are lost on restart and only run by the replica they were enqueued on
+func newJobStore(server env.ServerProperties, properties env.JobProperties) (job.Store, error) {
+	switch properties.Store {
+	case redisDriver:
+		return jobRedis.New(server.Host, server.Port, properties.Queue, properties.Retention), nil
+	case memoryDriver:
+		return jobMemory.New(properties.Retention), nil
+	default:
+		return nil, fmt.Errorf(unknownJobStoreErr, properties.Store)
+	}
+}
+
+// newChangeFeed shares the change events with the other replicas on channel of the cache server. The events stay
+// in the process when the cache is in memory
+func newChangeFeed(e env.Env, channel string) changefeed.Broker {
+	var transport changefeed.Transport
+	if e.CacheEnv.Driver == redisDriver {
+		transport = changeFeedRedis.New(e.CacheEnv.Server.Host, e.CacheEnv.Server.Port, channel)
+	}
+	return changefeed.New(e.ServiceEnv.ChangeFeed.HistorySize, transport)
+}
+
+func newClient(baseURL string, timeout time.Duration) *httpclient.Client {
+	if baseURL == "" {
+		return nil
+	}
+	return httpclient.New(httpclient.Config{
+		BaseURL: baseURL,
+		Timeout: timeout,
+	})
+}
+
+func newMessaging(properties env.MessagingProperties, cacheEnv env.CacheEnv) (messaging.Broker, error) {
+	switch properties.Driver {
+	case "":
+		return nil, nil
+	case memoryDriver:
+		return memory.New(), nil
+	case redisDriver:
+		return messagingRedis.New(cacheEnv.Server.Host, cacheEnv.Server.Port), nil
+	case natsDriver:
+		return nats.New(properties.URL), nil
+	case kafkaDriver:
+		return kafka.New(strings.Split(properties.URL, kafkaBrokersSeparator)), nil
+	default:
+		return nil, fmt.Errorf(unknownMessagingDriverErr, properties.Driver)
+	}
+}
+
+func newOutboxRelay(database storage.Database, broker messaging.Broker, log logger.Logger,
+	properties env.OutboxProperties) outbox.Relay {
+	if broker == nil {
+		return nil
+	}
+	return outbox.NewRelay(
+		&outbox.DependenciesNode{
+			Database:  database,
+			Publisher: broker,
+			Log:       log,
+		},
+		outbox.Config{
+			Interval:    properties.Interval,
+			BatchSize:   properties.BatchSize,
+			Retention:   properties.Retention,
+			MaxAttempts: properties.MaxAttempts,
+		},
+	)
+}
+
+func newConsumer(broker messaging.Broker, cache storage.Cache, log logger.Logger,
+	properties env.ConsumerProperties) consumer.Consumer {
+	if broker == nil {
+		return nil
+	}
+	return consumer.New(
+		&consumer.DependenciesNode{
+			Subscriber:  broker,
+			DeadLetters: broker,
+			Cache:       cache,
+			Log:         log,
+		},
+		consumer.Config{
+			Group:           properties.Group,
+			Concurrency:     properties.Concurrency,
+			MaxAttempts:     properties.MaxAttempts,
+			ShutdownTimeout: properties.ShutdownTimeout,
+			DedupTTL:        properties.DedupTTL,
+		},
+	)
+}
+
+func newJobPool(store job.Store, log logger.Logger, properties env.JobProperties) job.Pool {
+	return job.NewPool(
+		&job.DependenciesNode{
+			Store: store,
+			Log:   log,
+		},
+		job.Config{
+			Workers:         properties.Workers,
+			PollInterval:    properties.PollInterval,
+			ShutdownTimeout: properties.ShutdownTimeout,
+			Lease:           properties.Lease,
+		},
+	)
+}
+
+func newScheduler(c *container.Container, cacheEnv env.CacheEnv,
+	properties env.SchedulerProperties) (scheduler.Scheduler, error) {
+	locker, err := newLocker(c, cacheEnv, properties.Lock, properties.LockTTL)
+	if err != nil {
+		return nil, err
 	}
+	return scheduler.New(
+		&scheduler.DependenciesNode{
+			Locker: locker,
+			Log:    container.MustResolve(c, Logger),
+		},
+		scheduler.Config{
+			LeaderKey: properties.LeaderKey,
+			Jitter:    properties.Jitter,
+		},
+	), nil
 }
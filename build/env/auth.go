@@ -4,3 +4,9 @@ type StandardAuth struct {
 	Username string
 	Password string
 }
+
+// AuthProperties configures who the services trust. GatewaySecret is the secret Kong adds to the requests it
+// forwards, the consumer headers of the requests without it being ignored
+type AuthProperties struct {
+	GatewaySecret string
+}
@@ -1,5 +1,46 @@
 package env
 
+import "time"
+
 type CacheEnv struct {
+	// Driver is redis, the cache server at Server, or memory, keeping the cache, the jobs and the change feed in
+	// the memory of the process for local development
+	Driver string
 	Server ServerProperties
+	Memory MemoryCacheProperties
+	// L1 bounds the in-process tier of the redis cache, enabled when its TTL is set
+	L1    MemoryCacheProperties
+	Aside CacheAsideProperties
+	// RetryInterval is how long the cache is bypassed after failing, the repositories reading the database
+	RetryInterval time.Duration
+	// Required makes the replica not ready while the cache is degraded, it stays ready and reads the database
+	// otherwise
+	Required bool
+	// MaxTTL expires the values cached without a TTL, bounding how long one whose removal was lost is served
+	MaxTTL time.Duration
+	// MaxPendingRemovals bounds the keys removed once the degraded cache is back
+	MaxPendingRemovals int
+}
+
+// CacheAsideProperties protect the database from the concurrent reloads of the items missing from the cache. TTL
+// expires the cached items, recomputed up to EarlyExpiration times their load duration ahead of it, or served
+// StaleWhileRevalidate after it while they're recomputed. RecomputeLock is the lock of redis, postgres or memory
+// letting a single replica at a time reload an item, none when empty, the others waiting RecomputeLockWait for it.
+// LoadTimeout bounds a reload shared by the concurrent callers of an item
+type CacheAsideProperties struct {
+	TTL                  time.Duration
+	EarlyExpiration      float64
+	StaleWhileRevalidate time.Duration
+	RecomputeLock        string
+	RecomputeLockTTL     time.Duration
+	RecomputeLockWait    time.Duration
+	LoadTimeout          time.Duration
+}
+
+// MemoryCacheProperties bound the memory cache, evicting the least recently used entries past MaxEntries or
+// MaxBytes, zero being unbounded. TTL expires the entries, zero keeping them until evicted
+type MemoryCacheProperties struct {
+	MaxEntries int
+	MaxBytes   int
+	TTL        time.Duration
 }
@@ -1,7 +1,12 @@
 package env
 
 type DBEnv struct {
+	// Driver is postgres, the database at Server, sqlite, the database file at Path, or memory, keeping the rows
+	// in the memory of the process for the local development
+	Driver       string
 	Server       ServerProperties
 	Credentials  StandardAuth
 	DatabaseName string
+	// Path is the file of the sqlite database, :memory: keeping it in the memory of the process
+	Path string
 }
@@ -3,20 +3,137 @@ package env
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
-	dbHostEnv     = "DB_HOST"
-	dbPortEnv     = "DB_PORT"
-	dbUsernameEnv = "DB_USERNAME"
-	dbPasswordEnv = "DB_PASSWORD"
-	dbNameEnv     = "DB_NAME"
-	cacheHostEnv  = "CACHE_HOST"
-	cachePortEnv  = "CACHE_PORT"
-	hostEnv       = "SERVER_HOST"
-	portEnv       = "SERVER_PORT"
+	dbDriverEnv    = "DB_DRIVER"
+	dbHostEnv      = "DB_HOST"
+	dbPortEnv      = "DB_PORT"
+	dbUsernameEnv  = "DB_USERNAME"
+	dbPasswordEnv  = "DB_PASSWORD"
+	dbNameEnv      = "DB_NAME"
+	dbPathEnv      = "DB_PATH"
+	cacheDriverEnv = "CACHE_DRIVER"
+	cacheHostEnv   = "CACHE_HOST"
+	cachePortEnv   = "CACHE_PORT"
+	hostEnv        = "SERVER_HOST"
+	portEnv        = "SERVER_PORT"
+	idStrategyEnv  = "ID_STRATEGY"
+	grpcPortEnv    = "GRPC_PORT"
+
+	cacheMaxEntriesEnv = "CACHE_MAX_ENTRIES"
+	cacheMaxBytesEnv   = "CACHE_MAX_BYTES"
+	cacheTTLEnv        = "CACHE_TTL"
+	cacheL1TTLEnv      = "CACHE_L1_TTL"
+
+	cacheAsideTTLEnv             = "CACHE_ASIDE_TTL"
+	cacheEarlyExpirationEnv      = "CACHE_EARLY_EXPIRATION"
+	cacheStaleWhileRevalidateEnv = "CACHE_STALE_WHILE_REVALIDATE"
+	cacheRecomputeLockEnv        = "CACHE_RECOMPUTE_LOCK"
+	cacheRecomputeLockTTLEnv     = "CACHE_RECOMPUTE_LOCK_TTL"
+	cacheRecomputeLockWaitEnv    = "CACHE_RECOMPUTE_LOCK_WAIT"
+	cacheLoadTimeoutEnv          = "CACHE_LOAD_TIMEOUT"
+
+	cacheRetryIntervalEnv = "CACHE_RETRY_INTERVAL"
+	cacheRequiredEnv      = "CACHE_REQUIRED"
+	cacheMaxTTLEnv        = "CACHE_MAX_TTL"
+	cacheMaxPendingEnv    = "CACHE_MAX_PENDING_REMOVALS"
+
+	servicePortsEnv = "SERVICE_PORTS"
+
+	serviceBURLEnv   = "SERVICE_B_URL"
+	clientTimeoutEnv = "CLIENT_TIMEOUT"
+
+	changeFeedHistoryEnv = "CHANGEFEED_HISTORY_SIZE"
+	changeFeedOriginsEnv = "CHANGEFEED_ORIGINS"
+
+	messagingDriverEnv = "MESSAGING_DRIVER"
+	messagingURLEnv    = "MESSAGING_URL"
+
+	outboxIntervalEnv  = "OUTBOX_RELAY_INTERVAL"
+	outboxBatchSizeEnv = "OUTBOX_BATCH_SIZE"
+	outboxRetentionEnv = "OUTBOX_RETENTION"
+	outboxAttemptsEnv  = "OUTBOX_MAX_ATTEMPTS"
+
+	consumerGroupEnv           = "CONSUMER_GROUP"
+	consumerConcurrencyEnv     = "CONSUMER_CONCURRENCY"
+	consumerMaxAttemptsEnv     = "CONSUMER_MAX_ATTEMPTS"
+	consumerShutdownTimeoutEnv = "CONSUMER_SHUTDOWN_TIMEOUT"
+	consumerDedupTTLEnv        = "CONSUMER_DEDUP_TTL"
+
+	jobQueueEnv           = "JOB_QUEUE"
+	jobWorkersEnv         = "JOB_WORKERS"
+	jobPollIntervalEnv    = "JOB_POLL_INTERVAL"
+	jobShutdownTimeoutEnv = "JOB_SHUTDOWN_TIMEOUT"
+	jobRetentionEnv       = "JOB_RETENTION"
+	jobLeaseEnv           = "JOB_LEASE"
+	jobStoreEnv           = "JOB_STORE"
+
+	schedulerLockEnv      = "SCHEDULER_LOCK"
+	schedulerLockTTLEnv   = "SCHEDULER_LOCK_TTL"
+	schedulerLeaderKeyEnv = "SCHEDULER_LEADER_KEY"
+	schedulerJitterEnv    = "SCHEDULER_JITTER"
+
+	purgeScheduleEnv      = "PURGE_SCHEDULE"
+	purgeRetentionDaysEnv = "PURGE_RETENTION_DAYS"
+
+	gatewaySecretEnv = "GATEWAY_SECRET"
+
+	postgresDriver = "postgres"
+	sqliteDriver   = "sqlite"
+	redisDriver    = "redis"
+
+	defaultDBDriver    = postgresDriver
+	defaultCacheDriver = redisDriver
+	defaultDBPath      = ":memory:"
+
+	defaultCacheMaxEntries = 10000
+	defaultCacheMaxBytes   = 64 << 20
+
+	defaultCacheRecomputeLockTTL  = 10 * time.Second
+	defaultCacheRecomputeLockWait = time.Second
+	defaultCacheLoadTimeout       = 10 * time.Second
+
+	defaultCacheRetryInterval = 5 * time.Second
+	defaultCacheMaxTTL        = time.Hour
+	defaultCacheMaxPending    = 10000
+
+	listSeparator  = ","
+	entrySeparator = "="
+
+	defaultClientTimeout = 5 * time.Second
+
+	defaultChangeFeedHistorySize = 1000
+
+	defaultOutboxInterval  = time.Second
+	defaultOutboxBatchSize = 100
+	defaultOutboxRetention = 7 * 24 * time.Hour
+	defaultOutboxAttempts  = 10
+
+	defaultConsumerConcurrency     = 10
+	defaultConsumerMaxAttempts     = 5
+	defaultConsumerShutdownTimeout = 30 * time.Second
+	defaultConsumerDedupTTL        = 24 * time.Hour
+
+	defaultJobQueue           = "default"
+	defaultJobWorkers         = 4
+	defaultJobPollInterval    = time.Second
+	defaultJobShutdownTimeout = 30 * time.Second
+	defaultJobRetention       = 24 * time.Hour
+	defaultJobLease           = 30 * time.Second
+
+	defaultSchedulerLockTTL   = 30 * time.Second
+	defaultSchedulerLeaderKey = "scheduler"
+	defaultSchedulerJitter    = time.Minute
+
+	defaultPurgeSchedule      = "@daily"
+	defaultPurgeRetentionDays = 30
 
 	missingEnvErr = "missing env: %s"
+	invalidEnvErr = "invalid env %s: %v"
 )
 
 type Env struct {
@@ -28,41 +145,189 @@ type Env struct {
 func Build() Env {
 	var env Env
 	var ok bool
-	env.DBEnv.Server.Host, ok = os.LookupEnv(dbHostEnv)
-	if !ok {
-		log.Fatalf(missingEnvErr, dbHostEnv)
+	env.DBEnv.Driver = lookupString(dbDriverEnv, defaultDBDriver)
+	if env.DBEnv.Driver == postgresDriver {
+		env.DBEnv.Server.Host, ok = os.LookupEnv(dbHostEnv)
+		if !ok {
+			log.Fatalf(missingEnvErr, dbHostEnv)
+		}
+		env.DBEnv.Server.Port, ok = os.LookupEnv(dbPortEnv)
+		if !ok {
+			log.Fatalf(missingEnvErr, dbPortEnv)
+		}
+		env.DBEnv.Credentials.Username, ok = os.LookupEnv(dbUsernameEnv)
+		if !ok {
+			log.Fatalf(missingEnvErr, dbUsernameEnv)
+		}
+		env.DBEnv.Credentials.Password, ok = os.LookupEnv(dbPasswordEnv)
+		if !ok {
+			log.Fatalf(missingEnvErr, dbPasswordEnv)
+		}
+		env.DBEnv.DatabaseName, ok = os.LookupEnv(dbNameEnv)
+		if !ok {
+			log.Fatalf(missingEnvErr, dbNameEnv)
+		}
 	}
-	env.DBEnv.Server.Port, ok = os.LookupEnv(dbPortEnv)
-	if !ok {
-		log.Fatalf(missingEnvErr, dbPortEnv)
+	if env.DBEnv.Driver == sqliteDriver {
+		env.DBEnv.Path = lookupString(dbPathEnv, defaultDBPath)
 	}
-	env.DBEnv.Credentials.Username, ok = os.LookupEnv(dbUsernameEnv)
+	env.CacheEnv.Driver = lookupString(cacheDriverEnv, defaultCacheDriver)
+	if env.CacheEnv.Driver == redisDriver {
+		env.CacheEnv.Server.Host, ok = os.LookupEnv(cacheHostEnv)
+		if !ok {
+			log.Fatalf(missingEnvErr, cacheHostEnv)
+		}
+		env.CacheEnv.Server.Port, ok = os.LookupEnv(cachePortEnv)
+		if !ok {
+			log.Fatalf(missingEnvErr, cachePortEnv)
+		}
+	}
+	env.CacheEnv.Memory.MaxEntries = lookupInt(cacheMaxEntriesEnv, defaultCacheMaxEntries)
+	env.CacheEnv.Memory.MaxBytes = lookupInt(cacheMaxBytesEnv, defaultCacheMaxBytes)
+	env.CacheEnv.Memory.TTL = lookupDuration(cacheTTLEnv, 0)
+	// the L1 shares the bounds of the memory cache, its TTL bounding how long a lost invalidation leaves it stale
+	env.CacheEnv.L1 = env.CacheEnv.Memory
+	env.CacheEnv.L1.TTL = lookupDuration(cacheL1TTLEnv, 0)
+	env.CacheEnv.Aside.TTL = lookupDuration(cacheAsideTTLEnv, 0)
+	env.CacheEnv.Aside.EarlyExpiration = lookupFloat(cacheEarlyExpirationEnv, 0)
+	env.CacheEnv.Aside.StaleWhileRevalidate = lookupDuration(cacheStaleWhileRevalidateEnv, 0)
+	env.CacheEnv.Aside.RecomputeLock = os.Getenv(cacheRecomputeLockEnv)
+	env.CacheEnv.Aside.RecomputeLockTTL = lookupDuration(cacheRecomputeLockTTLEnv, defaultCacheRecomputeLockTTL)
+	env.CacheEnv.Aside.RecomputeLockWait = lookupDuration(cacheRecomputeLockWaitEnv, defaultCacheRecomputeLockWait)
+	env.CacheEnv.Aside.LoadTimeout = lookupDuration(cacheLoadTimeoutEnv, defaultCacheLoadTimeout)
+	env.CacheEnv.RetryInterval = lookupDuration(cacheRetryIntervalEnv, defaultCacheRetryInterval)
+	env.CacheEnv.Required = lookupBool(cacheRequiredEnv, false)
+	env.CacheEnv.MaxTTL = lookupDuration(cacheMaxTTLEnv, defaultCacheMaxTTL)
+	env.CacheEnv.MaxPendingRemovals = lookupInt(cacheMaxPendingEnv, defaultCacheMaxPending)
+	env.ServiceEnv.Server.Host, ok = os.LookupEnv(hostEnv)
 	if !ok {
-		log.Fatalf(missingEnvErr, dbUsernameEnv)
+		log.Fatalf(missingEnvErr, hostEnv)
 	}
-	env.DBEnv.Credentials.Password, ok = os.LookupEnv(dbPasswordEnv)
+	env.ServiceEnv.Server.Port, ok = os.LookupEnv(portEnv)
 	if !ok {
-		log.Fatalf(missingEnvErr, dbPasswordEnv)
+		log.Fatalf(missingEnvErr, portEnv)
 	}
-	env.DBEnv.DatabaseName, ok = os.LookupEnv(dbNameEnv)
+	env.ServiceEnv.Server.GRPCPort = os.Getenv(grpcPortEnv)
+	env.ServiceEnv.Server.ServicePorts = lookupMap(servicePortsEnv)
+	env.ServiceEnv.IDStrategy = os.Getenv(idStrategyEnv)
+	env.ServiceEnv.Purge.Schedule = lookupString(purgeScheduleEnv, defaultPurgeSchedule)
+	env.ServiceEnv.Purge.RetentionDays = lookupInt(purgeRetentionDaysEnv, defaultPurgeRetentionDays)
+	env.ServiceEnv.Clients.ServiceBURL = os.Getenv(serviceBURLEnv)
+	env.ServiceEnv.Clients.Timeout = lookupDuration(clientTimeoutEnv, defaultClientTimeout)
+	env.ServiceEnv.ChangeFeed.HistorySize = lookupInt(changeFeedHistoryEnv, defaultChangeFeedHistorySize)
+	env.ServiceEnv.ChangeFeed.Origins = lookupList(changeFeedOriginsEnv)
+	env.ServiceEnv.Messaging.Driver = os.Getenv(messagingDriverEnv)
+	env.ServiceEnv.Messaging.URL = os.Getenv(messagingURLEnv)
+	env.ServiceEnv.Outbox.Interval = lookupDuration(outboxIntervalEnv, defaultOutboxInterval)
+	env.ServiceEnv.Outbox.BatchSize = lookupInt(outboxBatchSizeEnv, defaultOutboxBatchSize)
+	env.ServiceEnv.Outbox.Retention = lookupDuration(outboxRetentionEnv, defaultOutboxRetention)
+	env.ServiceEnv.Outbox.MaxAttempts = lookupInt(outboxAttemptsEnv, defaultOutboxAttempts)
+	env.ServiceEnv.Consumer.Group = os.Getenv(consumerGroupEnv)
+	env.ServiceEnv.Consumer.Concurrency = lookupInt(consumerConcurrencyEnv, defaultConsumerConcurrency)
+	env.ServiceEnv.Consumer.MaxAttempts = lookupInt(consumerMaxAttemptsEnv, defaultConsumerMaxAttempts)
+	env.ServiceEnv.Consumer.ShutdownTimeout = lookupDuration(consumerShutdownTimeoutEnv, defaultConsumerShutdownTimeout)
+	env.ServiceEnv.Consumer.DedupTTL = lookupDuration(consumerDedupTTLEnv, defaultConsumerDedupTTL)
+	env.ServiceEnv.Jobs.Queue = lookupString(jobQueueEnv, defaultJobQueue)
+	env.ServiceEnv.Jobs.Workers = lookupInt(jobWorkersEnv, defaultJobWorkers)
+	env.ServiceEnv.Jobs.PollInterval = lookupDuration(jobPollIntervalEnv, defaultJobPollInterval)
+	env.ServiceEnv.Jobs.ShutdownTimeout = lookupDuration(jobShutdownTimeoutEnv, defaultJobShutdownTimeout)
+	env.ServiceEnv.Jobs.Retention = lookupDuration(jobRetentionEnv, defaultJobRetention)
+	env.ServiceEnv.Jobs.Lease = lookupDuration(jobLeaseEnv, defaultJobLease)
+	// the jobs are kept on the cache server, unless the cache is in memory
+	env.ServiceEnv.Jobs.Store = lookupString(jobStoreEnv, env.CacheEnv.Driver)
+	// the leader is elected on the cache server, unless the cache is in memory
+	env.ServiceEnv.Scheduler.Lock = lookupString(schedulerLockEnv, env.CacheEnv.Driver)
+	env.ServiceEnv.Scheduler.LockTTL = lookupDuration(schedulerLockTTLEnv, defaultSchedulerLockTTL)
+	env.ServiceEnv.Scheduler.LeaderKey = lookupString(schedulerLeaderKeyEnv, defaultSchedulerLeaderKey)
+	env.ServiceEnv.Scheduler.Jitter = lookupDuration(schedulerJitterEnv, defaultSchedulerJitter)
+	env.ServiceEnv.Auth.GatewaySecret = os.Getenv(gatewaySecretEnv)
+	return env
+}
+
+func lookupString(key string, fallback string) string {
+	value, ok := os.LookupEnv(key)
 	if !ok {
-		log.Fatalf(missingEnvErr, dbNameEnv)
+		return fallback
+	}
+	return value
+}
+
+// lookupList reads a list of values separated by commas
+func lookupList(key string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return nil
 	}
-	env.CacheEnv.Server.Host, ok = os.LookupEnv(cacheHostEnv)
+	var values []string
+	for _, v := range strings.Split(value, listSeparator) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// lookupMap reads a list of key=value entries separated by commas, like serviceA=:8085,serviceB=:8086
+func lookupMap(key string) map[string]string {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return nil
+	}
+	entries := map[string]string{}
+	for _, entry := range strings.Split(value, listSeparator) {
+		name, v, found := strings.Cut(entry, entrySeparator)
+		if !found || name == "" {
+			log.Fatalf(invalidEnvErr, key, entry)
+		}
+		entries[strings.TrimSpace(name)] = strings.TrimSpace(v)
+	}
+	return entries
+}
+
+func lookupDuration(key string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
 	if !ok {
-		log.Fatalf(missingEnvErr, cacheHostEnv)
+		return fallback
 	}
-	env.CacheEnv.Server.Port, ok = os.LookupEnv(cachePortEnv)
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf(invalidEnvErr, key, err)
+	}
+	return duration
+}
+
+func lookupInt(key string, fallback int) int {
+	value, ok := os.LookupEnv(key)
 	if !ok {
-		log.Fatalf(missingEnvErr, cachePortEnv)
+		return fallback
 	}
-	env.ServiceEnv.Server.Host, ok = os.LookupEnv(hostEnv)
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf(invalidEnvErr, key, err)
+	}
+	return number
+}
+
+func lookupFloat(key string, fallback float64) float64 {
+	value, ok := os.LookupEnv(key)
 	if !ok {
-		log.Fatalf(missingEnvErr, hostEnv)
+		return fallback
 	}
-	env.ServiceEnv.Server.Port, ok = os.LookupEnv(portEnv)
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Fatalf(invalidEnvErr, key, err)
+	}
+	return number
+}
+
+func lookupBool(key string, fallback bool) bool {
+	value, ok := os.LookupEnv(key)
 	if !ok {
-		log.Fatalf(missingEnvErr, portEnv)
+		return fallback
 	}
-	return env
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf(invalidEnvErr, key, err)
+	}
+	return flag
 }
@@ -3,4 +3,9 @@ package env
 type ServerProperties struct {
 	Host string
 	Port string
+	// GRPCPort serves gRPC on its own port, gRPC shares Port with the REST API when empty
+	GRPCPort string
+	// ServicePorts gives each service hosted by cmd/all its own port, like serviceA=:8085. The services without
+	// one are mounted under /<service> on Port
+	ServicePorts map[string]string
 }
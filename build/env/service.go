@@ -1,5 +1,87 @@
 package env
 
+import "time"
+
 type ServiceEnv struct {
-	Server ServerProperties
+	Server     ServerProperties
+	IDStrategy string
+	Purge      PurgeProperties
+	Clients    ClientsProperties
+	ChangeFeed ChangeFeedProperties
+	Messaging  MessagingProperties
+	Outbox     OutboxProperties
+	Consumer   ConsumerProperties
+	Jobs       JobProperties
+	Scheduler  SchedulerProperties
+	Auth       AuthProperties
+}
+
+// SchedulerProperties configures the scheduled tasks, run by the replica holding the LeaderKey lock of the redis
+// cache server, of the postgres database or of the process for a single replica, as Lock says. Every run is delayed
+// by up to Jitter
+type SchedulerProperties struct {
+	Lock      string
+	LockTTL   time.Duration
+	LeaderKey string
+	Jitter    time.Duration
+}
+
+// JobProperties configures the background jobs, stored under Queue on the redis cache server or in the memory of the
+// process, as Store says, and run by Workers workers
+type JobProperties struct {
+	Store           string
+	Queue           string
+	Workers         int
+	PollInterval    time.Duration
+	ShutdownTimeout time.Duration
+	// Retention is how long done jobs can be polled
+	Retention time.Duration
+	// Lease is how long a job stays with a worker that stopped renewing it before another worker runs it
+	Lease time.Duration
+}
+
+// ConsumerProperties configures the consumer group the service reads its topics as
+type ConsumerProperties struct {
+	Group           string
+	Concurrency     int
+	MaxAttempts     int
+	ShutdownTimeout time.Duration
+	// DedupTTL is how long the handled events are remembered
+	DedupTTL time.Duration
+}
+
+// OutboxProperties configures the relay publishing the events stored in the outbox table, giving an event up after
+// MaxAttempts failures
+type OutboxProperties struct {
+	Interval    time.Duration
+	BatchSize   int
+	Retention   time.Duration
+	MaxAttempts int
+}
+
+// MessagingProperties selects the message broker the domain events are published on, none when Driver is empty.
+// URL is the server URL for nats and the comma separated brokers for kafka, redis using the cache server
+type MessagingProperties struct {
+	Driver string
+	URL    string
+}
+
+// ChangeFeedProperties configures how many recent events are kept for subscribers resuming from an event ID, and
+// the Origins of the pages besides those of the service allowed to open the change feed WebSocket
+type ChangeFeedProperties struct {
+	HistorySize int
+	Origins     []string
+}
+
+// ClientsProperties configures the clients of the other services, a client is disabled when its URL is empty
+type ClientsProperties struct {
+	ServiceBURL string
+	Timeout     time.Duration
+}
+
+// PurgeProperties configures the task purging the items soft deleted more than RetentionDays ago, Schedule being
+// a cron expression or a descriptor like @daily
+type PurgeProperties struct {
+	Schedule      string
+	RetentionDays int
 }
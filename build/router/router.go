@@ -5,19 +5,26 @@ import (
 
 	"app/api/middleware"
 	"app/build/router/tools"
+	"app/internal/auth"
 )
 
-func New(routerEngine *gin.Engine) *gin.Engine {
-	registerStandardMiddlewares(routerEngine)
+// New registers the standard middlewares and tools on routerEngine, trusting the consumers of the requests
+// forwarded by gateway
+func New(routerEngine *gin.Engine, gateway auth.Gateway) *gin.Engine {
+	registerStandardMiddlewares(routerEngine, gateway)
 	tools.RegisterStandardTools(routerEngine)
 
 	return routerEngine
 }
 
-func registerStandardMiddlewares(router *gin.Engine) {
+func registerStandardMiddlewares(router *gin.Engine, gateway auth.Gateway) {
 	corsMiddleware := middleware.NewCorsMiddleware()
 	prometheusMiddleware := middleware.NewPrometheusMiddleware(router)
+	authMiddleware := middleware.NewAuthMiddleware(gateway)
+	traceMiddleware := middleware.NewTraceMiddleware()
 
 	router.Use(corsMiddleware.HandleFunc())
 	router.Use(prometheusMiddleware.HandleFunc())
+	router.Use(authMiddleware.HandleFunc())
+	router.Use(traceMiddleware.HandleFunc())
 }
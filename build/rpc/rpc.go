@@ -0,0 +1,31 @@
+package rpc
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"app/api/interceptor"
+	"app/internal/auth"
+	"app/internal/logger"
+)
+
+// New returns the gRPC server of a service with the standard interceptors, the counterpart of router.New.
+// The error interceptor runs last so the others see the final status code
+func New(log logger.Logger, gateway auth.Gateway) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(standardInterceptors(log, gateway)...),
+	)
+	reflection.Register(server)
+
+	return server
+}
+
+func standardInterceptors(log logger.Logger, gateway auth.Gateway) []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		interceptor.NewPrometheusInterceptor().Unary(),
+		interceptor.NewLoggingInterceptor(log).Unary(),
+		interceptor.NewAuthInterceptor(gateway).Unary(),
+		interceptor.NewTraceInterceptor().Unary(),
+		interceptor.NewErrorInterceptor().Unary(),
+	}
+}
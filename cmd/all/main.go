@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"app/build/config"
+	"app/build/env"
+	"app/build/flags"
+	"app/init/server"
+	"app/internal/container"
+	"app/internal/serviceA"
+	"app/internal/serviceB"
+
+	_ "app/api/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+const pathSeparator = "/"
+
+// hosted are the services run in the process, each on its own port of SERVICE_PORTS or under /<name> of
+// SERVER_PORT
+var hosted = []struct {
+	name    string
+	provide func(c *container.Container)
+	router  func(c *container.Container) (*gin.Engine, error)
+}{
+	{serviceA.Name, serviceA.Provide, serviceA.Router},
+	{serviceB.Name, serviceB.Provide, serviceB.Router},
+}
+
+// main runs every service in a single process for local development, sharing the logger, the metrics and the
+// storage, which CACHE_DRIVER=memory keeps in the process
+func main() {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Println("recovered in main")
+		}
+	}()
+
+	environment := env.Build()
+	shared := config.NewShared(
+		config.BuildArgs{
+			Env:   environment,
+			Flags: flags.Build(),
+		},
+	)
+
+	ports := environment.ServiceEnv.Server.ServicePorts
+	mux := http.NewServeMux()
+	services := make([]*container.Container, 0, len(hosted))
+	routers := make(map[string]*gin.Engine, len(hosted))
+	for _, service := range hosted {
+		c := config.NewService(shared, config.ServiceArgs{
+			Name:   service.name,
+			Router: gin.Default(),
+			Server: config.ServerConfig{Port: ports[service.name]},
+		})
+		service.provide(c)
+
+		router, err := service.router(c)
+		if err != nil {
+			log.Fatal(err)
+		}
+		services = append(services, c)
+		routers[service.name] = router
+
+		if ports[service.name] == "" {
+			prefix := pathSeparator + service.name
+			mux.Handle(prefix+pathSeparator, http.StripPrefix(prefix, router))
+		}
+	}
+	if _, err := container.Resolve(shared, config.Consumer); err != nil {
+		log.Fatal(err)
+	}
+	grpcServer, _ := container.Resolve(shared, config.GRPCServer)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// the shared components start first, the services building on them
+	if err := shared.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+	for _, c := range services {
+		if err := c.Start(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	for name, router := range routers {
+		port := ports[name]
+		if port == "" {
+			log.Printf("serving %s under %s%s", name, pathSeparator, name)
+			continue
+		}
+		log.Printf("serving %s on %s", name, port)
+		go func(router http.Handler) {
+			if err := http.ListenAndServe(port, router); err != nil {
+				log.Fatal(err)
+			}
+		}(router)
+	}
+	go func() {
+		// the gRPC services of every service share the server of SERVER_PORT, or GRPC_PORT when set
+		serverProperties := environment.ServiceEnv.Server
+		err := server.New(mux, grpcServer, serverProperties.GRPCPort).Run(serverProperties.Port)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	for i := len(services) - 1; i >= 0; i-- {
+		if err := services[i].Stop(context.Background()); err != nil {
+			log.Println(err)
+		}
+	}
+	if err := shared.Stop(context.Background()); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"app/internal/scaffold"
+)
+
+const (
+	usage = `Generates a new service backed by the generic crud layers.
+
+  go run ./cmd/scaffold -service serviceC -entity ItemC -field name:string:required,max=255 -field dueDate:time
+
+Flags:
+`
+	nextSteps = `Next steps:
+  - run swag init to add the routes of the service to api/docs
+  - add the service to the prometheus and kong configurations under build/docker if needed
+  - add the service to the hosted services of cmd/all to run it with the others
+  - run go test ./internal/%s/...
+`
+)
+
+// fields collects the repeated -field flags
+type fields []scaffold.Field
+
+func (f *fields) String() string {
+	names := make([]string, 0, len(*f))
+	for _, field := range *f {
+		names = append(names, field.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func (f *fields) Set(value string) error {
+	field, err := scaffold.ParseField(value)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, field)
+	return nil
+}
+
+func main() {
+	var (
+		spec   scaffold.Spec
+		fields fields
+		root   string
+		dryRun bool
+	)
+	flag.StringVar(&spec.Service, "service", "", "name of the service packages, like serviceC")
+	flag.StringVar(&spec.Entity, "entity", "", "name of the domain type, like ItemC")
+	flag.StringVar(&spec.Resource, "resource", "", "path of the items under /api/v1, the kebab case plural of the entity by default")
+	flag.IntVar(&spec.Port, "port", scaffold.DefaultPort, "port the service listens on")
+	flag.Var(&fields, "field", "field of the entity as name:type or name:type:rules, repeated, the types being "+
+		strings.Join(scaffold.TypeNames(), ", "))
+	flag.StringVar(&root, "root", ".", "root of the module")
+	flag.BoolVar(&dryRun, "dry-run", false, "list the files without writing them")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	spec.Fields = fields
+
+	scaffolder := scaffold.New(scaffold.Config{
+		Root:   root,
+		DryRun: dryRun,
+	})
+	files, err := scaffolder.Generate(spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := scaffolder.Write(files); err != nil {
+		log.Fatal(err)
+	}
+
+	verb := "created"
+	if dryRun {
+		verb = "would create"
+	}
+	for _, file := range files {
+		fmt.Printf("%s %s\n", verb, file.Path)
+	}
+	if !dryRun {
+		fmt.Printf(nextSteps, spec.Service)
+	}
+}
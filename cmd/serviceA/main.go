@@ -1,15 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"app/build/config"
 	"app/build/env"
 	"app/build/flags"
 	"app/init/server"
-	"app/internal/serviceA/handler"
-	"app/internal/serviceA/repository"
-	"app/internal/serviceA/service"
+	"app/internal/container"
+	"app/internal/serviceA"
 
 	_ "app/api/docs"
 
@@ -30,37 +33,42 @@ func main() {
 		}
 	}()
 
-	cfg := config.Build(
+	c := config.New(
 		config.BuildArgs{
 			Env:    env.Build(),
 			Flags:  flags.Build(),
 			Router: gin.Default(),
 		},
 	)
+	serviceA.Provide(c)
 
-	repo := repository.New(
-		&repository.DependenciesNode{
-			Database: cfg.Database,
-			Cache:    cfg.Cache,
-		},
-	)
-
-	api := service.New(
-		&service.DependenciesNode{
-			Log:        cfg.Logger,
-			Repository: repo,
-		},
-	)
+	router, err := serviceA.Router(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := container.Resolve(c, config.Consumer); err != nil {
+		log.Fatal(err)
+	}
+	serverConfig, _ := container.Resolve(c, config.Server)
+	grpcServer, _ := container.Resolve(c, config.GRPCServer)
 
-	handlerGateway := handler.New(
-		&handler.DependenciesNode{
-			Service: api,
-			Router:  cfg.Router,
-		},
-	)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	err := server.New(handlerGateway.GetRouter()).Run(cfg.ServicePort)
-	if err != nil {
+	if err := c.Start(ctx); err != nil {
 		log.Fatal(err)
 	}
+
+	go func() {
+		err := server.New(router, grpcServer, serverConfig.GRPCPort).Run(serverConfig.Port)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	// the server stops with the process, once the consumer and the workers are done with what they started
+	<-ctx.Done()
+	if err := c.Stop(context.Background()); err != nil {
+		log.Println(err)
+	}
 }
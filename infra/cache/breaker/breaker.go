@@ -0,0 +1,221 @@
+// Package breaker keeps a failing cache from slowing down or failing the callers: once a call fails, the cache is
+// degraded and bypassed for a while, the callers falling back to what the cache was sparing them, like the database
+package breaker
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"app/infra/cache/metrics"
+	appErrors "app/internal/errors"
+	"app/internal/storage"
+)
+
+const (
+	DefaultMaxPending = 10000
+
+	defaultName = "cache"
+	// probeKey is read to tell whether a degraded cache is back when no call reaches it
+	probeKey = "breaker:probe"
+
+	cacheDegraded   = "cache %s degraded, bypassing it for %v: %v\n"
+	cacheRecovered  = "cache %s recovered\n"
+	removalsDropped = "cache %s has %d removals pending, dropping the next ones until it recovers\n"
+)
+
+// Cache is a storage.Cache telling whether it's degraded
+type Cache interface {
+	storage.Cache
+	// Degraded tells whether the last calls to the cache failed, until one succeeds
+	Degraded() bool
+}
+
+type DependenciesNode struct {
+	Cache storage.Cache
+}
+
+type Config struct {
+	// Name labels the metrics of the cache
+	Name string
+	// RetryInterval is how long the cache is bypassed after a failure before it's called again
+	RetryInterval time.Duration
+	// MaxTTL expires the values set without a TTL, so a value whose removal was lost, like a pending removal
+	// dropped or one another replica couldn't make, isn't served forever. Zero keeps them until removed
+	MaxTTL time.Duration
+	// MaxPending bounds the keys waiting to be removed once the cache is back, DefaultMaxPending when zero. The
+	// next ones are dropped, the values they hold being served until MaxTTL
+	MaxPending int
+}
+
+type breaker struct {
+	deps    *DependenciesNode
+	config  Config
+	metrics *metrics.Metrics
+	now     func() time.Time
+
+	mu sync.Mutex
+	// failedAt is when the last call failed, zero while the cache is healthy
+	failedAt time.Time
+	// pending holds the keys whose removal failed or was bypassed, removed once the cache is back so it doesn't
+	// serve the values they had before
+	pending map[string]struct{}
+	// dropping tells removals were dropped since the cache was degraded, logged once
+	dropping bool
+}
+
+// New returns a Cache calling deps.Cache until it fails. It's bypassed for RetryInterval afterwards: Get and Set fail
+// with errors.ErrCacheUnavailable without reaching it, and Remove defers the removal of the keys until it's back
+func New(deps *DependenciesNode, config Config) Cache {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.MaxPending <= 0 {
+		config.MaxPending = DefaultMaxPending
+	}
+	b := &breaker{
+		deps:    deps,
+		config:  config,
+		metrics: metrics.Initialize(),
+		now:     time.Now,
+		pending: map[string]struct{}{},
+	}
+	b.metrics.Degraded.Set(0, config.Name)
+	return b
+}
+
+// Set sets value with MaxTTL when it's set
+func (b *breaker) Set(key string, value interface{}) error {
+	if b.config.MaxTTL > 0 {
+		return b.SetWithTTL(key, value, b.config.MaxTTL)
+	}
+	if !b.available() {
+		return appErrors.ErrCacheUnavailable
+	}
+	err := b.deps.Cache.Set(key, value)
+	b.observe(err)
+	return err
+}
+
+func (b *breaker) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	if !b.available() {
+		return appErrors.ErrCacheUnavailable
+	}
+	err := b.deps.Cache.SetWithTTL(key, value, ttl)
+	b.observe(err)
+	return err
+}
+
+func (b *breaker) Get(key string) ([]byte, error) {
+	if !b.available() {
+		return nil, appErrors.ErrCacheUnavailable
+	}
+	data, err := b.deps.Cache.Get(key)
+	b.observe(err)
+	return data, err
+}
+
+// Remove never fails, the keys it couldn't remove being removed once the cache is back, up to MaxPending keys
+func (b *breaker) Remove(keys ...string) error {
+	if b.available() {
+		err := b.deps.Cache.Remove(keys...)
+		b.observe(err)
+		if err == nil {
+			return nil
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, key := range keys {
+		if _, ok := b.pending[key]; !ok && len(b.pending) >= b.config.MaxPending {
+			if !b.dropping {
+				log.Printf(removalsDropped, b.config.Name, len(b.pending))
+				b.dropping = true
+			}
+			continue
+		}
+		b.pending[key] = struct{}{}
+	}
+	return nil
+}
+
+// Degraded probes a degraded cache once RetryInterval elapses, making the pending removals and reading it, so it
+// recovers without waiting for calls that may never come, like those of a replica taken out of rotation because of it
+func (b *breaker) Degraded() bool {
+	if !b.degraded() {
+		return false
+	}
+	if b.available() {
+		_, err := b.deps.Cache.Get(probeKey)
+		b.observe(err)
+	}
+	return b.degraded()
+}
+
+func (b *breaker) degraded() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.failedAt.IsZero()
+}
+
+// available tells whether the cache can be called, which it can't for RetryInterval after a failure. Once it
+// elapses, the pending removals are made before anything is read
+func (b *breaker) available() bool {
+	b.mu.Lock()
+	if b.failedAt.IsZero() {
+		b.mu.Unlock()
+		return true
+	}
+	if b.now().Before(b.failedAt.Add(b.config.RetryInterval)) {
+		b.mu.Unlock()
+		return false
+	}
+	keys := make([]string, 0, len(b.pending))
+	for key := range b.pending {
+		keys = append(keys, key)
+	}
+	b.mu.Unlock()
+	sort.Strings(keys)
+
+	if len(keys) == 0 {
+		return true
+	}
+	err := b.deps.Cache.Remove(keys...)
+	if err != nil {
+		b.observe(err)
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, key := range keys {
+		delete(b.pending, key)
+	}
+	return true
+}
+
+// observe degrades the cache when err is set, restoring it otherwise once no removal is pending
+func (b *breaker) observe(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err != nil {
+		b.metrics.Failures.Increment(b.config.Name)
+		if b.failedAt.IsZero() {
+			log.Printf(cacheDegraded, b.config.Name, b.config.RetryInterval, err)
+			b.metrics.Degraded.Set(1, b.config.Name)
+		}
+		b.failedAt = b.now()
+		return
+	}
+
+	if b.failedAt.IsZero() || len(b.pending) > 0 {
+		return
+	}
+	b.failedAt = time.Time{}
+	b.dropping = false
+	log.Printf(cacheRecovered, b.config.Name)
+	b.metrics.Degraded.Set(0, b.config.Name)
+}
@@ -0,0 +1,144 @@
+package breaker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	appErrors "app/internal/errors"
+	errorsAssertion "app/internal/test/assertion/errors"
+	storageMock "app/internal/test/mocks/storage"
+)
+
+func TestBreaker(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cache Breaker Suits")
+}
+
+var _ = Describe("Cache Breaker", func() {
+	const retryInterval = time.Minute
+
+	var (
+		cacheMock *storageMock.Cache
+		cache     *breaker
+		now       time.Time
+	)
+
+	BeforeEach(func() {
+		cacheMock = &storageMock.Cache{}
+		now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		cache = New(&DependenciesNode{Cache: cacheMock}, Config{RetryInterval: retryInterval}).(*breaker)
+		cache.now = func() time.Time { return now }
+	})
+
+	When("the cache answers", func() {
+		It("Should pass the calls through", func() {
+			cacheMock.On("Get", "missing").Return(nil, nil).Once()
+			cacheMock.On("Get", "key").Return([]byte(`"value"`), nil).Once()
+
+			Expect(cache.Get("missing")).To(BeNil())
+			Expect(cache.Get("key")).To(MatchJSON(`"value"`))
+			Expect(cache.Degraded()).To(BeFalse())
+		})
+	})
+
+	When("a call fails", func() {
+		BeforeEach(func() {
+			cacheMock.On("Get", "key").Return(nil, errorsAssertion.ErrGeneric).Once()
+			_, err := cache.Get("key")
+			Expect(err).To(MatchError(errorsAssertion.ErrGeneric))
+		})
+
+		It("Should bypass the cache until the retry interval elapses", func() {
+			Expect(cache.Degraded()).To(BeTrue())
+
+			_, err := cache.Get("key")
+			Expect(err).To(MatchError(appErrors.ErrCacheUnavailable))
+			Expect(cache.Set("key", "value")).To(MatchError(appErrors.ErrCacheUnavailable))
+			Expect(cache.Remove("key")).To(Succeed())
+			cacheMock.AssertNumberOfCalls(GinkgoT(), "Get", 1)
+			cacheMock.AssertNotCalled(GinkgoT(), "Set", "key", "value")
+			cacheMock.AssertNotCalled(GinkgoT(), "Remove", "key")
+		})
+
+		It("Should make the pending removals before reading the cache again", func() {
+			Expect(cache.Remove("first", "second")).To(Succeed())
+
+			now = now.Add(retryInterval)
+			cacheMock.On("Remove", "first", "second").Return(nil).Once()
+			cacheMock.On("Get", "key").Return(nil, nil).Once()
+
+			Expect(cache.Get("key")).To(BeNil())
+			Expect(cache.Degraded()).To(BeFalse())
+			cacheMock.AssertNumberOfCalls(GinkgoT(), "Remove", 1)
+		})
+
+		It("Should stay degraded while the pending removals fail", func() {
+			Expect(cache.Remove("key")).To(Succeed())
+
+			now = now.Add(retryInterval)
+			cacheMock.On("Remove", "key").Return(errorsAssertion.ErrGeneric).Once()
+
+			_, err := cache.Get("key")
+			Expect(err).To(MatchError(appErrors.ErrCacheUnavailable))
+			Expect(cache.Degraded()).To(BeTrue())
+			cacheMock.AssertNumberOfCalls(GinkgoT(), "Get", 1)
+		})
+
+		It("Should recover once the cache is back without any call reaching it", func() {
+			Expect(cache.Remove("key")).To(Succeed())
+
+			now = now.Add(retryInterval)
+			cacheMock.On("Remove", "key").Return(nil).Once()
+			cacheMock.On("Get", probeKey).Return(nil, nil).Once()
+
+			Expect(cache.Degraded()).To(BeFalse())
+			Expect(cache.pending).To(BeEmpty())
+			cacheMock.AssertNumberOfCalls(GinkgoT(), "Remove", 1)
+		})
+
+		It("Should stay degraded while the probe fails", func() {
+			now = now.Add(retryInterval)
+			cacheMock.On("Get", probeKey).Return(nil, errorsAssertion.ErrGeneric).Once()
+
+			Expect(cache.Degraded()).To(BeTrue())
+			Expect(cache.Degraded()).To(BeTrue())
+			cacheMock.AssertNumberOfCalls(GinkgoT(), "Get", 2)
+		})
+
+		It("Should report it as degraded on /metrics", func() {
+			recorder := httptest.NewRecorder()
+			promhttp.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+			Expect(recorder.Body.String()).To(ContainSubstring(`cache_degraded{cache="cache"} 1`))
+		})
+
+		It("Should drop the removals past the pending bound", func() {
+			cache.config.MaxPending = 2
+			Expect(cache.Remove("first", "second", "third")).To(Succeed())
+			Expect(cache.Remove("first")).To(Succeed())
+
+			now = now.Add(retryInterval)
+			cacheMock.On("Remove", "first", "second").Return(nil).Once()
+			cacheMock.On("Get", "key").Return(nil, nil).Once()
+
+			Expect(cache.Get("key")).To(BeNil())
+			cacheMock.AssertNumberOfCalls(GinkgoT(), "Remove", 1)
+		})
+	})
+
+	When("a value is set without a TTL", func() {
+		It("Should expire it after the max TTL", func() {
+			cache.config.MaxTTL = time.Hour
+			cacheMock.On("SetWithTTL", "key", "value", time.Hour).Return(nil).Once()
+
+			Expect(cache.Set("key", "value")).To(Succeed())
+			cacheMock.AssertNotCalled(GinkgoT(), "Set", "key", "value")
+		})
+	})
+})
@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"app/infra/cache/metrics"
+	"app/internal/storage"
+)
+
+const defaultName = "memory"
+
+type Config struct {
+	// Name identifies the cache in the metrics, memory by default
+	Name string
+	// MaxEntries bounds the number of cached keys, evicting the least recently used ones past it. Zero is unbounded
+	MaxEntries int
+	// MaxBytes bounds the size of the cached values, evicting the least recently used ones past it. Zero is
+	// unbounded
+	MaxBytes int
+	// TTL expires an entry once elapsed since it was set. Zero keeps the entries until evicted or removed
+	TTL time.Duration
+}
+
+// Cache is a storage.Cache that can be emptied at once
+type Cache interface {
+	storage.Cache
+	// Clear removes every entry
+	Clear()
+}
+
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+type memory struct {
+	mu      sync.Mutex
+	config  Config
+	metrics *metrics.Metrics
+	now     func() time.Time
+	// lru holds the entries from the most to the least recently used
+	lru   *list.List
+	items map[string]*list.Element
+	bytes int
+}
+
+// New returns a storage.Cache on the memory of the process, evicting the least recently used entries past the
+// bounds of config. Values are stored as JSON like in the cache server, so the callers decode them the same way
+func New(config Config) Cache {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	return &memory{
+		config:  config,
+		metrics: metrics.Initialize(),
+		now:     time.Now,
+		lru:     list.New(),
+		items:   map[string]*list.Element{},
+	}
+}
+
+func (m *memory) Set(key string, value interface{}) error {
+	return m.set(key, value, m.config.TTL)
+}
+
+// SetWithTTL expires the entry once ttl elapsed, or the TTL of the cache when shorter
+func (m *memory) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	if m.config.TTL > 0 && m.config.TTL < ttl {
+		ttl = m.config.TTL
+	}
+	return m.set(key, value, ttl)
+}
+
+func (m *memory) set(key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = m.now().Add(ttl)
+	}
+	if element, ok := m.items[key]; ok {
+		m.drop(element)
+	}
+	m.items[key] = m.lru.PushFront(&entry{key: key, value: data, expiresAt: expiresAt})
+	m.bytes += len(data)
+
+	for m.overBounds() {
+		m.drop(m.lru.Back())
+		m.metrics.Evictions.Increment(m.config.Name, metrics.EvictedForSize)
+	}
+	m.observeSize()
+	return nil
+}
+
+// Get returns nil when key isn't cached or has expired
+func (m *memory) Get(key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.items[key]
+	if !ok {
+		m.metrics.Misses.Increment(m.config.Name)
+		return nil, nil
+	}
+
+	cached := element.Value.(*entry)
+	if !cached.expiresAt.IsZero() && !m.now().Before(cached.expiresAt) {
+		m.drop(element)
+		m.metrics.Evictions.Increment(m.config.Name, metrics.EvictedForExpiration)
+		m.metrics.Misses.Increment(m.config.Name)
+		m.observeSize()
+		return nil, nil
+	}
+
+	m.lru.MoveToFront(element)
+	m.metrics.Hits.Increment(m.config.Name)
+	return cached.value, nil
+}
+
+func (m *memory) Remove(keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		if element, ok := m.items[key]; ok {
+			m.drop(element)
+		}
+	}
+	m.observeSize()
+	return nil
+}
+
+func (m *memory) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lru.Init()
+	m.items = map[string]*list.Element{}
+	m.bytes = 0
+	m.observeSize()
+}
+
+func (m *memory) overBounds() bool {
+	if m.lru.Len() == 0 {
+		return false
+	}
+	return (m.config.MaxEntries > 0 && m.lru.Len() > m.config.MaxEntries) ||
+		(m.config.MaxBytes > 0 && m.bytes > m.config.MaxBytes)
+}
+
+func (m *memory) drop(element *list.Element) {
+	cached := m.lru.Remove(element).(*entry)
+	delete(m.items, cached.key)
+	m.bytes -= len(cached.value)
+}
+
+func (m *memory) observeSize() {
+	m.metrics.Entries.Set(float64(m.lru.Len()), m.config.Name)
+	m.metrics.Bytes.Set(float64(m.bytes), m.config.Name)
+}
@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMemory(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Memory Cache Suits")
+}
+
+var _ = Describe("Memory Cache", func() {
+	var cache Cache
+
+	BeforeEach(func() {
+		cache = New(Config{})
+	})
+
+	When("the key is cached", func() {
+		It("Should return the value as JSON", func() {
+			Expect(cache.Set("key", map[string]string{"name": "item"})).To(Succeed())
+
+			data, err := cache.Get("key")
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(data).To(MatchJSON(`{"name":"item"}`))
+		})
+	})
+
+	When("the key isn't cached", func() {
+		It("Should return nil", func() {
+			data, err := cache.Get("missing")
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(data).To(BeNil())
+		})
+	})
+
+	When("keys are removed", func() {
+		It("Should forget them", func() {
+			Expect(cache.Set("first", 1)).To(Succeed())
+			Expect(cache.Set("second", 2)).To(Succeed())
+
+			Expect(cache.Remove("first", "second")).To(Succeed())
+
+			Expect(cache.Get("first")).To(BeNil())
+			Expect(cache.Get("second")).To(BeNil())
+		})
+	})
+
+	When("the cache is cleared", func() {
+		It("Should forget every key", func() {
+			Expect(cache.Set("first", 1)).To(Succeed())
+			Expect(cache.Set("second", 2)).To(Succeed())
+
+			cache.Clear()
+
+			Expect(cache.Get("first")).To(BeNil())
+			Expect(cache.Get("second")).To(BeNil())
+		})
+	})
+
+	Context("Bounding the cache", func() {
+		When("there are more keys than MaxEntries", func() {
+			It("Should evict the least recently used", func() {
+				cache = New(Config{MaxEntries: 2})
+				Expect(cache.Set("first", 1)).To(Succeed())
+				Expect(cache.Set("second", 2)).To(Succeed())
+				Expect(cache.Get("first")).ToNot(BeNil())
+
+				Expect(cache.Set("third", 3)).To(Succeed())
+
+				Expect(cache.Get("first")).ToNot(BeNil())
+				Expect(cache.Get("second")).To(BeNil())
+				Expect(cache.Get("third")).ToNot(BeNil())
+			})
+		})
+		When("the values take more than MaxBytes", func() {
+			It("Should evict the least recently used until they fit", func() {
+				cache = New(Config{MaxBytes: 10})
+				Expect(cache.Set("first", "abc")).To(Succeed())
+				Expect(cache.Set("second", "def")).To(Succeed())
+
+				Expect(cache.Set("third", "ghijkl")).To(Succeed())
+
+				Expect(cache.Get("first")).To(BeNil())
+				Expect(cache.Get("second")).To(BeNil())
+				Expect(cache.Get("third")).To(MatchJSON(`"ghijkl"`))
+			})
+		})
+		When("a value alone takes more than MaxBytes", func() {
+			It("Should not cache it", func() {
+				cache = New(Config{MaxBytes: 4})
+
+				Expect(cache.Set("key", "too long")).To(Succeed())
+
+				Expect(cache.Get("key")).To(BeNil())
+			})
+		})
+		When("a key is set again", func() {
+			It("Should count its new value only", func() {
+				cache = New(Config{MaxBytes: 10})
+				Expect(cache.Set("first", "abc")).To(Succeed())
+				Expect(cache.Set("first", "def")).To(Succeed())
+
+				Expect(cache.Set("second", "ghi")).To(Succeed())
+
+				Expect(cache.Get("first")).To(MatchJSON(`"def"`))
+			})
+		})
+	})
+
+	When("the TTL of a key elapses", func() {
+		It("Should expire it", func() {
+			now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+			cache = New(Config{TTL: time.Minute})
+			cache.(*memory).now = func() time.Time { return now }
+			Expect(cache.Set("key", 1)).To(Succeed())
+
+			now = now.Add(59 * time.Second)
+			Expect(cache.Get("key")).ToNot(BeNil())
+
+			now = now.Add(time.Second)
+			Expect(cache.Get("key")).To(BeNil())
+		})
+	})
+
+	When("a key is set with a TTL", func() {
+		It("Should expire it once the shorter of its TTL and the TTL of the cache elapses", func() {
+			now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+			cache = New(Config{TTL: time.Minute})
+			cache.(*memory).now = func() time.Time { return now }
+			Expect(cache.SetWithTTL("short", 1, 10*time.Second)).To(Succeed())
+			Expect(cache.SetWithTTL("long", 1, time.Hour)).To(Succeed())
+
+			now = now.Add(10 * time.Second)
+			Expect(cache.Get("short")).To(BeNil())
+			Expect(cache.Get("long")).ToNot(BeNil())
+
+			now = now.Add(50 * time.Second)
+			Expect(cache.Get("long")).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"app/internal/metric"
+)
+
+const (
+	NamespaceProperty = "cache"
+
+	HitNameProperty        = "hit_count"
+	HitDescriptionProperty = "Lookups that found the key cached"
+
+	MissNameProperty        = "miss_count"
+	MissDescriptionProperty = "Lookups that didn't find the key cached"
+
+	EvictionNameProperty        = "eviction_count"
+	EvictionDescriptionProperty = "Entries dropped by the cache, for its bounds or their expiration"
+
+	EntriesNameProperty        = "entries"
+	EntriesDescriptionProperty = "Number of cached entries"
+
+	BytesNameProperty        = "size_in_bytes"
+	BytesDescriptionProperty = "Size of the cached values in bytes"
+
+	FailureNameProperty        = "failure_count"
+	FailureDescriptionProperty = "Calls that failed to reach the cache"
+
+	DegradedNameProperty        = "degraded"
+	DegradedDescriptionProperty = "Whether the cache is bypassed after failing, 1 when degraded and 0 otherwise"
+
+	// EvictedForSize labels the entries evicted as the least recently used past the bounds of the cache
+	EvictedForSize = "size"
+	// EvictedForExpiration labels the entries dropped once their TTL elapsed
+	EvictedForExpiration = "expired"
+
+	cachePropertyKey  = "cache"
+	reasonPropertyKey = "reason"
+)
+
+type Metrics struct {
+	Hits      metric.CounterVec
+	Misses    metric.CounterVec
+	Evictions metric.CounterVec
+	Entries   metric.GaugeVec
+	Bytes     metric.GaugeVec
+	Failures  metric.CounterVec
+	Degraded  metric.GaugeVec
+}
+
+// Initialize returns the metrics of the caches, labeled with the name of the cache, like memory
+func Initialize() *Metrics {
+	return &Metrics{
+		Hits:      metric.NewCounter(counterMetricProperties(HitNameProperty, HitDescriptionProperty)),
+		Misses:    metric.NewCounter(counterMetricProperties(MissNameProperty, MissDescriptionProperty)),
+		Evictions: metric.NewCounter(evictionMetricProperties()),
+		Entries:   metric.NewGauge(gaugeMetricProperties(EntriesNameProperty, EntriesDescriptionProperty)),
+		Bytes:     metric.NewGauge(gaugeMetricProperties(BytesNameProperty, BytesDescriptionProperty)),
+		Failures:  metric.NewCounter(counterMetricProperties(FailureNameProperty, FailureDescriptionProperty)),
+		Degraded:  metric.NewGauge(gaugeMetricProperties(DegradedNameProperty, DegradedDescriptionProperty)),
+	}
+}
+
+func counterMetricProperties(name, description string) metric.Properties {
+	return metric.Properties{
+		Name:        name,
+		Namespace:   NamespaceProperty,
+		Description: description,
+		Type:        metric.CounterVecType,
+		Properties:  []string{cachePropertyKey},
+	}
+}
+
+func evictionMetricProperties() metric.Properties {
+	return metric.Properties{
+		Name:        EvictionNameProperty,
+		Namespace:   NamespaceProperty,
+		Description: EvictionDescriptionProperty,
+		Type:        metric.CounterVecType,
+		Properties:  []string{cachePropertyKey, reasonPropertyKey},
+	}
+}
+
+func gaugeMetricProperties(name, description string) metric.Properties {
+	return metric.Properties{
+		Name:        name,
+		Namespace:   NamespaceProperty,
+		Description: description,
+		Type:        metric.GaugeVecType,
+		Properties:  []string{cachePropertyKey},
+	}
+}
@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"app/infra/cache/tiered"
+	pubsubRedis "app/infra/pubsub/redis"
+)
+
+const failedToDecodeInvalidation = "failed to decode invalidation received on %s: %v\n"
+
+type invalidation struct {
+	channel pubsubRedis.Channel
+}
+
+// NewInvalidation returns a tiered.Transport relying on redis pub/sub, so every replica subscribed to channel
+// receives the invalidations published by the others
+func NewInvalidation(host, port, channel string) tiered.Transport {
+	return &invalidation{
+		channel: pubsubRedis.New(host, port, channel),
+	}
+}
+
+func (i *invalidation) Publish(ctx context.Context, inv tiered.Invalidation) error {
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return err
+	}
+	return i.channel.Publish(ctx, data)
+}
+
+func (i *invalidation) Receive(ctx context.Context, subscribed func(), deliver func(tiered.Invalidation)) error {
+	return i.channel.Receive(ctx, subscribed, func(data []byte) {
+		var inv tiered.Invalidation
+		if err := json.Unmarshal(data, &inv); err != nil {
+			log.Printf(failedToDecodeInvalidation, i.channel.Name(), err)
+			return
+		}
+		deliver(inv)
+	})
+}
@@ -1,8 +1,11 @@
 package redis
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	redigo "github.com/gomodule/redigo/redis"
 
@@ -13,66 +16,102 @@ const (
 	failedToConnectToRedisServer = "failed to connect to redis server: %v\n"
 	failedToSetKey               = "failed to set key %s, value %s: %v\n"
 	failedToGetKey               = "failed to get key %s: %v\n"
-	failedToRemoveKey            = "failed to remove key %s: %v\n"
+	failedToRemoveKey            = "failed to remove keys %v: %v\n"
 
 	deleteAction = "DEL"
 	getAction    = "GET"
-	setAction    = "GET"
+	setAction    = "SET"
+	// expireOption sets the TTL of SET in milliseconds
+	expireOption = "PX"
+
+	maxIdleConns = 8
+	// timeout bounds every round trip, so an unreachable server fails the lookups instead of hanging them
+	timeout = time.Second
 )
 
 type redis struct {
-	addr string
-	port string
-	conn redigo.Conn
+	pool *redigo.Pool
 }
 
+// New returns the storage.Cache of a redis server. The connections are made on demand, a server down failing the
+// calls until it's back rather than the process
 func New(host, port string) storage.Cache {
-	cache := &redis{
-		addr: host,
-		port: port,
+	return &redis{
+		pool: &redigo.Pool{
+			MaxIdle: maxIdleConns,
+			Dial: func() (redigo.Conn, error) {
+				conn, err := redigo.Dial("tcp", fmt.Sprintf("%s:%s", host, port),
+					redigo.DialConnectTimeout(timeout),
+					redigo.DialReadTimeout(timeout),
+					redigo.DialWriteTimeout(timeout),
+				)
+				if err != nil {
+					return nil, fmt.Errorf(failedToConnectToRedisServer, err)
+				}
+				return conn, nil
+			},
+		},
 	}
-	cache.connect()
+}
 
-	return cache
+func (r *redis) Set(key string, value interface{}) error {
+	return r.set(key, value)
 }
 
-func (r *redis) connect() {
-	if r.conn != nil {
-		return
-	}
+func (r *redis) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	return r.set(key, value, expireOption, ttl.Milliseconds())
+}
 
-	conn, err := redigo.Dial("tcp", r.getHost())
+func (r *redis) set(key string, value interface{}, options ...interface{}) error {
+	data, err := json.Marshal(value)
 	if err != nil {
-		log.Fatalf(failedToConnectToRedisServer, err)
+		log.Printf(failedToSetKey, key, value, err)
+		return err
 	}
 
-	r.conn = conn
-}
+	conn := r.pool.Get()
+	defer conn.Close()
 
-func (r *redis) Set(key string, value interface{}) error {
-	_, err := r.conn.Do(setAction, key, value)
+	_, err = conn.Do(setAction, append([]interface{}{key, data}, options...)...)
 	if err != nil {
 		log.Printf(failedToSetKey, key, value, err)
 	}
 	return err
 }
 
+// Get returns nil without an error when key isn't cached
 func (r *redis) Get(key string) ([]byte, error) {
-	data, err := redigo.Bytes(r.conn.Do(getAction, key))
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	data, err := redigo.Bytes(conn.Do(getAction, key))
+	if errors.Is(err, redigo.ErrNil) {
+		return nil, nil
+	}
 	if err != nil {
 		log.Printf(failedToGetKey, key, err)
 	}
 	return data, err
 }
 
-func (r *redis) Remove(key string) error {
-	_, err := r.conn.Do(deleteAction, key)
+// Remove deletes every given key in a single round trip
+func (r *redis) Remove(keys ...string) error {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do(deleteAction, args...)
 	if err != nil {
-		log.Printf(failedToRemoveKey, key, err)
+		log.Printf(failedToRemoveKey, keys, err)
 	}
 	return err
 }
 
-func (r *redis) getHost() string {
-	return fmt.Sprintf("%s:%s", r.addr, r.port)
+// Close closes the connections to the cache server
+func (r *redis) Close() error {
+	return r.pool.Close()
 }
@@ -0,0 +1,175 @@
+package tiered
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"app/infra/cache/memory"
+	"app/infra/cache/metrics"
+	"app/internal/storage"
+)
+
+const (
+	l1Name = "l1"
+	l2Name = "l2"
+
+	retryDelay = time.Second
+
+	failedToPublishInvalidation = "failed to publish the invalidation of %v: %v\n"
+	failedToReceiveInvalidation = "failed to receive cache invalidations: %v\n"
+)
+
+// Invalidation tells the other replicas to drop Keys from their L1, written or removed by the replica Origin
+type Invalidation struct {
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys"`
+}
+
+// Transport shares the invalidations between the replicas
+type Transport interface {
+	Publish(ctx context.Context, invalidation Invalidation) error
+	// Receive calls subscribed once listening, then deliver with every invalidation until ctx is done or the
+	// connection fails
+	Receive(ctx context.Context, subscribed func(), deliver func(Invalidation)) error
+}
+
+// Cache is a storage.Cache whose L1 is kept consistent with the other replicas while Run
+type Cache interface {
+	storage.Cache
+	Run(ctx context.Context)
+}
+
+type DependenciesNode struct {
+	// L2 is the cache shared by the replicas, like redis
+	L2        storage.Cache
+	Transport Transport
+}
+
+type Config struct {
+	// L1 bounds the in-process tier. Its TTL bounds how long an entry written by another replica can be read stale
+	// when an invalidation is lost
+	L1 memory.Config
+}
+
+type tiered struct {
+	deps    *DependenciesNode
+	l1      memory.Cache
+	metrics *metrics.Metrics
+	origin  string
+}
+
+// New returns a storage.Cache reading an in-process LRU first, then deps.L2. The keys written or removed are
+// invalidated in the L1 of the other replicas through deps.Transport
+func New(deps *DependenciesNode, config Config) Cache {
+	if config.L1.Name == "" {
+		config.L1.Name = l1Name
+	}
+	return &tiered{
+		deps:    deps,
+		l1:      memory.New(config.L1),
+		metrics: metrics.Initialize(),
+		origin:  uuid.NewV4().String(),
+	}
+}
+
+func (t *tiered) Set(key string, value interface{}) error {
+	return t.set(key, value, func(cache storage.Cache, data json.RawMessage) error {
+		return cache.Set(key, data)
+	})
+}
+
+func (t *tiered) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	return t.set(key, value, func(cache storage.Cache, data json.RawMessage) error {
+		return cache.SetWithTTL(key, data, ttl)
+	})
+}
+
+// set writes value to the L2 then to the L1 with write, invalidating key in the L1 of the other replicas
+func (t *tiered) set(key string, value interface{}, write func(cache storage.Cache, data json.RawMessage) error) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if err = write(t.deps.L2, data); err != nil {
+		return err
+	}
+	if err = write(t.l1, data); err != nil {
+		return err
+	}
+	t.invalidate(key)
+	return nil
+}
+
+// Get reads key from the L1, then from the L2, caching it in the L1 when found
+func (t *tiered) Get(key string) ([]byte, error) {
+	if data, _ := t.l1.Get(key); data != nil {
+		return data, nil
+	}
+
+	data, err := t.deps.L2.Get(key)
+	if err != nil || data == nil {
+		t.metrics.Misses.Increment(l2Name)
+		return data, err
+	}
+	t.metrics.Hits.Increment(l2Name)
+
+	if err = t.l1.Set(key, json.RawMessage(data)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Remove removes keys from the L1 then from the L2. The keys are invalidated in the L1 of the other replicas even
+// when the L2 fails, which may have removed some of them
+func (t *tiered) Remove(keys ...string) error {
+	defer t.invalidate(keys...)
+	if err := t.l1.Remove(keys...); err != nil {
+		return err
+	}
+	return t.deps.L2.Remove(keys...)
+}
+
+// Run drops the keys invalidated by the other replicas from the L1 until ctx is done. The L1 is cleared every time
+// the subscription starts, since the invalidations sent while it was down are lost
+func (t *tiered) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := t.deps.Transport.Receive(ctx, t.l1.Clear, t.deliver); err != nil && ctx.Err() == nil {
+			log.Printf(failedToReceiveInvalidation, err)
+			t.l1.Clear()
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryDelay):
+			}
+		}
+	}
+}
+
+// Close closes the L2, like the connection to the cache server
+func (t *tiered) Close() error {
+	if closer, ok := t.deps.L2.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (t *tiered) deliver(invalidation Invalidation) {
+	if invalidation.Origin == t.origin {
+		return
+	}
+	_ = t.l1.Remove(invalidation.Keys...)
+}
+
+// invalidate publishes keys to the other replicas. A failure is only logged, the write being done, the L1 TTL
+// bounding how long the others read the previous value
+func (t *tiered) invalidate(keys ...string) {
+	err := t.deps.Transport.Publish(context.Background(), Invalidation{Origin: t.origin, Keys: keys})
+	if err != nil {
+		log.Printf(failedToPublishInvalidation, keys, err)
+	}
+}
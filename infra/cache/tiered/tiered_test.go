@@ -0,0 +1,144 @@
+package tiered_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"app/infra/cache/memory"
+	"app/infra/cache/redis"
+	"app/infra/cache/tiered"
+	"app/internal/storage"
+	errorsAssertion "app/internal/test/assertion/errors"
+)
+
+func TestTiered(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tiered Cache Suits")
+}
+
+const channel = "cache:invalidation"
+
+// subscribedTransport signals on subscribed every time the transport it wraps starts listening
+type subscribedTransport struct {
+	tiered.Transport
+	subscribed chan struct{}
+}
+
+// failingRemoval is an L2 failing to remove keys
+type failingRemoval struct {
+	storage.Cache
+}
+
+func (c *failingRemoval) Remove(...string) error {
+	return errorsAssertion.ErrGeneric
+}
+
+func (t *subscribedTransport) Receive(ctx context.Context, subscribed func(), deliver func(tiered.Invalidation)) error {
+	return t.Transport.Receive(ctx, func() {
+		subscribed()
+		t.subscribed <- struct{}{}
+	}, deliver)
+}
+
+var _ = Describe("Tiered Cache", func() {
+	var server *miniredis.Miniredis
+
+	// replicaOn returns a tiered cache on l2 once it receives the invalidations, until the spec ends
+	replicaOn := func(l2 storage.Cache, config tiered.Config) tiered.Cache {
+		transport := &subscribedTransport{
+			Transport:  redis.NewInvalidation(server.Host(), server.Port(), channel),
+			subscribed: make(chan struct{}, 1),
+		}
+		cache := tiered.New(&tiered.DependenciesNode{
+			L2:        l2,
+			Transport: transport,
+		}, config)
+
+		ctx, stop := context.WithCancel(context.Background())
+		DeferCleanup(stop)
+		go cache.Run(ctx)
+		Eventually(transport.subscribed).Should(Receive())
+		return cache
+	}
+
+	// replica returns a tiered cache on the redis of server
+	replica := func(config tiered.Config) tiered.Cache {
+		return replicaOn(redis.New(server.Host(), server.Port()), config)
+	}
+
+	read := func(cache tiered.Cache, key string) func() []byte {
+		return func() []byte {
+			data, _ := cache.Get(key)
+			return data
+		}
+	}
+
+	BeforeEach(func() {
+		server = miniredis.RunT(GinkgoT())
+	})
+
+	When("a key is read", func() {
+		It("Should keep it in the L1", func() {
+			first, second := replica(tiered.Config{}), replica(tiered.Config{})
+			Expect(first.Set("key", "value")).To(Succeed())
+
+			Expect(second.Get("key")).To(MatchJSON(`"value"`))
+			server.Del("key")
+
+			Expect(second.Get("key")).To(MatchJSON(`"value"`))
+		})
+	})
+
+	When("another replica writes a key", func() {
+		It("Should drop it from the L1", func() {
+			first, second := replica(tiered.Config{}), replica(tiered.Config{})
+			Expect(first.Set("key", "old")).To(Succeed())
+			Expect(second.Get("key")).To(MatchJSON(`"old"`))
+
+			Expect(first.Set("key", "new")).To(Succeed())
+
+			Eventually(read(second, "key")).Should(MatchJSON(`"new"`))
+		})
+	})
+
+	When("another replica removes a key", func() {
+		It("Should drop it from the L1", func() {
+			first, second := replica(tiered.Config{}), replica(tiered.Config{})
+			Expect(first.Set("key", "value")).To(Succeed())
+			Expect(second.Get("key")).To(MatchJSON(`"value"`))
+
+			Expect(first.Remove("key")).To(Succeed())
+
+			Eventually(read(second, "key")).Should(BeNil())
+		})
+	})
+
+	When("another replica fails to remove a key from the L2", func() {
+		It("Should drop it from the L1 all the same", func() {
+			first := replicaOn(&failingRemoval{Cache: redis.New(server.Host(), server.Port())}, tiered.Config{})
+			second := replica(tiered.Config{})
+			Expect(first.Set("key", "value")).To(Succeed())
+			Expect(second.Get("key")).To(MatchJSON(`"value"`))
+			server.Del("key")
+
+			Expect(first.Remove("key")).To(MatchError(errorsAssertion.ErrGeneric))
+
+			Eventually(read(second, "key")).Should(BeNil())
+		})
+	})
+
+	When("the L1 TTL elapses", func() {
+		It("Should read the key from the L2 again", func() {
+			cache := replica(tiered.Config{L1: memory.Config{TTL: 50 * time.Millisecond}})
+			Expect(cache.Set("key", "value")).To(Succeed())
+			server.Del("key")
+
+			Eventually(read(cache, "key")).Should(BeNil())
+		})
+	})
+})
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	pubsubRedis "app/infra/pubsub/redis"
+	"app/internal/changefeed"
+)
+
+const (
+	failedToPublishEvent = "failed to publish event %s on %s: %v\n"
+	failedToDecodeEvent  = "failed to decode event received on %s: %v\n"
+)
+
+type transport struct {
+	channel pubsubRedis.Channel
+}
+
+// New returns a changefeed.Transport relying on redis pub/sub, so every replica subscribed to channel
+// receives the events published by the others
+func New(host, port, channel string) changefeed.Transport {
+	return &transport{
+		channel: pubsubRedis.New(host, port, channel),
+	}
+}
+
+func (t *transport) Send(ctx context.Context, event changefeed.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if err = t.channel.Publish(ctx, data); err != nil {
+		log.Printf(failedToPublishEvent, event.ID, t.channel.Name(), err)
+	}
+	return err
+}
+
+// Receive delivers the events published on the channel until ctx is done or the connection fails
+func (t *transport) Receive(ctx context.Context, deliver func(changefeed.Event)) error {
+	return t.channel.Receive(ctx, func() {}, func(data []byte) {
+		var event changefeed.Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Printf(failedToDecodeEvent, t.channel.Name(), err)
+			return
+		}
+		deliver(event)
+	})
+}
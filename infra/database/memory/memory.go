@@ -0,0 +1,511 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"app/internal/auth"
+	"app/internal/batch"
+	"app/internal/entity"
+	appErrors "app/internal/errors"
+	"app/internal/storage"
+)
+
+const (
+	unknownColumnErr  = "unknown column %s of %s"
+	unsupportedObjErr = "unsupported object %T"
+)
+
+// ErrRawUnsupported is returned by Raw, the memory database having no SQL engine
+var ErrRawUnsupported = errors.New("raw queries aren't supported by the memory database")
+
+// txKey marks the ctx given to a Transaction callback, holding the database it's a transaction of
+type txKey struct{}
+
+type memory struct {
+	// mu is held for writing by the transactions for their whole duration
+	mu      sync.RWMutex
+	tables  map[string]table
+	schemas *sync.Map
+	now     func() time.Time
+}
+
+// table maps the primary key of the rows to pointers to them. A row is never changed in place but replaced by an
+// updated copy, so a copy of the map is a snapshot of the table
+type table map[interface{}]reflect.Value
+
+// New returns a storage.Database keeping the rows in the memory of the process, for the tests and the local
+// development. It follows the behavior of the postgres database: the rows of the entities with a DeletedAt are soft
+// deleted, hidden until restored or purged, and the audit metadata of entity.Auditable rows is stamped. Rows are
+// shallow copies of the objects given, Raw queries aren't supported
+func New() storage.Database {
+	return &memory{
+		tables:  map[string]table{},
+		schemas: &sync.Map{},
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (m *memory) Create(ctx context.Context, obj interface{}) error {
+	s, row, err := m.parseRow(obj)
+	if err != nil {
+		return err
+	}
+	defer m.lock(ctx)()
+
+	m.stampCreated(ctx, obj)
+	return m.insert(ctx, s, row)
+}
+
+// Update overwrites the columns of the row id with the non-zero fields of obj, like a gorm Updates, the immutable
+// columns of an entity.Auditable being kept
+func (m *memory) Update(ctx context.Context, id uuid.UUID, obj interface{}) error {
+	s, row, err := m.parseRow(obj)
+	if err != nil {
+		return err
+	}
+	defer m.lock(ctx)()
+
+	current, ok := m.live(ctx, s, id)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	auditable := m.stampUpdated(ctx, obj)
+
+	updated := copyRow(current)
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.PrimaryKey || auditable && isImmutable(field.DBName) {
+			continue
+		}
+		value := field.ReflectValueOf(ctx, row)
+		if !value.IsZero() {
+			field.ReflectValueOf(ctx, updated).Set(value)
+		}
+	}
+	if auditable {
+		incrementVersion(ctx, s, updated)
+	}
+	m.tables[s.Table][id] = updated
+	return nil
+}
+
+func (m *memory) Set(ctx context.Context, obj interface{}, field string, value interface{}) error {
+	return m.SetColumns(ctx, obj, map[string]interface{}{field: value})
+}
+
+// SetColumns sets the columns of the row of obj, the modification metadata of an entity.Auditable included
+func (m *memory) SetColumns(ctx context.Context, obj interface{}, columns map[string]interface{}) error {
+	s, row, err := m.parseRow(obj)
+	if err != nil {
+		return err
+	}
+	id, zero := s.PrioritizedPrimaryField.ValueOf(ctx, row)
+	if zero {
+		return gorm.ErrMissingWhereClause
+	}
+	defer m.lock(ctx)()
+
+	current, ok := m.live(ctx, s, id)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if _, ok := obj.(entity.Auditable); ok {
+		columns = m.withAuditColumns(ctx, columns)
+	}
+
+	updated := copyRow(current)
+	if err = setColumns(ctx, s, updated, columns); err != nil {
+		return err
+	}
+	m.tables[s.Table][id] = updated
+	return nil
+}
+
+// Select loads every row into a pointer to a slice, or the first row matching the non-zero fields of a pointer to
+// a struct, like the ID set by the repository, failing with gorm.ErrRecordNotFound when none does. The rows are in
+// the order of their primary key, soft deleted rows being left out
+func (m *memory) Select(ctx context.Context, obj interface{}) error {
+	s, err := m.parse(obj)
+	if err != nil {
+		return err
+	}
+	if reflect.ValueOf(obj).Kind() != reflect.Ptr {
+		return fmt.Errorf(unsupportedObjErr, obj)
+	}
+	defer m.rlock(ctx)()
+
+	rows := m.liveRows(ctx, s)
+	dest := reflect.ValueOf(obj).Elem()
+	if dest.Kind() == reflect.Slice {
+		fill(dest, rows)
+		return nil
+	}
+
+	for _, row := range rows {
+		if matches(ctx, s, row, dest.Addr()) {
+			dest.Set(row.Elem())
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *memory) Raw(context.Context, string, interface{}) error {
+	return ErrRawUnsupported
+}
+
+// Stream scans the rows of obj's table one at a time into obj, calling each after every row. The rows are those of
+// the table when Stream is called, each being free to use the database
+func (m *memory) Stream(ctx context.Context, obj interface{}, each func() error) error {
+	s, _, err := m.parseRow(obj)
+	if err != nil {
+		return err
+	}
+	unlock := m.rlock(ctx)
+	rows := m.liveRows(ctx, s)
+	unlock()
+
+	dest := reflect.ValueOf(obj).Elem()
+	for _, row := range rows {
+		dest.Set(row.Elem())
+		if err = each(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete soft deletes the row id when its entity has a DeletedAt, removing it otherwise
+func (m *memory) Delete(ctx context.Context, id uuid.UUID, obj interface{}) error {
+	s, err := m.parse(obj)
+	if err != nil {
+		return err
+	}
+	defer m.lock(ctx)()
+
+	return m.delete(ctx, s, id)
+}
+
+// Restore undeletes the soft deleted row id, failing with gorm.ErrRecordNotFound when there's none
+func (m *memory) Restore(ctx context.Context, id uuid.UUID, obj interface{}) error {
+	s, err := m.parse(obj)
+	if err != nil {
+		return err
+	}
+	defer m.lock(ctx)()
+
+	current, ok := m.tables[s.Table][id]
+	if !ok || !isDeleted(ctx, s, current) {
+		return gorm.ErrRecordNotFound
+	}
+
+	columns := m.withAuditColumns(ctx, map[string]interface{}{
+		entity.DeletedAtColumn: nil,
+	})
+	restored := copyRow(current)
+	if err = setColumns(ctx, s, restored, columns); err != nil {
+		return err
+	}
+	m.tables[s.Table][id] = restored
+	return nil
+}
+
+// Purge removes the row id, deleted or not
+func (m *memory) Purge(ctx context.Context, id uuid.UUID, obj interface{}) error {
+	s, err := m.parse(obj)
+	if err != nil {
+		return err
+	}
+	defer m.lock(ctx)()
+
+	if _, ok := m.tables[s.Table][id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(m.tables[s.Table], id)
+	return nil
+}
+
+// PurgeDeleted removes the rows soft deleted before deletedBefore
+func (m *memory) PurgeDeleted(ctx context.Context, obj interface{}, deletedBefore time.Time) error {
+	s, err := m.parse(obj)
+	if err != nil {
+		return err
+	}
+	defer m.lock(ctx)()
+
+	for id, row := range m.tables[s.Table] {
+		deletedAt, ok := deletedAtOf(ctx, s, row)
+		if ok && deletedAt.Valid && deletedAt.Time.Before(deletedBefore) {
+			delete(m.tables[s.Table], id)
+		}
+	}
+	return nil
+}
+
+// CreateBatch inserts the objects of a slice. In atomic mode nothing is inserted unless every object is
+func (m *memory) CreateBatch(ctx context.Context, objs interface{}, mode batch.Mode) error {
+	return m.execBatch(ctx, objs, mode, func(ctx context.Context, s *schema.Schema, obj interface{}) error {
+		m.stampCreated(ctx, obj)
+		return m.insert(ctx, s, reflect.ValueOf(obj))
+	})
+}
+
+// UpsertBatch inserts the objects of a slice, overwriting the mutable columns of the rows that already exist and
+// incrementing their version, their creation metadata being kept. A soft deleted row fails with
+// errors.ErrItemDeleted
+func (m *memory) UpsertBatch(ctx context.Context, objs interface{}, mode batch.Mode) error {
+	return m.execBatch(ctx, objs, mode, func(ctx context.Context, s *schema.Schema, obj interface{}) error {
+		m.stampCreated(ctx, obj)
+		row := reflect.ValueOf(obj)
+		id, zero := s.PrioritizedPrimaryField.ValueOf(ctx, row)
+		current, ok := m.tables[s.Table][id]
+		if zero || !ok {
+			return m.insert(ctx, s, row)
+		}
+		if isDeleted(ctx, s, current) {
+			return appErrors.ErrItemDeleted
+		}
+
+		updated := copyRow(current)
+		for _, field := range s.Fields {
+			if field.DBName != "" && !isImmutable(field.DBName) {
+				field.ReflectValueOf(ctx, updated).Set(field.ReflectValueOf(ctx, row))
+			}
+		}
+		incrementVersion(ctx, s, updated)
+		m.tables[s.Table][id] = updated
+		return nil
+	})
+}
+
+// DeleteBatch deletes the rows ids like Delete. In atomic mode nothing is deleted unless every row exists
+func (m *memory) DeleteBatch(ctx context.Context, ids []uuid.UUID, obj interface{}, mode batch.Mode) error {
+	s, err := m.parse(obj)
+	if err != nil {
+		return err
+	}
+	defer m.lock(ctx)()
+
+	if mode != batch.BestEffort {
+		for _, id := range ids {
+			if _, ok := m.live(ctx, s, id); !ok {
+				return gorm.ErrRecordNotFound
+			}
+		}
+	}
+
+	batchErr := batch.NewError()
+	for i, id := range ids {
+		if err = m.delete(ctx, s, id); err != nil {
+			batchErr.Add(i, err)
+		}
+	}
+	return batchErr.Err()
+}
+
+// Transaction runs fn holding the database, so the other calls wait for it to be done, and restores the tables as
+// they were before fn when it fails
+func (m *memory) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.inTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := m.snapshot()
+	if err := fn(context.WithValue(ctx, txKey{}, m)); err != nil {
+		m.tables = snapshot
+		return err
+	}
+	return nil
+}
+
+// execBatch runs fn with every object of the slice objs. In atomic mode the tables are restored when one fails,
+// the failures being reported by index in best-effort mode
+func (m *memory) execBatch(ctx context.Context, objs interface{}, mode batch.Mode,
+	fn func(ctx context.Context, s *schema.Schema, obj interface{}) error) error {
+	s, err := m.parse(objs)
+	if err != nil {
+		return err
+	}
+	defer m.lock(ctx)()
+
+	var snapshot map[string]table
+	if mode != batch.BestEffort {
+		snapshot = m.snapshot()
+	}
+
+	batchErr := batch.NewError()
+	value := reflect.Indirect(reflect.ValueOf(objs))
+	for i := 0; i < value.Len(); i++ {
+		elem := value.Index(i)
+		if elem.Kind() != reflect.Ptr {
+			elem = elem.Addr()
+		}
+		err = fn(ctx, s, elem.Interface())
+		if err != nil && snapshot != nil {
+			m.tables = snapshot
+			return err
+		}
+		if err != nil {
+			batchErr.Add(i, err)
+		}
+	}
+	return batchErr.Err()
+}
+
+func (m *memory) insert(ctx context.Context, s *schema.Schema, row reflect.Value) error {
+	id, zero := s.PrioritizedPrimaryField.ValueOf(ctx, row)
+	if zero {
+		return gorm.ErrPrimaryKeyRequired
+	}
+	rows := m.table(s)
+	if _, ok := rows[id]; ok {
+		return appErrors.ErrDuplicateKey
+	}
+	rows[id] = copyRow(row)
+	return nil
+}
+
+func (m *memory) delete(ctx context.Context, s *schema.Schema, id interface{}) error {
+	current, ok := m.live(ctx, s, id)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	field, ok := deletedAtField(s)
+	if !ok {
+		delete(m.tables[s.Table], id)
+		return nil
+	}
+
+	deleted := copyRow(current)
+	field.ReflectValueOf(ctx, deleted).Set(reflect.ValueOf(gorm.DeletedAt{Time: m.now(), Valid: true}))
+	m.tables[s.Table][id] = deleted
+	return nil
+}
+
+// live returns the row id unless it's soft deleted
+func (m *memory) live(ctx context.Context, s *schema.Schema, id interface{}) (reflect.Value, bool) {
+	row, ok := m.tables[s.Table][id]
+	if !ok || isDeleted(ctx, s, row) {
+		return reflect.Value{}, false
+	}
+	return row, true
+}
+
+// liveRows returns the rows of the table of s which aren't soft deleted, in the order of their primary key
+func (m *memory) liveRows(ctx context.Context, s *schema.Schema) []reflect.Value {
+	rows := make([]reflect.Value, 0, len(m.tables[s.Table]))
+	for _, row := range m.tables[s.Table] {
+		if !isDeleted(ctx, s, row) {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return primaryKey(ctx, s, rows[i]) < primaryKey(ctx, s, rows[j])
+	})
+	return rows
+}
+
+func (m *memory) table(s *schema.Schema) table {
+	rows, ok := m.tables[s.Table]
+	if !ok {
+		rows = table{}
+		m.tables[s.Table] = rows
+	}
+	return rows
+}
+
+func (m *memory) snapshot() map[string]table {
+	snapshot := make(map[string]table, len(m.tables))
+	for name, rows := range m.tables {
+		copied := make(table, len(rows))
+		for id, row := range rows {
+			copied[id] = row
+		}
+		snapshot[name] = copied
+	}
+	return snapshot
+}
+
+// withAuditColumns returns a copy of columns including the modification metadata of a column level update
+func (m *memory) withAuditColumns(ctx context.Context, columns map[string]interface{}) map[string]interface{} {
+	audited := map[string]interface{}{
+		entity.UpdatedAtColumn: m.now(),
+		entity.UpdatedByColumn: auth.Subject(ctx),
+		entity.VersionColumn:   increment{},
+	}
+	for column, value := range columns {
+		audited[column] = value
+	}
+	return audited
+}
+
+// lock holds the database for writing unless ctx is in one of its transactions, returning the function releasing it
+func (m *memory) lock(ctx context.Context) func() {
+	if m.inTransaction(ctx) {
+		return func() {}
+	}
+	m.mu.Lock()
+	return m.mu.Unlock
+}
+
+// rlock holds the database for reading unless ctx is in one of its transactions
+func (m *memory) rlock(ctx context.Context) func() {
+	if m.inTransaction(ctx) {
+		return func() {}
+	}
+	m.mu.RLock()
+	return m.mu.RUnlock
+}
+
+func (m *memory) inTransaction(ctx context.Context) bool {
+	tx, ok := ctx.Value(txKey{}).(*memory)
+	return ok && tx == m
+}
+
+// parse parses the schema of obj, a struct, a slice or a pointer to them, like the value of the entity given to
+// Delete or the slice of a batch
+func (m *memory) parse(obj interface{}) (*schema.Schema, error) {
+	value := reflect.ValueOf(obj)
+	switch value.Kind() {
+	case reflect.Struct, reflect.Slice:
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil, fmt.Errorf(unsupportedObjErr, obj)
+		}
+	default:
+		return nil, fmt.Errorf(unsupportedObjErr, obj)
+	}
+	s, err := schema.Parse(obj, m.schemas, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+	if s.PrioritizedPrimaryField == nil {
+		return nil, gorm.ErrPrimaryKeyRequired
+	}
+	return s, nil
+}
+
+// parseRow parses a pointer to a struct, returned as the row it's stored as
+func (m *memory) parseRow(obj interface{}) (*schema.Schema, reflect.Value, error) {
+	s, err := m.parse(obj)
+	if err != nil {
+		return nil, reflect.Value{}, err
+	}
+	row := reflect.ValueOf(obj)
+	if row.Elem().Kind() != reflect.Struct {
+		return nil, reflect.Value{}, fmt.Errorf(unsupportedObjErr, obj)
+	}
+	return s, row, nil
+}
@@ -0,0 +1,349 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+
+	"app/internal/auth"
+	"app/internal/batch"
+	"app/internal/errors"
+	assertion "app/internal/test/assertion/crud"
+	errorsAssertion "app/internal/test/assertion/errors"
+)
+
+func TestMemory(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Memory Database Suits")
+}
+
+func newItem(id string, name string) *assertion.Item {
+	item := assertion.NewItemWithID(id)
+	item.Name = name
+	return item
+}
+
+var _ = Describe("Memory Database", func() {
+	const (
+		firstID  = "2a2acd06-c4ce-4bce-aaf9-09a379f02cf8"
+		secondID = "481da253-2dda-46e5-9963-58611eb72d7b"
+		missing  = "667f4eda-6825-445c-bf45-289f3b64b02b"
+	)
+
+	var (
+		ctx      context.Context
+		database *memory
+		now      time.Time
+	)
+
+	get := func(id string) (*assertion.Item, error) {
+		item := &assertion.Item{}
+		item.SetID(uuid.FromStringOrNil(id))
+		return item, database.Select(ctx, item)
+	}
+
+	BeforeEach(func() {
+		ctx = auth.WithPrincipal(context.Background(), auth.Principal{Subject: "john"})
+		database = New().(*memory)
+		now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		database.now = func() time.Time { return now }
+
+		Expect(database.Create(ctx, newItem(secondID, "second"))).To(Succeed())
+		Expect(database.Create(ctx, newItem(firstID, "first"))).To(Succeed())
+	})
+
+	Context("Creating items", func() {
+		When("The item is new", func() {
+			It("Should store it with its audit metadata", func() {
+				item, err := get(firstID)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(item.Name).To(Equal("first"))
+				Expect(item.CreatedBy).To(Equal("john"))
+				Expect(item.CreatedAt).To(Equal(now))
+				Expect(item.Version).To(Equal(int64(1)))
+			})
+		})
+		When("The id is taken", func() {
+			It("Should fail", func() {
+				err := database.Create(ctx, newItem(firstID, "other"))
+
+				Expect(err).To(MatchError(errors.ErrDuplicateKey))
+			})
+		})
+		When("The item is changed after being stored", func() {
+			It("Should keep the stored copy", func() {
+				item := newItem(missing, "third")
+				Expect(database.Create(ctx, item)).To(Succeed())
+				item.Name = "changed"
+
+				stored, err := get(missing)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(stored.Name).To(Equal("third"))
+			})
+		})
+	})
+
+	Context("Selecting items", func() {
+		When("Selecting every item", func() {
+			It("Should return them in the order of their id", func() {
+				var items []*assertion.Item
+
+				Expect(database.Select(ctx, &items)).To(Succeed())
+
+				Expect(items).To(HaveLen(2))
+				Expect(items[0].Name).To(Equal("first"))
+				Expect(items[1].Name).To(Equal("second"))
+			})
+		})
+		When("Filtering on the fields of the item", func() {
+			It("Should return the first item matching them", func() {
+				item := &assertion.Item{Name: "second"}
+
+				Expect(database.Select(ctx, item)).To(Succeed())
+
+				Expect(item.GetID()).To(Equal(uuid.FromStringOrNil(secondID)))
+			})
+		})
+		When("No item matches", func() {
+			It("Should fail with not found", func() {
+				_, err := get(missing)
+
+				Expect(err).To(MatchError(gorm.ErrRecordNotFound))
+			})
+		})
+		When("Streaming the items", func() {
+			It("Should scan them one at a time", func() {
+				item := &assertion.Item{}
+				var names []string
+
+				err := database.Stream(ctx, item, func() error {
+					names = append(names, item.Name)
+					return nil
+				})
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(names).To(Equal([]string{"first", "second"}))
+			})
+		})
+		When("Running a raw query", func() {
+			It("Should fail", func() {
+				var count int
+
+				Expect(database.Raw(ctx, "SELECT 1", &count)).To(MatchError(ErrRawUnsupported))
+			})
+		})
+	})
+
+	Context("Updating items", func() {
+		When("The item exists", func() {
+			It("Should update its fields, keeping the immutable ones", func() {
+				now = now.Add(time.Hour)
+				update := &assertion.Item{Description: "updated"}
+				update.CreatedBy = "forged"
+
+				Expect(database.Update(ctx, uuid.FromStringOrNil(firstID), update)).To(Succeed())
+
+				item, err := get(firstID)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(item.Name).To(Equal("first"))
+				Expect(item.Description).To(Equal("updated"))
+				Expect(item.CreatedBy).To(Equal("john"))
+				Expect(item.UpdatedAt).To(Equal(now))
+				Expect(item.Version).To(Equal(int64(2)))
+			})
+		})
+		When("The item doesn't exist", func() {
+			It("Should fail with not found", func() {
+				err := database.Update(ctx, uuid.FromStringOrNil(missing), &assertion.Item{Name: "x"})
+
+				Expect(err).To(MatchError(gorm.ErrRecordNotFound))
+			})
+		})
+		When("Setting columns", func() {
+			It("Should set them and the modification metadata", func() {
+				item := newItem(firstID, "")
+
+				Expect(database.SetColumns(ctx, item, map[string]interface{}{"name": "renamed"})).To(Succeed())
+
+				stored, err := get(firstID)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(stored.Name).To(Equal("renamed"))
+				Expect(stored.Version).To(Equal(int64(2)))
+			})
+		})
+		When("Setting an unknown column", func() {
+			It("Should fail", func() {
+				err := database.Set(ctx, newItem(firstID, ""), "unknown", 1)
+
+				Expect(err).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("Deleting items", func() {
+		When("The item is deleted", func() {
+			It("Should hide it until restored", func() {
+				id := uuid.FromStringOrNil(firstID)
+				Expect(database.Delete(ctx, id, assertion.Item{})).To(Succeed())
+
+				_, err := get(firstID)
+				Expect(err).To(MatchError(gorm.ErrRecordNotFound))
+
+				Expect(database.Restore(ctx, id, &assertion.Item{})).To(Succeed())
+				item, err := get(firstID)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(item.DeletedAt.Valid).To(BeFalse())
+				Expect(item.Version).To(Equal(int64(2)))
+			})
+		})
+		When("The item doesn't exist", func() {
+			It("Should fail with not found", func() {
+				err := database.Delete(ctx, uuid.FromStringOrNil(missing), &assertion.Item{})
+
+				Expect(err).To(MatchError(gorm.ErrRecordNotFound))
+			})
+		})
+		When("Restoring an item that isn't deleted", func() {
+			It("Should fail with not found", func() {
+				err := database.Restore(ctx, uuid.FromStringOrNil(firstID), &assertion.Item{})
+
+				Expect(err).To(MatchError(gorm.ErrRecordNotFound))
+			})
+		})
+		When("Purging an item", func() {
+			It("Should remove it for good", func() {
+				id := uuid.FromStringOrNil(firstID)
+				Expect(database.Delete(ctx, id, &assertion.Item{})).To(Succeed())
+
+				Expect(database.Purge(ctx, id, &assertion.Item{})).To(Succeed())
+
+				Expect(database.Restore(ctx, id, &assertion.Item{})).To(MatchError(gorm.ErrRecordNotFound))
+			})
+		})
+		When("Purging the items deleted long ago", func() {
+			It("Should keep the items deleted since", func() {
+				Expect(database.Delete(ctx, uuid.FromStringOrNil(firstID), &assertion.Item{})).To(Succeed())
+				now = now.Add(48 * time.Hour)
+				Expect(database.Delete(ctx, uuid.FromStringOrNil(secondID), &assertion.Item{})).To(Succeed())
+
+				Expect(database.PurgeDeleted(ctx, &assertion.Item{}, now.Add(-24*time.Hour))).To(Succeed())
+
+				Expect(database.Restore(ctx, uuid.FromStringOrNil(firstID), &assertion.Item{})).
+					To(MatchError(gorm.ErrRecordNotFound))
+				Expect(database.Restore(ctx, uuid.FromStringOrNil(secondID), &assertion.Item{})).To(Succeed())
+			})
+		})
+	})
+
+	Context("Running batches", func() {
+		When("An item of an atomic batch fails", func() {
+			It("Should store none of them", func() {
+				items := []*assertion.Item{newItem(missing, "third"), newItem(firstID, "duplicate")}
+
+				err := database.CreateBatch(ctx, items, batch.Atomic)
+
+				Expect(err).To(MatchError(errors.ErrDuplicateKey))
+				_, err = get(missing)
+				Expect(err).To(MatchError(gorm.ErrRecordNotFound))
+			})
+		})
+		When("An item of a best-effort batch fails", func() {
+			It("Should store the others and report it", func() {
+				items := []*assertion.Item{newItem(missing, "third"), newItem(firstID, "duplicate")}
+
+				err := database.CreateBatch(ctx, items, batch.BestEffort)
+
+				Expect(err).To(BeAssignableToTypeOf(&batch.Error{}))
+				Expect(err.(*batch.Error).Errors).To(HaveKey(1))
+				_, err = get(missing)
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+		When("Upserting existing items", func() {
+			It("Should overwrite them, keeping their creation metadata", func() {
+				items := []*assertion.Item{newItem(firstID, "upserted"), newItem(missing, "third")}
+
+				Expect(database.UpsertBatch(ctx, items, batch.Atomic)).To(Succeed())
+
+				item, err := get(firstID)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(item.Name).To(Equal("upserted"))
+				Expect(item.Version).To(Equal(int64(2)))
+				_, err = get(missing)
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+		When("Upserting a soft deleted item", func() {
+			It("Should report it without overwriting or restoring it", func() {
+				Expect(database.Delete(ctx, uuid.FromStringOrNil(firstID), &assertion.Item{})).To(Succeed())
+				items := []*assertion.Item{newItem(missing, "third"), newItem(firstID, "upserted")}
+
+				err := database.UpsertBatch(ctx, items, batch.BestEffort)
+
+				Expect(err).To(BeAssignableToTypeOf(&batch.Error{}))
+				Expect(err.(*batch.Error).Errors).To(HaveKeyWithValue(1, MatchError(errors.ErrItemDeleted)))
+				_, err = get(firstID)
+				Expect(err).To(MatchError(gorm.ErrRecordNotFound))
+				_, err = get(missing)
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+		When("An item of an atomic delete doesn't exist", func() {
+			It("Should delete none of them", func() {
+				ids := []uuid.UUID{uuid.FromStringOrNil(firstID), uuid.FromStringOrNil(missing)}
+
+				err := database.DeleteBatch(ctx, ids, &assertion.Item{}, batch.Atomic)
+
+				Expect(err).To(MatchError(gorm.ErrRecordNotFound))
+				_, err = get(firstID)
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+	})
+
+	Context("Running transactions", func() {
+		When("The transaction fails", func() {
+			It("Should roll back its changes", func() {
+				err := database.Transaction(ctx, func(ctx context.Context) error {
+					Expect(database.Create(ctx, newItem(missing, "third"))).To(Succeed())
+					return database.Transaction(ctx, func(ctx context.Context) error {
+						Expect(database.Delete(ctx, uuid.FromStringOrNil(firstID), &assertion.Item{})).To(Succeed())
+						return errorsAssertion.ErrGeneric
+					})
+				})
+
+				Expect(err).To(MatchError(errorsAssertion.ErrGeneric))
+				_, err = get(missing)
+				Expect(err).To(MatchError(gorm.ErrRecordNotFound))
+				_, err = get(firstID)
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+		When("Items are created concurrently", func() {
+			It("Should store every one of them", func() {
+				var wg sync.WaitGroup
+				for i := 0; i < 20; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						defer GinkgoRecover()
+						Expect(database.Create(ctx, newItem(uuid.NewV4().String(), "concurrent"))).To(Succeed())
+					}()
+				}
+				wg.Wait()
+
+				var items []assertion.Item
+				Expect(database.Select(ctx, &items)).To(Succeed())
+				Expect(items).To(HaveLen(22))
+			})
+		})
+	})
+})
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"app/internal/auth"
+	"app/internal/entity"
+)
+
+// increment is the value of a column incremented by a column level update, like the version
+type increment struct{}
+
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// stampCreated fills the audit metadata of a new entity using the principal stored in ctx
+func (m *memory) stampCreated(ctx context.Context, obj interface{}) {
+	if auditable, ok := obj.(entity.Auditable); ok {
+		auditable.Created(auth.Subject(ctx), m.now())
+	}
+}
+
+// stampUpdated fills the modification metadata of an entity using the principal stored in ctx
+func (m *memory) stampUpdated(ctx context.Context, obj interface{}) bool {
+	auditable, ok := obj.(entity.Auditable)
+	if ok {
+		auditable.Updated(auth.Subject(ctx), m.now())
+	}
+	return ok
+}
+
+func incrementVersion(ctx context.Context, s *schema.Schema, row reflect.Value) {
+	if field := s.LookUpField(entity.VersionColumn); field != nil {
+		version := field.ReflectValueOf(ctx, row)
+		version.SetInt(version.Int() + 1)
+	}
+}
+
+// setColumns sets the columns of row, named after their db names
+func setColumns(ctx context.Context, s *schema.Schema, row reflect.Value, columns map[string]interface{}) error {
+	for column, value := range columns {
+		field, ok := s.FieldsByDBName[column]
+		if !ok {
+			return fmt.Errorf(unknownColumnErr, column, s.Table)
+		}
+		switch value.(type) {
+		case increment:
+			version := field.ReflectValueOf(ctx, row)
+			version.SetInt(version.Int() + 1)
+		case nil:
+			target := field.ReflectValueOf(ctx, row)
+			target.Set(reflect.Zero(target.Type()))
+		default:
+			if err := field.Set(ctx, row, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// matches tells whether row holds the non-zero fields of example
+func matches(ctx context.Context, s *schema.Schema, row, example reflect.Value) bool {
+	for _, field := range s.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		value, zero := field.ValueOf(ctx, example)
+		if zero {
+			continue
+		}
+		current, _ := field.ValueOf(ctx, row)
+		if !reflect.DeepEqual(current, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// fill sets dest, a slice of structs or of pointers to structs, to copies of rows
+func fill(dest reflect.Value, rows []reflect.Value) {
+	elemType := dest.Type().Elem()
+	filled := reflect.MakeSlice(dest.Type(), 0, len(rows))
+	for _, row := range rows {
+		copied := copyRow(row)
+		if elemType.Kind() != reflect.Ptr {
+			copied = copied.Elem()
+		}
+		filled = reflect.Append(filled, copied)
+	}
+	dest.Set(filled)
+}
+
+// copyRow returns a pointer to a shallow copy of the struct row points to
+func copyRow(row reflect.Value) reflect.Value {
+	copied := reflect.New(row.Elem().Type())
+	copied.Elem().Set(row.Elem())
+	return copied
+}
+
+func deletedAtField(s *schema.Schema) (*schema.Field, bool) {
+	field := s.LookUpField(entity.DeletedAtColumn)
+	if field == nil || field.FieldType != deletedAtType {
+		return nil, false
+	}
+	return field, true
+}
+
+func deletedAtOf(ctx context.Context, s *schema.Schema, row reflect.Value) (gorm.DeletedAt, bool) {
+	field, ok := deletedAtField(s)
+	if !ok {
+		return gorm.DeletedAt{}, false
+	}
+	return field.ReflectValueOf(ctx, row).Interface().(gorm.DeletedAt), true
+}
+
+func isDeleted(ctx context.Context, s *schema.Schema, row reflect.Value) bool {
+	deletedAt, ok := deletedAtOf(ctx, s, row)
+	return ok && deletedAt.Valid
+}
+
+// primaryKey returns the primary key of row as a string, the rows being ordered by it
+func primaryKey(ctx context.Context, s *schema.Schema, row reflect.Value) string {
+	value, _ := s.PrioritizedPrimaryField.ValueOf(ctx, row)
+	return fmt.Sprint(value)
+}
+
+func isImmutable(column string) bool {
+	for _, immutable := range entity.ImmutableColumns {
+		if column == immutable {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"app/internal/auth"
+	"app/internal/entity"
+)
+
+// stampCreated fills the audit metadata of a new entity using the principal stored in ctx
+func stampCreated(ctx context.Context, obj interface{}) {
+	if auditable, ok := obj.(entity.Auditable); ok {
+		auditable.Created(auth.Subject(ctx), time.Now().UTC())
+	}
+}
+
+// stampUpdated fills the modification metadata of an entity using the principal stored in ctx
+func stampUpdated(ctx context.Context, obj interface{}) bool {
+	auditable, ok := obj.(entity.Auditable)
+	if ok {
+		auditable.Updated(auth.Subject(ctx), time.Now().UTC())
+	}
+	return ok
+}
+
+// withAuditColumns returns a copy of columns including the modification metadata of a column level update
+func withAuditColumns(ctx context.Context, columns map[string]interface{}) map[string]interface{} {
+	audited := map[string]interface{}{
+		entity.UpdatedAtColumn: time.Now().UTC(),
+		entity.UpdatedByColumn: auth.Subject(ctx),
+		entity.VersionColumn:   incrementVersion(),
+	}
+	for column, value := range columns {
+		audited[column] = value
+	}
+	return audited
+}
+
+func incrementVersion() clause.Expr {
+	return gorm.Expr(versionIncrementQuery)
+}
@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+
+	"app/internal/batch"
+)
+
+// execBatch runs fn with every object of the batch in a single transaction. In best-effort mode a failed
+// transaction is retried object by object, so the objects that can be applied are committed and the others reported.
+// Every object gets its own transaction, a savepoint when conn is already in one, so a failure doesn't abort the others
+func execBatch(ctx context.Context, conn *gorm.DB, args ExecArgs, fn func(tx *gorm.DB, objs interface{}) error) error {
+	db := conn.WithContext(ctx)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return fn(tx, args.Object)
+	})
+	if err == nil || args.BatchArgs.Mode != batch.BestEffort {
+		return err
+	}
+
+	return forEach(args.Object, func(obj interface{}) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			return fn(tx, obj)
+		})
+	}).Err()
+}
+
+// forEach calls fn with a pointer to every element of the slice held by objs, reporting the failures by index
+func forEach(objs interface{}, fn func(obj interface{}) error) *batch.Error {
+	batchErr := batch.NewError()
+	value := reflect.Indirect(reflect.ValueOf(objs))
+	for i := 0; i < value.Len(); i++ {
+		elem := value.Index(i)
+		if elem.Kind() != reflect.Ptr {
+			elem = elem.Addr()
+		}
+		if err := fn(elem.Interface()); err != nil {
+			batchErr.Add(i, err)
+		}
+	}
+	return batchErr
+}
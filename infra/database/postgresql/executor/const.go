@@ -1,5 +1,12 @@
 package executor
 
 const (
-	idStringQuery = "ID = ?"
+	idStringQuery            = "ID = ?"
+	deletedStringQuery       = "deleted_at IS NOT NULL"
+	deletedBeforeStringQuery = "deleted_at < ?"
+	versionIncrementQuery    = "version + 1"
+	idInStringQuery          = "ID IN ?"
+	columnIncrementQuery     = "? + 1"
+
+	batchSize = 500
 )
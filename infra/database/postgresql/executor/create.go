@@ -12,5 +12,6 @@ func NewCreateExecutor() Executor {
 }
 
 func (e *createExecutor) Exec(ctx context.Context, conn *gorm.DB, args ExecArgs) error {
+	stampCreated(ctx, args.Object)
 	return conn.WithContext(ctx).Create(args.Object).Error
 }
@@ -0,0 +1,23 @@
+package executor
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+type createBatchExecutor struct{}
+
+func NewCreateBatchExecutor() Executor {
+	return &createBatchExecutor{}
+}
+
+func (e *createBatchExecutor) Exec(ctx context.Context, conn *gorm.DB, args ExecArgs) error {
+	forEach(args.Object, func(obj interface{}) error {
+		stampCreated(ctx, obj)
+		return nil
+	})
+
+	return execBatch(ctx, conn, args, func(tx *gorm.DB, objs interface{}) error {
+		return tx.CreateInBatches(objs, batchSize).Error
+	})
+}
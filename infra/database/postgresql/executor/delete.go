@@ -12,5 +12,5 @@ func NewDeleteExecutor() Executor {
 }
 
 func (e *deleteExecutor) Exec(ctx context.Context, conn *gorm.DB, args ExecArgs) error {
-	return conn.WithContext(ctx).Where(idStringQuery, args.ID).Delete(args.Object).Error
+	return affected(conn.WithContext(ctx).Where(idStringQuery, args.ID).Delete(args.Object))
 }
@@ -0,0 +1,44 @@
+package executor
+
+import (
+	"context"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+
+	"app/internal/batch"
+)
+
+type deleteBatchExecutor struct{}
+
+func NewDeleteBatchExecutor() Executor {
+	return &deleteBatchExecutor{}
+}
+
+// Exec soft deletes the rows matching args.IDs. In atomic mode nothing is deleted unless every row exists
+func (e *deleteBatchExecutor) Exec(ctx context.Context, conn *gorm.DB, args ExecArgs) error {
+	db := conn.WithContext(ctx)
+	if args.BatchArgs.Mode != batch.BestEffort {
+		return db.Transaction(func(tx *gorm.DB) error {
+			return deleteByIDs(tx, args.Object, args.BatchArgs.IDs...)
+		})
+	}
+
+	batchErr := batch.NewError()
+	for i, id := range args.BatchArgs.IDs {
+		if err := deleteByIDs(db, args.Object, id); err != nil {
+			batchErr.Add(i, err)
+		}
+	}
+	return batchErr.Err()
+}
+
+func deleteByIDs(tx *gorm.DB, obj interface{}, ids ...uuid.UUID) error {
+	result := tx.Where(idInStringQuery, ids).Delete(obj)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(ids)) {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
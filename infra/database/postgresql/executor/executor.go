@@ -2,8 +2,12 @@ package executor
 
 import (
 	"context"
+	"time"
+
 	uuid "github.com/satori/go.uuid"
 	"gorm.io/gorm"
+
+	"app/internal/batch"
 )
 
 type ExecArgs struct {
@@ -12,6 +16,9 @@ type ExecArgs struct {
 	Object interface{}
 	QueryArgs
 	SetColumnArgs
+	PurgeArgs
+	BatchArgs
+	StreamArgs
 }
 
 type QueryArgs struct {
@@ -19,10 +26,35 @@ type QueryArgs struct {
 }
 
 type SetColumnArgs struct {
-	Field string
-	Value interface{}
+	Field   string
+	Value   interface{}
+	Columns map[string]interface{}
+}
+
+type PurgeArgs struct {
+	DeletedBefore time.Time
+}
+
+type BatchArgs struct {
+	IDs  []uuid.UUID
+	Mode batch.Mode
+}
+
+type StreamArgs struct {
+	Each func() error
 }
 
 type Executor interface {
 	Exec(ctx context.Context, conn *gorm.DB, args ExecArgs) error
 }
+
+// affected fails with gorm.ErrRecordNotFound when result changed no row, the row being missing or soft deleted
+func affected(result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -8,7 +8,16 @@ var (
 	SetType    ExecutorType = "set"
 	SelectType ExecutorType = "select"
 	RawType    ExecutorType = "raw"
+	StreamType ExecutorType = "stream"
 	DeleteType ExecutorType = "delete"
+
+	RestoreType      ExecutorType = "restore"
+	PurgeType        ExecutorType = "purge"
+	PurgeDeletedType ExecutorType = "purge_deleted"
+
+	CreateBatchType ExecutorType = "create_batch"
+	UpsertBatchType ExecutorType = "upsert_batch"
+	DeleteBatchType ExecutorType = "delete_batch"
 )
 
 func NewExecutor(executorType ExecutorType) Executor {
@@ -23,8 +32,22 @@ func NewExecutor(executorType ExecutorType) Executor {
 		return NewSelectExecutor()
 	case RawType:
 		return NewRawExecutor()
+	case StreamType:
+		return NewStreamExecutor()
 	case DeleteType:
 		return NewDeleteExecutor()
+	case RestoreType:
+		return NewRestoreExecutor()
+	case PurgeType:
+		return NewPurgeExecutor()
+	case PurgeDeletedType:
+		return NewPurgeDeletedExecutor()
+	case CreateBatchType:
+		return NewCreateBatchExecutor()
+	case UpsertBatchType:
+		return NewUpsertBatchExecutor()
+	case DeleteBatchType:
+		return NewDeleteBatchExecutor()
 	}
 	return nil
 }
@@ -0,0 +1,16 @@
+package executor
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+type purgeExecutor struct{}
+
+func NewPurgeExecutor() Executor {
+	return &purgeExecutor{}
+}
+
+func (e *purgeExecutor) Exec(ctx context.Context, conn *gorm.DB, args ExecArgs) error {
+	return affected(conn.WithContext(ctx).Unscoped().Where(idStringQuery, args.ID).Delete(args.Object))
+}
@@ -0,0 +1,19 @@
+package executor
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+type purgeDeletedExecutor struct{}
+
+func NewPurgeDeletedExecutor() Executor {
+	return &purgeDeletedExecutor{}
+}
+
+func (e *purgeDeletedExecutor) Exec(ctx context.Context, conn *gorm.DB, args ExecArgs) error {
+	return conn.WithContext(ctx).
+		Unscoped().
+		Where(deletedBeforeStringQuery, args.PurgeArgs.DeletedBefore).
+		Delete(args.Object).Error
+}
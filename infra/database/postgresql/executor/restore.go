@@ -0,0 +1,27 @@
+package executor
+
+import (
+	"context"
+	"gorm.io/gorm"
+
+	"app/internal/entity"
+)
+
+type restoreExecutor struct{}
+
+func NewRestoreExecutor() Executor {
+	return &restoreExecutor{}
+}
+
+func (e *restoreExecutor) Exec(ctx context.Context, conn *gorm.DB, args ExecArgs) error {
+	columns := withAuditColumns(ctx, map[string]interface{}{
+		entity.DeletedAtColumn: nil,
+	})
+
+	return affected(conn.WithContext(ctx).
+		Unscoped().
+		Model(args.Object).
+		Where(idStringQuery, args.ID).
+		Where(deletedStringQuery).
+		UpdateColumns(columns))
+}
@@ -3,6 +3,8 @@ package executor
 import (
 	"context"
 	"gorm.io/gorm"
+
+	"app/internal/entity"
 )
 
 type setExecutor struct{}
@@ -12,5 +14,12 @@ func NewSetExecutor() Executor {
 }
 
 func (e *setExecutor) Exec(ctx context.Context, conn *gorm.DB, args ExecArgs) error {
-	return conn.WithContext(ctx).Model(args.Object).UpdateColumn(args.SetColumnArgs.Field, args.SetColumnArgs.Value).Error
+	columns := args.SetColumnArgs.Columns
+	if len(columns) == 0 {
+		columns = map[string]interface{}{args.SetColumnArgs.Field: args.SetColumnArgs.Value}
+	}
+	if _, ok := args.Object.(entity.Auditable); ok {
+		columns = withAuditColumns(ctx, columns)
+	}
+	return conn.WithContext(ctx).Model(args.Object).UpdateColumns(columns).Error
 }
@@ -0,0 +1,34 @@
+package executor
+
+import (
+	"context"
+	"gorm.io/gorm"
+
+	"app/internal/entity"
+)
+
+type streamExecutor struct{}
+
+func NewStreamExecutor() Executor {
+	return &streamExecutor{}
+}
+
+// Exec iterates the table with a cursor instead of loading it, scanning every row into args.Object
+func (e *streamExecutor) Exec(ctx context.Context, conn *gorm.DB, args ExecArgs) error {
+	db := conn.WithContext(ctx)
+	rows, err := db.Model(args.Object).Order(entity.IDColumn).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = db.ScanRows(rows, args.Object); err != nil {
+			return err
+		}
+		if err = args.StreamArgs.Each(); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
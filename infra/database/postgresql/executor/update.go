@@ -3,6 +3,8 @@ package executor
 import (
 	"context"
 	"gorm.io/gorm"
+
+	"app/internal/entity"
 )
 
 type updateExecutor struct{}
@@ -12,5 +14,20 @@ func NewUpdateExecutor() Executor {
 }
 
 func (e *updateExecutor) Exec(ctx context.Context, conn *gorm.DB, args ExecArgs) error {
-	return conn.WithContext(ctx).Where(idStringQuery, args.ID).Updates(args.Object).Error
+	if !stampUpdated(ctx, args.Object) {
+		return affected(conn.WithContext(ctx).Where(idStringQuery, args.ID).Updates(args.Object))
+	}
+
+	return conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := affected(tx.Model(args.Object).
+			Where(idStringQuery, args.ID).
+			Omit(entity.ImmutableColumns...).
+			Updates(args.Object))
+		if err != nil {
+			return err
+		}
+		return tx.Model(args.Object).
+			Where(idStringQuery, args.ID).
+			UpdateColumn(entity.VersionColumn, incrementVersion()).Error
+	})
 }
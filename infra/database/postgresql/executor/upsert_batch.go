@@ -0,0 +1,90 @@
+package executor
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"app/internal/entity"
+	appErrors "app/internal/errors"
+)
+
+type upsertBatchExecutor struct{}
+
+func NewUpsertBatchExecutor() Executor {
+	return &upsertBatchExecutor{}
+}
+
+func (e *upsertBatchExecutor) Exec(ctx context.Context, conn *gorm.DB, args ExecArgs) error {
+	onConflict, err := upsertClause(conn, args.Object)
+	if err != nil {
+		return err
+	}
+
+	forEach(args.Object, func(obj interface{}) error {
+		stampCreated(ctx, obj)
+		return nil
+	})
+
+	// a soft deleted row isn't overwritten, so fewer rows than objects are affected
+	return execBatch(ctx, conn, args, func(tx *gorm.DB, objs interface{}) error {
+		result := tx.Clauses(onConflict).CreateInBatches(objs, batchSize)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < int64(count(objs)) {
+			return appErrors.ErrItemDeleted
+		}
+		return nil
+	})
+}
+
+// count returns the number of objects held by objs, a slice or a single object
+func count(objs interface{}) int {
+	value := reflect.Indirect(reflect.ValueOf(objs))
+	if value.Kind() != reflect.Slice {
+		return 1
+	}
+	return value.Len()
+}
+
+// upsertClause overwrites the mutable columns of a row that already exists and increments its version,
+// keeping its creation metadata. A soft deleted row is left as is, to be restored first
+func upsertClause(conn *gorm.DB, objs interface{}) (clause.OnConflict, error) {
+	stmt := &gorm.Statement{DB: conn}
+	if err := stmt.Parse(objs); err != nil {
+		return clause.OnConflict{}, err
+	}
+
+	var columns []string
+	for _, column := range stmt.Schema.DBNames {
+		if !isImmutable(column) {
+			columns = append(columns, column)
+		}
+	}
+
+	set := clause.AssignmentColumns(columns)
+	set = append(set, clause.Assignment{
+		Column: clause.Column{Name: entity.VersionColumn},
+		Value:  gorm.Expr(columnIncrementQuery, clause.Column{Table: clause.CurrentTable, Name: entity.VersionColumn}),
+	})
+
+	return clause.OnConflict{
+		Columns:   []clause.Column{{Name: entity.IDColumn}},
+		DoUpdates: set,
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: entity.DeletedAtColumn}, Value: nil},
+		}},
+	}, nil
+}
+
+func isImmutable(column string) bool {
+	for _, immutable := range entity.ImmutableColumns {
+		if column == immutable {
+			return true
+		}
+	}
+	return false
+}
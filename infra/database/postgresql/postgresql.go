@@ -4,11 +4,13 @@ import (
 	"app/infra/database/postgresql/executor"
 	"context"
 	"fmt"
+	"time"
 
 	uuid "github.com/satori/go.uuid"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	"app/internal/batch"
 	"app/internal/storage"
 )
 
@@ -19,6 +21,9 @@ const (
 	unmappedExecutorErr         = "executor type %v is not mapped"
 )
 
+// txKey holds the transaction of a ctx given to a Transaction callback
+type txKey struct{}
+
 type postgresql struct {
 	host     string
 	port     string
@@ -76,6 +81,16 @@ func (p *postgresql) Set(ctx context.Context, obj interface{}, field string, val
 	})
 }
 
+func (p *postgresql) SetColumns(ctx context.Context, obj interface{}, columns map[string]interface{}) error {
+	return p.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.SetType,
+		Object:       obj,
+		SetColumnArgs: executor.SetColumnArgs{
+			Columns: columns,
+		},
+	})
+}
+
 func (p *postgresql) Select(ctx context.Context, obj interface{}) error {
 	return p.Exec(ctx, executor.ExecArgs{
 		ExecutorType: executor.SelectType,
@@ -93,6 +108,17 @@ func (p *postgresql) Raw(ctx context.Context, query string, obj interface{}) err
 	})
 }
 
+// Stream scans the rows of obj's table one at a time into obj, calling each after every row
+func (p *postgresql) Stream(ctx context.Context, obj interface{}, each func() error) error {
+	return p.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.StreamType,
+		Object:       obj,
+		StreamArgs: executor.StreamArgs{
+			Each: each,
+		},
+	})
+}
+
 func (p *postgresql) Delete(ctx context.Context, id uuid.UUID, obj interface{}) error {
 	return p.Exec(ctx, executor.ExecArgs{
 		ExecutorType: executor.DeleteType,
@@ -101,7 +127,68 @@ func (p *postgresql) Delete(ctx context.Context, id uuid.UUID, obj interface{})
 	})
 }
 
-func (p *postgresql) Exec(ctx context.Context, args executor.ExecArgs) error {
+func (p *postgresql) Restore(ctx context.Context, id uuid.UUID, obj interface{}) error {
+	return p.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.RestoreType,
+		ID:           id,
+		Object:       obj,
+	})
+}
+
+func (p *postgresql) Purge(ctx context.Context, id uuid.UUID, obj interface{}) error {
+	return p.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.PurgeType,
+		ID:           id,
+		Object:       obj,
+	})
+}
+
+func (p *postgresql) PurgeDeleted(ctx context.Context, obj interface{}, deletedBefore time.Time) error {
+	return p.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.PurgeDeletedType,
+		Object:       obj,
+		PurgeArgs: executor.PurgeArgs{
+			DeletedBefore: deletedBefore,
+		},
+	})
+}
+
+func (p *postgresql) CreateBatch(ctx context.Context, objs interface{}, mode batch.Mode) error {
+	return p.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.CreateBatchType,
+		Object:       objs,
+		BatchArgs: executor.BatchArgs{
+			Mode: mode,
+		},
+	})
+}
+
+func (p *postgresql) UpsertBatch(ctx context.Context, objs interface{}, mode batch.Mode) error {
+	return p.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.UpsertBatchType,
+		Object:       objs,
+		BatchArgs: executor.BatchArgs{
+			Mode: mode,
+		},
+	})
+}
+
+func (p *postgresql) DeleteBatch(ctx context.Context, ids []uuid.UUID, obj interface{}, mode batch.Mode) error {
+	return p.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.DeleteBatchType,
+		Object:       obj,
+		BatchArgs: executor.BatchArgs{
+			IDs:  ids,
+			Mode: mode,
+		},
+	})
+}
+
+func (p *postgresql) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
 	conn, err := p.connect()
 	if err != nil {
 		return err
@@ -111,12 +198,34 @@ func (p *postgresql) Exec(ctx context.Context, args executor.ExecArgs) error {
 	if err != nil {
 		return err
 	}
+	defer db.Close()
 
+	return conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+func (p *postgresql) Exec(ctx context.Context, args executor.ExecArgs) error {
 	dbExecutor := executor.NewExecutor(args.ExecutorType)
 	if dbExecutor == nil {
 		return fmt.Errorf(unmappedExecutorErr, args.ExecutorType)
 	}
 
+	// the connection of a transaction is closed once it's done
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return dbExecutor.Exec(ctx, tx, args)
+	}
+
+	conn, err := p.connect()
+	if err != nil {
+		return err
+	}
+
+	db, err := conn.DB()
+	if err != nil {
+		return err
+	}
+
 	err = dbExecutor.Exec(ctx, conn, args)
 	if err != nil {
 		return err
@@ -0,0 +1,272 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	sqliteDriver "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"app/infra/database/postgresql/executor"
+	"app/internal/batch"
+	appErrors "app/internal/errors"
+	"app/internal/storage"
+)
+
+const (
+	// MemoryPath keeps the database in the memory of the process, dropped once it's closed
+	MemoryPath = ":memory:"
+
+	pragmas = "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+
+	// the extended result codes of a violated primary key or unique constraint
+	primaryKeyConstraintCode = 1555
+	uniqueConstraintCode     = 2067
+
+	failedToOpenSqlite  = "failed to open sqlite database %s: %v"
+	unmappedExecutorErr = "executor type %v is not mapped"
+)
+
+// txKey holds the transaction of a ctx given to a Transaction callback
+type txKey struct{}
+
+type sqliteDB struct {
+	conn *gorm.DB
+	// migrated holds the tables already created, by name
+	migrated sync.Map
+}
+
+// New opens the sqlite database at path, MemoryPath keeping it in the memory of the process. It runs the
+// executors of postgresql, creating the table of a model on its first use. The database holds a single
+// connection, sqlite serializing the writes anyway and a memory database living as long as its connection
+func New(path string) (storage.Database, error) {
+	conn, err := gorm.Open(sqlite.Open(path+pragmas), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf(failedToOpenSqlite, path, err)
+	}
+
+	db, err := conn.DB()
+	if err != nil {
+		return nil, fmt.Errorf(failedToOpenSqlite, path, err)
+	}
+	db.SetMaxOpenConns(1)
+	// an idle memory database closed by the pool would lose its rows
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+
+	return &sqliteDB{conn: conn}, nil
+}
+
+func (s *sqliteDB) Create(ctx context.Context, obj interface{}) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.CreateType,
+		Object:       obj,
+	})
+}
+
+func (s *sqliteDB) Update(ctx context.Context, id uuid.UUID, obj interface{}) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.UpdateType,
+		ID:           id,
+		Object:       obj,
+	})
+}
+
+func (s *sqliteDB) Set(ctx context.Context, obj interface{}, field string, value interface{}) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.SetType,
+		Object:       obj,
+		SetColumnArgs: executor.SetColumnArgs{
+			Field: field,
+			Value: value,
+		},
+	})
+}
+
+func (s *sqliteDB) SetColumns(ctx context.Context, obj interface{}, columns map[string]interface{}) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.SetType,
+		Object:       obj,
+		SetColumnArgs: executor.SetColumnArgs{
+			Columns: columns,
+		},
+	})
+}
+
+func (s *sqliteDB) Select(ctx context.Context, obj interface{}) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.SelectType,
+		Object:       obj,
+	})
+}
+
+// Raw runs query as is, so the postgres specific queries, like the ones of the outbox relay and the scheduler
+// lock, fail
+func (s *sqliteDB) Raw(ctx context.Context, query string, obj interface{}) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.RawType,
+		Object:       obj,
+		QueryArgs: executor.QueryArgs{
+			QueryString: query,
+		},
+	})
+}
+
+// Stream scans the rows of obj's table one at a time into obj, calling each after every row. The rows hold the
+// connection, so each must not use the database
+func (s *sqliteDB) Stream(ctx context.Context, obj interface{}, each func() error) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.StreamType,
+		Object:       obj,
+		StreamArgs: executor.StreamArgs{
+			Each: each,
+		},
+	})
+}
+
+func (s *sqliteDB) Delete(ctx context.Context, id uuid.UUID, obj interface{}) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.DeleteType,
+		ID:           id,
+		Object:       obj,
+	})
+}
+
+func (s *sqliteDB) Restore(ctx context.Context, id uuid.UUID, obj interface{}) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.RestoreType,
+		ID:           id,
+		Object:       obj,
+	})
+}
+
+func (s *sqliteDB) Purge(ctx context.Context, id uuid.UUID, obj interface{}) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.PurgeType,
+		ID:           id,
+		Object:       obj,
+	})
+}
+
+func (s *sqliteDB) PurgeDeleted(ctx context.Context, obj interface{}, deletedBefore time.Time) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.PurgeDeletedType,
+		Object:       obj,
+		PurgeArgs: executor.PurgeArgs{
+			DeletedBefore: deletedBefore,
+		},
+	})
+}
+
+func (s *sqliteDB) CreateBatch(ctx context.Context, objs interface{}, mode batch.Mode) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.CreateBatchType,
+		Object:       objs,
+		BatchArgs: executor.BatchArgs{
+			Mode: mode,
+		},
+	})
+}
+
+func (s *sqliteDB) UpsertBatch(ctx context.Context, objs interface{}, mode batch.Mode) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.UpsertBatchType,
+		Object:       objs,
+		BatchArgs: executor.BatchArgs{
+			Mode: mode,
+		},
+	})
+}
+
+func (s *sqliteDB) DeleteBatch(ctx context.Context, ids []uuid.UUID, obj interface{}, mode batch.Mode) error {
+	return s.Exec(ctx, executor.ExecArgs{
+		ExecutorType: executor.DeleteBatchType,
+		Object:       obj,
+		BatchArgs: executor.BatchArgs{
+			IDs:  ids,
+			Mode: mode,
+		},
+	})
+}
+
+func (s *sqliteDB) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return s.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+func (s *sqliteDB) Exec(ctx context.Context, args executor.ExecArgs) error {
+	dbExecutor := executor.NewExecutor(args.ExecutorType)
+	if dbExecutor == nil {
+		return fmt.Errorf(unmappedExecutorErr, args.ExecutorType)
+	}
+
+	conn := s.conn
+	tx, inTransaction := ctx.Value(txKey{}).(*gorm.DB)
+	if inTransaction {
+		conn = tx
+	}
+
+	if args.ExecutorType != executor.RawType {
+		if err := s.migrate(ctx, conn, args.Object, !inTransaction); err != nil {
+			return err
+		}
+	}
+
+	return translateError(dbExecutor.Exec(ctx, conn, args))
+}
+
+// Close closes the connection, dropping a memory database
+func (s *sqliteDB) Close() error {
+	db, err := s.conn.DB()
+	if err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+// migrate creates the table of obj's model when it doesn't exist yet. A table created in a transaction isn't
+// remembered, being dropped when the transaction is rolled back
+func (s *sqliteDB) migrate(ctx context.Context, conn *gorm.DB, obj interface{}, remember bool) error {
+	stmt := &gorm.Statement{DB: conn}
+	if err := stmt.Parse(obj); err != nil {
+		return err
+	}
+	if _, ok := s.migrated.Load(stmt.Schema.Table); ok {
+		return nil
+	}
+
+	model := reflect.New(stmt.Schema.ModelType).Interface()
+	if err := conn.WithContext(ctx).AutoMigrate(model); err != nil {
+		return err
+	}
+	if remember {
+		s.migrated.Store(stmt.Schema.Table, struct{}{})
+	}
+	return nil
+}
+
+// translateError maps the violated primary key or unique constraint of err to appErrors.ErrDuplicateKey
+func translateError(err error) error {
+	var sqliteErr *sqliteDriver.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case primaryKeyConstraintCode, uniqueConstraintCode:
+			return appErrors.ErrDuplicateKey
+		}
+	}
+	return err
+}
@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/errors"
+	"app/internal/job"
+)
+
+type entry struct {
+	job     job.Job
+	payload []byte
+	queued  bool
+	// lease identifies the dequeue that handed the job out, which holds it until leasedUntil. It's zero while
+	// the job is queued or done
+	lease       uuid.UUID
+	leasedUntil time.Time
+	// expiresAt is when a done job is forgotten, zero while it isn't done
+	expiresAt time.Time
+}
+
+type memory struct {
+	mu        sync.Mutex
+	entries   map[uuid.UUID]*entry
+	cancels   map[uuid.UUID]bool
+	retention time.Duration
+	now       func() time.Time
+}
+
+// New returns a job.Store keeping the jobs in the memory of the process, for a single replica. Done jobs are
+// forgotten after retention
+func New(retention time.Duration) job.Store {
+	return &memory{
+		entries:   map[uuid.UUID]*entry{},
+		cancels:   map[uuid.UUID]bool{},
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+func (m *memory) Enqueue(_ context.Context, j *job.Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.holds(j) {
+		return job.ErrLeaseLost
+	}
+	m.entries[j.ID] = &entry{
+		job:     *j,
+		payload: append([]byte(nil), j.Payload...),
+		queued:  true,
+	}
+	return nil
+}
+
+// Dequeue scans the queued jobs for the due one of highest priority, the oldest first among equal priorities,
+// queuing again the jobs whose lease expired
+func (m *memory) Dequeue(_ context.Context, lease time.Duration) (*job.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	var next *entry
+	for _, e := range m.entries {
+		if e.leased() && !now.Before(e.leasedUntil) {
+			e.release()
+			e.queued = true
+		}
+		if !e.queued || e.job.RunAt.After(now) {
+			continue
+		}
+		// a job cancelled while queued leaves the queue without being handed out
+		if e.job.Done() {
+			e.queued = false
+			continue
+		}
+		if next == nil || e.job.Priority > next.job.Priority ||
+			e.job.Priority == next.job.Priority && e.job.CreatedAt.Before(next.job.CreatedAt) {
+			next = e
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+	next.queued = false
+	next.lease = uuid.NewV4()
+	next.leasedUntil = now.Add(lease)
+	return next.copy(), nil
+}
+
+func (m *memory) Renew(_ context.Context, j *job.Job, lease time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.lookup(j.ID)
+	if !ok || !e.leased() || e.lease != j.Lease {
+		return false, nil
+	}
+	e.leasedUntil = m.now().Add(lease)
+	return true, nil
+}
+
+func (m *memory) Get(_ context.Context, id uuid.UUID) (*job.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.lookup(id)
+	if !ok {
+		return nil, errors.ErrJobNotFound
+	}
+	return e.copy(), nil
+}
+
+func (m *memory) Save(_ context.Context, j *job.Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.holds(j) {
+		return job.ErrLeaseLost
+	}
+	e, ok := m.lookup(j.ID)
+	if !ok {
+		e = &entry{}
+		m.entries[j.ID] = e
+	}
+	e.job = *j
+	if j.Done() {
+		e.release()
+		e.expiresAt = m.now().Add(m.retention)
+	}
+	return nil
+}
+
+func (m *memory) RequestCancel(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cancels[id] = true
+	return nil
+}
+
+func (m *memory) CancelRequested(_ context.Context, id uuid.UUID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lookup(id)
+	return m.cancels[id], nil
+}
+
+// holds tells whether j may be stored: it isn't leased, or its lease wasn't handed out again
+func (m *memory) holds(j *job.Job) bool {
+	if uuid.Equal(j.Lease, uuid.Nil) {
+		return true
+	}
+	e, ok := m.lookup(j.ID)
+	return ok && e.lease == j.Lease
+}
+
+// lookup returns the entry of id, forgetting it when it expired
+func (m *memory) lookup(id uuid.UUID) (*entry, bool) {
+	e, ok := m.entries[id]
+	if !ok {
+		return nil, false
+	}
+	if !e.expiresAt.IsZero() && !m.now().Before(e.expiresAt) {
+		delete(m.entries, id)
+		delete(m.cancels, id)
+		return nil, false
+	}
+	return e, true
+}
+
+func (e *entry) leased() bool {
+	return !e.leasedUntil.IsZero()
+}
+
+func (e *entry) release() {
+	e.lease = uuid.UUID{}
+	e.leasedUntil = time.Time{}
+}
+
+func (e *entry) copy() *job.Job {
+	j := e.job
+	j.Payload = append([]byte(nil), e.payload...)
+	j.Lease = e.lease
+	return &j
+}
@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/errors"
+	"app/internal/job"
+)
+
+func TestMemory(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Memory Job Store Suits")
+}
+
+// lease is how long the jobs are handed out for by the tests
+const lease = time.Minute
+
+func newJob(priority int, createdAt time.Time) *job.Job {
+	return &job.Job{
+		ID:          uuid.NewV4(),
+		Type:        "test",
+		Priority:    priority,
+		Payload:     json.RawMessage(`{"value":1}`),
+		Status:      job.Queued,
+		MaxAttempts: 1,
+		RunAt:       createdAt,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+}
+
+var _ = Describe("Memory Job Store", func() {
+	var (
+		ctx   context.Context
+		store *memory
+		now   time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = New(time.Hour).(*memory)
+		now = time.Now().UTC().Add(-time.Minute)
+	})
+
+	Context("Dequeuing jobs", func() {
+		When("jobs of several priorities are queued", func() {
+			It("Should hand them out by priority, then oldest first", func() {
+				low := newJob(0, now)
+				high := newJob(10, now.Add(2*time.Second))
+				older := newJob(10, now.Add(time.Second))
+				for _, j := range []*job.Job{low, high, older} {
+					Expect(store.Enqueue(ctx, j)).Should(Succeed())
+				}
+
+				var ids []uuid.UUID
+				for range []int{1, 2, 3} {
+					j, err := store.Dequeue(ctx, lease)
+					Expect(err).ShouldNot(HaveOccurred())
+					ids = append(ids, j.ID)
+				}
+				Expect(ids).To(Equal([]uuid.UUID{older.ID, high.ID, low.ID}))
+
+				j, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j).To(BeNil())
+			})
+		})
+
+		When("the job is due later", func() {
+			It("Should keep it until then", func() {
+				delayed := newJob(0, now)
+				delayed.RunAt = time.Now().Add(time.Hour)
+				Expect(store.Enqueue(ctx, delayed)).Should(Succeed())
+
+				j, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j).To(BeNil())
+			})
+		})
+
+		When("the job was cancelled while queued", func() {
+			It("Should skip it", func() {
+				cancelled := newJob(0, now)
+				Expect(store.Enqueue(ctx, cancelled)).Should(Succeed())
+				cancelled.Status = job.Cancelled
+				Expect(store.Save(ctx, cancelled)).Should(Succeed())
+
+				j, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j).To(BeNil())
+			})
+		})
+	})
+
+	Context("Leasing jobs", func() {
+		When("the worker renews the lease", func() {
+			It("Should keep the job handed out", func() {
+				Expect(store.Enqueue(ctx, newJob(0, now))).Should(Succeed())
+				leased, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				renewed, err := store.Renew(ctx, leased, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(renewed).To(BeTrue())
+				j, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j).To(BeNil())
+			})
+		})
+
+		When("the lease expired", func() {
+			It("Should hand the job out again", func() {
+				queued := newJob(0, now)
+				Expect(store.Enqueue(ctx, queued)).Should(Succeed())
+				leased, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				leased.Status = job.Running
+				leased.Attempts = 1
+				Expect(store.Save(ctx, leased)).Should(Succeed())
+
+				store.now = func() time.Time {
+					return time.Now().Add(2 * lease)
+				}
+
+				reclaimed, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(reclaimed.ID).To(Equal(queued.ID))
+				Expect(reclaimed.Attempts).To(Equal(1))
+				renewed, err := store.Renew(ctx, leased, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(renewed).To(BeFalse())
+			})
+
+			It("Should leave the job to its new worker", func() {
+				queued := newJob(0, now)
+				Expect(store.Enqueue(ctx, queued)).Should(Succeed())
+				stale, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				store.now = func() time.Time {
+					return time.Now().Add(2 * lease)
+				}
+				reclaimed, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				reclaimed.Status = job.Running
+				reclaimed.Progress = 10
+				Expect(store.Save(ctx, reclaimed)).Should(Succeed())
+
+				stale.Status = job.Failed
+				Expect(store.Save(ctx, stale)).To(MatchError(job.ErrLeaseLost))
+				Expect(store.Enqueue(ctx, stale)).To(MatchError(job.ErrLeaseLost))
+
+				j, err := store.Get(ctx, queued.ID)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j.Status).To(Equal(job.Running))
+				Expect(j.Progress).To(Equal(10))
+				renewed, err := store.Renew(ctx, reclaimed, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(renewed).To(BeTrue())
+			})
+		})
+
+		When("the job is done", func() {
+			It("Should end its lease", func() {
+				Expect(store.Enqueue(ctx, newJob(0, now))).Should(Succeed())
+				leased, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				leased.Status = job.Succeeded
+				Expect(store.Save(ctx, leased)).Should(Succeed())
+
+				renewed, err := store.Renew(ctx, leased, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(renewed).To(BeFalse())
+
+				store.now = func() time.Time {
+					return time.Now().Add(2 * lease)
+				}
+				j, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j).To(BeNil())
+			})
+		})
+	})
+
+	Context("Getting a job", func() {
+		When("the job was saved", func() {
+			It("Should return its state with the payload it was enqueued with", func() {
+				queued := newJob(0, now)
+				Expect(store.Enqueue(ctx, queued)).Should(Succeed())
+				queued.Status = job.Running
+				queued.Progress = 50
+				queued.Payload = nil
+				Expect(store.Save(ctx, queued)).Should(Succeed())
+
+				j, err := store.Get(ctx, queued.ID)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j.Status).To(Equal(job.Running))
+				Expect(j.Progress).To(Equal(50))
+				Expect(j.Payload).To(MatchJSON(`{"value":1}`))
+			})
+		})
+
+		When("the job is done", func() {
+			It("Should expire after the retention", func() {
+				done := newJob(0, now)
+				Expect(store.Enqueue(ctx, done)).Should(Succeed())
+				done.Status = job.Succeeded
+				Expect(store.Save(ctx, done)).Should(Succeed())
+
+				store.now = func() time.Time {
+					return time.Now().Add(2 * time.Hour)
+				}
+				_, err := store.Get(ctx, done.ID)
+				Expect(err).To(MatchError(errors.ErrJobNotFound))
+			})
+		})
+	})
+
+	Context("Cancelling a job", func() {
+		It("Should only flag the job cancelled", func() {
+			flagged, other := newJob(0, now), newJob(0, now)
+			Expect(store.RequestCancel(ctx, flagged.ID)).Should(Succeed())
+
+			requested, err := store.CancelRequested(ctx, flagged.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(requested).To(BeTrue())
+
+			requested, err = store.CancelRequested(ctx, other.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(requested).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,319 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	redigo "github.com/gomodule/redigo/redis"
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/errors"
+	"app/internal/job"
+)
+
+const (
+	keyPrefix       = "jobs:"
+	keySep          = ":"
+	queueKey        = "queue"
+	delayedKey      = "delayed"
+	scoresKey       = "scores"
+	leasedKey       = "leased"
+	leasesKey       = "leases"
+	cancelKeyPrefix = "cancel:"
+
+	jobField     = "job"
+	payloadField = "payload"
+
+	hashGetAction   = "HMGET"
+	hashDelAction   = "HDEL"
+	sortedRemAction = "ZREM"
+	multiAction     = "MULTI"
+	execAction      = "EXEC"
+	setAction       = "SET"
+	existsAction    = "EXISTS"
+
+	// priorityWeight puts every job of a priority before the lower ones, the creation milliseconds ordering
+	// the jobs of a priority
+	priorityWeight = 1e13
+	// promoteLimit bounds the delayed jobs moved to the queue by a dequeue
+	promoteLimit = 100
+	maxIdleConns = 4
+)
+
+// dequeueScript queues again the jobs whose lease expired and moves the due delayed jobs to the queue, then pops
+// the queued job of lowest score and leases it. A job without score is done and leaves the queue.
+// KEYS: queue, delayed, scores, leased, leases. ARGV: now in milliseconds, promoteLimit, lease deadline in
+// milliseconds, lease
+var dequeueScript = redigo.NewScript(5, `
+local expired = redis.call('ZRANGEBYSCORE', KEYS[4], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, id in ipairs(expired) do
+	redis.call('ZREM', KEYS[4], id)
+	redis.call('HDEL', KEYS[5], id)
+	local score = redis.call('HGET', KEYS[3], id)
+	if score then
+		redis.call('ZADD', KEYS[1], score, id)
+	end
+end
+local due = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, id in ipairs(due) do
+	redis.call('ZREM', KEYS[2], id)
+	local score = redis.call('HGET', KEYS[3], id)
+	if score then
+		redis.call('ZADD', KEYS[1], score, id)
+	end
+end
+local popped = redis.call('ZPOPMIN', KEYS[1])
+local id = popped[1]
+if id then
+	redis.call('ZADD', KEYS[4], ARGV[3], id)
+	redis.call('HSET', KEYS[5], id, ARGV[4])
+end
+return id
+`)
+
+// renewScript extends the lease of a job unless it was handed out again.
+// KEYS: leased, leases. ARGV: lease deadline in milliseconds, job id, lease
+var renewScript = redigo.NewScript(2, `
+if redis.call('HGET', KEYS[2], ARGV[2]) ~= ARGV[3] then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+return 1
+`)
+
+// enqueueScript stores a job with its payload and queues it until it's due, ending its lease. A job enqueued again
+// by a worker is left alone once its lease was handed out again, returning 0.
+// KEYS: job, scores, delayed, leased, leases. ARGV: job id, lease or an empty string when the job isn't leased,
+// job field, job, payload field, payload, score, due time in milliseconds
+var enqueueScript = redigo.NewScript(5, `
+if ARGV[2] ~= '' and redis.call('HGET', KEYS[5], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+redis.call('HSET', KEYS[1], ARGV[3], ARGV[4], ARGV[5], ARGV[6])
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[7])
+redis.call('ZADD', KEYS[3], ARGV[8], ARGV[1])
+redis.call('ZREM', KEYS[4], ARGV[1])
+redis.call('HDEL', KEYS[5], ARGV[1])
+return 1
+`)
+
+// saveScript stores the state of a job unless its lease was handed out again, returning 0 then. A done job leaves
+// the queue, its lease ends and it expires after the retention.
+// KEYS: job, cancel, scores, leased, leases. ARGV: job id, lease or an empty string when the job isn't leased,
+// job field, job, 1 when the job is done, retention in seconds
+var saveScript = redigo.NewScript(5, `
+if ARGV[2] ~= '' and redis.call('HGET', KEYS[5], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+redis.call('HSET', KEYS[1], ARGV[3], ARGV[4])
+if ARGV[5] == '1' then
+	redis.call('EXPIRE', KEYS[1], ARGV[6])
+	redis.call('EXPIRE', KEYS[2], ARGV[6])
+	redis.call('HDEL', KEYS[3], ARGV[1])
+	redis.call('ZREM', KEYS[4], ARGV[1])
+	redis.call('HDEL', KEYS[5], ARGV[1])
+end
+return 1
+`)
+
+type redis struct {
+	pool      *redigo.Pool
+	prefix    string
+	retention time.Duration
+}
+
+// New returns a job.Store keeping every job in a hash, queued by priority in a sorted set. A job is first added
+// to a sorted set of delayed jobs by due time, and moved to the queue once due. A dequeued job is kept in a sorted
+// set of leased jobs by lease deadline until it's done, and queued again when its lease expires. Done jobs expire
+// after retention.
+// The keys are prefixed by queue, so the services sharing a server only see their own jobs
+func New(host, port, queue string, retention time.Duration) job.Store {
+	return &redis{
+		pool: &redigo.Pool{
+			MaxIdle: maxIdleConns,
+			Dial: func() (redigo.Conn, error) {
+				return redigo.Dial("tcp", fmt.Sprintf("%s:%s", host, port))
+			},
+		},
+		prefix:    keyPrefix + queue + keySep,
+		retention: retention,
+	}
+}
+
+func (r *redis) Enqueue(ctx context.Context, j *job.Job) error {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	id := j.ID.String()
+	score := float64(-j.Priority)*priorityWeight + float64(j.CreatedAt.UnixMilli())
+
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stored, err := redigo.Bool(enqueueScript.Do(conn, r.jobKey(id), r.prefix+scoresKey, r.prefix+delayedKey,
+		r.prefix+leasedKey, r.prefix+leasesKey, id, leaseOf(j), jobField, data, payloadField, []byte(j.Payload),
+		score, j.RunAt.UnixMilli()))
+	if err == nil && !stored {
+		return job.ErrLeaseLost
+	}
+	return err
+}
+
+func (r *redis) Dequeue(ctx context.Context, lease time.Duration) (*job.Job, error) {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	for {
+		now := time.Now()
+		token := uuid.NewV4()
+		id, err := redigo.String(dequeueScript.Do(conn, r.prefix+queueKey, r.prefix+delayedKey, r.prefix+scoresKey,
+			r.prefix+leasedKey, r.prefix+leasesKey, now.UnixMilli(), promoteLimit, now.Add(lease).UnixMilli(),
+			token.String()))
+		if err == redigo.ErrNil {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		j, err := r.get(conn, id)
+		if err != nil && err != errors.ErrJobNotFound {
+			return nil, err
+		}
+		// a job cancelled while queued stays in the queue until popped
+		if err == errors.ErrJobNotFound || j.Done() {
+			if err = r.release(conn, id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		j.Lease = token
+		return j, nil
+	}
+}
+
+func (r *redis) Renew(ctx context.Context, j *job.Job, lease time.Duration) (bool, error) {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	return redigo.Bool(renewScript.Do(conn, r.prefix+leasedKey, r.prefix+leasesKey,
+		time.Now().Add(lease).UnixMilli(), j.ID.String(), j.Lease.String()))
+}
+
+func (r *redis) Get(ctx context.Context, id uuid.UUID) (*job.Job, error) {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return r.get(conn, id.String())
+}
+
+func (r *redis) Save(ctx context.Context, j *job.Job) error {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	id := j.ID.String()
+
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	done := 0
+	if j.Done() {
+		done = 1
+	}
+	stored, err := redigo.Bool(saveScript.Do(conn, r.jobKey(id), r.cancelKey(id), r.prefix+scoresKey,
+		r.prefix+leasedKey, r.prefix+leasesKey, id, leaseOf(j), jobField, data, done, r.retentionSeconds()))
+	if err == nil && !stored {
+		return job.ErrLeaseLost
+	}
+	return err
+}
+
+func (r *redis) RequestCancel(ctx context.Context, id uuid.UUID) error {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Do(setAction, r.cancelKey(id.String()), true, "EX", r.retentionSeconds())
+	return err
+}
+
+func (r *redis) CancelRequested(ctx context.Context, id uuid.UUID) (bool, error) {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	return redigo.Bool(conn.Do(existsAction, r.cancelKey(id.String())))
+}
+
+func (r *redis) get(conn redigo.Conn, id string) (*job.Job, error) {
+	values, err := redigo.ByteSlices(conn.Do(hashGetAction, r.jobKey(id), jobField, payloadField))
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 2 || values[0] == nil {
+		return nil, errors.ErrJobNotFound
+	}
+
+	var j *job.Job
+	if err = json.Unmarshal(values[0], &j); err != nil {
+		return nil, err
+	}
+	j.Payload = values[1]
+	return j, nil
+}
+
+// release ends the lease of the job with id, just handed out by Dequeue
+func (r *redis) release(conn redigo.Conn, id string) error {
+	_ = conn.Send(multiAction)
+	_ = conn.Send(sortedRemAction, r.prefix+leasedKey, id)
+	_ = conn.Send(hashDelAction, r.prefix+leasesKey, id)
+	_, err := conn.Do(execAction)
+	return err
+}
+
+// leaseOf returns the lease of j as stored in the leases hash, an empty string when j isn't leased
+func leaseOf(j *job.Job) string {
+	if uuid.Equal(j.Lease, uuid.Nil) {
+		return ""
+	}
+	return j.Lease.String()
+}
+
+func (r *redis) retentionSeconds() int64 {
+	seconds := int64(r.retention.Seconds())
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+func (r *redis) jobKey(id string) string {
+	return r.prefix + id
+}
+
+func (r *redis) cancelKey(id string) string {
+	return r.prefix + cancelKeyPrefix + id
+}
@@ -0,0 +1,249 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/errors"
+	"app/internal/job"
+)
+
+func TestRedis(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Redis Job Store Suits")
+}
+
+const (
+	// lease is how long the jobs are handed out for by the tests, shortLease for those waiting for it to expire
+	lease      = time.Minute
+	shortLease = 10 * time.Millisecond
+)
+
+func newJob(priority int, createdAt time.Time) *job.Job {
+	return &job.Job{
+		ID:          uuid.NewV4(),
+		Type:        "test",
+		Priority:    priority,
+		Payload:     json.RawMessage(`{"value":1}`),
+		Status:      job.Queued,
+		MaxAttempts: 1,
+		RunAt:       createdAt,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+}
+
+var _ = Describe("Redis Job Store", func() {
+	var (
+		ctx    context.Context
+		server *miniredis.Miniredis
+		store  job.Store
+		now    time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = miniredis.RunT(GinkgoT())
+		store = New(server.Host(), server.Port(), "service", time.Hour)
+		now = time.Now().UTC().Add(-time.Minute)
+	})
+
+	Context("Dequeuing jobs", func() {
+		When("jobs of several priorities are queued", func() {
+			It("Should hand them out by priority, then oldest first", func() {
+				low := newJob(0, now)
+				high := newJob(10, now.Add(2*time.Second))
+				older := newJob(10, now.Add(time.Second))
+				for _, j := range []*job.Job{low, high, older} {
+					Expect(store.Enqueue(ctx, j)).Should(Succeed())
+				}
+
+				var ids []uuid.UUID
+				for range []int{1, 2, 3} {
+					j, err := store.Dequeue(ctx, lease)
+					Expect(err).ShouldNot(HaveOccurred())
+					ids = append(ids, j.ID)
+				}
+				Expect(ids).To(Equal([]uuid.UUID{older.ID, high.ID, low.ID}))
+
+				j, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j).To(BeNil())
+			})
+		})
+
+		When("the job is due later", func() {
+			It("Should keep it until then", func() {
+				delayed := newJob(0, now)
+				delayed.RunAt = time.Now().Add(time.Hour)
+				Expect(store.Enqueue(ctx, delayed)).Should(Succeed())
+
+				j, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j).To(BeNil())
+			})
+		})
+
+		When("the job was queued by another service", func() {
+			It("Should leave it to that service", func() {
+				other := New(server.Host(), server.Port(), "other", time.Hour)
+				Expect(other.Enqueue(ctx, newJob(0, now))).Should(Succeed())
+
+				j, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j).To(BeNil())
+			})
+		})
+
+		When("the job was cancelled while queued", func() {
+			It("Should skip it", func() {
+				cancelled := newJob(0, now)
+				Expect(store.Enqueue(ctx, cancelled)).Should(Succeed())
+				cancelled.Status = job.Cancelled
+				Expect(store.Save(ctx, cancelled)).Should(Succeed())
+
+				j, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j).To(BeNil())
+			})
+		})
+	})
+
+	Context("Leasing jobs", func() {
+		When("the worker renews the lease", func() {
+			It("Should keep the job handed out", func() {
+				Expect(store.Enqueue(ctx, newJob(0, now))).Should(Succeed())
+				leased, err := store.Dequeue(ctx, shortLease)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				renewed, err := store.Renew(ctx, leased, shortLease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(renewed).To(BeTrue())
+				j, err := store.Dequeue(ctx, shortLease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j).To(BeNil())
+			})
+		})
+
+		When("the lease expired", func() {
+			It("Should hand the job out again", func() {
+				queued := newJob(0, now)
+				Expect(store.Enqueue(ctx, queued)).Should(Succeed())
+				leased, err := store.Dequeue(ctx, shortLease)
+				Expect(err).ShouldNot(HaveOccurred())
+				leased.Status = job.Running
+				leased.Attempts = 1
+				Expect(store.Save(ctx, leased)).Should(Succeed())
+
+				time.Sleep(2 * shortLease)
+
+				reclaimed, err := store.Dequeue(ctx, shortLease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(reclaimed.ID).To(Equal(queued.ID))
+				Expect(reclaimed.Attempts).To(Equal(1))
+				renewed, err := store.Renew(ctx, leased, shortLease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(renewed).To(BeFalse())
+			})
+
+			It("Should leave the job to its new worker", func() {
+				queued := newJob(0, now)
+				Expect(store.Enqueue(ctx, queued)).Should(Succeed())
+				stale, err := store.Dequeue(ctx, shortLease)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				time.Sleep(2 * shortLease)
+				reclaimed, err := store.Dequeue(ctx, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				reclaimed.Status = job.Running
+				reclaimed.Progress = 10
+				Expect(store.Save(ctx, reclaimed)).Should(Succeed())
+
+				stale.Status = job.Failed
+				Expect(store.Save(ctx, stale)).To(MatchError(job.ErrLeaseLost))
+				Expect(store.Enqueue(ctx, stale)).To(MatchError(job.ErrLeaseLost))
+
+				j, err := store.Get(ctx, queued.ID)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j.Status).To(Equal(job.Running))
+				Expect(j.Progress).To(Equal(10))
+				renewed, err := store.Renew(ctx, reclaimed, lease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(renewed).To(BeTrue())
+			})
+		})
+
+		When("the job is done", func() {
+			It("Should end its lease", func() {
+				Expect(store.Enqueue(ctx, newJob(0, now))).Should(Succeed())
+				leased, err := store.Dequeue(ctx, shortLease)
+				Expect(err).ShouldNot(HaveOccurred())
+				leased.Status = job.Succeeded
+				Expect(store.Save(ctx, leased)).Should(Succeed())
+
+				renewed, err := store.Renew(ctx, leased, shortLease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(renewed).To(BeFalse())
+
+				time.Sleep(2 * shortLease)
+				j, err := store.Dequeue(ctx, shortLease)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j).To(BeNil())
+			})
+		})
+	})
+
+	Context("Getting a job", func() {
+		When("the job was saved", func() {
+			It("Should return its state with the payload it was enqueued with", func() {
+				queued := newJob(0, now)
+				Expect(store.Enqueue(ctx, queued)).Should(Succeed())
+				queued.Status = job.Running
+				queued.Progress = 50
+				queued.Payload = nil
+				Expect(store.Save(ctx, queued)).Should(Succeed())
+
+				j, err := store.Get(ctx, queued.ID)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j.Status).To(Equal(job.Running))
+				Expect(j.Progress).To(Equal(50))
+				Expect(j.Payload).To(MatchJSON(`{"value":1}`))
+			})
+		})
+
+		When("the job is done", func() {
+			It("Should expire after the retention", func() {
+				done := newJob(0, now)
+				Expect(store.Enqueue(ctx, done)).Should(Succeed())
+				done.Status = job.Succeeded
+				Expect(store.Save(ctx, done)).Should(Succeed())
+
+				server.FastForward(2 * time.Hour)
+				_, err := store.Get(ctx, done.ID)
+				Expect(err).To(MatchError(errors.ErrJobNotFound))
+			})
+		})
+	})
+
+	Context("Cancelling a job", func() {
+		It("Should only flag the job cancelled", func() {
+			flagged, other := newJob(0, now), newJob(0, now)
+			Expect(store.RequestCancel(ctx, flagged.ID)).Should(Succeed())
+
+			requested, err := store.CancelRequested(ctx, flagged.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(requested).To(BeTrue())
+
+			requested, err = store.CancelRequested(ctx, other.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(requested).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"app/internal/lock"
+)
+
+type memory struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+// New returns a lock.Locker on the memory of the process, for a single replica or the services of a single
+// process. A lock is never lost while held
+func New() lock.Locker {
+	return &memory{
+		held: map[string]bool{},
+	}
+}
+
+func (m *memory) Hold(ctx context.Context, key string, fn func(ctx context.Context)) (bool, error) {
+	m.mu.Lock()
+	if m.held[key] {
+		m.mu.Unlock()
+		return false, nil
+	}
+	m.held[key] = true
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.held, key)
+		m.mu.Unlock()
+	}()
+	fn(ctx)
+	return true, nil
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"app/internal/lock"
+)
+
+func TestMemory(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Memory Locker Suits")
+}
+
+var _ = Describe("Memory Locker", func() {
+	var (
+		ctx    context.Context
+		locker lock.Locker
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		locker = New()
+	})
+
+	Context("Holding a lock", func() {
+		When("the lock is free", func() {
+			It("Should run fn and release the lock afterwards", func() {
+				var ran bool
+				acquired, err := locker.Hold(ctx, "key", func(context.Context) {
+					ran = true
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(acquired).To(BeTrue())
+				Expect(ran).To(BeTrue())
+
+				acquired, err = locker.Hold(ctx, "key", func(context.Context) {})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(acquired).To(BeTrue())
+			})
+		})
+
+		When("the lock is held", func() {
+			It("Should not run fn", func() {
+				_, err := locker.Hold(ctx, "key", func(context.Context) {
+					acquired, err := locker.Hold(ctx, "key", func(context.Context) {
+						Fail("ran while the lock was held")
+					})
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(acquired).To(BeFalse())
+
+					acquired, err = locker.Hold(ctx, "other", func(context.Context) {})
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(acquired).To(BeTrue())
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+	})
+})
@@ -0,0 +1,110 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	// registers the pgx driver of database/sql
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"app/internal/lock"
+)
+
+const (
+	dbInfo     = "host=%s port=%s user=%s password=%s dbname=%s sslmode=disable"
+	driverName = "pgx"
+
+	// lockQuery takes the session level advisory lock of a key, held until it's unlocked or the session ends
+	lockQuery   = "SELECT pg_try_advisory_lock(hashtext($1))"
+	unlockQuery = "SELECT pg_advisory_unlock(hashtext($1))"
+
+	failedToPingLock    = "failed to ping the connection of lock %s: %v\n"
+	failedToReleaseLock = "failed to release lock %s: %v\n"
+)
+
+type postgresql struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
+// New returns a lock.Locker on postgres advisory locks. A lock is held by a connection of its own, pinged every
+// third of ttl while held, so a replica that dies loses its locks with its connection and a replica whose
+// connection drops stops running fn within ttl
+func New(host, port, user, pass, dbName string, ttl time.Duration) (lock.Locker, error) {
+	db, err := sql.Open(driverName, fmt.Sprintf(dbInfo, host, port, user, pass, dbName))
+	if err != nil {
+		return nil, err
+	}
+	return newLocker(db, ttl), nil
+}
+
+func newLocker(db *sql.DB, ttl time.Duration) lock.Locker {
+	// a connection is closed once its lock is released, never holding a lock back in the pool
+	db.SetMaxIdleConns(0)
+	return &postgresql{
+		db:  db,
+		ttl: ttl,
+	}
+}
+
+func (p *postgresql) Hold(ctx context.Context, key string, fn func(ctx context.Context)) (bool, error) {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var locked bool
+	if err = conn.QueryRowContext(ctx, lockQuery, key).Scan(&locked); err != nil || !locked {
+		_ = conn.Close()
+		return false, err
+	}
+
+	held, lost := context.WithCancel(ctx)
+	defer lost()
+	pinged := make(chan struct{})
+	go func() {
+		defer close(pinged)
+		p.keep(held, conn, key, lost)
+	}()
+
+	fn(held)
+	lost()
+	<-pinged
+
+	p.release(conn, key)
+	return true, nil
+}
+
+// keep pings the connection holding the lock until ctx is done, calling lost once it fails, the session and
+// its lock being gone
+func (p *postgresql) keep(ctx context.Context, conn *sql.Conn, key string, lost context.CancelFunc) {
+	ticker := time.NewTicker(p.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.PingContext(ctx); err != nil {
+				if ctx.Err() == nil {
+					log.Printf(failedToPingLock, key, err)
+				}
+				lost()
+				return
+			}
+		}
+	}
+}
+
+// release unlocks the lock and closes its connection. It's made without the ctx of Hold, which may be done on
+// shutdown, and closing the connection releases the lock anyway
+func (p *postgresql) release(conn *sql.Conn, key string) {
+	var unlocked bool
+	if err := conn.QueryRowContext(context.Background(), unlockQuery, key).Scan(&unlocked); err != nil {
+		log.Printf(failedToReleaseLock, key, err)
+	}
+	_ = conn.Close()
+}
@@ -0,0 +1,201 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"app/internal/lock"
+	commonAssertion "app/internal/test/assertion/common"
+	errorsAssertion "app/internal/test/assertion/errors"
+)
+
+func TestPostgresql(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Postgresql Locker Suits")
+}
+
+// server fakes the advisory locks of postgres, a lock being held by a session until it's unlocked or the session
+// is closed
+type server struct {
+	mu      sync.Mutex
+	holders map[string]*session
+	err     error
+	pingErr error
+}
+
+func (s *server) Connect(context.Context) (driver.Conn, error) {
+	return &session{server: s}, nil
+}
+
+func (s *server) Driver() driver.Driver {
+	return nil
+}
+
+func (s *server) held(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.holders[key] != nil
+}
+
+func (s *server) fail(err, pingErr error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err, s.pingErr = err, pingErr
+}
+
+type session struct {
+	server *server
+}
+
+func (c *session) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	if c.server.err != nil {
+		return nil, c.server.err
+	}
+
+	key := args[0].Value.(string)
+	holder := c.server.holders[key]
+	switch query {
+	case lockQuery:
+		if holder != nil && holder != c {
+			return &row{value: false}, nil
+		}
+		c.server.holders[key] = c
+	case unlockQuery:
+		if holder != c {
+			return &row{value: false}, nil
+		}
+		delete(c.server.holders, key)
+	}
+	return &row{value: true}, nil
+}
+
+func (c *session) Ping(context.Context) error {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	return c.server.pingErr
+}
+
+func (c *session) Close() error {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	for key, holder := range c.server.holders {
+		if holder == c {
+			delete(c.server.holders, key)
+		}
+	}
+	return nil
+}
+
+func (c *session) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *session) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+// row is the single boolean row answered by the lock queries
+type row struct {
+	value bool
+	read  bool
+}
+
+func (r *row) Columns() []string {
+	return []string{"locked"}
+}
+
+func (r *row) Close() error {
+	return nil
+}
+
+func (r *row) Next(dest []driver.Value) error {
+	if r.read {
+		return io.EOF
+	}
+	r.read = true
+	dest[0] = r.value
+	return nil
+}
+
+var _ = Describe("Postgresql Locker", func() {
+	var (
+		fake   *server
+		locker lock.Locker
+		ran    bool
+		fn     func(ctx context.Context)
+	)
+
+	BeforeEach(func() {
+		fake = &server{holders: map[string]*session{}}
+		locker = newLocker(sql.OpenDB(fake), 30*time.Millisecond)
+		ran = false
+		fn = func(context.Context) {
+			ran = true
+		}
+	})
+
+	Context("Holding a lock", func() {
+		When("the lock is free", func() {
+			It("Should run fn holding it, then release it", func() {
+				var held bool
+				acquired, err := locker.Hold(commonAssertion.EmptyCtx, "key", func(context.Context) {
+					held = fake.held("key")
+				})
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(acquired).To(BeTrue())
+				Expect(held).To(BeTrue())
+				Expect(fake.held("key")).To(BeFalse())
+			})
+		})
+		When("another replica holds the lock", func() {
+			It("Should not run fn", func() {
+				fake.holders["key"] = &session{server: fake}
+
+				acquired, err := locker.Hold(commonAssertion.EmptyCtx, "key", fn)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(acquired).To(BeFalse())
+				Expect(ran).To(BeFalse())
+			})
+		})
+		When("the connection holding the lock drops", func() {
+			It("Should cancel the ctx of fn", func() {
+				acquired, err := locker.Hold(commonAssertion.EmptyCtx, "key", func(ctx context.Context) {
+					fake.fail(nil, driver.ErrBadConn)
+					select {
+					case <-ctx.Done():
+						ran = true
+					case <-time.After(time.Second):
+					}
+				})
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(acquired).To(BeTrue())
+				Expect(ran).To(BeTrue())
+				Expect(fake.held("key")).To(BeFalse())
+			})
+		})
+		When("the database fails", func() {
+			It("Should return the error", func() {
+				fake.fail(errorsAssertion.ErrGeneric, nil)
+
+				acquired, err := locker.Hold(commonAssertion.EmptyCtx, "key", fn)
+
+				Expect(err).To(MatchError(errorsAssertion.ErrGeneric))
+				Expect(acquired).To(BeFalse())
+				Expect(ran).To(BeFalse())
+			})
+		})
+	})
+})
@@ -0,0 +1,141 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	redigo "github.com/gomodule/redigo/redis"
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/lock"
+)
+
+const (
+	failedToRefreshLock = "failed to refresh lock %s: %v\n"
+	failedToReleaseLock = "failed to release lock %s: %v\n"
+
+	keyPrefix = "lock:"
+
+	setAction = "SET"
+
+	maxIdleConns = 2
+)
+
+var (
+	// refreshScript extends the lock, provided it's still held by the token. KEYS: lock. ARGV: token, ttl in ms
+	refreshScript = redigo.NewScript(1, `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+	// releaseScript deletes the lock, provided it's still held by the token. KEYS: lock. ARGV: token
+	releaseScript = redigo.NewScript(1, `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+)
+
+type redis struct {
+	pool *redigo.Pool
+	ttl  time.Duration
+}
+
+// New returns a lock.Locker on the keys of a redis server. A lock expires ttl after it was last refreshed, and
+// is refreshed every third of ttl while held, so a replica that dies loses its locks after ttl at most
+func New(host, port string, ttl time.Duration) lock.Locker {
+	return &redis{
+		pool: &redigo.Pool{
+			MaxIdle: maxIdleConns,
+			Dial: func() (redigo.Conn, error) {
+				return redigo.Dial("tcp", fmt.Sprintf("%s:%s", host, port))
+			},
+		},
+		ttl: ttl,
+	}
+}
+
+func (r *redis) Hold(ctx context.Context, key string, fn func(ctx context.Context)) (bool, error) {
+	key = keyPrefix + key
+	token := uuid.NewV4().String()
+
+	acquired, err := r.acquire(ctx, key, token)
+	if err != nil || !acquired {
+		return false, err
+	}
+
+	held, lost := context.WithCancel(ctx)
+	defer lost()
+	refreshed := make(chan struct{})
+	go func() {
+		defer close(refreshed)
+		r.keep(held, key, token, lost)
+	}()
+
+	fn(held)
+	lost()
+	<-refreshed
+
+	if err = r.release(key, token); err != nil {
+		log.Printf(failedToReleaseLock, key, err)
+	}
+	return true, nil
+}
+
+func (r *redis) acquire(ctx context.Context, key, token string) (bool, error) {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	_, err = redigo.String(conn.Do(setAction, key, token, "NX", "PX", r.ttl.Milliseconds()))
+	if err == redigo.ErrNil {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// keep refreshes the lock until ctx is done, calling lost once the lock can't be refreshed
+func (r *redis) keep(ctx context.Context, key, token string, lost context.CancelFunc) {
+	ticker := time.NewTicker(r.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refreshed, err := r.refresh(ctx, key, token)
+			if err != nil && ctx.Err() == nil {
+				log.Printf(failedToRefreshLock, key, err)
+			}
+			if !refreshed {
+				lost()
+				return
+			}
+		}
+	}
+}
+
+func (r *redis) refresh(ctx context.Context, key, token string) (bool, error) {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	return redigo.Bool(refreshScript.Do(conn, key, token, r.ttl.Milliseconds()))
+}
+
+// release is made without the ctx of Hold, which may be done on shutdown
+func (r *redis) release(key, token string) error {
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	_, err := releaseScript.Do(conn, key, token)
+	return err
+}
@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"app/internal/lock"
+)
+
+func TestRedis(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Redis Locker Suits")
+}
+
+var _ = Describe("Redis Locker", func() {
+	var (
+		ctx    context.Context
+		server *miniredis.Miniredis
+		locker lock.Locker
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = miniredis.RunT(GinkgoT())
+		locker = New(server.Host(), server.Port(), 30*time.Millisecond)
+	})
+
+	Context("Holding a lock", func() {
+		When("the lock is free", func() {
+			It("Should run fn holding it and release it afterwards", func() {
+				var held bool
+				acquired, err := locker.Hold(ctx, "key", func(context.Context) {
+					held = server.Exists(keyPrefix + "key")
+				})
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(acquired).To(BeTrue())
+				Expect(held).To(BeTrue())
+				Expect(server.Exists(keyPrefix + "key")).To(BeFalse())
+			})
+		})
+		When("another holder has the lock", func() {
+			It("Should not run fn", func() {
+				Expect(server.Set(keyPrefix+"key", "other")).To(Succeed())
+
+				var ran bool
+				acquired, err := locker.Hold(ctx, "key", func(context.Context) {
+					ran = true
+				})
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(acquired).To(BeFalse())
+				Expect(ran).To(BeFalse())
+				Expect(server.Get(keyPrefix + "key")).To(Equal("other"))
+			})
+		})
+		When("fn lasts longer than the ttl", func() {
+			It("Should keep refreshing the lock", func() {
+				acquired, err := locker.Hold(ctx, "key", func(ctx context.Context) {
+					time.Sleep(100 * time.Millisecond)
+					Expect(ctx.Err()).ToNot(HaveOccurred())
+					Expect(server.TTL(keyPrefix + "key")).To(Equal(30 * time.Millisecond))
+				})
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(acquired).To(BeTrue())
+			})
+		})
+		When("the lock is lost while fn runs", func() {
+			It("Should cancel the ctx of fn and leave the new holder's lock", func() {
+				acquired, err := locker.Hold(ctx, "key", func(ctx context.Context) {
+					server.Del(keyPrefix + "key")
+					Expect(server.Set(keyPrefix+"key", "other")).To(Succeed())
+					Eventually(ctx.Done()).Should(BeClosed())
+				})
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(acquired).To(BeTrue())
+				Expect(server.Get(keyPrefix + "key")).To(Equal("other"))
+			})
+		})
+		When("the cache server is down", func() {
+			It("Should return an error", func() {
+				server.Close()
+
+				acquired, err := locker.Hold(ctx, "key", func(context.Context) {})
+
+				Expect(err).To(HaveOccurred())
+				Expect(acquired).To(BeFalse())
+			})
+		})
+	})
+})
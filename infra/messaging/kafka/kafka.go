@@ -0,0 +1,155 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"app/internal/messaging"
+)
+
+const (
+	failedToFetchMessage  = "failed to fetch message of %s as %s: %v\n"
+	failedToDecodeMessage = "failed to decode message %d of %s: %v\n"
+	failedToCloseReader   = "failed to close reader of %s as %s: %v\n"
+
+	attemptHeader = "attempt"
+	retryDelay    = time.Second
+)
+
+// writer and reader are the parts of kafka-go used by the broker, so it can run against a stand-in
+type writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafkago.Message) error
+}
+
+type reader interface {
+	FetchMessage(ctx context.Context) (kafkago.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafkago.Message) error
+	Close() error
+}
+
+type kafka struct {
+	writer    writer
+	newReader func(topic, group string) reader
+}
+
+// New returns a messaging.Broker on kafka, the events being keyed by subject so those of an item keep their order.
+// Offsets are committed per partition, acknowledging a message also acknowledges the ones before it
+func New(brokers []string) messaging.Broker {
+	return &kafka{
+		writer: &kafkago.Writer{
+			Addr:                   kafkago.TCP(brokers...),
+			Balancer:               &kafkago.Hash{},
+			AllowAutoTopicCreation: true,
+		},
+		newReader: func(topic, group string) reader {
+			return kafkago.NewReader(kafkago.ReaderConfig{
+				Brokers:     brokers,
+				Topic:       topic,
+				GroupID:     group,
+				StartOffset: kafkago.LastOffset,
+			})
+		},
+	}
+}
+
+func (k *kafka) Publish(ctx context.Context, topic string, event messaging.Event) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return k.writer.WriteMessages(ctx, kafkago.Message{
+		Topic:   topic,
+		Key:     []byte(event.Subject),
+		Value:   value,
+		Headers: []kafkago.Header{attempt(1)},
+	})
+}
+
+func (k *kafka) Subscribe(ctx context.Context, topic, group string) (<-chan *messaging.Message, error) {
+	r := k.newReader(topic, group)
+
+	out := make(chan *messaging.Message)
+	go func() {
+		defer close(out)
+		defer func() {
+			if err := r.Close(); err != nil {
+				log.Printf(failedToCloseReader, topic, group, err)
+			}
+		}()
+
+		for ctx.Err() == nil {
+			msg, err := r.FetchMessage(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf(failedToFetchMessage, topic, group, err)
+					select {
+					case <-ctx.Done():
+					case <-time.After(retryDelay):
+					}
+				}
+				continue
+			}
+
+			message, ok := k.newMessage(topic, r, msg)
+			if !ok {
+				continue
+			}
+			select {
+			case out <- message:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (k *kafka) newMessage(topic string, r reader, msg kafkago.Message) (*messaging.Message, bool) {
+	var event messaging.Event
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		log.Printf(failedToDecodeMessage, msg.Offset, topic, err)
+		// skip it for good, it can't be handled by any member
+		_ = r.CommitMessages(context.Background(), msg)
+		return nil, false
+	}
+	n := attemptOf(msg)
+
+	ack := func(ctx context.Context) error {
+		return r.CommitMessages(ctx, msg)
+	}
+	// kafka can't deliver a message again, so it's appended at the end of the topic with the next attempt
+	nack := func(ctx context.Context) error {
+		retry := kafkago.Message{
+			Topic:   topic,
+			Key:     msg.Key,
+			Value:   msg.Value,
+			Headers: []kafkago.Header{attempt(n + 1)},
+		}
+		if err := k.writer.WriteMessages(ctx, retry); err != nil {
+			return err
+		}
+		return r.CommitMessages(ctx, msg)
+	}
+	return messaging.NewMessage(topic, event, n, ack, nack), true
+}
+
+func attempt(n int) kafkago.Header {
+	return kafkago.Header{Key: attemptHeader, Value: []byte(strconv.Itoa(n))}
+}
+
+func attemptOf(msg kafkago.Message) int {
+	for _, header := range msg.Headers {
+		if header.Key != attemptHeader {
+			continue
+		}
+		if n, err := strconv.Atoi(string(header.Value)); err == nil {
+			return n
+		}
+	}
+	return 1
+}
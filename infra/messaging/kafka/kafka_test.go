@@ -0,0 +1,126 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kafkago "github.com/segmentio/kafka-go"
+
+	"app/internal/messaging"
+	"app/internal/test/conformance"
+)
+
+func TestKafka(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Kafka Broker Suits")
+}
+
+const fetchInterval = 5 * time.Millisecond
+
+// cluster stands in for kafka with a single partition per topic, the members of a group sharing its offset
+type cluster struct {
+	mu      sync.Mutex
+	logs    map[string][]kafkago.Message
+	offsets map[string]int
+}
+
+func newCluster() *cluster {
+	return &cluster{
+		logs:    make(map[string][]kafkago.Message),
+		offsets: make(map[string]int),
+	}
+}
+
+func (c *cluster) WriteMessages(_ context.Context, msgs ...kafkago.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, msg := range msgs {
+		msg.Offset = int64(len(c.logs[msg.Topic]))
+		c.logs[msg.Topic] = append(c.logs[msg.Topic], msg)
+	}
+	return nil
+}
+
+func (c *cluster) reader(topic, group string) reader {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := topic + "/" + group
+	if _, ok := c.offsets[key]; !ok {
+		c.offsets[key] = len(c.logs[topic])
+	}
+	return &clusterReader{cluster: c, topic: topic, key: key}
+}
+
+type clusterReader struct {
+	cluster *cluster
+	topic   string
+	key     string
+}
+
+func (r *clusterReader) FetchMessage(ctx context.Context) (kafkago.Message, error) {
+	for {
+		if msg, ok := r.next(); ok {
+			return msg, nil
+		}
+		select {
+		case <-ctx.Done():
+			return kafkago.Message{}, ctx.Err()
+		case <-time.After(fetchInterval):
+		}
+	}
+}
+
+func (r *clusterReader) next() (kafkago.Message, bool) {
+	r.cluster.mu.Lock()
+	defer r.cluster.mu.Unlock()
+	offset := r.cluster.offsets[r.key]
+	if offset >= len(r.cluster.logs[r.topic]) {
+		return kafkago.Message{}, false
+	}
+	r.cluster.offsets[r.key]++
+	return r.cluster.logs[r.topic][offset], true
+}
+
+func (r *clusterReader) CommitMessages(context.Context, ...kafkago.Message) error {
+	return nil
+}
+
+func (r *clusterReader) Close() error {
+	return nil
+}
+
+var _ = Describe("Kafka Broker", func() {
+	var (
+		c      *cluster
+		topics int
+	)
+
+	BeforeEach(func() {
+		c = newCluster()
+	})
+
+	conformance.DescribeBroker(func() messaging.Broker {
+		return &kafka{writer: c, newReader: c.reader}
+	}, func() string {
+		topics++
+		return fmt.Sprintf("items-%d", topics)
+	})
+
+	Context("Publishing an event", func() {
+		It("Should key the message by the event subject", func() {
+			event, err := messaging.NewEvent("/test", messaging.ItemUpdated, "subject", nil)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect((&kafka{writer: c}).Publish(context.Background(), "items", event)).To(Succeed())
+
+			Expect(c.logs["items"]).To(HaveLen(1))
+			Expect(string(c.logs["items"][0].Key)).To(Equal("subject"))
+			Expect(attemptOf(c.logs["items"][0])).To(Equal(1))
+		})
+	})
+})
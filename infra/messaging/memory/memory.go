@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"app/internal/messaging"
+)
+
+const queueSize = 1024
+
+type memory struct {
+	mu     sync.Mutex
+	groups map[string]map[string]chan *messaging.Message
+}
+
+// New returns a messaging.Broker keeping the messages in memory, meant for tests and local runs.
+// Messages published before a group subscribes aren't delivered to it
+func New() messaging.Broker {
+	return &memory{
+		groups: make(map[string]map[string]chan *messaging.Message),
+	}
+}
+
+func (m *memory) Publish(ctx context.Context, topic string, event messaging.Event) error {
+	m.mu.Lock()
+	queues := make([]chan *messaging.Message, 0, len(m.groups[topic]))
+	for _, queue := range m.groups[topic] {
+		queues = append(queues, queue)
+	}
+	m.mu.Unlock()
+
+	for _, queue := range queues {
+		select {
+		case queue <- m.newMessage(topic, event, 1, queue):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Subscribe returns the messages of the group, the members of a group competing for the same queue
+func (m *memory) Subscribe(ctx context.Context, topic, group string) (<-chan *messaging.Message, error) {
+	queue := m.queue(topic, group)
+	out := make(chan *messaging.Message)
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg := <-queue:
+				select {
+				case out <- msg:
+				case <-ctx.Done():
+					// leave the message to the other members of the group
+					go func() { queue <- msg }()
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (m *memory) queue(topic, group string) chan *messaging.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.groups[topic] == nil {
+		m.groups[topic] = make(map[string]chan *messaging.Message)
+	}
+	queue, ok := m.groups[topic][group]
+	if !ok {
+		queue = make(chan *messaging.Message, queueSize)
+		m.groups[topic][group] = queue
+	}
+	return queue
+}
+
+func (m *memory) newMessage(topic string, event messaging.Event, attempt int, queue chan *messaging.Message) *messaging.Message {
+	ack := func(context.Context) error {
+		return nil
+	}
+	nack := func(ctx context.Context) error {
+		select {
+		case queue <- m.newMessage(topic, event, attempt+1, queue):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return messaging.NewMessage(topic, event, attempt, ack, nack)
+}
@@ -0,0 +1,21 @@
+package memory
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"app/internal/test/conformance"
+)
+
+func TestMemory(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Memory Broker Suits")
+}
+
+var _ = Describe("Memory Broker", func() {
+	conformance.DescribeBroker(New, func() string {
+		return "items"
+	})
+})
@@ -0,0 +1,170 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"app/internal/messaging"
+)
+
+const (
+	failedToConnectToNatsServer = "failed to connect to nats server: %v\n"
+	failedToFetchMessages       = "failed to fetch messages of %s as %s: %v\n"
+	failedToDecodeMessage       = "failed to decode message of %s: %v\n"
+
+	fetchCount   = 10
+	fetchTimeout = time.Second
+	retryDelay   = time.Second
+
+	subjectSeparator = "."
+	streamSeparator  = "_"
+	reconnectForever = -1
+)
+
+type natsBroker struct {
+	mu      sync.Mutex
+	js      nats.JetStreamContext
+	streams map[string]bool
+}
+
+// New returns a messaging.Broker on NATS JetStream, a topic being the subject of a stream of the same name
+// and a group a durable pull consumer of that stream
+func New(url string) messaging.Broker {
+	conn, err := nats.Connect(url, nats.MaxReconnects(reconnectForever))
+	if err != nil {
+		log.Fatalf(failedToConnectToNatsServer, err)
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		log.Fatalf(failedToConnectToNatsServer, err)
+	}
+
+	return &natsBroker{
+		js:      js,
+		streams: make(map[string]bool),
+	}
+}
+
+func (n *natsBroker) Publish(ctx context.Context, topic string, event messaging.Event) error {
+	if err := n.ensureStream(topic); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = n.js.Publish(topic, data, nats.Context(ctx))
+	return err
+}
+
+func (n *natsBroker) Subscribe(ctx context.Context, topic, group string) (<-chan *messaging.Message, error) {
+	if err := n.ensureStream(topic); err != nil {
+		return nil, err
+	}
+
+	// the consumer is created beforehand so it outlives the subscriptions bound to it, creating it again
+	// with the same config being a no-op
+	stream := streamName(topic)
+	_, err := n.js.AddConsumer(stream, &nats.ConsumerConfig{
+		Durable:       group,
+		AckPolicy:     nats.AckExplicitPolicy,
+		DeliverPolicy: nats.DeliverNewPolicy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := n.js.PullSubscribe(topic, group, nats.Bind(stream, group))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *messaging.Message)
+	go func() {
+		defer close(out)
+		defer sub.Unsubscribe()
+
+		for ctx.Err() == nil {
+			msgs, err := sub.Fetch(fetchCount, nats.MaxWait(fetchTimeout))
+			if stdErrors.Is(err, nats.ErrTimeout) {
+				continue
+			}
+			if err != nil {
+				log.Printf(failedToFetchMessages, topic, group, err)
+				select {
+				case <-ctx.Done():
+				case <-time.After(retryDelay):
+				}
+				continue
+			}
+
+			for _, msg := range msgs {
+				message, ok := newMessage(topic, msg)
+				if !ok {
+					continue
+				}
+				select {
+				case out <- message:
+				case <-ctx.Done():
+					// the message is delivered again once its ack wait elapses
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func newMessage(topic string, msg *nats.Msg) (*messaging.Message, bool) {
+	var event messaging.Event
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Printf(failedToDecodeMessage, topic, err)
+		_ = msg.Term()
+		return nil, false
+	}
+
+	attempt := 1
+	if meta, err := msg.Metadata(); err == nil {
+		attempt = int(meta.NumDelivered)
+	}
+
+	ack := func(context.Context) error {
+		return msg.Ack()
+	}
+	nack := func(context.Context) error {
+		return msg.Nak()
+	}
+	return messaging.NewMessage(topic, event, attempt, ack, nack), true
+}
+
+// ensureStream creates the stream of topic the first time it's used by this process
+func (n *natsBroker) ensureStream(topic string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.streams[topic] {
+		return nil
+	}
+	_, err := n.js.AddStream(&nats.StreamConfig{
+		Name:     streamName(topic),
+		Subjects: []string{topic},
+	})
+	if err != nil && !stdErrors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+		return err
+	}
+	n.streams[topic] = true
+	return nil
+}
+
+// streamName derives a valid stream name from a subject, which may contain dots
+func streamName(topic string) string {
+	return strings.ReplaceAll(topic, subjectSeparator, streamSeparator)
+}
@@ -0,0 +1,52 @@
+package nats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"app/internal/messaging"
+	"app/internal/test/conformance"
+)
+
+func TestNats(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "NATS Broker Suits")
+}
+
+var _ = Describe("NATS Broker", func() {
+	var (
+		srv    *server.Server
+		topics int
+	)
+
+	BeforeEach(func() {
+		var err error
+		srv, err = server.NewServer(&server.Options{
+			Host:      "127.0.0.1",
+			Port:      server.RANDOM_PORT,
+			JetStream: true,
+			StoreDir:  GinkgoT().TempDir(),
+			NoLog:     true,
+			NoSigs:    true,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		go srv.Start()
+		Expect(srv.ReadyForConnections(5 * time.Second)).To(BeTrue())
+	})
+
+	AfterEach(func() {
+		srv.Shutdown()
+	})
+
+	conformance.DescribeBroker(func() messaging.Broker {
+		return New(srv.ClientURL())
+	}, func() string {
+		topics++
+		return fmt.Sprintf("items.%d", topics)
+	})
+})
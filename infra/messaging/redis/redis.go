@@ -0,0 +1,211 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	redigo "github.com/gomodule/redigo/redis"
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/messaging"
+)
+
+const (
+	failedToReadStream   = "failed to read stream %s as %s: %v\n"
+	failedToDecodeEntry  = "failed to decode entry %s of stream %s: %v\n"
+	failedToCreateGroup  = "failed to create group %s on stream %s: %w"
+	busyGroupErrorPrefix = "BUSYGROUP"
+
+	addAction       = "XADD"
+	groupAction     = "XGROUP"
+	readGroupAction = "XREADGROUP"
+	ackAction       = "XACK"
+
+	eventField   = "event"
+	attemptField = "attempt"
+	newEntryID   = "*"
+	lastEntryID  = "$"
+	undelivered  = ">"
+
+	readCount    = 10
+	blockTimeout = time.Second
+	retryDelay   = time.Second
+	maxIdleConns = 4
+)
+
+type redis struct {
+	pool     *redigo.Pool
+	consumer string
+}
+
+// New returns a messaging.Broker on redis streams, a topic being a stream read by consumer groups
+func New(host, port string) messaging.Broker {
+	return &redis{
+		pool: &redigo.Pool{
+			MaxIdle: maxIdleConns,
+			Dial: func() (redigo.Conn, error) {
+				return redigo.Dial("tcp", fmt.Sprintf("%s:%s", host, port))
+			},
+		},
+		consumer: consumerName(),
+	}
+}
+
+func (r *redis) Publish(ctx context.Context, topic string, event messaging.Event) error {
+	return r.add(ctx, topic, event, 1)
+}
+
+func (r *redis) Subscribe(ctx context.Context, topic, group string) (<-chan *messaging.Message, error) {
+	if err := r.createGroup(ctx, topic, group); err != nil {
+		return nil, err
+	}
+
+	out := make(chan *messaging.Message)
+	go func() {
+		defer close(out)
+		for ctx.Err() == nil {
+			if err := r.read(ctx, topic, group, out); err != nil && ctx.Err() == nil {
+				log.Printf(failedToReadStream, topic, group, err)
+				select {
+				case <-ctx.Done():
+				case <-time.After(retryDelay):
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// read hands out the entries never delivered to the group, blocking until some are added or the timeout elapses
+func (r *redis) read(ctx context.Context, topic, group string, out chan<- *messaging.Message) error {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for ctx.Err() == nil {
+		streams, err := redigo.Values(conn.Do(readGroupAction,
+			"GROUP", group, r.consumer,
+			"COUNT", readCount,
+			"BLOCK", blockTimeout.Milliseconds(),
+			"STREAMS", topic, undelivered,
+		))
+		if err == redigo.ErrNil {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range r.parse(topic, group, streams) {
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+				// unacknowledged entries stay pending in the group
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// parse reads the entries of a single stream XREADGROUP reply: [[stream, [[id, [field, value...]]...]]]
+func (r *redis) parse(topic, group string, streams []interface{}) []*messaging.Message {
+	var messages []*messaging.Message
+	for _, stream := range streams {
+		values, err := redigo.Values(stream, nil)
+		if err != nil || len(values) != 2 {
+			continue
+		}
+		entries, err := redigo.Values(values[1], nil)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			parts, err := redigo.Values(entry, nil)
+			if err != nil || len(parts) != 2 {
+				continue
+			}
+			id, _ := redigo.String(parts[0], nil)
+			fields, err := redigo.StringMap(parts[1], nil)
+			if err != nil {
+				log.Printf(failedToDecodeEntry, id, topic, err)
+				continue
+			}
+
+			var event messaging.Event
+			if err = json.Unmarshal([]byte(fields[eventField]), &event); err != nil {
+				log.Printf(failedToDecodeEntry, id, topic, err)
+				_ = r.ack(context.Background(), topic, group, id)
+				continue
+			}
+			attempt, _ := strconv.Atoi(fields[attemptField])
+			messages = append(messages, r.newMessage(topic, group, id, event, attempt))
+		}
+	}
+	return messages
+}
+
+// newMessage acknowledges the entry on Ack. Nack acknowledges it as well and adds it again to the stream
+// with the next attempt number, so it's delivered again
+func (r *redis) newMessage(topic, group, id string, event messaging.Event, attempt int) *messaging.Message {
+	ack := func(ctx context.Context) error {
+		return r.ack(ctx, topic, group, id)
+	}
+	nack := func(ctx context.Context) error {
+		if err := r.add(ctx, topic, event, attempt+1); err != nil {
+			return err
+		}
+		return r.ack(ctx, topic, group, id)
+	}
+	return messaging.NewMessage(topic, event, attempt, ack, nack)
+}
+
+func (r *redis) add(ctx context.Context, topic string, event messaging.Event, attempt int) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return r.do(ctx, addAction, topic, newEntryID, eventField, data, attemptField, attempt)
+}
+
+func (r *redis) ack(ctx context.Context, topic, group, id string) error {
+	return r.do(ctx, ackAction, topic, group, id)
+}
+
+// createGroup creates the group from the end of the stream, creating the stream as well when needed
+func (r *redis) createGroup(ctx context.Context, topic, group string) error {
+	err := r.do(ctx, groupAction, "CREATE", topic, group, lastEntryID, "MKSTREAM")
+	if err != nil && !strings.HasPrefix(err.Error(), busyGroupErrorPrefix) {
+		return fmt.Errorf(failedToCreateGroup, group, topic, err)
+	}
+	return nil
+}
+
+func (r *redis) do(ctx context.Context, action string, args ...interface{}) error {
+	conn, err := r.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Do(action, args...)
+	return err
+}
+
+// consumerName identifies this process within the consumer groups
+func consumerName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "consumer"
+	}
+	return host + "-" + uuid.NewV4().String()
+}
@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"app/internal/messaging"
+	"app/internal/test/conformance"
+)
+
+func TestRedis(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Redis Streams Broker Suits")
+}
+
+var _ = Describe("Redis Streams Broker", func() {
+	var (
+		server *miniredis.Miniredis
+		topics int
+	)
+
+	BeforeEach(func() {
+		server = miniredis.RunT(GinkgoT())
+	})
+
+	conformance.DescribeBroker(func() messaging.Broker {
+		return New(server.Host(), server.Port())
+	}, func() string {
+		topics++
+		return fmt.Sprintf("items-%d", topics)
+	})
+})
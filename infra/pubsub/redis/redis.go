@@ -0,0 +1,104 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	redigo "github.com/gomodule/redigo/redis"
+)
+
+const (
+	failedToConnectToRedisServer = "failed to connect to redis server: %v\n"
+
+	publishAction = "PUBLISH"
+)
+
+// Channel is a redis pub/sub channel, every replica subscribed to it receiving the messages published by all of
+// them, including its own
+type Channel interface {
+	Publish(ctx context.Context, data []byte) error
+	// Receive subscribes to the channel on a dedicated connection, calls subscribed once listening, then deliver
+	// with every message until ctx is done or the connection fails
+	Receive(ctx context.Context, subscribed func(), deliver func(data []byte)) error
+	// Name is the name of the channel
+	Name() string
+}
+
+type redis struct {
+	mu      sync.Mutex
+	addr    string
+	port    string
+	channel string
+	conn    redigo.Conn
+}
+
+// New returns the Channel named channel on a redis server. The messages are published on a connection kept open
+// and dialled again once it fails
+func New(host, port, channel string) Channel {
+	return &redis{
+		addr:    host,
+		port:    port,
+		channel: channel,
+	}
+}
+
+func (r *redis) Publish(_ context.Context, data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var err error
+	if r.conn == nil || r.conn.Err() != nil {
+		if r.conn, err = redigo.Dial("tcp", r.getHost()); err != nil {
+			return fmt.Errorf(failedToConnectToRedisServer, err)
+		}
+	}
+
+	_, err = r.conn.Do(publishAction, r.channel, data)
+	return err
+}
+
+func (r *redis) Receive(ctx context.Context, subscribed func(), deliver func(data []byte)) error {
+	conn, err := redigo.Dial("tcp", r.getHost())
+	if err != nil {
+		return fmt.Errorf(failedToConnectToRedisServer, err)
+	}
+
+	psc := redigo.PubSubConn{Conn: conn}
+	defer psc.Close()
+	if err = psc.Subscribe(r.channel); err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = psc.Unsubscribe()
+		case <-stop:
+		}
+	}()
+
+	for {
+		switch msg := psc.Receive().(type) {
+		case redigo.Message:
+			deliver(msg.Data)
+		case redigo.Subscription:
+			if msg.Count == 0 {
+				return nil
+			}
+			subscribed()
+		case error:
+			return msg
+		}
+	}
+}
+
+func (r *redis) Name() string {
+	return r.channel
+}
+
+func (r *redis) getHost() string {
+	return fmt.Sprintf("%s:%s", r.addr, r.port)
+}
@@ -1,23 +1,78 @@
 package server
 
 import (
-	"github.com/gin-gonic/gin"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultAddress = ":8080"
+
+	contentTypeHeader = "Content-Type"
+	grpcContentType   = "application/grpc"
 )
 
 type Server interface {
 	Run(addr ...string) error
 }
 
-func New(httpEngine *gin.Engine) Server {
+// New returns a server running the REST API served by httpHandler, like a gin engine, and the gRPC API. gRPC
+// listens on grpcPort when set, otherwise both APIs share the address given to Run, gRPC calls being told apart by
+// their content type
+func New(httpHandler http.Handler, grpcServer *grpc.Server, grpcPort string) Server {
 	return &server{
-		httpEngine: httpEngine,
+		httpHandler: httpHandler,
+		grpcServer:  grpcServer,
+		grpcPort:    grpcPort,
 	}
 }
 
 type server struct {
-	httpEngine *gin.Engine
+	httpHandler http.Handler
+	grpcServer  *grpc.Server
+	grpcPort    string
 }
 
 func (s *server) Run(addr ...string) error {
-	return s.httpEngine.Run(addr...)
+	address := defaultAddress
+	if len(addr) > 0 {
+		address = addr[0]
+	}
+
+	if s.grpcPort == "" {
+		return http.ListenAndServe(address, h2c.NewHandler(s.handler(), &http2.Server{}))
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- s.runGRPC()
+	}()
+	go func() {
+		errs <- http.ListenAndServe(address, s.httpHandler)
+	}()
+	return <-errs
+}
+
+func (s *server) runGRPC() error {
+	listener, err := net.Listen("tcp", s.grpcPort)
+	if err != nil {
+		return err
+	}
+	return s.grpcServer.Serve(listener)
+}
+
+// handler sends the HTTP/2 gRPC calls to the gRPC server and every other request to the REST API
+func (s *server) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get(contentTypeHeader), grpcContentType) {
+			s.grpcServer.ServeHTTP(w, r)
+			return
+		}
+		s.httpHandler.ServeHTTP(w, r)
+	})
 }
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+)
+
+const (
+	// headers set by Kong on the requests it forwards, the consumer authenticated by the jwt plugin and its groups
+	// by the acl plugin. A client reaching the service another way can set them too, so they're only trusted along
+	// with GatewaySecretHeader
+	ConsumerUsernameHeader = "X-Consumer-Username"
+	ConsumerCustomIDHeader = "X-Consumer-Custom-ID"
+	ConsumerGroupsHeader   = "X-Consumer-Groups"
+
+	// GatewaySecretHeader carries the secret Kong shares with the services, proving it forwarded the request
+	GatewaySecretHeader = "X-Gateway-Secret"
+
+	groupsSeparator = ","
+
+	// AnonymousSubject identifies requests that reached the service without an authenticated consumer
+	AnonymousSubject = "anonymous"
+
+	// AdminGroup is the consumer group allowed to run administrative operations
+	AdminGroup = "admin"
+)
+
+type principalKey struct{}
+
+// Principal describes the authenticated consumer of a request
+type Principal struct {
+	Subject string
+	Groups  []string
+}
+
+// HasGroup reports whether the principal belongs to the given group
+func (p Principal) HasGroup(group string) bool {
+	for _, g := range p.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// Gateway reads the principal of the requests forwarded by Kong
+type Gateway interface {
+	// Principal returns the consumer authenticated by Kong, reading the headers of the request with get. It
+	// reports false when the request carries no consumer or wasn't forwarded by Kong
+	Principal(get func(key string) string) (Principal, bool)
+}
+
+type gateway struct {
+	secret []byte
+}
+
+// NewGateway returns the Gateway of the requests carrying secret in GatewaySecretHeader. Without a secret no
+// request is trusted, every one being anonymous
+func NewGateway(secret string) Gateway {
+	return &gateway{secret: []byte(secret)}
+}
+
+func (g *gateway) Principal(get func(key string) string) (Principal, bool) {
+	if len(g.secret) == 0 || subtle.ConstantTimeCompare([]byte(get(GatewaySecretHeader)), g.secret) != 1 {
+		return Principal{}, false
+	}
+	return ConsumerPrincipal(get)
+}
+
+// ConsumerPrincipal builds the principal of the consumer authenticated by Kong, reading its headers with get.
+// It reports false when the request carries no consumer. It doesn't check the request was forwarded by Kong,
+// which Gateway does
+func ConsumerPrincipal(get func(key string) string) (Principal, bool) {
+	principal := Principal{
+		Subject: get(ConsumerUsernameHeader),
+		Groups:  parseGroups(get(ConsumerGroupsHeader)),
+	}
+	if principal.Subject == "" {
+		principal.Subject = get(ConsumerCustomIDHeader)
+	}
+	return principal, principal.Subject != ""
+}
+
+func parseGroups(header string) []string {
+	if header == "" {
+		return nil
+	}
+
+	var groups []string
+	for _, group := range strings.Split(header, groupsSeparator) {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// WithPrincipal returns a copy of ctx carrying the given principal
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Subject returns the subject of the principal stored in ctx or AnonymousSubject when there is none
+func Subject(ctx context.Context) string {
+	p, ok := FromContext(ctx)
+	if !ok || p.Subject == "" {
+		return AnonymousSubject
+	}
+	return p.Subject
+}
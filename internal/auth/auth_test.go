@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	commonAssertion "app/internal/test/assertion/common"
+)
+
+func TestAuth(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Auth Suits")
+}
+
+var _ = Describe("Auth", func() {
+	Context("Reading the principal from context", func() {
+		When("Context carries a principal", func() {
+			It("Should return its subject and groups", func() {
+				ctx := WithPrincipal(commonAssertion.EmptyCtx, Principal{Subject: "john", Groups: []string{"admin"}})
+
+				p, ok := FromContext(ctx)
+
+				Expect(ok).To(BeTrue())
+				Expect(Subject(ctx)).To(Equal("john"))
+				Expect(p.HasGroup("admin")).To(BeTrue())
+				Expect(p.HasGroup("support")).To(BeFalse())
+			})
+		})
+		When("Context has no principal", func() {
+			It("Should return the anonymous subject", func() {
+				_, ok := FromContext(commonAssertion.EmptyCtx)
+
+				Expect(ok).To(BeFalse())
+				Expect(Subject(commonAssertion.EmptyCtx)).To(Equal(AnonymousSubject))
+			})
+		})
+	})
+
+	Context("Reading the principal of a Kong consumer", func() {
+		When("Consumer headers are set", func() {
+			It("Should return the consumer with its groups", func() {
+				headers := map[string]string{
+					ConsumerUsernameHeader: "jane",
+					ConsumerGroupsHeader:   "admin, readers,",
+				}
+
+				p, ok := ConsumerPrincipal(func(key string) string { return headers[key] })
+
+				Expect(ok).To(BeTrue())
+				Expect(p).To(Equal(Principal{Subject: "jane", Groups: []string{"admin", "readers"}}))
+			})
+		})
+		When("Only the custom ID is set", func() {
+			It("Should use it as subject", func() {
+				headers := map[string]string{ConsumerCustomIDHeader: "42"}
+
+				p, ok := ConsumerPrincipal(func(key string) string { return headers[key] })
+
+				Expect(ok).To(BeTrue())
+				Expect(p.Subject).To(Equal("42"))
+			})
+		})
+		When("No consumer is set", func() {
+			It("Should report it", func() {
+				_, ok := ConsumerPrincipal(func(string) string { return "" })
+
+				Expect(ok).To(BeFalse())
+			})
+		})
+	})
+
+	Context("Reading the principal of a request forwarded by Kong", func() {
+		headers := func(secret string) func(key string) string {
+			values := map[string]string{
+				ConsumerUsernameHeader: "jane",
+				ConsumerGroupsHeader:   AdminGroup,
+				GatewaySecretHeader:    secret,
+			}
+			return func(key string) string { return values[key] }
+		}
+
+		When("The request carries the gateway secret", func() {
+			It("Should return the consumer", func() {
+				p, ok := NewGateway("secret").Principal(headers("secret"))
+
+				Expect(ok).To(BeTrue())
+				Expect(p).To(Equal(Principal{Subject: "jane", Groups: []string{AdminGroup}}))
+			})
+		})
+		When("The request carries another secret", func() {
+			It("Should ignore the consumer", func() {
+				_, ok := NewGateway("secret").Principal(headers("forged"))
+
+				Expect(ok).To(BeFalse())
+			})
+		})
+		When("No secret is configured", func() {
+			It("Should ignore the consumer", func() {
+				_, ok := NewGateway("").Principal(headers(""))
+
+				Expect(ok).To(BeFalse())
+			})
+		})
+	})
+})
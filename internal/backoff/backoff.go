@@ -0,0 +1,35 @@
+// Package backoff spaces out the attempts of the operations retried after a failure
+package backoff
+
+import (
+	"context"
+	"time"
+)
+
+// Exponential doubles the delay with every failed attempt, from Min up to Max
+type Exponential struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Delay returns the delay before the attempt following the given number of failed attempts, Min after the first
+func (e Exponential) Delay(attempts int) time.Duration {
+	delay := e.Min
+	for i := 1; i < attempts && delay < e.Max; i++ {
+		delay *= 2
+	}
+	if delay > e.Max {
+		return e.Max
+	}
+	return delay
+}
+
+// Sleep waits for d, returning early once ctx is done
+func Sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
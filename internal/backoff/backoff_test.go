@@ -0,0 +1,43 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBackoff(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Backoff Suits")
+}
+
+var _ = Describe("Backoff", func() {
+	Context("Backing off exponentially", func() {
+		When("An operation keeps failing", func() {
+			It("Should double the delay up to the maximum", func() {
+				exponential := Exponential{Min: time.Second, Max: 5 * time.Minute}
+
+				Expect(exponential.Delay(1)).To(Equal(time.Second))
+				Expect(exponential.Delay(3)).To(Equal(4 * time.Second))
+				Expect(exponential.Delay(100)).To(Equal(5 * time.Minute))
+			})
+		})
+	})
+
+	Context("Sleeping", func() {
+		When("The context is done", func() {
+			It("Should return right away", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				start := time.Now()
+
+				Sleep(ctx, time.Minute)
+
+				Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+			})
+		})
+	})
+})
@@ -0,0 +1,168 @@
+package batch
+
+import (
+	stdErrors "errors"
+	"fmt"
+
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/errors"
+	"app/internal/validation"
+)
+
+// Mode tells how a batch handles the items that fail
+type Mode string
+
+const (
+	// Atomic applies every item of the batch or none of them
+	Atomic Mode = "atomic"
+	// BestEffort applies every item that succeeds and reports the failures item by item
+	BestEffort Mode = "best-effort"
+
+	// MaxSize is the largest number of items accepted in a single batch
+	MaxSize = 1000
+
+	itemsField      = "items"
+	itemFieldFormat = "items[%d].%s"
+	itemErrorFormat = "items[%d]: %w"
+	minSizeRule     = "min"
+	maxSizeRule     = "max"
+	minSizeMessage  = "must have at least 1 item"
+	maxSizeMessage  = "must have at most %d items"
+)
+
+// ParseMode returns the Mode named by mode, defaulting to Atomic when it's empty
+func ParseMode(mode string) (Mode, error) {
+	switch Mode(mode) {
+	case "", Atomic:
+		return Atomic, nil
+	case BestEffort:
+		return BestEffort, nil
+	}
+	return "", errors.ErrInvalidBatchMode
+}
+
+// Batch keeps track of the items of a bulk request rejected along the way, so the caller can
+// report the outcome of every item once the batch is applied
+type Batch[T any] struct {
+	mode     Mode
+	items    []T
+	failures map[int]error
+}
+
+func New[T any](mode Mode, items []T) *Batch[T] {
+	return &Batch[T]{
+		mode:     mode,
+		items:    items,
+		failures: make(map[int]error),
+	}
+}
+
+// ValidateSize checks that the batch has between 1 and MaxSize items
+func (b *Batch[T]) ValidateSize() error {
+	switch {
+	case len(b.items) == 0:
+		return validation.NewError(validation.FieldError{Field: itemsField, Rule: minSizeRule, Message: minSizeMessage})
+	case len(b.items) > MaxSize:
+		return validation.NewError(validation.FieldError{
+			Field:   itemsField,
+			Rule:    maxSizeRule,
+			Message: fmt.Sprintf(maxSizeMessage, MaxSize),
+		})
+	}
+	return nil
+}
+
+// Validate checks the batch size and validates every item. In atomic mode every invalid field of every item
+// is reported in a single error, in best-effort mode the invalid items are rejected
+func (b *Batch[T]) Validate() error {
+	if err := b.ValidateSize(); err != nil {
+		return err
+	}
+
+	var fields []validation.FieldError
+	for i, item := range b.items {
+		err := validation.Struct(item)
+		if err == nil {
+			continue
+		}
+
+		var validationErr *validation.Error
+		if b.mode == BestEffort || !stdErrors.As(err, &validationErr) {
+			if err = b.reject(i, err); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, field := range validationErr.Fields {
+			field.Field = fmt.Sprintf(itemFieldFormat, i, field.Field)
+			fields = append(fields, field)
+		}
+	}
+
+	if len(fields) > 0 {
+		return validation.NewError(fields...)
+	}
+	return nil
+}
+
+// Each calls fn with every item not rejected yet. An error rejects the item, failing the whole batch in atomic mode
+func (b *Batch[T]) Each(fn func(item T) error) error {
+	for i, item := range b.items {
+		if _, rejected := b.failures[i]; rejected {
+			continue
+		}
+		if err := fn(item); err != nil {
+			if err = b.reject(i, err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Apply calls fn once with the items not rejected yet. In best-effort mode an *Error returned by fn
+// rejects the failed items only, any other error fails the whole batch
+func (b *Batch[T]) Apply(fn func(items []T) error) error {
+	pending := make([]T, 0, len(b.items))
+	indexes := make([]int, 0, len(b.items))
+	for i, item := range b.items {
+		if _, rejected := b.failures[i]; !rejected {
+			pending = append(pending, item)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err := fn(pending)
+	var batchErr *Error
+	if b.mode == BestEffort && stdErrors.As(err, &batchErr) {
+		for i, itemErr := range batchErr.Errors {
+			b.failures[indexes[i]] = itemErr
+		}
+		return nil
+	}
+	return err
+}
+
+// Result reports the outcome of every item, using status for the applied ones
+func (b *Batch[T]) Result(status int, id func(item T) uuid.UUID) *Result {
+	result := &Result{
+		Mode:  b.mode,
+		Items: make([]ItemResult, 0, len(b.items)),
+	}
+	for i, item := range b.items {
+		result.add(i, id(item), status, b.failures[i])
+	}
+	return result
+}
+
+func (b *Batch[T]) reject(index int, err error) error {
+	if b.mode != BestEffort {
+		return fmt.Errorf(itemErrorFormat, index, err)
+	}
+	b.failures[index] = err
+	return nil
+}
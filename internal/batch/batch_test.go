@@ -0,0 +1,145 @@
+package batch
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/errors"
+	"app/internal/validation"
+)
+
+func TestBatch(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Batch Suits")
+}
+
+type item struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name" validate:"required"`
+}
+
+func itemID(i *item) uuid.UUID {
+	if i == nil {
+		return uuid.Nil
+	}
+	return i.ID
+}
+
+var (
+	firstID  = uuid.FromStringOrNil("481da253-2dda-46e5-9963-58611eb72d7b")
+	secondID = uuid.FromStringOrNil("2a2acd06-c4ce-4bce-aaf9-09a379f02cf8")
+)
+
+var _ = Describe("Batch", func() {
+	Context("Parsing the batch mode", func() {
+		When("Mode is empty", func() {
+			It("Should default to atomic", func() {
+				mode, err := ParseMode("")
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(mode).To(Equal(Atomic))
+			})
+		})
+		When("Mode is unknown", func() {
+			It("Should return an invalid batch mode error", func() {
+				_, err := ParseMode("eventually")
+
+				Expect(err).To(Equal(errors.ErrInvalidBatchMode))
+			})
+		})
+	})
+
+	Context("Validating a batch", func() {
+		When("Batch is empty", func() {
+			It("Should return a validation error", func() {
+				err := New(Atomic, []*item{}).Validate()
+
+				Expect(err).To(MatchError(errors.ErrValidation))
+			})
+		})
+		When("Batch is atomic", func() {
+			It("Should report every invalid field with the item index", func() {
+				err := New(Atomic, []*item{{Name: "valid"}, {}, nil}).Validate()
+
+				Expect(err).To(MatchError(errors.ErrValidation))
+				Expect(err.(*validation.Error).Fields).To(Equal([]validation.FieldError{
+					{Field: "items[1].name", Rule: "required", Message: "is required"},
+					{Field: "items[2].body", Rule: "required", Message: "is required"},
+				}))
+			})
+		})
+		When("Batch is best-effort", func() {
+			It("Should reject the invalid items only", func() {
+				b := New(BestEffort, []*item{{ID: firstID, Name: "valid"}, {ID: secondID}})
+
+				Expect(b.Validate()).To(Succeed())
+
+				var applied []*item
+				Expect(b.Apply(func(items []*item) error {
+					applied = items
+					return nil
+				})).To(Succeed())
+				Expect(applied).To(HaveLen(1))
+
+				result := b.Result(http.StatusCreated, itemID)
+				Expect(result.Succeeded).To(Equal(1))
+				Expect(result.Failed).To(Equal(1))
+				Expect(result.Status(http.StatusCreated)).To(Equal(http.StatusMultiStatus))
+				Expect(result.Items[1]).To(Equal(ItemResult{
+					Index:  1,
+					ID:     secondID.String(),
+					Status: http.StatusUnprocessableEntity,
+					Error:  "validation failed: name is required",
+					Fields: []validation.FieldError{{Field: "name", Rule: "required", Message: "is required"}},
+				}))
+			})
+		})
+	})
+
+	Context("Applying a batch", func() {
+		When("Storage reports failed items in best-effort mode", func() {
+			It("Should map the failures back to the request indexes", func() {
+				b := New(BestEffort, []*item{{Name: "rejected"}, {ID: firstID}, {ID: secondID}})
+				Expect(b.Each(func(i *item) error {
+					if i.ID == uuid.Nil {
+						return errors.ErrClientSuppliedID
+					}
+					return nil
+				})).To(Succeed())
+
+				err := b.Apply(func(items []*item) error {
+					Expect(items).To(HaveLen(2))
+					batchErr := NewError()
+					batchErr.Add(1, errors.ErrDuplicateBatchItem)
+					return batchErr
+				})
+
+				Expect(err).ShouldNot(HaveOccurred())
+				result := b.Result(http.StatusOK, itemID)
+				Expect(result.Status(http.StatusOK)).To(Equal(http.StatusMultiStatus))
+				Expect(result.Items[0].Status).To(Equal(http.StatusBadRequest))
+				Expect(result.Items[1].Status).To(Equal(http.StatusOK))
+				Expect(result.Items[2].Status).To(Equal(http.StatusUnprocessableEntity))
+			})
+		})
+		When("An item fails in atomic mode", func() {
+			It("Should fail the whole batch", func() {
+				b := New(Atomic, []*item{{ID: firstID}, {}})
+
+				err := b.Each(func(i *item) error {
+					if i.ID == uuid.Nil {
+						return errors.ErrClientSuppliedID
+					}
+					return nil
+				})
+
+				Expect(err).To(MatchError(errors.ErrClientSuppliedID))
+				Expect(err.Error()).To(HavePrefix("items[1]"))
+			})
+		})
+	})
+})
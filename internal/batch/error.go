@@ -0,0 +1,33 @@
+package batch
+
+import "fmt"
+
+const errorFormat = "%d batch items failed"
+
+// Error reports the items of a best-effort batch that failed, keyed by their index in the batch
+type Error struct {
+	Errors map[int]error
+}
+
+func NewError() *Error {
+	return &Error{
+		Errors: make(map[int]error),
+	}
+}
+
+// Add records the failure of the item at index
+func (e *Error) Add(index int, err error) {
+	e.Errors[index] = err
+}
+
+// Err returns e when at least one item failed and nil otherwise
+func (e *Error) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf(errorFormat, len(e.Errors))
+}
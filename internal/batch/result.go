@@ -0,0 +1,61 @@
+package batch
+
+import (
+	stdErrors "errors"
+	"net/http"
+
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/errors"
+	"app/internal/validation"
+)
+
+// ItemResult is the outcome of a single item, identified by its index in the request
+type ItemResult struct {
+	Index  int                     `json:"index"`
+	ID     string                  `json:"id,omitempty"`
+	Status int                     `json:"status"`
+	Error  string                  `json:"error,omitempty"`
+	Fields []validation.FieldError `json:"fields,omitempty"`
+}
+
+// Result is the outcome of a batch, with one entry per requested item in request order
+type Result struct {
+	Mode      Mode         `json:"mode"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Items     []ItemResult `json:"items"`
+}
+
+// Status returns succeeded when every item succeeded and 207 Multi-Status otherwise
+func (r *Result) Status(succeeded int) int {
+	if r.Failed > 0 {
+		return http.StatusMultiStatus
+	}
+	return succeeded
+}
+
+func (r *Result) add(index int, id uuid.UUID, status int, err error) {
+	item := ItemResult{
+		Index:  index,
+		Status: status,
+	}
+	if id != uuid.Nil {
+		item.ID = id.String()
+	}
+
+	if err == nil {
+		r.Succeeded++
+		r.Items = append(r.Items, item)
+		return
+	}
+
+	item.Status = errors.GetStatus(err)
+	item.Error = err.Error()
+	var validationErr *validation.Error
+	if stdErrors.As(err, &validationErr) {
+		item.Fields = validationErr.Fields
+	}
+	r.Failed++
+	r.Items = append(r.Items, item)
+}
@@ -0,0 +1,292 @@
+// Package cacheaside loads values through a storage.Cache, protecting the loader from a stampede of the callers
+// missing the same key: the loads of a process are coalesced, those of the replicas optionally serialized by a
+// lock, and a value can be recomputed ahead of its expiration or served stale while it's recomputed
+package cacheaside
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"app/internal/lock"
+	"app/internal/storage"
+)
+
+const (
+	DefaultLoadTimeout = 10 * time.Second
+
+	lockPollInterval = 50 * time.Millisecond
+
+	failedToRefresh = "failed to refresh cached key %s: %v\n"
+	failedToLock    = "failed to lock cached key %s, loading it without the lock: %v\n"
+)
+
+// Loader returns the values T cached aside, loading and caching them on a miss
+type Loader[T interface{}] interface {
+	// Load returns the value cached at key, or the one returned by load, telling whether it was cached. The
+	// cache failing isn't an error, the value being loaded as on a miss
+	Load(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, bool, error)
+}
+
+type DependenciesNode struct {
+	Cache storage.Cache
+	// Locker is optional, serializing the loads of a key across the replicas when set
+	Locker lock.Locker
+}
+
+type Config struct {
+	// TTL expires the cached values, which are kept until removed when zero. The other options rely on it
+	TTL time.Duration
+	// EarlyExpiration is the beta of the probabilistic early expiration, a caller recomputing a value before it
+	// expires with a probability growing as it gets closer to expiring and as it took longer to load. Zero
+	// disables it, and one is the usual value
+	EarlyExpiration float64
+	// StaleWhileRevalidate serves an expired value for that long after it expired, recomputing it in the
+	// background. Zero disables it
+	StaleWhileRevalidate time.Duration
+	// LockWait is how long a replica waits for the one holding the lock of a key to cache its value before
+	// loading it too
+	LockWait time.Duration
+	// LoadTimeout bounds a load shared by the callers of a key, which outlives the caller that started it.
+	// DefaultLoadTimeout when zero
+	LoadTimeout time.Duration
+}
+
+// entry is a cached value along with what's needed to expire it
+type entry[T interface{}] struct {
+	Value     T         `json:"value"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Delta is how long loading the value took, scaling its early expiration
+	Delta time.Duration `json:"delta"`
+}
+
+type loader[T interface{}] struct {
+	deps   *DependenciesNode
+	config Config
+	group  singleflight.Group
+	now    func() time.Time
+	random func() float64
+}
+
+// New returns the Loader of the values T. Without a TTL the values are cached as is, like before they expired
+func New[T interface{}](deps *DependenciesNode, config Config) Loader[T] {
+	if config.LoadTimeout <= 0 {
+		config.LoadTimeout = DefaultLoadTimeout
+	}
+	return &loader[T]{
+		deps:   deps,
+		config: config,
+		now:    time.Now,
+		random: rand.Float64,
+	}
+}
+
+func (l *loader[T]) Load(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, bool, error) {
+	data, err := l.deps.Cache.Get(key)
+	if err != nil {
+		// the cache is unavailable, the value is loaded without being cached
+		value, err := l.coalesce(ctx, key, func(ctx context.Context) (interface{}, error) {
+			return load(ctx)
+		})
+		return value, false, err
+	}
+
+	if cached, ok := l.decode(data); ok {
+		switch {
+		case l.fresh(cached):
+			return cached.Value, true, nil
+		case l.servable(cached):
+			go l.refresh(key, load)
+			return cached.Value, true, nil
+		}
+	}
+
+	value, err := l.recompute(ctx, key, load)
+	return value, false, err
+}
+
+// fresh tells whether cached is served without being recomputed. Its expiration is brought forward by a random
+// amount scaled by its Delta, so the callers close to it rarely recompute it at the same time
+func (l *loader[T]) fresh(cached entry[T]) bool {
+	if l.config.TTL <= 0 {
+		return true
+	}
+	expiresAt := cached.ExpiresAt
+	if l.config.EarlyExpiration > 0 {
+		// random is in [0, 1), the logarithm of 1 - random being negative or zero
+		early := -float64(cached.Delta) * l.config.EarlyExpiration * math.Log(1-l.random())
+		expiresAt = expiresAt.Add(-time.Duration(early))
+	}
+	return l.now().Before(expiresAt)
+}
+
+func (l *loader[T]) expired(cached entry[T]) bool {
+	return l.config.TTL > 0 && !l.now().Before(cached.ExpiresAt)
+}
+
+// servable tells whether cached can be served while it's recomputed in the background
+func (l *loader[T]) servable(cached entry[T]) bool {
+	return l.config.StaleWhileRevalidate > 0 && l.now().Before(cached.ExpiresAt.Add(l.config.StaleWhileRevalidate))
+}
+
+func (l *loader[T]) refresh(key string, load func(ctx context.Context) (T, error)) {
+	if _, err := l.recompute(context.Background(), key, load); err != nil {
+		log.Printf(failedToRefresh, key, err)
+	}
+}
+
+// recompute loads the value of key once for the concurrent callers of the process, holding the lock of key when
+// there's a Locker. A replica that doesn't get the lock waits for the value cached by the holder, and one that
+// fails to take it loads the value without it
+func (l *loader[T]) recompute(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	return l.coalesce(ctx, key, func(ctx context.Context) (interface{}, error) {
+		if l.deps.Locker == nil {
+			return l.store(ctx, key, load)
+		}
+
+		var (
+			value   T
+			loadErr error
+		)
+		acquired, err := l.deps.Locker.Hold(ctx, key, func(ctx context.Context) {
+			value, loadErr = l.store(ctx, key, load)
+		})
+		if err != nil {
+			log.Printf(failedToLock, key, err)
+			return l.store(ctx, key, load)
+		}
+		if acquired {
+			return value, loadErr
+		}
+
+		if cached, ok := l.await(ctx, key); ok {
+			return cached, nil
+		}
+		return l.store(ctx, key, load)
+	})
+}
+
+// coalesce runs fn once for the concurrent callers of the process asking for key. fn runs with the values of the
+// ctx of the caller starting it, but is only cancelled by LoadTimeout, every caller giving up on it once its own
+// ctx is done
+func (l *loader[T]) coalesce(ctx context.Context, key string,
+	fn func(ctx context.Context) (interface{}, error)) (T, error) {
+	results := l.group.DoChan(key, func() (interface{}, error) {
+		shared, cancel := context.WithTimeout(detached{values: ctx}, l.config.LoadTimeout)
+		defer cancel()
+		return fn(shared)
+	})
+
+	select {
+	case <-ctx.Done():
+		return *new(T), ctx.Err()
+	case result := <-results:
+		if result.Err != nil {
+			return *new(T), result.Err
+		}
+		if result.Shared {
+			// every caller gets its own copy, free to change it
+			return clone(result.Val.(T))
+		}
+		return result.Val.(T), nil
+	}
+}
+
+// detached carries the values of a ctx, like its principal, without its deadline and cancellation
+type detached struct {
+	values context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (detached) Err() error {
+	return nil
+}
+
+func (d detached) Value(key interface{}) interface{} {
+	return d.values.Value(key)
+}
+
+func clone[T interface{}](value T) (T, error) {
+	var copied T
+	data, err := json.Marshal(value)
+	if err != nil {
+		return copied, err
+	}
+	return copied, json.Unmarshal(data, &copied)
+}
+
+// await polls the cache for the value of key until LockWait elapses
+func (l *loader[T]) await(ctx context.Context, key string) (T, bool) {
+	deadline := time.NewTimer(l.config.LockWait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return *new(T), false
+		case <-deadline.C:
+			return *new(T), false
+		case <-ticker.C:
+			data, err := l.deps.Cache.Get(key)
+			if err != nil {
+				continue
+			}
+			if cached, ok := l.decode(data); ok && !l.expired(cached) {
+				return cached.Value, true
+			}
+		}
+	}
+}
+
+// store loads the value of key and caches it
+func (l *loader[T]) store(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	start := l.now()
+	value, err := load(ctx)
+	if err != nil {
+		return *new(T), err
+	}
+
+	// the value is returned even when it can't be cached, the cache being an optimization
+	if l.config.TTL <= 0 {
+		_ = l.deps.Cache.Set(key, value)
+		return value, nil
+	}
+
+	now := l.now()
+	_ = l.deps.Cache.Set(key, entry[T]{
+		Value:     value,
+		ExpiresAt: now.Add(l.config.TTL),
+		Delta:     now.Sub(start),
+	})
+	return value, nil
+}
+
+// decode returns the entry cached as data, the value alone when there's no TTL. A value cached in another format,
+// like before the TTL was set, is a miss
+func (l *loader[T]) decode(data []byte) (entry[T], bool) {
+	var cached entry[T]
+	if data == nil {
+		return cached, false
+	}
+	if l.config.TTL <= 0 {
+		return cached, json.Unmarshal(data, &cached.Value) == nil
+	}
+	if err := json.Unmarshal(data, &cached); err != nil || cached.ExpiresAt.IsZero() {
+		return cached, false
+	}
+	return cached, true
+}
@@ -0,0 +1,301 @@
+package cacheaside
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	cacheMemory "app/infra/cache/memory"
+	lockMemory "app/infra/lock/memory"
+	"app/internal/lock"
+	"app/internal/storage"
+	errorsAssertion "app/internal/test/assertion/errors"
+	storageMock "app/internal/test/mocks/storage"
+)
+
+func TestCacheAside(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cache Aside Suits")
+}
+
+var _ = Describe("Cache Aside", func() {
+	const key = "key"
+
+	var (
+		ctx   context.Context
+		cache storage.Cache
+		now   time.Time
+		loads int32
+	)
+
+	newLoader := func(deps *DependenciesNode, config Config) *loader[string] {
+		l := New[string](deps, config).(*loader[string])
+		l.now = func() time.Time { return now }
+		return l
+	}
+
+	// loadValue counts the loads, returning value
+	loadValue := func(value string) func(ctx context.Context) (string, error) {
+		return func(context.Context) (string, error) {
+			atomic.AddInt32(&loads, 1)
+			return value, nil
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cache = cacheMemory.New(cacheMemory.Config{})
+		now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		atomic.StoreInt32(&loads, 0)
+	})
+
+	When("the value isn't cached", func() {
+		It("Should load and cache it", func() {
+			l := newLoader(&DependenciesNode{Cache: cache}, Config{})
+
+			value, cached, err := l.Load(ctx, key, loadValue("loaded"))
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).To(Equal("loaded"))
+			Expect(cached).To(BeFalse())
+			Expect(cache.Get(key)).To(MatchJSON(`"loaded"`))
+
+			value, cached, err = l.Load(ctx, key, loadValue("reloaded"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).To(Equal("loaded"))
+			Expect(cached).To(BeTrue())
+		})
+	})
+
+	When("loading the value fails", func() {
+		It("Should return the error without caching", func() {
+			l := newLoader(&DependenciesNode{Cache: cache}, Config{})
+
+			_, _, err := l.Load(ctx, key, func(context.Context) (string, error) {
+				return "", errorsAssertion.ErrGeneric
+			})
+
+			Expect(err).To(MatchError(errorsAssertion.ErrGeneric))
+			Expect(cache.Get(key)).To(BeNil())
+		})
+	})
+
+	When("the cache fails", func() {
+		It("Should load the value without caching it", func() {
+			failing := &storageMock.Cache{}
+			failing.On("Get", key).Return(nil, errorsAssertion.ErrGeneric)
+			l := newLoader(&DependenciesNode{Cache: failing}, Config{})
+
+			value, cached, err := l.Load(ctx, key, loadValue("loaded"))
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).To(Equal("loaded"))
+			Expect(cached).To(BeFalse())
+			failing.AssertNotCalled(GinkgoT(), "Set", key, "loaded")
+		})
+	})
+
+	When("the lock of the key can't be taken", func() {
+		It("Should load the value without it", func() {
+			l := newLoader(&DependenciesNode{Cache: cache, Locker: failingLocker{}}, Config{})
+
+			value, _, err := l.Load(ctx, key, loadValue("loaded"))
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).To(Equal("loaded"))
+			Expect(cache.Get(key)).To(MatchJSON(`"loaded"`))
+		})
+	})
+
+	When("callers miss the same key concurrently", func() {
+		It("Should load it once", func() {
+			l := newLoader(&DependenciesNode{Cache: cache}, Config{})
+			release := make(chan struct{})
+			load := func(context.Context) (string, error) {
+				atomic.AddInt32(&loads, 1)
+				<-release
+				return "loaded", nil
+			}
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer GinkgoRecover()
+					value, _, err := l.Load(ctx, key, load)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(value).To(Equal("loaded"))
+				}()
+			}
+			Eventually(func() int32 { return atomic.LoadInt32(&loads) }).Should(Equal(int32(1)))
+			time.Sleep(10 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			Expect(atomic.LoadInt32(&loads)).To(Equal(int32(1)))
+		})
+	})
+
+	When("the caller starting a shared load gives up", func() {
+		It("Should go on loading the value for the others", func() {
+			l := newLoader(&DependenciesNode{Cache: cache}, Config{})
+			started, release := make(chan struct{}), make(chan struct{})
+			var loadErr error
+			load := func(ctx context.Context) (string, error) {
+				close(started)
+				select {
+				case <-release:
+				case <-ctx.Done():
+					loadErr = ctx.Err()
+				}
+				return "loaded", loadErr
+			}
+			first, giveUp := context.WithCancel(ctx)
+			gaveUp := make(chan error, 1)
+			go func() {
+				_, _, err := l.Load(first, key, load)
+				gaveUp <- err
+			}()
+			Eventually(started).Should(BeClosed())
+
+			giveUp()
+			Eventually(gaveUp).Should(Receive(MatchError(context.Canceled)))
+			loaded := make(chan string, 1)
+			go func() {
+				value, _, _ := l.Load(ctx, key, load)
+				loaded <- value
+			}()
+			close(release)
+
+			Eventually(loaded).Should(Receive(Equal("loaded")))
+			Expect(loadErr).ShouldNot(HaveOccurred())
+		})
+	})
+
+	When("a shared load outlasts the load timeout", func() {
+		It("Should cancel it", func() {
+			l := newLoader(&DependenciesNode{Cache: cache}, Config{LoadTimeout: 10 * time.Millisecond})
+
+			_, _, err := l.Load(ctx, key, func(ctx context.Context) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			})
+
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+		})
+	})
+
+	Context("Expiring the values", func() {
+		When("the TTL elapses", func() {
+			It("Should load the value again", func() {
+				l := newLoader(&DependenciesNode{Cache: cache}, Config{TTL: time.Minute})
+				_, _, err := l.Load(ctx, key, loadValue("first"))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				now = now.Add(time.Minute)
+				value, cached, err := l.Load(ctx, key, loadValue("second"))
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(value).To(Equal("second"))
+				Expect(cached).To(BeFalse())
+			})
+		})
+		When("the value is cached in another format", func() {
+			It("Should load it again", func() {
+				l := newLoader(&DependenciesNode{Cache: cache}, Config{TTL: time.Minute})
+				Expect(cache.Set(key, "plain")).To(Succeed())
+
+				value, _, err := l.Load(ctx, key, loadValue("loaded"))
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(value).To(Equal("loaded"))
+			})
+		})
+		When("the early expiration draws a recomputation", func() {
+			It("Should load the value before it expires", func() {
+				l := newLoader(&DependenciesNode{Cache: cache}, Config{TTL: time.Minute, EarlyExpiration: 1})
+				Expect(cache.Set(key, entry[string]{
+					Value:     "first",
+					ExpiresAt: now.Add(time.Second),
+					Delta:     time.Second,
+				})).To(Succeed())
+
+				// -ln(1 - 0.9) is about 2.3 deltas before the expiration
+				l.random = func() float64 { return 0.9 }
+				value, _, err := l.Load(ctx, key, loadValue("second"))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(value).To(Equal("second"))
+
+				l.random = func() float64 { return 0 }
+				value, cached, err := l.Load(ctx, key, loadValue("third"))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(value).To(Equal("second"))
+				Expect(cached).To(BeTrue())
+			})
+		})
+		When("the value expired within the stale window", func() {
+			It("Should serve it while reloading it in the background", func() {
+				l := newLoader(&DependenciesNode{Cache: cache}, Config{
+					TTL:                  time.Minute,
+					StaleWhileRevalidate: time.Minute,
+				})
+				_, _, err := l.Load(ctx, key, loadValue("first"))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				now = now.Add(90 * time.Second)
+				value, cached, err := l.Load(ctx, key, loadValue("second"))
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(value).To(Equal("first"))
+				Expect(cached).To(BeTrue())
+				Eventually(func() string {
+					value, _, _ := l.Load(ctx, key, loadValue("third"))
+					return value
+				}).Should(Equal("second"))
+			})
+		})
+	})
+
+	When("another replica holds the lock of the key", func() {
+		It("Should wait for the value it caches", func() {
+			locker := lockMemory.New()
+			l := newLoader(&DependenciesNode{Cache: cache, Locker: locker}, Config{LockWait: time.Second})
+
+			held := make(chan struct{})
+			go holdLock(locker, key, held, func() {
+				Expect(cache.Set(key, "other")).To(Succeed())
+			})
+			<-held
+
+			value, cached, err := l.Load(ctx, key, loadValue("loaded"))
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).To(Equal("other"))
+			Expect(cached).To(BeFalse())
+			Expect(atomic.LoadInt32(&loads)).To(BeZero())
+		})
+	})
+})
+
+// holdLock holds the lock of key, signaling held, then runs fn and releases the lock
+func holdLock(locker lock.Locker, key string, held chan struct{}, fn func()) {
+	defer GinkgoRecover()
+	_, _ = locker.Hold(context.Background(), key, func(context.Context) {
+		close(held)
+		time.Sleep(100 * time.Millisecond)
+		fn()
+	})
+}
+
+// failingLocker fails to reach the server of the locks
+type failingLocker struct{}
+
+func (failingLocker) Hold(context.Context, string, func(ctx context.Context)) (bool, error) {
+	return false, errorsAssertion.ErrGeneric
+}
@@ -0,0 +1,158 @@
+package changefeed
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"app/internal/identifier"
+)
+
+const (
+	DefaultHistorySize = 1000
+
+	subscriberBufferSize = 64
+	retryDelay           = time.Second
+
+	failedToReceiveEvents = "failed to receive change feed events: %v\n"
+)
+
+// Publisher publishes the changes made by a service
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Broker delivers the published events to the subscribers of every replica sharing its Transport
+type Broker interface {
+	Publisher
+	// Subscribe returns the events matching filter, starting with the recent ones published after lastEventID
+	// when it's set. The channel is closed once ctx is done or when the subscriber can't keep up, in which case
+	// it should subscribe again from the last event it received
+	Subscribe(ctx context.Context, filter Filter, lastEventID string) (<-chan Event, error)
+	// Run receives the events sent by the other replicas until ctx is done
+	Run(ctx context.Context)
+}
+
+// Transport carries every published event to all the replicas, including the one publishing it
+type Transport interface {
+	Send(ctx context.Context, event Event) error
+	Receive(ctx context.Context, deliver func(Event)) error
+}
+
+type subscriber struct {
+	events chan Event
+	filter Filter
+}
+
+type broker struct {
+	mu          sync.Mutex
+	transport   Transport
+	ids         identifier.Generator
+	history     []Event
+	historySize int
+	subscribers map[*subscriber]struct{}
+}
+
+// New returns a Broker keeping the last historySize events for resuming subscribers.
+// Events only reach the subscribers of this process when transport is nil
+func New(historySize int, transport Transport) Broker {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	return &broker{
+		transport:   transport,
+		ids:         identifier.NewULIDGenerator(),
+		historySize: historySize,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+func (b *broker) Publish(ctx context.Context, event Event) error {
+	event.ID = b.ids.NewID().String()
+	event.Time = time.Now().UTC()
+
+	if b.transport == nil {
+		b.deliver(event)
+		return nil
+	}
+	return b.transport.Send(ctx, event)
+}
+
+func (b *broker) Subscribe(ctx context.Context, filter Filter, lastEventID string) (<-chan Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []Event
+	if lastEventID != "" {
+		for _, event := range b.history {
+			if event.ID > lastEventID && filter.Matches(event) {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	sub := &subscriber{
+		events: make(chan Event, len(replay)+subscriberBufferSize),
+		filter: filter,
+	}
+	for _, event := range replay {
+		sub.events <- event
+	}
+	b.subscribers[sub] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		b.unsubscribe(sub)
+	}()
+	return sub.events, nil
+}
+
+func (b *broker) Run(ctx context.Context) {
+	if b.transport == nil {
+		return
+	}
+
+	for ctx.Err() == nil {
+		if err := b.transport.Receive(ctx, b.deliver); err != nil && ctx.Err() == nil {
+			log.Printf(failedToReceiveEvents, err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryDelay):
+			}
+		}
+	}
+}
+
+// deliver records event and hands it to the matching subscribers, dropping those whose buffer is full
+func (b *broker) deliver(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+func (b *broker) unsubscribe(sub *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
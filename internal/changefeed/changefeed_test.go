@@ -0,0 +1,274 @@
+package changefeed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/errors"
+)
+
+func TestChangeFeed(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Change Feed Suits")
+}
+
+var (
+	firstID  = uuid.FromStringOrNil("481da253-2dda-46e5-9963-58611eb72d7b")
+	secondID = uuid.FromStringOrNil("2a2acd06-c4ce-4bce-aaf9-09a379f02cf8")
+)
+
+// memoryTransport shares events between the brokers of a test like redis does between replicas
+type memoryTransport struct {
+	mu       sync.Mutex
+	delivers []func(Event)
+	ready    chan struct{}
+}
+
+func newMemoryTransport(receivers int) *memoryTransport {
+	return &memoryTransport{ready: make(chan struct{}, receivers)}
+}
+
+func (t *memoryTransport) Send(_ context.Context, event Event) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, deliver := range t.delivers {
+		deliver(event)
+	}
+	return nil
+}
+
+func (t *memoryTransport) Receive(ctx context.Context, deliver func(Event)) error {
+	t.mu.Lock()
+	t.delivers = append(t.delivers, deliver)
+	t.mu.Unlock()
+	t.ready <- struct{}{}
+
+	<-ctx.Done()
+	return nil
+}
+
+func publish(b Broker, eventType EventType, id uuid.UUID) {
+	event, err := NewEvent(eventType, id, nil)
+	Expect(err).ShouldNot(HaveOccurred())
+	Expect(b.Publish(context.Background(), event)).To(Succeed())
+}
+
+var _ = Describe("Change Feed", func() {
+	Context("Parsing a filter", func() {
+		When("Types and IDs are valid", func() {
+			It("Should return the filter", func() {
+				filter, err := ParseFilter("created, deleted", firstID.String())
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(filter.Types).To(Equal([]EventType{Created, Deleted}))
+				Expect(filter.IDs).To(Equal([]uuid.UUID{firstID}))
+			})
+		})
+		When("A type is unknown", func() {
+			It("Should return an invalid parameter error", func() {
+				_, err := ParseFilter("created,moved", "")
+
+				Expect(err).To(MatchError(errors.ErrInvalidParameter))
+			})
+		})
+		When("An ID is invalid", func() {
+			It("Should return an invalid parameter error", func() {
+				_, err := ParseFilter("", "15664c2f")
+
+				Expect(err).To(MatchError(errors.ErrInvalidParameter))
+			})
+		})
+	})
+
+	Context("Subscribing to the broker", func() {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+			b      Broker
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			b = New(DefaultHistorySize, nil)
+		})
+
+		AfterEach(func() {
+			cancel()
+		})
+
+		When("Events are published", func() {
+			It("Should deliver those matching the filter", func() {
+				events, err := b.Subscribe(ctx, Filter{Types: []EventType{Deleted}}, "")
+				Expect(err).ShouldNot(HaveOccurred())
+
+				publish(b, Created, firstID)
+				publish(b, Deleted, firstID)
+
+				var event Event
+				Eventually(events).Should(Receive(&event))
+				Expect(event.Type).To(Equal(Deleted))
+				Expect(event.ItemID).To(Equal(firstID))
+				Expect(event.ID).NotTo(BeEmpty())
+				Consistently(events).ShouldNot(Receive())
+			})
+		})
+		When("Subscriber resumes from an event ID", func() {
+			It("Should replay the events published after it", func() {
+				publish(b, Created, firstID)
+				publish(b, Created, secondID)
+				publish(b, Updated, firstID)
+
+				all, err := b.Subscribe(ctx, Filter{}, "0")
+				Expect(err).ShouldNot(HaveOccurred())
+				var first Event
+				Eventually(all).Should(Receive(&first))
+
+				events, err := b.Subscribe(ctx, Filter{IDs: []uuid.UUID{firstID}}, first.ID)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				var event Event
+				Eventually(events).Should(Receive(&event))
+				Expect(event.Type).To(Equal(Updated))
+				Consistently(events).ShouldNot(Receive())
+			})
+		})
+		When("Subscriber can't keep up", func() {
+			It("Should close its channel", func() {
+				events, err := b.Subscribe(ctx, Filter{}, "")
+				Expect(err).ShouldNot(HaveOccurred())
+
+				for i := 0; i <= subscriberBufferSize; i++ {
+					publish(b, Created, firstID)
+				}
+
+				Expect(events).To(HaveLen(subscriberBufferSize))
+				Eventually(events).Should(BeClosed())
+			})
+		})
+		When("Context is done", func() {
+			It("Should close the channel", func() {
+				events, err := b.Subscribe(ctx, Filter{}, "")
+				Expect(err).ShouldNot(HaveOccurred())
+
+				cancel()
+
+				Eventually(events).Should(BeClosed())
+			})
+		})
+	})
+
+	Context("Fanning out across replicas", func() {
+		When("An event is published on a replica", func() {
+			It("Should reach the subscribers of every replica", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				transport := newMemoryTransport(2)
+				first, second := New(DefaultHistorySize, transport), New(DefaultHistorySize, transport)
+				go first.Run(ctx)
+				go second.Run(ctx)
+				Eventually(transport.ready).Should(HaveLen(2))
+
+				events, err := second.Subscribe(ctx, Filter{}, "")
+				Expect(err).ShouldNot(HaveOccurred())
+
+				publish(first, Restored, secondID)
+
+				var event Event
+				Eventually(events).Should(Receive(&event))
+				Expect(event.Type).To(Equal(Restored))
+				Expect(event.ItemID).To(Equal(secondID))
+			})
+		})
+	})
+
+	Context("Writing events", func() {
+		var (
+			event  Event
+			events chan Event
+		)
+
+		BeforeEach(func() {
+			var err error
+			event, err = NewEvent(Created, firstID, map[string]string{"name": "sample"})
+			Expect(err).ShouldNot(HaveOccurred())
+			event.ID = "01"
+			events = make(chan Event, 1)
+			events <- event
+			close(events)
+		})
+
+		When("Client reads Server-Sent Events", func() {
+			It("Should write the event with its ID and type", func() {
+				w := httptest.NewRecorder()
+
+				err := WriteSSE(context.Background(), w, events)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(w.Header().Get(contentTypeHeader)).To(Equal(eventStreamType))
+				Expect(w.Body.String()).To(HavePrefix("id: 01\nevent: created\ndata: {"))
+				Expect(w.Body.String()).To(ContainSubstring(`"data":{"name":"sample"}`))
+			})
+		})
+		When("Client connects with WebSocket", func() {
+			It("Should send the event as a JSON message", func() {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					Expect(IsWebSocket(r)).To(BeTrue())
+					_ = WriteWebSocket(r.Context(), w, r, Origins{"https://app.example.com"}, events)
+				}))
+				defer server.Close()
+
+				conn, _, err := websocket.DefaultDialer.Dial(strings.Replace(server.URL, "http", "ws", 1), nil)
+				Expect(err).ShouldNot(HaveOccurred())
+				defer conn.Close()
+				_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+
+				var received Event
+				Expect(conn.ReadJSON(&received)).To(Succeed())
+				Expect(received.ID).To(Equal(event.ID))
+				Expect(received.ItemID).To(Equal(firstID))
+			})
+		})
+	})
+
+	Context("Checking the origin of a WebSocket", func() {
+		origins := Origins{"https://app.example.com"}
+		request := func(origin string) *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "http://service.example.com/api/v1/items/stream", nil)
+			if origin != "" {
+				r.Header.Set(originHeader, origin)
+			}
+			return r
+		}
+
+		When("The page is of an allowed origin", func() {
+			It("Should allow it", func() {
+				Expect(origins.Allow(request("https://app.example.com"))).To(BeTrue())
+			})
+		})
+		When("The page is served by the service", func() {
+			It("Should allow it", func() {
+				Expect(origins.Allow(request("http://service.example.com"))).To(BeTrue())
+			})
+		})
+		When("The client isn't a browser", func() {
+			It("Should allow it", func() {
+				Expect(origins.Allow(request(""))).To(BeTrue())
+			})
+		})
+		When("The page is of another origin", func() {
+			It("Should refuse it", func() {
+				Expect(origins.Allow(request("https://evil.example.com"))).To(BeFalse())
+			})
+		})
+	})
+})
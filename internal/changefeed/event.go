@@ -0,0 +1,45 @@
+package changefeed
+
+import (
+	"encoding/json"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+type EventType string
+
+const (
+	Created  EventType = "created"
+	Updated  EventType = "updated"
+	Deleted  EventType = "deleted"
+	Restored EventType = "restored"
+)
+
+// Event describes a change of an item. IDs are time ordered, so a subscriber can resume after the last one it saw
+type Event struct {
+	ID     string          `json:"id"`
+	Type   EventType       `json:"type"`
+	ItemID uuid.UUID       `json:"itemId"`
+	Time   time.Time       `json:"time"`
+	Data   json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+}
+
+// NewEvent returns an event about the item with the given ID, data being the item itself when not nil.
+// The ID and time are set once the event is published
+func NewEvent(eventType EventType, itemID uuid.UUID, data interface{}) (Event, error) {
+	event := Event{
+		Type:   eventType,
+		ItemID: itemID,
+	}
+	if data == nil {
+		return event, nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+	event.Data = raw
+	return event, nil
+}
@@ -0,0 +1,79 @@
+package changefeed
+
+import (
+	"strings"
+
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/validation"
+)
+
+const (
+	listSeparator = ","
+
+	typesParam = "types"
+	idsParam   = "ids"
+	typesRule  = "oneof=created updated deleted restored"
+)
+
+// Filter selects the events a subscriber receives, an empty list matching everything
+type Filter struct {
+	Types []EventType
+	IDs   []uuid.UUID
+}
+
+// ParseFilter reads comma separated event types and item IDs, as sent in the query of a subscription
+func ParseFilter(types, ids string) (Filter, error) {
+	var filter Filter
+	for _, value := range splitList(types) {
+		if err := validation.Param(typesParam, value, typesRule); err != nil {
+			return Filter{}, err
+		}
+		filter.Types = append(filter.Types, EventType(value))
+	}
+	for _, value := range splitList(ids) {
+		if err := validation.Param(idsParam, value, validation.UUIDRule); err != nil {
+			return Filter{}, err
+		}
+		filter.IDs = append(filter.IDs, uuid.FromStringOrNil(value))
+	}
+	return filter, nil
+}
+
+func (f Filter) Matches(event Event) bool {
+	return f.matchesType(event.Type) && f.matchesID(event.ItemID)
+}
+
+func (f Filter) matchesType(eventType EventType) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Filter) matchesID(id uuid.UUID) bool {
+	if len(f.IDs) == 0 {
+		return true
+	}
+	for _, filterID := range f.IDs {
+		if uuid.Equal(filterID, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, listSeparator) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
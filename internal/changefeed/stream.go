@@ -0,0 +1,152 @@
+package changefeed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	HeartbeatInterval = 15 * time.Second
+
+	contentTypeHeader     = "Content-Type"
+	cacheControlHeader    = "Cache-Control"
+	connectionHeader      = "Connection"
+	accelBufferingHeader  = "X-Accel-Buffering"
+	originHeader          = "Origin"
+	eventStreamType       = "text/event-stream"
+	noCache               = "no-cache"
+	keepAlive             = "keep-alive"
+	disabled              = "no"
+	sseEventFormat        = "id: %s\nevent: %s\ndata: %s\n\n"
+	sseHeartbeat          = ": heartbeat\n\n"
+	webSocketWriteTimeout = 10 * time.Second
+
+	streamingUnsupported = "streaming is not supported by %T"
+)
+
+// Origins are the origins, like https://app.example.com, of the pages allowed to open the change feed WebSocket
+// besides the pages served by the service itself. Browsers send the cookies of the service along with the upgrade
+// request whatever the page, so the pages of other origins are refused
+type Origins []string
+
+// Allow tells whether the WebSocket upgrade request r comes from an allowed origin. The requests without Origin,
+// which aren't sent by browsers, are allowed
+func (o Origins) Allow(r *http.Request) bool {
+	origin := r.Header.Get(originHeader)
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range o {
+		if strings.EqualFold(origin, allowed) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// IsWebSocket tells whether r asks to switch to the WebSocket protocol
+func IsWebSocket(r *http.Request) bool {
+	return websocket.IsWebSocketUpgrade(r)
+}
+
+// WriteSSE streams events as Server-Sent Events, with the event ID in the id field so browsers resume
+// with the Last-Event-ID header. It returns once ctx is done or events is closed
+func WriteSSE(ctx context.Context, w http.ResponseWriter, events <-chan Event) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return fmt.Errorf(streamingUnsupported, w)
+	}
+
+	w.Header().Set(contentTypeHeader, eventStreamType)
+	w.Header().Set(cacheControlHeader, noCache)
+	w.Header().Set(connectionHeader, keepAlive)
+	w.Header().Set(accelBufferingHeader, disabled)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, sseHeartbeat); err != nil {
+				return err
+			}
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			if _, err = fmt.Fprintf(w, sseEventFormat, event.ID, event.Type, data); err != nil {
+				return err
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// WriteWebSocket upgrades the connection of a request from one of origins and sends every event as a JSON text
+// message. It returns once ctx is done, events is closed or the client closes the connection
+func WriteWebSocket(ctx context.Context, w http.ResponseWriter, r *http.Request, origins Origins,
+	events <-chan Event) error {
+	upgrader := websocket.Upgrader{CheckOrigin: origins.Allow}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// the client isn't expected to send anything, reading only notices when it goes away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return closeWebSocket(conn)
+		case <-closed:
+			return nil
+		case <-heartbeat.C:
+			if err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(webSocketWriteTimeout)); err != nil {
+				return err
+			}
+		case event, ok := <-events:
+			if !ok {
+				return closeWebSocket(conn)
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+			if err = conn.WriteJSON(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func closeWebSocket(conn *websocket.Conn) error {
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	return conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(webSocketWriteTimeout))
+}
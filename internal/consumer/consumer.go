@@ -0,0 +1,246 @@
+package consumer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"app/internal/backoff"
+	"app/internal/consumer/metrics"
+	"app/internal/logger"
+	"app/internal/messaging"
+	"app/internal/storage"
+)
+
+const (
+	// DeadLetterSuffix is appended to a topic to name the topic its dead letters are published on
+	DeadLetterSuffix = ".dlq"
+
+	DefaultConcurrency     = 10
+	DefaultMaxAttempts     = 5
+	DefaultShutdownTimeout = 30 * time.Second
+	DefaultDedupTTL        = 24 * time.Hour
+
+	dedupKeyPrefix = "consumer:"
+	dedupKeySep    = ":"
+
+	FailedToSubscribe  = "failed to subscribe to topic"
+	FailedToHandle     = "failed to handle message"
+	FailedToAck        = "failed to acknowledge message"
+	FailedToDeadLetter = "failed to dead letter message"
+	FailedToMarkDone   = "failed to mark message as handled"
+	DeadLettered       = "message dead lettered"
+
+	topicKey   = "topic"
+	eventIDKey = "eventID"
+	attemptKey = "attempt"
+
+	handledOutcome      = "handled"
+	duplicateOutcome    = "duplicate"
+	retriedOutcome      = "retried"
+	deadLetteredOutcome = "dead_lettered"
+)
+
+// retries spaces out the deliveries of a failing message
+var retries = backoff.Exponential{Min: 100 * time.Millisecond, Max: 30 * time.Second}
+
+// Handler processes the event of a message, returning an error to have it delivered again
+type Handler func(ctx context.Context, event messaging.Event) error
+
+// Consumer runs the handlers registered for the topics of a consumer group
+type Consumer interface {
+	// Handle registers the handler of topic, it must be called before Run
+	Handle(topic string, handler Handler)
+	// Run consumes the registered topics until ctx is done, then waits for the messages being handled
+	Run(ctx context.Context) error
+}
+
+type Config struct {
+	Group string
+	// Concurrency bounds the messages handled at the same time across every topic
+	Concurrency int
+	// MaxAttempts is the number of deliveries after which a failing message is dead lettered
+	MaxAttempts int
+	// ShutdownTimeout is how long the handlers running when Run's ctx is done are given to finish
+	ShutdownTimeout time.Duration
+	// DedupTTL is how long a handled event is remembered, a message delivered again later being handled again
+	DedupTTL time.Duration
+}
+
+type DependenciesNode struct {
+	Subscriber messaging.Subscriber
+	// DeadLetters receives the messages that failed MaxAttempts times
+	DeadLetters messaging.Publisher
+	// Cache remembers the handled events, so a message delivered again is acknowledged without being handled twice
+	Cache storage.Cache
+	Log   logger.Logger
+}
+
+type consumer struct {
+	deps     *DependenciesNode
+	config   Config
+	metrics  *metrics.Metrics
+	handlers map[string]Handler
+	sleep    func(ctx context.Context, d time.Duration)
+}
+
+func New(deps *DependenciesNode, config Config) Consumer {
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultConcurrency
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.ShutdownTimeout <= 0 {
+		config.ShutdownTimeout = DefaultShutdownTimeout
+	}
+	if config.DedupTTL <= 0 {
+		config.DedupTTL = DefaultDedupTTL
+	}
+
+	return &consumer{
+		deps:     deps,
+		config:   config,
+		metrics:  metrics.Initialize(),
+		handlers: make(map[string]Handler),
+		sleep:    backoff.Sleep,
+	}
+}
+
+func (c *consumer) Handle(topic string, handler Handler) {
+	c.handlers[topic] = handler
+}
+
+func (c *consumer) Run(ctx context.Context) error {
+	subscriptions, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the handlers outlive ctx by ShutdownTimeout at most, so the messages received are handled before returning
+	handlers, stopHandlers := context.WithCancel(context.Background())
+	defer stopHandlers()
+	go func() {
+		select {
+		case <-handlers.Done():
+		case <-ctx.Done():
+			select {
+			case <-handlers.Done():
+			case <-time.After(c.config.ShutdownTimeout):
+				stopHandlers()
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, c.config.Concurrency)
+	for topic, handler := range c.handlers {
+		messages, err := c.deps.Subscriber.Subscribe(subscriptions, topic, c.config.Group)
+		if err != nil {
+			c.deps.Log.Error(ctx, err, FailedToSubscribe, logrus.Fields{topicKey: topic})
+			cancel()
+			wg.Wait()
+			return err
+		}
+
+		wg.Add(1)
+		go func(handler Handler, messages <-chan *messaging.Message) {
+			defer wg.Done()
+			for msg := range messages {
+				slots <- struct{}{}
+				wg.Add(1)
+				go func(msg *messaging.Message) {
+					defer wg.Done()
+					retry := c.process(handlers, handler, msg)
+					// the slot is released before backing off, so a failing message doesn't hold up the others
+					<-slots
+					if retry {
+						c.retry(handlers, msg)
+					}
+				}(msg)
+			}
+		}(handler, messages)
+	}
+
+	<-ctx.Done()
+	wg.Wait()
+	return nil
+}
+
+// process handles msg once, acknowledging it when it succeeds or was already handled. It tells whether a failed
+// message is to be retried, until it's dead lettered at its last attempt
+func (c *consumer) process(ctx context.Context, handler Handler, msg *messaging.Message) bool {
+	fields := messageFields(msg)
+	key := c.dedupKey(msg.Event.ID)
+
+	if c.handled(key) {
+		c.metrics.Processed.Increment(msg.Topic, duplicateOutcome)
+		c.ack(ctx, msg, fields)
+		return false
+	}
+
+	err := handler(ctx, msg.Event)
+	if err == nil {
+		if err = c.deps.Cache.SetWithTTL(key, true, c.config.DedupTTL); err != nil {
+			c.deps.Log.Error(ctx, err, FailedToMarkDone, fields)
+		}
+		c.metrics.Processed.Increment(msg.Topic, handledOutcome)
+		c.ack(ctx, msg, fields)
+		return false
+	}
+	c.deps.Log.Error(ctx, err, FailedToHandle, fields)
+
+	if msg.Attempt >= c.config.MaxAttempts {
+		c.deadLetter(ctx, msg, fields)
+		return false
+	}
+
+	c.metrics.Processed.Increment(msg.Topic, retriedOutcome)
+	return true
+}
+
+// retry has msg delivered again after an exponential backoff
+func (c *consumer) retry(ctx context.Context, msg *messaging.Message) {
+	c.sleep(ctx, retries.Delay(msg.Attempt))
+	if err := msg.Nack(ctx); err != nil {
+		c.deps.Log.Error(ctx, err, FailedToAck, messageFields(msg))
+	}
+}
+
+func (c *consumer) deadLetter(ctx context.Context, msg *messaging.Message, fields logrus.Fields) {
+	if c.deps.DeadLetters != nil {
+		if err := c.deps.DeadLetters.Publish(ctx, msg.Topic+DeadLetterSuffix, msg.Event); err != nil {
+			// delivered again so it isn't lost, to be dead lettered by its next attempt
+			c.deps.Log.Error(ctx, err, FailedToDeadLetter, fields)
+			if err = msg.Nack(ctx); err != nil {
+				c.deps.Log.Error(ctx, err, FailedToAck, fields)
+			}
+			return
+		}
+	}
+
+	c.deps.Log.Warn(ctx, DeadLettered, fields)
+	c.metrics.Processed.Increment(msg.Topic, deadLetteredOutcome)
+	c.ack(ctx, msg, fields)
+}
+
+func (c *consumer) ack(ctx context.Context, msg *messaging.Message, fields logrus.Fields) {
+	if err := msg.Ack(ctx); err != nil {
+		c.deps.Log.Error(ctx, err, FailedToAck, fields)
+	}
+}
+
+// handled tells whether the event was handled by the group, a cache failure being taken as not handled
+// since handling a message twice is better than losing it
+func (c *consumer) handled(key string) bool {
+	data, err := c.deps.Cache.Get(key)
+	return err == nil && data != nil
+}
+
+func messageFields(msg *messaging.Message) logrus.Fields {
+	return logrus.Fields{topicKey: msg.Topic, eventIDKey: msg.Event.ID, attemptKey: msg.Attempt}
+}
+
+func (c *consumer) dedupKey(eventID string) string {
+	return dedupKeyPrefix + c.config.Group + dedupKeySep + eventID
+}
@@ -0,0 +1,280 @@
+package consumer
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"app/infra/messaging/memory"
+	"app/internal/messaging"
+	errorsAssertion "app/internal/test/assertion/errors"
+	pkgMock "app/internal/test/mocks/pkg"
+)
+
+func TestConsumer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Consumer Suits")
+}
+
+const topic = "items"
+
+// memoryCache is a storage.Cache safe for the concurrent handlers of a test
+type memoryCache struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	ttl  map[string]time.Duration
+}
+
+func (m *memoryCache) Set(key string, _ interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = []byte("true")
+	return nil
+}
+
+func (m *memoryCache) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ttl[key] = ttl
+	m.data[key] = []byte("true")
+	return nil
+}
+
+func (m *memoryCache) Get(key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.data[key], nil
+}
+
+func (m *memoryCache) TTL(key string) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ttl[key]
+}
+
+func (m *memoryCache) Remove(keys ...string) error {
+	return nil
+}
+
+// subscriber reports the topics subscribed to, so events are only published once the consumer listens
+type subscriber struct {
+	messaging.Subscriber
+	subscribed chan string
+}
+
+func (s *subscriber) Subscribe(ctx context.Context, topic, group string) (<-chan *messaging.Message, error) {
+	messages, err := s.Subscriber.Subscribe(ctx, topic, group)
+	s.subscribed <- topic
+	return messages, err
+}
+
+func newEvent() messaging.Event {
+	event, err := messaging.NewEvent("/test", messaging.ItemCreated, "subject", nil)
+	Expect(err).ShouldNot(HaveOccurred())
+	return event
+}
+
+var _ = Describe("Consumer", func() {
+	var (
+		ctx     context.Context
+		cancel  context.CancelFunc
+		broker  messaging.Broker
+		logMock *pkgMock.Logger
+		sub     *subscriber
+		config  Config
+		done    chan error
+		sleep   func(ctx context.Context, d time.Duration)
+		cache   *memoryCache
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		broker = memory.New()
+		logMock = pkgMock.NewLogger(GinkgoT())
+		logMock.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		logMock.On("Warn", mock.Anything, mock.Anything, mock.Anything).Maybe()
+		sub = &subscriber{Subscriber: broker, subscribed: make(chan string, 2)}
+		config = Config{Group: "group", Concurrency: 2, MaxAttempts: 3, ShutdownTimeout: time.Second}
+		done = make(chan error, 1)
+		sleep = func(context.Context, time.Duration) {}
+		cache = &memoryCache{data: make(map[string][]byte), ttl: make(map[string]time.Duration)}
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	run := func(handler Handler) {
+		c := New(&DependenciesNode{
+			Subscriber:  sub,
+			DeadLetters: broker,
+			Cache:       cache,
+			Log:         logMock,
+		}, config).(*consumer)
+		c.sleep = sleep
+		c.Handle(topic, handler)
+
+		go func(ctx context.Context, done chan<- error) {
+			done <- c.Run(ctx)
+		}(ctx, done)
+		Eventually(sub.subscribed).Should(Receive(Equal(topic)))
+	}
+
+	When("A message is handled", func() {
+		It("Should acknowledge it and remember it for the dedup TTL", func() {
+			handled := make(chan messaging.Event, 1)
+			run(func(_ context.Context, event messaging.Event) error {
+				handled <- event
+				return nil
+			})
+			event := newEvent()
+
+			Expect(broker.Publish(ctx, topic, event)).To(Succeed())
+
+			var received messaging.Event
+			Eventually(handled).Should(Receive(&received))
+			Expect(received.ID).To(Equal(event.ID))
+			Eventually(func() time.Duration {
+				return cache.TTL(dedupKeyPrefix + config.Group + dedupKeySep + event.ID)
+			}).Should(Equal(DefaultDedupTTL))
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+	})
+	When("The handler fails", func() {
+		It("Should deliver the message again", func() {
+			var calls int32
+			handled := make(chan struct{}, 1)
+			run(func(context.Context, messaging.Event) error {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					return errorsAssertion.ErrGeneric
+				}
+				handled <- struct{}{}
+				return nil
+			})
+
+			Expect(broker.Publish(ctx, topic, newEvent())).To(Succeed())
+
+			Eventually(handled).Should(Receive())
+			Expect(atomic.LoadInt32(&calls)).To(Equal(int32(2)))
+		})
+	})
+	When("A failed message is backing off", func() {
+		It("Should handle the other messages meanwhile", func() {
+			config.Concurrency = 1
+			var once sync.Once
+			backingOff := make(chan struct{})
+			release := make(chan struct{})
+			sleep = func(context.Context, time.Duration) {
+				once.Do(func() { close(backingOff) })
+				<-release
+			}
+			failing := newEvent()
+			handled := make(chan messaging.Event, 2)
+			run(func(_ context.Context, event messaging.Event) error {
+				if event.ID == failing.ID {
+					return errorsAssertion.ErrGeneric
+				}
+				handled <- event
+				return nil
+			})
+
+			Expect(broker.Publish(ctx, topic, failing)).To(Succeed())
+			Eventually(backingOff).Should(BeClosed())
+			other := newEvent()
+			Expect(broker.Publish(ctx, topic, other)).To(Succeed())
+
+			var received messaging.Event
+			Eventually(handled).Should(Receive(&received))
+			Expect(received.ID).To(Equal(other.ID))
+			close(release)
+		})
+	})
+	When("The handler fails at every attempt", func() {
+		It("Should dead letter the message", func() {
+			deadLetters, err := broker.Subscribe(ctx, topic+DeadLetterSuffix, "dlq")
+			Expect(err).ShouldNot(HaveOccurred())
+			var calls int32
+			run(func(context.Context, messaging.Event) error {
+				atomic.AddInt32(&calls, 1)
+				return errorsAssertion.ErrGeneric
+			})
+			event := newEvent()
+
+			Expect(broker.Publish(ctx, topic, event)).To(Succeed())
+
+			var msg *messaging.Message
+			Eventually(deadLetters).Should(Receive(&msg))
+			Expect(msg.Event.ID).To(Equal(event.ID))
+			Expect(atomic.LoadInt32(&calls)).To(Equal(int32(config.MaxAttempts)))
+		})
+	})
+	When("A message is delivered twice", func() {
+		It("Should handle it once", func() {
+			var calls int32
+			run(func(context.Context, messaging.Event) error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			})
+			event := newEvent()
+
+			Expect(broker.Publish(ctx, topic, event)).To(Succeed())
+			Eventually(func() int32 { return atomic.LoadInt32(&calls) }).Should(Equal(int32(1)))
+			Expect(broker.Publish(ctx, topic, event)).To(Succeed())
+
+			Consistently(func() int32 { return atomic.LoadInt32(&calls) }, 200*time.Millisecond).Should(Equal(int32(1)))
+		})
+	})
+	When("More messages arrive than the concurrency", func() {
+		It("Should handle at most Concurrency of them at once", func() {
+			var running, peak int32
+			release := make(chan struct{})
+			run(func(context.Context, messaging.Event) error {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					seen := atomic.LoadInt32(&peak)
+					if current <= seen || atomic.CompareAndSwapInt32(&peak, seen, current) {
+						break
+					}
+				}
+				<-release
+				atomic.AddInt32(&running, -1)
+				return nil
+			})
+
+			for i := 0; i < 5; i++ {
+				Expect(broker.Publish(ctx, topic, newEvent())).To(Succeed())
+			}
+
+			Eventually(func() int32 { return atomic.LoadInt32(&running) }).Should(Equal(int32(config.Concurrency)))
+			Consistently(func() int32 { return atomic.LoadInt32(&peak) }, 100*time.Millisecond).
+				Should(Equal(int32(config.Concurrency)))
+			close(release)
+		})
+	})
+	When("Context is done while a message is handled", func() {
+		It("Should wait for the handler before returning", func() {
+			started := make(chan struct{})
+			var finished int32
+			run(func(context.Context, messaging.Event) error {
+				close(started)
+				time.Sleep(100 * time.Millisecond)
+				atomic.StoreInt32(&finished, 1)
+				return nil
+			})
+			Expect(broker.Publish(ctx, topic, newEvent())).To(Succeed())
+			Eventually(started).Should(BeClosed())
+
+			cancel()
+
+			Eventually(done).Should(Receive(BeNil()))
+			Expect(atomic.LoadInt32(&finished)).To(Equal(int32(1)))
+		})
+	})
+})
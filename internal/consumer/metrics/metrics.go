@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"app/internal/metric"
+)
+
+const (
+	NameProperty        = "processed_count"
+	NamespaceProperty   = "consumer"
+	DescriptionProperty = "Messages processed by outcome"
+
+	topicPropertyKey   = "topic"
+	outcomePropertyKey = "outcome"
+)
+
+type Metrics struct {
+	Processed metric.CounterVec
+}
+
+func Initialize() *Metrics {
+	return &Metrics{
+		Processed: metric.NewCounter(processedMetricProperties()),
+	}
+}
+
+func processedMetricProperties() metric.Properties {
+	return metric.Properties{
+		Name:        NameProperty,
+		Namespace:   NamespaceProperty,
+		Description: DescriptionProperty,
+		Type:        metric.CounterVecType,
+		Properties:  []string{topicPropertyKey, outcomePropertyKey},
+	}
+}
@@ -0,0 +1,309 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	cycleSeparator = " -> "
+
+	duplicateErr = "component %s is already provided"
+	missingErr   = "%w: %s"
+	cycleErr     = "%w: %s"
+	typeErr      = "component %s is a %T, not a %s"
+	provideErr   = "failed to provide %s: %w"
+	startErr     = "failed to start %s: %w"
+	stopErr      = "failed to stop %s: %w"
+)
+
+var (
+	ErrNotProvided = errors.New("component not provided")
+	ErrCycle       = errors.New("dependency cycle")
+)
+
+// Key names a component of type T, so resolving it is type safe
+type Key[T interface{}] struct {
+	name string
+}
+
+func NewKey[T interface{}](name string) Key[T] {
+	return Key[T]{name: name}
+}
+
+func (k Key[T]) String() string {
+	return k.name
+}
+
+// Provider builds a component, resolving its own dependencies from c with MustResolve
+type Provider[T interface{}] func(c *Container) (T, error)
+
+// Hooks are the optional lifecycle of a component, OnStart running on Start and OnStop on Stop. They aren't run
+// for a nil component, like a broker left out of the configuration
+type Hooks[T interface{}] struct {
+	OnStart func(ctx context.Context, component T) error
+	OnStop  func(ctx context.Context, component T) error
+}
+
+// Container builds the components of an application the first time they're resolved, once, and starts and stops
+// them in dependency order: a component starts after the components it resolved and stops before them. It isn't
+// safe for concurrent use, components being resolved while the main builds the application
+type Container struct {
+	parent     *Container
+	components map[string]*component
+	resolving  []string
+	// resolved lists the components built, each after its dependencies
+	resolved []*component
+	started  []*component
+}
+
+type component struct {
+	name    string
+	provide func(c *Container) (interface{}, error)
+	value   interface{}
+	built   bool
+	start   func(ctx context.Context) error
+	stop    func(ctx context.Context) error
+}
+
+func New() *Container {
+	return &Container{
+		components: map[string]*component{},
+	}
+}
+
+// Child returns a container resolving the components it doesn't provide from c, like the components a service
+// can't share with the other services of its process. The components of c are still built, started and stopped by c
+func (c *Container) Child() *Container {
+	child := New()
+	child.parent = c
+	return child
+}
+
+// Provide registers the provider of the component named key, which isn't called until the component is resolved.
+// It panics when key is already provided, as a programming error
+func Provide[T interface{}](c *Container, key Key[T], provider Provider[T], hooks ...Hooks[T]) {
+	if _, ok := c.components[key.name]; ok {
+		panic(fmt.Sprintf(duplicateErr, key))
+	}
+
+	comp := &component{name: key.name}
+	comp.provide = func(c *Container) (interface{}, error) {
+		value, err := provider(c)
+		if err != nil {
+			return nil, err
+		}
+		if isNil(value) {
+			return value, nil
+		}
+		for _, hook := range hooks {
+			hook := hook
+			if hook.OnStart != nil {
+				comp.start = chain(comp.start, func(ctx context.Context) error {
+					return hook.OnStart(ctx, value)
+				})
+			}
+			if hook.OnStop != nil {
+				comp.stop = chain(comp.stop, func(ctx context.Context) error {
+					return hook.OnStop(ctx, value)
+				})
+			}
+		}
+		return value, nil
+	}
+	c.components[key.name] = comp
+}
+
+// Value provides a component already built, like a configuration
+func Value[T interface{}](c *Container, key Key[T], value T) {
+	Provide(c, key, func(*Container) (T, error) {
+		return value, nil
+	})
+}
+
+// Resolve returns the component named key, building it and its dependencies the first time
+func Resolve[T interface{}](c *Container, key Key[T]) (result T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			resolveErr, ok := r.(resolveError)
+			if !ok {
+				panic(r)
+			}
+			err = resolveErr.err
+		}
+	}()
+	return MustResolve(c, key), nil
+}
+
+// MustResolve is Resolve for providers, a failure unwinding to the Resolve that started the resolution
+func MustResolve[T interface{}](c *Container, key Key[T]) T {
+	value, err := c.resolve(key.name)
+	if err != nil {
+		panic(resolveError{err: err})
+	}
+	component, ok := value.(T)
+	if !ok && value != nil {
+		panic(resolveError{err: fmt.Errorf(typeErr, key, value, typeName[T]())})
+	}
+	return component
+}
+
+// resolveError carries a failure from MustResolve to Resolve
+type resolveError struct {
+	err error
+}
+
+func (c *Container) resolve(name string) (interface{}, error) {
+	comp, ok := c.components[name]
+	if !ok && c.parent != nil {
+		return c.parent.resolve(name)
+	}
+	if !ok {
+		return nil, fmt.Errorf(missingErr, ErrNotProvided, name)
+	}
+	if comp.built {
+		return comp.value, nil
+	}
+	for i, resolving := range c.resolving {
+		if resolving == name {
+			cycle := append(append([]string{}, c.resolving[i:]...), name)
+			return nil, fmt.Errorf(cycleErr, ErrCycle, strings.Join(cycle, cycleSeparator))
+		}
+	}
+
+	value, err := c.provide(comp)
+	if err != nil {
+		return nil, fmt.Errorf(provideErr, name, err)
+	}
+
+	comp.value = value
+	comp.built = true
+	c.resolved = append(c.resolved, comp)
+	return value, nil
+}
+
+// provide builds comp, which may unwind on a failure of MustResolve
+func (c *Container) provide(comp *component) (interface{}, error) {
+	c.resolving = append(c.resolving, comp.name)
+	defer func() {
+		c.resolving = c.resolving[:len(c.resolving)-1]
+	}()
+	return comp.provide(c)
+}
+
+// Start runs the OnStart hooks of the components resolved so far, each after those of its dependencies. When one
+// fails, the components already started are stopped. The components resolved afterwards aren't started
+func (c *Container) Start(ctx context.Context) error {
+	for _, comp := range c.resolved[len(c.started):] {
+		if comp.start != nil {
+			if err := comp.start(ctx); err != nil {
+				_ = c.Stop(ctx)
+				return fmt.Errorf(startErr, comp.name, err)
+			}
+		}
+		c.started = append(c.started, comp)
+	}
+	return nil
+}
+
+// Stop runs the OnStop hooks of the components started, each before those of its dependencies, returning the
+// first failure once all of them ran
+func (c *Container) Stop(ctx context.Context) error {
+	var first error
+	for i := len(c.started) - 1; i >= 0; i-- {
+		comp := c.started[i]
+		if comp.stop == nil {
+			continue
+		}
+		if err := comp.stop(ctx); err != nil && first == nil {
+			first = fmt.Errorf(stopErr, comp.name, err)
+		}
+	}
+	c.started = nil
+	return first
+}
+
+// Background is the lifecycle of a component running until it's stopped, like a broker or a worker pool: run is
+// called in its own goroutine on Start, with a ctx cancelled on Stop, which waits for run to return
+func Background[T interface{}](run func(ctx context.Context, component T)) Hooks[T] {
+	var (
+		mu     sync.Mutex
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+	return Hooks[T]{
+		OnStart: func(_ context.Context, component T) error {
+			mu.Lock()
+			defer mu.Unlock()
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			done = make(chan struct{})
+			go func(done chan struct{}) {
+				defer close(done)
+				run(ctx, component)
+			}(done)
+			return nil
+		},
+		OnStop: func(ctx context.Context, _ T) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if cancel == nil {
+				return nil
+			}
+			cancel()
+			cancel = nil
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+// Closer is the lifecycle of a component holding connections, closed on Stop when it implements io.Closer
+func Closer[T interface{}]() Hooks[T] {
+	return Hooks[T]{
+		OnStop: func(_ context.Context, component T) error {
+			if closer, ok := interface{}(component).(io.Closer); ok {
+				return closer.Close()
+			}
+			return nil
+		},
+	}
+}
+
+func chain(first, then func(ctx context.Context) error) func(ctx context.Context) error {
+	if first == nil {
+		return then
+	}
+	return func(ctx context.Context) error {
+		if err := first(ctx); err != nil {
+			return err
+		}
+		return then(ctx)
+	}
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+func typeName[T interface{}]() string {
+	return fmt.Sprintf("%T", (*T)(nil))[1:]
+}
@@ -0,0 +1,240 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	errorsAssertion "app/internal/test/assertion/errors"
+)
+
+func TestContainer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Container Suits")
+}
+
+type closer struct {
+	closed bool
+}
+
+func (c *closer) Close() error {
+	c.closed = true
+	return nil
+}
+
+var (
+	databaseKey = NewKey[string]("database")
+	repoKey     = NewKey[string]("repository")
+	serviceKey  = NewKey[string]("service")
+	closerKey   = NewKey[*closer]("closer")
+)
+
+var _ = Describe("Container", func() {
+	var (
+		c      *Container
+		events []string
+		built  int
+	)
+
+	// record is the lifecycle of a component recording when it starts and stops
+	record := func(name string) Hooks[string] {
+		return Hooks[string]{
+			OnStart: func(context.Context, string) error {
+				events = append(events, "start "+name)
+				return nil
+			},
+			OnStop: func(context.Context, string) error {
+				events = append(events, "stop "+name)
+				return nil
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		c = New()
+		events = nil
+		built = 0
+		Provide(c, databaseKey, func(*Container) (string, error) {
+			built++
+			return "database", nil
+		}, record("database"))
+		Provide(c, repoKey, func(c *Container) (string, error) {
+			return MustResolve(c, databaseKey) + " repository", nil
+		}, record("repository"))
+		Provide(c, serviceKey, func(c *Container) (string, error) {
+			return MustResolve(c, repoKey) + " service", nil
+		}, record("service"))
+	})
+
+	Context("Resolving components", func() {
+		When("The component depends on others", func() {
+			It("Should build its dependencies once, when first resolved", func() {
+				Expect(built).To(BeZero())
+
+				service, err := Resolve(c, serviceKey)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(service).To(Equal("database repository service"))
+
+				repository, err := Resolve(c, repoKey)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(repository).To(Equal("database repository"))
+				Expect(built).To(Equal(1))
+			})
+		})
+		When("A dependency isn't provided", func() {
+			It("Should fail", func() {
+				Provide(c, NewKey[int]("broken"), func(c *Container) (int, error) {
+					return len(MustResolve(c, NewKey[string]("missing"))), nil
+				})
+
+				_, err := Resolve(c, NewKey[int]("broken"))
+
+				Expect(err).To(MatchError(ErrNotProvided))
+				Expect(err.Error()).To(ContainSubstring("missing"))
+			})
+		})
+		When("The components depend on each other", func() {
+			It("Should fail with the cycle", func() {
+				first, second := NewKey[string]("first"), NewKey[string]("second")
+				Provide(c, first, func(c *Container) (string, error) {
+					return MustResolve(c, second), nil
+				})
+				Provide(c, second, func(c *Container) (string, error) {
+					return MustResolve(c, first), nil
+				})
+
+				_, err := Resolve(c, first)
+
+				Expect(err).To(MatchError(ErrCycle))
+				Expect(err.Error()).To(ContainSubstring("first -> second -> first"))
+			})
+		})
+		When("A provider fails", func() {
+			It("Should return its error", func() {
+				failing := NewKey[string]("failing")
+				Provide(c, failing, func(*Container) (string, error) {
+					return "", errorsAssertion.ErrGeneric
+				})
+
+				_, err := Resolve(c, failing)
+
+				Expect(err).To(MatchError(errorsAssertion.ErrGeneric))
+			})
+		})
+		When("The component is provided by the parent", func() {
+			It("Should share it with every child", func() {
+				first, second := c.Child(), c.Child()
+				Value(first, NewKey[string]("name"), "first")
+				Value(second, NewKey[string]("name"), "second")
+				for _, child := range []*Container{first, second} {
+					Provide(child, NewKey[string]("scoped"), func(c *Container) (string, error) {
+						return MustResolve(c, NewKey[string]("name")) + " " + MustResolve(c, repoKey), nil
+					})
+				}
+
+				firstScoped, err := Resolve(first, NewKey[string]("scoped"))
+				Expect(err).ShouldNot(HaveOccurred())
+				secondScoped, err := Resolve(second, NewKey[string]("scoped"))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(firstScoped).To(Equal("first database repository"))
+				Expect(secondScoped).To(Equal("second database repository"))
+				Expect(built).To(Equal(1))
+			})
+		})
+		When("The component is provided twice", func() {
+			It("Should panic", func() {
+				Expect(func() {
+					Value(c, databaseKey, "other")
+				}).To(Panic())
+			})
+		})
+	})
+
+	Context("Running the lifecycle", func() {
+		When("Starting and stopping", func() {
+			It("Should start the dependencies first and stop them last", func() {
+				_, err := Resolve(c, serviceKey)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(c.Start(context.Background())).To(Succeed())
+				Expect(c.Stop(context.Background())).To(Succeed())
+
+				Expect(events).To(Equal([]string{
+					"start database", "start repository", "start service",
+					"stop service", "stop repository", "stop database",
+				}))
+			})
+		})
+		When("A component fails to start", func() {
+			It("Should stop the components already started", func() {
+				failing := NewKey[string]("failing")
+				Provide(c, failing, func(c *Container) (string, error) {
+					return MustResolve(c, repoKey), nil
+				}, Hooks[string]{
+					OnStart: func(context.Context, string) error {
+						return errorsAssertion.ErrGeneric
+					},
+				})
+				_, err := Resolve(c, failing)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				err = c.Start(context.Background())
+
+				Expect(err).To(MatchError(errorsAssertion.ErrGeneric))
+				Expect(events).To(Equal([]string{
+					"start database", "start repository", "stop repository", "stop database",
+				}))
+			})
+		})
+		When("A component is nil", func() {
+			It("Should skip its hooks", func() {
+				Provide(c, closerKey, func(*Container) (*closer, error) {
+					return nil, nil
+				}, Hooks[*closer]{
+					OnStart: func(context.Context, *closer) error {
+						return errorsAssertion.ErrGeneric
+					},
+				})
+				_, err := Resolve(c, closerKey)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(c.Start(context.Background())).To(Succeed())
+			})
+		})
+		When("A component runs in the background", func() {
+			It("Should run it from Start to Stop", func() {
+				running := make(chan struct{})
+				runKey := NewKey[string]("runner")
+				Provide(c, runKey, func(*Container) (string, error) {
+					return "runner", nil
+				}, Background(func(ctx context.Context, _ string) {
+					close(running)
+					<-ctx.Done()
+				}))
+				_, err := Resolve(c, runKey)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(c.Start(context.Background())).To(Succeed())
+				Eventually(running).Should(BeClosed())
+				Expect(c.Stop(context.Background())).To(Succeed())
+			})
+		})
+		When("A component holds connections", func() {
+			It("Should close it on Stop", func() {
+				component := &closer{}
+				Provide(c, closerKey, func(*Container) (*closer, error) {
+					return component, nil
+				}, Closer[*closer]())
+				_, err := Resolve(c, closerKey)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(c.Start(context.Background())).To(Succeed())
+				Expect(c.Stop(context.Background())).To(Succeed())
+				Expect(component.closed).To(BeTrue())
+			})
+		})
+	})
+})
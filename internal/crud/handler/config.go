@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"app/api/middleware"
+	"app/internal/auth"
+	"app/internal/validation"
+)
+
+// Register mounts the routes of the handler on path of group, purging being restricted to admins
+func (h *Handler[T]) Register(group *gin.RouterGroup, path string) {
+	items := group.Group(path)
+	items.Use(middleware.NewParamsMiddleware(map[string]string{ParamID: validation.UUIDRule}).HandleFunc())
+	{
+		items.GET("", h.Get)
+		items.GET("/export", h.Export)
+		items.GET("/stream", h.Stream)
+		items.GET("/:id", h.Find)
+		items.POST("", h.Create)
+		items.POST("/batch", h.CreateBatch)
+		items.PUT("/batch", h.UpsertBatch)
+		items.DELETE("/batch", h.DeleteBatch)
+		items.POST("/import", h.Import)
+		items.PUT("/:id", h.Update)
+		items.PATCH("/:id", h.Patch)
+		items.DELETE("/:id", h.Delete)
+		items.POST("/:id/restore", h.Restore)
+		items.DELETE(
+			"/:id/purge",
+			middleware.NewAuthorizationMiddleware(auth.AdminGroup).HandleFunc(),
+			h.Purge,
+		)
+	}
+}
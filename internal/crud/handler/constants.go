@@ -0,0 +1,20 @@
+package handler
+
+const (
+	ParamID = "id"
+
+	QueryMode        = "mode"
+	QueryFormat      = "format"
+	QueryTypes       = "types"
+	QueryIDs         = "ids"
+	QueryLastEventID = "lastEventId"
+
+	HeaderLocation           = "Location"
+	HeaderContentType        = "Content-Type"
+	HeaderContentDisposition = "Content-Disposition"
+	HeaderLastEventID        = "Last-Event-ID"
+	HeaderPreferenceApplied  = "Preference-Applied"
+
+	locationFormat   = "%s/%s"
+	attachmentFormat = `attachment; filename="%s%s"`
+)
@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/batch"
+	"app/internal/changefeed"
+	"app/internal/crud/service"
+	"app/internal/entity"
+	"app/internal/errors"
+	jobHandler "app/internal/job/handler"
+	"app/internal/transfer"
+)
+
+type DependenciesNode[T interface{}] struct {
+	Service service.Service[T]
+}
+
+type Config struct {
+	// ExportFilename is the name of the exported files, without extension
+	ExportFilename string
+	// Origins are the other origins whose pages may open the change feed WebSocket
+	Origins changefeed.Origins
+}
+
+// Handler serves the REST API of the entities T, mounted on a path by Register
+type Handler[T interface{}] struct {
+	deps   *DependenciesNode[T]
+	config Config
+	id     func(item *T) uuid.UUID
+}
+
+// New returns the Handler of the entities T, P being inferred as *T
+func New[T interface{}, P entity.Entity[T]](deps *DependenciesNode[T], config Config) *Handler[T] {
+	return &Handler[T]{
+		deps:   deps,
+		config: config,
+		id: func(item *T) uuid.UUID {
+			return P(item).GetID()
+		},
+	}
+}
+
+// Get answers every item
+func (h *Handler[T]) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+	resp, err := h.deps.Service.GetAll(ctx)
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Find answers the item of the id path param
+func (h *Handler[T]) Find(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param(ParamID)
+	resp, err := h.deps.Service.GetOneByID(ctx, id)
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Create creates the item of the body, answering its URL as Location
+func (h *Handler[T]) Create(c *gin.Context) {
+	var input *T
+	err := c.ShouldBindJSON(&input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	obj, err := h.deps.Service.Create(ctx, input)
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.Header(HeaderLocation, fmt.Sprintf(locationFormat, c.Request.URL.Path, h.id(obj)))
+	c.JSON(http.StatusCreated, obj)
+}
+
+// Update replaces the item of the id path param
+func (h *Handler[T]) Update(c *gin.Context) {
+	var input *T
+	err := c.ShouldBindJSON(&input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	id := c.Param(ParamID)
+	if err = h.deps.Service.Update(ctx, id, input); err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Patch applies the JSON Merge Patch or JSON Patch document of the body to the item of the id path param
+func (h *Handler[T]) Patch(c *gin.Context) {
+	doc, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	id := c.Param(ParamID)
+	obj, err := h.deps.Service.Patch(ctx, id, c.ContentType(), doc)
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, obj)
+}
+
+// Delete soft deletes the item of the id path param
+func (h *Handler[T]) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param(ParamID)
+	if err := h.deps.Service.Delete(ctx, id); err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Restore restores the soft deleted item of the id path param
+func (h *Handler[T]) Restore(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param(ParamID)
+	if err := h.deps.Service.Restore(ctx, id); err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Purge permanently deletes the item of the id path param, including a soft deleted one
+func (h *Handler[T]) Purge(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param(ParamID)
+	if err := h.deps.Service.Purge(ctx, id); err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// CreateBatch creates the items of the body in the batch mode of the mode query param
+func (h *Handler[T]) CreateBatch(c *gin.Context) {
+	mode, err := batch.ParseMode(c.Query(QueryMode))
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	var input []*T
+	if err = c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.deps.Service.CreateBatch(ctx, input, mode)
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.JSON(result.Status(http.StatusCreated), result)
+}
+
+// UpsertBatch creates or replaces by ID the items of the body in the batch mode of the mode query param
+func (h *Handler[T]) UpsertBatch(c *gin.Context) {
+	mode, err := batch.ParseMode(c.Query(QueryMode))
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	var input []*T
+	if err = c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.deps.Service.UpsertBatch(ctx, input, mode)
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.JSON(result.Status(http.StatusOK), result)
+}
+
+// DeleteBatch soft deletes the items of the IDs of the body in the batch mode of the mode query param
+func (h *Handler[T]) DeleteBatch(c *gin.Context) {
+	mode, err := batch.ParseMode(c.Query(QueryMode))
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	var ids []uuid.UUID
+	if err = c.ShouldBindJSON(&ids); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.deps.Service.DeleteBatch(ctx, ids, mode)
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.JSON(result.Status(http.StatusOK), result)
+}
+
+// Export streams every item in the format of the format query param
+func (h *Handler[T]) Export(c *gin.Context) {
+	format, err := transfer.ParseFormat(c.Query(QueryFormat))
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.Header(HeaderContentType, format.ContentType())
+	c.Header(HeaderContentDisposition, fmt.Sprintf(attachmentFormat, h.config.ExportFilename, format.Extension()))
+	c.Status(http.StatusOK)
+
+	ctx := c.Request.Context()
+	if err = h.deps.Service.Export(ctx, format, c.Writer); err != nil {
+		if c.Writer.Written() {
+			// the status is already sent, the client only sees a truncated body
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del(HeaderContentType)
+		c.Writer.Header().Del(HeaderContentDisposition)
+		c.JSON(errors.GetStatus(err), err)
+	}
+}
+
+// Import upserts the rows of the file of the request, in a background job when the client prefers so
+func (h *Handler[T]) Import(c *gin.Context) {
+	file, format, err := transfer.FromRequest(c.Request)
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if transfer.PrefersAsync(c.Request) {
+		j, err := h.deps.Service.ImportAsync(ctx, format, file)
+		if err != nil {
+			c.JSON(errors.GetStatus(err), err)
+			return
+		}
+
+		c.Header(HeaderPreferenceApplied, transfer.RespondAsync)
+		c.Header(HeaderLocation, jobHandler.Location(j.ID))
+		c.JSON(http.StatusAccepted, j)
+		return
+	}
+
+	report, err := h.deps.Service.Import(ctx, format, file)
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	c.JSON(report.Status(), report)
+}
+
+// Stream sends the changes of the items as Server-Sent Events or WebSocket messages
+func (h *Handler[T]) Stream(c *gin.Context) {
+	filter, err := changefeed.ParseFilter(c.Query(QueryTypes), c.Query(QueryIDs))
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+	lastEventID := c.GetHeader(HeaderLastEventID)
+	if lastEventID == "" {
+		lastEventID = c.Query(QueryLastEventID)
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	events, err := h.deps.Service.Subscribe(ctx, filter, lastEventID)
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	if changefeed.IsWebSocket(c.Request) {
+		err = changefeed.WriteWebSocket(ctx, c.Writer, c.Request, h.config.Origins, events)
+	} else {
+		err = changefeed.WriteSSE(ctx, c.Writer, events)
+	}
+	if err != nil {
+		_ = c.Error(err)
+	}
+}
@@ -0,0 +1,908 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/mock"
+
+	cacheMemory "app/infra/cache/memory"
+	"app/infra/database/sqlite"
+	"app/internal/auth"
+	"app/internal/batch"
+	"app/internal/changefeed"
+	"app/internal/crud/repository"
+	"app/internal/crud/service"
+	"app/internal/errors"
+	"app/internal/job"
+	"app/internal/patch"
+	assertion "app/internal/test/assertion/crud"
+	errorsAssertion "app/internal/test/assertion/errors"
+	serviceMocks "app/internal/test/mocks/crud/service"
+	pkgMock "app/internal/test/mocks/pkg"
+	"app/internal/transfer"
+	"app/internal/validation"
+)
+
+func TestHandler(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Handler Suits")
+}
+
+func ginCtxParam(key, value string) gin.Param {
+	return gin.Param{
+		Key:   key,
+		Value: value,
+	}
+}
+
+var _ = Describe("Handler", func() {
+	var (
+		router      *gin.Engine
+		w           *httptest.ResponseRecorder
+		ginCtx      *gin.Context
+		serviceMock *serviceMocks.Service[assertion.Item]
+		deps        *DependenciesNode[assertion.Item]
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		w = httptest.NewRecorder()
+		ginCtx, router = gin.CreateTestContext(w)
+		serviceMock = serviceMocks.NewService[assertion.Item](GinkgoT())
+		deps = &DependenciesNode[assertion.Item]{
+			Service: serviceMock,
+		}
+	})
+
+	register := func() {
+		New[assertion.Item](deps, Config{ExportFilename: "items"}).Register(router.Group("/api/v1"), "/items")
+	}
+
+	Context("CRUD Operations", func() {
+		Context("GET", func() {
+			When("Succeed", func() {
+				It("Return an array of item from DB", func() {
+					arrayOfItemInBytes := assertion.ArrayOfItemInBytes(assertion.ArrayOfItem)
+					serviceMock.On("GetAll", ginCtx).
+						Return(assertion.ArrayOfItem, nil)
+
+					register()
+
+					request, err := http.NewRequestWithContext(ginCtx, http.MethodGet, "/api/v1/items", nil)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					respInBytes, err := ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusOK))
+					Expect(respInBytes).To(Equal(arrayOfItemInBytes))
+				})
+			})
+			When("Fails", func() {
+				It("Return an Internal Server Error", func() {
+					serviceMock.On("GetAll", ginCtx).
+						Return(nil, errorsAssertion.ErrGeneric)
+
+					register()
+
+					request, err := http.NewRequestWithContext(ginCtx, http.MethodGet, "/api/v1/items", nil)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					_, err = ioutil.ReadAll(w.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("FIND", func() {
+			When("Succeed", func() {
+				It("Return an item from DB", func() {
+					itemID := assertion.SampleID.String()
+					item := assertion.NewItemWithID(itemID)
+					itemInBytes := assertion.ItemInBytes(item)
+					serviceMock.On("GetOneByID", ginCtx, itemID).
+						Return(item, nil)
+					ginCtx.Params = []gin.Param{
+						ginCtxParam("id", itemID),
+					}
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodGet,
+						fmt.Sprintf("/api/v1/items/%s", itemID),
+						nil,
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					respInBytes, err := ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusOK))
+					Expect(respInBytes).To(Equal(itemInBytes))
+				})
+			})
+			When("Fails", func() {
+				It("Return a Not Found error", func() {
+					itemID := assertion.SampleID.String()
+					serviceMock.On("GetOneByID", ginCtx, itemID).
+						Return(nil, errorsAssertion.ErrNotFound)
+					ginCtx.Params = []gin.Param{
+						ginCtxParam("id", itemID),
+					}
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodGet,
+						fmt.Sprintf("/api/v1/items/%s", itemID),
+						nil,
+					)
+
+					router.ServeHTTP(w, request)
+
+					_, err = ioutil.ReadAll(w.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusNotFound))
+				})
+				It("Return a Bad Request error when the ID is not a UUID", func() {
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodGet,
+						"/api/v1/items/not-a-uuid",
+						nil,
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					respInBytes, err := ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusBadRequest))
+					Expect(respInBytes).To(MatchJSON(`{
+						"message": "validation failed",
+						"fields": [{"field": "id", "rule": "uuid", "message": "must be a valid UUID"}]
+					}`))
+					serviceMock.AssertNotCalled(GinkgoT(), "GetOneByID", mock.Anything, mock.Anything)
+				})
+			})
+		})
+
+		Context("CREATE", func() {
+			When("Succeed", func() {
+				It("Creates a new item", func() {
+					itemInput := assertion.NewItemWithoutID()
+					inputInBytes := assertion.ItemInBytes(itemInput)
+					expectedOutput := *itemInput
+					expectedOutput.ID = assertion.SampleID
+					serviceMock.On("Create", ginCtx, itemInput).
+						Return(&expectedOutput, nil)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPost,
+						"/api/v1/items",
+						bytes.NewBuffer(inputInBytes),
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					respInBytes, err := ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusCreated))
+					Expect(w.Header().Get(HeaderLocation)).To(Equal(fmt.Sprintf("/api/v1/items/%s", assertion.SampleID)))
+					Expect(respInBytes).To(Equal(assertion.ItemInBytes(&expectedOutput)))
+				})
+			})
+			When("Fails", func() {
+				It("Return Bad Request when fails to Bind Input JSON", func() {
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPost,
+						"/api/v1/items",
+						nil,
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					_, err = ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusBadRequest))
+				})
+				It("Return Unprocessable Entity when item is invalid", func() {
+					itemInput := assertion.NewItemWithoutID()
+					itemInput.Name = ""
+					inputInBytes := assertion.ItemInBytes(itemInput)
+					serviceMock.On("Create", ginCtx, itemInput).
+						Return(nil, validation.NewError(validation.FieldError{Field: "name", Rule: "required", Message: "is required"}))
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPost,
+						"/api/v1/items",
+						bytes.NewBuffer(inputInBytes),
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					respInBytes, err := ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusUnprocessableEntity))
+					Expect(respInBytes).To(MatchJSON(`{
+						"message": "validation failed",
+						"fields": [{"field": "name", "rule": "required", "message": "is required"}]
+					}`))
+				})
+				It("Return Internal Server Error when fails to create item", func() {
+					itemInput := assertion.NewItemWithoutID()
+					inputInBytes := assertion.ItemInBytes(itemInput)
+					expectedOutput := *itemInput
+					expectedOutput.ID = assertion.SampleID
+					serviceMock.On("Create", ginCtx, itemInput).
+						Return(nil, errorsAssertion.ErrCreatingUUID)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPost,
+						"/api/v1/items",
+						bytes.NewBuffer(inputInBytes),
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					_, err = ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("UPDATE", func() {
+			When("Succeed", func() {
+				It("Return no content", func() {
+					itemID := assertion.SampleID.String()
+					itemInput := assertion.NewItemWithoutID()
+					inputInBytes := assertion.ItemInBytes(itemInput)
+					serviceMock.On("Update", ginCtx, itemID, itemInput).
+						Return(nil)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPut,
+						fmt.Sprintf("/api/v1/items/%s", itemID),
+						bytes.NewBuffer(inputInBytes),
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					_, err = ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusNoContent))
+				})
+			})
+			When("Fails", func() {
+				It("Return a Bad Request Error when fails to Bind Input JSON", func() {
+					itemID := assertion.SampleID.String()
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPut,
+						fmt.Sprintf("/api/v1/items/%s", itemID),
+						nil,
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					_, err = ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusBadRequest))
+				})
+				It("Return a Not Found Error when fails to Update item", func() {
+					itemID := assertion.SampleID.String()
+					itemInput := assertion.NewItemWithoutID()
+					inputInBytes := assertion.ItemInBytes(itemInput)
+					serviceMock.On("Update", ginCtx, itemID, itemInput).
+						Return(errorsAssertion.ErrNotFound)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPut,
+						fmt.Sprintf("/api/v1/items/%s", itemID),
+						bytes.NewBuffer(inputInBytes),
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					_, err = ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusNotFound))
+				})
+			})
+		})
+
+		Context("PATCH", func() {
+			When("Succeed", func() {
+				It("Return the patched item", func() {
+					itemID := assertion.SampleID.String()
+					doc := []byte(`{"name":"new name"}`)
+					item := assertion.NewItemWithID(itemID)
+					item.Name = "new name"
+					serviceMock.On("Patch", ginCtx, itemID, patch.MergePatchContentType, doc).
+						Return(item, nil)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPatch,
+						fmt.Sprintf("/api/v1/items/%s", itemID),
+						bytes.NewBuffer(doc),
+					)
+					Expect(err).ToNot(HaveOccurred())
+					request.Header.Set("Content-Type", patch.MergePatchContentType)
+
+					router.ServeHTTP(w, request)
+
+					respInBytes, err := ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusOK))
+					Expect(respInBytes).To(Equal(assertion.ItemInBytes(item)))
+				})
+			})
+			When("Fails", func() {
+				It("Return an Unsupported Media Type error when content type is not a patch", func() {
+					itemID := assertion.SampleID.String()
+					doc := []byte(`{"name":"new name"}`)
+					serviceMock.On("Patch", ginCtx, itemID, "application/json", doc).
+						Return(nil, errors.ErrUnsupportedPatchType)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPatch,
+						fmt.Sprintf("/api/v1/items/%s", itemID),
+						bytes.NewBuffer(doc),
+					)
+					Expect(err).ToNot(HaveOccurred())
+					request.Header.Set("Content-Type", "application/json")
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusUnsupportedMediaType))
+				})
+			})
+		})
+
+		Context("DELETE", func() {
+			When("Succeed", func() {
+				It("Return an item from DB", func() {
+					itemID := assertion.SampleID.String()
+					serviceMock.On("Delete", ginCtx, itemID).
+						Return(nil)
+					ginCtx.Params = []gin.Param{
+						ginCtxParam("id", itemID),
+					}
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodDelete,
+						fmt.Sprintf("/api/v1/items/%s", itemID),
+						nil,
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					_, err = ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusNoContent))
+				})
+			})
+			When("Fails", func() {
+				It("Return a Not Found error", func() {
+					itemID := assertion.SampleID.String()
+					serviceMock.On("Delete", ginCtx, itemID).
+						Return(errorsAssertion.ErrNotFound)
+					ginCtx.Params = []gin.Param{
+						ginCtxParam("id", itemID),
+					}
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodDelete,
+						fmt.Sprintf("/api/v1/items/%s", itemID),
+						nil,
+					)
+
+					router.ServeHTTP(w, request)
+
+					_, err = ioutil.ReadAll(w.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusNotFound))
+				})
+			})
+		})
+
+		Context("RESTORE", func() {
+			When("Succeed", func() {
+				It("Return no content", func() {
+					itemID := assertion.SampleID.String()
+					serviceMock.On("Restore", ginCtx, itemID).
+						Return(nil)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPost,
+						fmt.Sprintf("/api/v1/items/%s/restore", itemID),
+						nil,
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusNoContent))
+				})
+			})
+			When("Fails", func() {
+				It("Return a Not Found error", func() {
+					itemID := assertion.SampleID.String()
+					serviceMock.On("Restore", ginCtx, itemID).
+						Return(errorsAssertion.ErrNotFound)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPost,
+						fmt.Sprintf("/api/v1/items/%s/restore", itemID),
+						nil,
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusNotFound))
+				})
+			})
+		})
+
+		Context("PURGE", func() {
+			When("Requested by an admin", func() {
+				It("Return no content", func() {
+					itemID := assertion.SampleID.String()
+					serviceMock.On("Purge", mock.Anything, itemID).
+						Return(nil)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						auth.WithPrincipal(ginCtx, auth.Principal{Subject: "admin", Groups: []string{auth.AdminGroup}}),
+						http.MethodDelete,
+						fmt.Sprintf("/api/v1/items/%s/purge", itemID),
+						nil,
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusNoContent))
+				})
+			})
+			When("Requested by a non admin", func() {
+				It("Return a Forbidden error", func() {
+					itemID := assertion.SampleID.String()
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						auth.WithPrincipal(ginCtx, auth.Principal{Subject: "john"}),
+						http.MethodDelete,
+						fmt.Sprintf("/api/v1/items/%s/purge", itemID),
+						nil,
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusForbidden))
+				})
+			})
+			When("Requested anonymously", func() {
+				It("Return an Unauthorized error", func() {
+					itemID := assertion.SampleID.String()
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodDelete,
+						fmt.Sprintf("/api/v1/items/%s/purge", itemID),
+						nil,
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusUnauthorized))
+				})
+			})
+		})
+
+		Context("BATCH", func() {
+			When("Every item is created", func() {
+				It("Return created with the result of every item", func() {
+					itemInput := assertion.NewItemWithoutID()
+					inputInBytes := assertion.ArrayOfItemInBytes([]*assertion.Item{itemInput})
+					result := &batch.Result{
+						Mode:      batch.Atomic,
+						Succeeded: 1,
+						Items:     []batch.ItemResult{{Index: 0, ID: assertion.SampleID.String(), Status: http.StatusCreated}},
+					}
+					serviceMock.On("CreateBatch", mock.Anything, []*assertion.Item{itemInput}, batch.Atomic).
+						Return(result, nil)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPost,
+						"/api/v1/items/batch",
+						bytes.NewBuffer(inputInBytes),
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					respInBytes, err := ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusCreated))
+					Expect(respInBytes).To(MatchJSON(fmt.Sprintf(`{
+						"mode": "atomic",
+						"succeeded": 1,
+						"failed": 0,
+						"items": [{"index": 0, "id": "%s", "status": 201}]
+					}`, assertion.SampleID)))
+				})
+			})
+			When("Some items fail in best-effort mode", func() {
+				It("Return multi-status", func() {
+					ids := []uuid.UUID{assertion.SampleID}
+					result := &batch.Result{
+						Mode:   batch.BestEffort,
+						Failed: 1,
+						Items:  []batch.ItemResult{{Index: 0, ID: assertion.SampleID.String(), Status: http.StatusNotFound}},
+					}
+					serviceMock.On("DeleteBatch", mock.Anything, ids, batch.BestEffort).
+						Return(result, nil)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodDelete,
+						"/api/v1/items/batch?mode=best-effort",
+						bytes.NewBufferString(fmt.Sprintf(`["%s"]`, assertion.SampleID)),
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusMultiStatus))
+				})
+			})
+			When("Mode is unknown", func() {
+				It("Return a Bad Request error", func() {
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPut,
+						"/api/v1/items/batch?mode=eventually",
+						bytes.NewBufferString("[]"),
+					)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusBadRequest))
+					serviceMock.AssertNotCalled(GinkgoT(), "UpsertBatch", mock.Anything, mock.Anything, mock.Anything)
+				})
+			})
+		})
+
+		Context("EXPORT", func() {
+			When("Succeed", func() {
+				It("Stream the items as an attachment", func() {
+					serviceMock.On("Export", mock.Anything, transfer.CSV, mock.Anything).
+						Run(func(args mock.Arguments) {
+							_, err := args.Get(2).(io.Writer).Write([]byte("id,name\n"))
+							Expect(err).ToNot(HaveOccurred())
+						}).
+						Return(nil)
+
+					register()
+
+					request, err := http.NewRequestWithContext(ginCtx, http.MethodGet, "/api/v1/items/export?format=csv", nil)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusOK))
+					Expect(w.Header().Get(HeaderContentType)).To(Equal(transfer.CSVContentType))
+					Expect(w.Header().Get(HeaderContentDisposition)).To(Equal(`attachment; filename="items.csv"`))
+					Expect(w.Body.String()).To(Equal("id,name\n"))
+				})
+			})
+			When("Fails before writing", func() {
+				It("Return an Internal Server Error", func() {
+					serviceMock.On("Export", mock.Anything, transfer.NDJSON, mock.Anything).
+						Return(errorsAssertion.ErrGeneric)
+
+					register()
+
+					request, err := http.NewRequestWithContext(ginCtx, http.MethodGet, "/api/v1/items/export", nil)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusInternalServerError))
+					Expect(w.Header().Get(HeaderContentDisposition)).To(BeEmpty())
+				})
+			})
+			When("Format is unknown", func() {
+				It("Return an Unsupported Media Type error", func() {
+					register()
+
+					request, err := http.NewRequestWithContext(ginCtx, http.MethodGet, "/api/v1/items/export?format=xml", nil)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusUnsupportedMediaType))
+				})
+			})
+		})
+
+		Context("IMPORT", func() {
+			When("Some rows fail", func() {
+				It("Return multi-status with the report", func() {
+					report := transfer.NewImportReport()
+					report.AddError(2, errorsAssertion.ErrGeneric)
+					serviceMock.On("Import", mock.Anything, transfer.NDJSON, mock.Anything).
+						Return(report, nil)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPost,
+						"/api/v1/items/import",
+						bytes.NewBufferString("{}\n"),
+					)
+					Expect(err).ToNot(HaveOccurred())
+					request.Header.Set(HeaderContentType, transfer.NDJSONContentType)
+
+					router.ServeHTTP(w, request)
+
+					respInBytes, err := ioutil.ReadAll(w.Body)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(w.Code).To(Equal(http.StatusMultiStatus))
+					Expect(respInBytes).To(MatchJSON(fmt.Sprintf(`{
+						"imported": 0,
+						"failed": 1,
+						"errors": [{"line": 2, "error": "%s"}]
+					}`, errorsAssertion.ErrGeneric)))
+				})
+			})
+			When("The client prefers an asynchronous response", func() {
+				It("Return accepted with the location of the import job", func() {
+					queued := &job.Job{ID: uuid.NewV4(), Type: "import", Status: job.Queued}
+					serviceMock.On("ImportAsync", mock.Anything, transfer.NDJSON, mock.Anything).
+						Return(queued, nil)
+
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPost,
+						"/api/v1/items/import",
+						bytes.NewBufferString("{}\n"),
+					)
+					Expect(err).ToNot(HaveOccurred())
+					request.Header.Set(HeaderContentType, transfer.NDJSONContentType)
+					request.Header.Set("Prefer", transfer.RespondAsync)
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusAccepted))
+					Expect(w.Header().Get(HeaderLocation)).To(Equal("/api/v1/jobs/" + queued.ID.String()))
+					Expect(w.Header().Get(HeaderPreferenceApplied)).To(Equal(transfer.RespondAsync))
+				})
+			})
+			When("File format is not supported", func() {
+				It("Return an Unsupported Media Type error", func() {
+					register()
+
+					request, err := http.NewRequestWithContext(
+						ginCtx,
+						http.MethodPost,
+						"/api/v1/items/import",
+						bytes.NewBufferString("<items/>"),
+					)
+					Expect(err).ToNot(HaveOccurred())
+					request.Header.Set(HeaderContentType, "application/xml")
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusUnsupportedMediaType))
+				})
+			})
+		})
+
+		Context("STREAM", func() {
+			When("Succeed", func() {
+				It("Stream the changes as Server-Sent Events", func() {
+					filter := changefeed.Filter{Types: []changefeed.EventType{changefeed.Deleted}}
+					events := make(chan changefeed.Event, 1)
+					events <- changefeed.Event{ID: "02", Type: changefeed.Deleted, ItemID: assertion.SampleID}
+					close(events)
+					serviceMock.On("Subscribe", mock.Anything, filter, "01").
+						Return((<-chan changefeed.Event)(events), nil)
+
+					register()
+
+					request, err := http.NewRequestWithContext(ginCtx, http.MethodGet, "/api/v1/items/stream?types=deleted", nil)
+					Expect(err).ToNot(HaveOccurred())
+					request.Header.Set(HeaderLastEventID, "01")
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusOK))
+					Expect(w.Header().Get(HeaderContentType)).To(Equal("text/event-stream"))
+					Expect(w.Body.String()).To(HavePrefix("id: 02\nevent: deleted\n"))
+				})
+			})
+			When("Filter is invalid", func() {
+				It("Return a Bad Request error", func() {
+					register()
+
+					request, err := http.NewRequestWithContext(ginCtx, http.MethodGet, "/api/v1/items/stream?ids=15664c2f", nil)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusBadRequest))
+					serviceMock.AssertNotCalled(GinkgoT(), "Subscribe", mock.Anything, mock.Anything, mock.Anything)
+				})
+			})
+			When("Change feed is disabled", func() {
+				It("Return a Service Unavailable error", func() {
+					serviceMock.On("Subscribe", mock.Anything, changefeed.Filter{}, "").
+						Return(nil, errors.ErrChangeFeedDisabled)
+
+					register()
+
+					request, err := http.NewRequestWithContext(ginCtx, http.MethodGet, "/api/v1/items/stream", nil)
+					Expect(err).ToNot(HaveOccurred())
+
+					router.ServeHTTP(w, request)
+
+					Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+				})
+			})
+		})
+	})
+
+	// the items are stored by the executors of postgresql, which sqlite runs
+	Context("Items stored in the database", func() {
+		BeforeEach(func() {
+			database, err := sqlite.New(sqlite.MemoryPath)
+			Expect(err).ToNot(HaveOccurred())
+			logMock := pkgMock.NewLogger(GinkgoT())
+			logMock.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			deps.Service = service.New[assertion.Item](&service.DependenciesNode[assertion.Item]{
+				Repository: repository.New[assertion.Item](&repository.DependenciesNode{
+					Database: database,
+					Cache:    cacheMemory.New(cacheMemory.Config{}),
+				}, repository.Config{Name: "handler"}),
+				Log: logMock,
+			}, service.Config{Name: "handler"})
+		})
+
+		When("the item is unknown", func() {
+			It("Return a Not Found error on PUT", func() {
+				register()
+
+				request, err := http.NewRequestWithContext(
+					ginCtx,
+					http.MethodPut,
+					fmt.Sprintf("/api/v1/items/%s", assertion.SampleID),
+					bytes.NewBuffer(assertion.ItemInBytes(assertion.NewItemWithoutID())),
+				)
+				Expect(err).ToNot(HaveOccurred())
+
+				router.ServeHTTP(w, request)
+
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+			It("Return a Not Found error on DELETE", func() {
+				register()
+
+				request, err := http.NewRequestWithContext(
+					ginCtx,
+					http.MethodDelete,
+					fmt.Sprintf("/api/v1/items/%s", assertion.SampleID),
+					nil,
+				)
+				Expect(err).ToNot(HaveOccurred())
+
+				router.ServeHTTP(w, request)
+
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+})
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"fmt"
+
+	"app/internal/metric"
+)
+
+const (
+	NameFormat          = "repository_%s_latency_in_seconds"
+	NamespaceFormat     = "repository_%s_gateway"
+	DescriptionProperty = "Describes http response time in seconds"
+
+	InvalidationFailureNameFormat          = "repository_%s_invalidation_failure_count"
+	InvalidationFailureDescriptionProperty = "Cache keys the writes failed to invalidate"
+
+	queryTypePropertyKey = "queryType"
+)
+
+type Metrics struct {
+	Latency              metric.HistogramVec
+	InvalidationFailures metric.CounterVec
+}
+
+// Initialize returns the metrics of the repository named name, like a for the repository of serviceA
+func Initialize(name string) *Metrics {
+	return &Metrics{
+		Latency:              metric.NewHistogram(latencyMetricProperties(name)),
+		InvalidationFailures: metric.NewCounter(invalidationFailureMetricProperties(name)),
+	}
+}
+
+func latencyMetricProperties(name string) metric.Properties {
+	return metric.Properties{
+		Name:        fmt.Sprintf(NameFormat, name),
+		Namespace:   fmt.Sprintf(NamespaceFormat, name),
+		Description: DescriptionProperty,
+		Type:        metric.HistogramVecType,
+		Properties:  []string{queryTypePropertyKey},
+	}
+}
+
+func invalidationFailureMetricProperties(name string) metric.Properties {
+	return metric.Properties{
+		Name:        fmt.Sprintf(InvalidationFailureNameFormat, name),
+		Namespace:   fmt.Sprintf(NamespaceFormat, name),
+		Description: InvalidationFailureDescriptionProperty,
+		Type:        metric.CounterVecType,
+	}
+}
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/batch"
+	"app/internal/cacheaside"
+	"app/internal/crud/repository/metrics"
+	"app/internal/entity"
+	"app/internal/lock"
+	"app/internal/storage"
+)
+
+const (
+	AllItemsKey = "all-items"
+
+	cachedQueryMetric = "cached"
+	dbQueryMetric     = "db"
+
+	failedToInvalidate = "failed to invalidate the cached keys %v: %v\n"
+)
+
+// Repository stores the entities T in the database, caching them aside in the cache
+type Repository[T interface{}] interface {
+	GetAll(ctx context.Context) ([]*T, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*T, error)
+	Stream(ctx context.Context, item *T, each func() error) error
+	Insert(ctx context.Context, item *T) (*T, error)
+	Update(ctx context.Context, id uuid.UUID, item *T) error
+	Patch(ctx context.Context, id uuid.UUID, item *T, columns map[string]interface{}) error
+	Remove(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, id uuid.UUID) error
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) error
+	InsertBatch(ctx context.Context, items []*T, mode batch.Mode) error
+	UpsertBatch(ctx context.Context, items []*T, mode batch.Mode) error
+	RemoveBatch(ctx context.Context, ids []uuid.UUID, mode batch.Mode) error
+	// Transaction runs fn in a database transaction joined by the calls made with the ctx given to fn
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type DependenciesNode struct {
+	Database storage.Database
+	Cache    storage.Cache
+	// Locker is optional, letting a single replica at a time reload an item or the list missing from the cache
+	Locker lock.Locker
+}
+
+type Config struct {
+	// Name identifies the repository in the metrics, like a for the repository of serviceA
+	Name string
+	// KeyPrefix prefixes the cache keys, so services sharing a cache server don't read each other's items
+	KeyPrefix string
+	// CacheAside protects the database from the concurrent reloads of an item or the list missing from the cache
+	CacheAside cacheaside.Config
+}
+
+type repository[T interface{}, P entity.Entity[T]] struct {
+	deps    *DependenciesNode
+	metrics *metrics.Metrics
+	prefix  string
+	list    cacheaside.Loader[[]*T]
+	item    cacheaside.Loader[*T]
+}
+
+// New returns the Repository of the entities T, P being inferred as *T
+func New[T interface{}, P entity.Entity[T]](deps *DependenciesNode, config Config) Repository[T] {
+	loaderDeps := &cacheaside.DependenciesNode{Cache: deps.Cache, Locker: deps.Locker}
+	return &repository[T, P]{
+		deps:    deps,
+		metrics: metrics.Initialize(config.Name),
+		prefix:  config.KeyPrefix,
+		list:    cacheaside.New[[]*T](loaderDeps, config.CacheAside),
+		item:    cacheaside.New[*T](loaderDeps, config.CacheAside),
+	}
+}
+
+func (r *repository[T, P]) GetAll(ctx context.Context) ([]*T, error) {
+	startTime := time.Now()
+	itemArr, cached, err := r.list.Load(ctx, r.allItemsKey(), func(ctx context.Context) ([]*T, error) {
+		var itemArr []*T
+		return itemArr, r.deps.Database.Select(ctx, &itemArr)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.observe(startTime, cached)
+
+	return itemArr, nil
+}
+
+func (r *repository[T, P]) GetByID(ctx context.Context, id uuid.UUID) (*T, error) {
+	startTime := time.Now()
+	item, cached, err := r.item.Load(ctx, r.idKey(id), func(ctx context.Context) (*T, error) {
+		item := new(T)
+		P(item).SetID(id)
+		return item, r.deps.Database.Select(ctx, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.observe(startTime, cached)
+
+	return item, nil
+}
+
+// Stream scans the stored items one at a time into item, calling each after every item.
+// The cache is bypassed since the whole table is read
+func (r *repository[T, P]) Stream(ctx context.Context, item *T, each func() error) error {
+	startTime := time.Now()
+	err := r.deps.Database.Stream(ctx, item, each)
+
+	r.metrics.Latency.Observe(time.Since(startTime).Seconds(), dbQueryMetric)
+
+	return err
+}
+
+func (r *repository[T, P]) Insert(ctx context.Context, item *T) (*T, error) {
+	startTime := time.Now()
+	r.invalidate(r.allItemsKey())
+	defer r.invalidateCommitted(ctx, r.allItemsKey())
+
+	if err := r.deps.Database.Create(ctx, item); err != nil {
+		return nil, err
+	}
+
+	r.metrics.Latency.Observe(time.Since(startTime).Seconds(), dbQueryMetric)
+
+	return item, nil
+}
+
+func (r *repository[T, P]) Update(ctx context.Context, id uuid.UUID, item *T) error {
+	startTime := time.Now()
+	keys := []string{r.idKey(id), r.allItemsKey()}
+	r.invalidate(keys...)
+	defer r.invalidateCommitted(ctx, keys...)
+
+	r.metrics.Latency.Observe(time.Since(startTime).Seconds(), dbQueryMetric)
+
+	return r.deps.Database.Update(ctx, id, item)
+}
+
+func (r *repository[T, P]) Patch(ctx context.Context, id uuid.UUID, item *T, columns map[string]interface{}) error {
+	startTime := time.Now()
+	keys := []string{r.idKey(id), r.allItemsKey()}
+	r.invalidate(keys...)
+	defer r.invalidateCommitted(ctx, keys...)
+
+	r.metrics.Latency.Observe(time.Since(startTime).Seconds(), dbQueryMetric)
+
+	return r.deps.Database.SetColumns(ctx, item, columns)
+}
+
+func (r *repository[T, P]) Remove(ctx context.Context, id uuid.UUID) error {
+	startTime := time.Now()
+	keys := []string{r.idKey(id), r.allItemsKey()}
+	r.invalidate(keys...)
+	defer r.invalidateCommitted(ctx, keys...)
+
+	r.metrics.Latency.Observe(time.Since(startTime).Seconds(), dbQueryMetric)
+
+	return r.deps.Database.Delete(ctx, id, new(T))
+}
+
+func (r *repository[T, P]) Restore(ctx context.Context, id uuid.UUID) error {
+	startTime := time.Now()
+	keys := []string{r.idKey(id), r.allItemsKey()}
+	r.invalidate(keys...)
+	defer r.invalidateCommitted(ctx, keys...)
+
+	r.metrics.Latency.Observe(time.Since(startTime).Seconds(), dbQueryMetric)
+
+	return r.deps.Database.Restore(ctx, id, new(T))
+}
+
+func (r *repository[T, P]) Purge(ctx context.Context, id uuid.UUID) error {
+	startTime := time.Now()
+	keys := []string{r.idKey(id), r.allItemsKey()}
+	r.invalidate(keys...)
+	defer r.invalidateCommitted(ctx, keys...)
+
+	r.metrics.Latency.Observe(time.Since(startTime).Seconds(), dbQueryMetric)
+
+	return r.deps.Database.Purge(ctx, id, new(T))
+}
+
+func (r *repository[T, P]) PurgeDeleted(ctx context.Context, deletedBefore time.Time) error {
+	startTime := time.Now()
+	err := r.deps.Database.PurgeDeleted(ctx, new(T), deletedBefore)
+
+	r.metrics.Latency.Observe(time.Since(startTime).Seconds(), dbQueryMetric)
+
+	return err
+}
+
+func (r *repository[T, P]) InsertBatch(ctx context.Context, items []*T, mode batch.Mode) error {
+	startTime := time.Now()
+	r.invalidate(r.allItemsKey())
+	defer r.invalidateCommitted(ctx, r.allItemsKey())
+
+	err := r.deps.Database.CreateBatch(ctx, items, mode)
+
+	r.metrics.Latency.Observe(time.Since(startTime).Seconds(), dbQueryMetric)
+
+	return err
+}
+
+// UpsertBatch invalidates the cached list and every item of the batch with a single cache call around the write
+func (r *repository[T, P]) UpsertBatch(ctx context.Context, items []*T, mode batch.Mode) error {
+	startTime := time.Now()
+	keys := make([]string, 0, len(items)+1)
+	keys = append(keys, r.allItemsKey())
+	for _, item := range items {
+		keys = append(keys, r.idKey(P(item).GetID()))
+	}
+
+	r.invalidate(keys...)
+	defer r.invalidateCommitted(ctx, keys...)
+
+	err := r.deps.Database.UpsertBatch(ctx, items, mode)
+
+	r.metrics.Latency.Observe(time.Since(startTime).Seconds(), dbQueryMetric)
+
+	return err
+}
+
+// RemoveBatch invalidates the cached list and every item of the batch with a single cache call around the write
+func (r *repository[T, P]) RemoveBatch(ctx context.Context, ids []uuid.UUID, mode batch.Mode) error {
+	startTime := time.Now()
+	keys := make([]string, 0, len(ids)+1)
+	keys = append(keys, r.allItemsKey())
+	for _, id := range ids {
+		keys = append(keys, r.idKey(id))
+	}
+
+	r.invalidate(keys...)
+	defer r.invalidateCommitted(ctx, keys...)
+
+	err := r.deps.Database.DeleteBatch(ctx, ids, new(T), mode)
+
+	r.metrics.Latency.Observe(time.Since(startTime).Seconds(), dbQueryMetric)
+
+	return err
+}
+
+// Transaction invalidates the keys of the writes made by fn once the transaction ends, a read made meanwhile caching
+// the rows as they were before the commit
+func (r *repository[T, P]) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(committedKey{}).(*committed); ok {
+		return r.deps.Database.Transaction(ctx, fn)
+	}
+
+	c := &committed{}
+	defer c.run()
+	return r.deps.Database.Transaction(context.WithValue(ctx, committedKey{}, c), fn)
+}
+
+// invalidateCommitted removes the cached keys once the write is committed: after the transaction of ctx if any,
+// right away otherwise
+func (r *repository[T, P]) invalidateCommitted(ctx context.Context, keys ...string) {
+	if c, ok := ctx.Value(committedKey{}).(*committed); ok {
+		c.add(func() {
+			r.invalidate(keys...)
+		})
+		return
+	}
+	r.invalidate(keys...)
+}
+
+// invalidate removes the cached keys around a write. A cache failing doesn't fail the write: it's logged and counted,
+// the keys left being served until they expire
+func (r *repository[T, P]) invalidate(keys ...string) {
+	if err := r.deps.Cache.Remove(keys...); err != nil {
+		log.Printf(failedToInvalidate, keys, err)
+		r.metrics.InvalidationFailures.Increment()
+	}
+}
+
+func (r *repository[T, P]) allItemsKey() string {
+	return r.prefix + AllItemsKey
+}
+
+func (r *repository[T, P]) idKey(id uuid.UUID) string {
+	return r.prefix + id.String()
+}
+
+func (r *repository[T, P]) observe(startTime time.Time, cached bool) {
+	queryType := dbQueryMetric
+	if cached {
+		queryType = cachedQueryMetric
+	}
+	r.metrics.Latency.Observe(time.Since(startTime).Seconds(), queryType)
+}
+
+type committedKey struct{}
+
+// committed holds the invalidations of the writes made in a transaction, made once it ends
+type committed struct {
+	mu            sync.Mutex
+	invalidations []func()
+}
+
+func (c *committed) add(invalidation func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidations = append(c.invalidations, invalidation)
+}
+
+func (c *committed) run() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, invalidation := range c.invalidations {
+		invalidation()
+	}
+}
@@ -0,0 +1,825 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/mock"
+
+	cacheMemory "app/infra/cache/memory"
+	databaseMemory "app/infra/database/memory"
+	"app/internal/batch"
+	commonAssertion "app/internal/test/assertion/common"
+	assertion "app/internal/test/assertion/crud"
+	errorsAssertion "app/internal/test/assertion/errors"
+	storageMock "app/internal/test/mocks/storage"
+)
+
+func TestService(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Repository Suits")
+}
+
+// loadCtx matches the ctx the items are loaded with, detached from the ctx of the caller so the concurrent callers
+// sharing the load can each give up on it
+var loadCtx = mock.MatchedBy(func(ctx context.Context) bool {
+	return ctx.Err() == nil
+})
+
+var _ = Describe("Repository", func() {
+	var (
+		cacheMock    *storageMock.Cache
+		databaseMock *storageMock.Database
+		repo         Repository[assertion.Item]
+	)
+
+	BeforeEach(func() {
+		cacheMock = storageMock.NewCache(GinkgoT())
+		databaseMock = storageMock.NewDatabase(GinkgoT())
+		repo = New[assertion.Item](
+			&DependenciesNode{
+				Database: databaseMock,
+				Cache:    cacheMock,
+			},
+			Config{Name: "test"},
+		)
+	})
+
+	Context("Testing CRUD operations", func() {
+		Context("Getting all items", func() {
+			When("Item is in cache", func() {
+				When("Succeeds", func() {
+					It("Should return an item from cache", func() {
+						expectedItemArr := assertion.ArrayOfItem
+						itemArrInBytes := assertion.ArrayOfItemInBytes(expectedItemArr)
+						cacheMock.On("Get", AllItemsKey).
+							Return(itemArrInBytes, nil).
+							Once()
+
+						item, err := repo.GetAll(commonAssertion.EmptyCtx)
+
+						Expect(err).ShouldNot(HaveOccurred())
+						Expect(item).To(Equal(expectedItemArr))
+					})
+				})
+				When("Fails", func() {
+					It("Should return the items from the database without caching them", func() {
+						var emptyArr []*assertion.Item
+						cacheMock.On("Get", AllItemsKey).
+							Return(nil, errorsAssertion.ErrGeneric).
+							Once()
+						databaseMock.On("Select", loadCtx, &emptyArr).
+							Return(nil).
+							Once()
+
+						_, err := repo.GetAll(commonAssertion.EmptyCtx)
+
+						Expect(err).ShouldNot(HaveOccurred())
+						cacheMock.AssertNotCalled(GinkgoT(), "Set", AllItemsKey, emptyArr)
+					})
+				})
+			})
+			When("Item is not in cache", func() {
+				When("Succeeds", func() {
+					It("Should return an item", func() {
+						var emptyArr []*assertion.Item
+						cacheMock.On("Get", AllItemsKey).
+							Return(nil, nil).
+							Once()
+						databaseMock.On("Select", loadCtx, &emptyArr).
+							Return(nil).
+							Once()
+						cacheMock.On("Set", AllItemsKey, emptyArr).
+							Return(nil).
+							Once()
+
+						_, err := repo.GetAll(commonAssertion.EmptyCtx)
+
+						Expect(err).ShouldNot(HaveOccurred())
+					})
+				})
+				When("Fails to get item from Database", func() {
+					It("Should return an error", func() {
+						var emptyArr []*assertion.Item
+						cacheMock.On("Get", AllItemsKey).
+							Return(nil, nil).
+							Once()
+						databaseMock.On("Select", loadCtx, &emptyArr).
+							Return(errorsAssertion.ErrGeneric).
+							Once()
+
+						item, err := repo.GetAll(commonAssertion.EmptyCtx)
+
+						Expect(err).Should(HaveOccurred())
+						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+						Expect(item).To(BeNil())
+					})
+				})
+				When("Fails to set cache", func() {
+					It("Should return the items from the database", func() {
+						var emptyArr []*assertion.Item
+						cacheMock.On("Get", AllItemsKey).
+							Return(nil, nil).
+							Once()
+						databaseMock.On("Select", loadCtx, &emptyArr).
+							Return(nil).
+							Once()
+						cacheMock.On("Set", AllItemsKey, emptyArr).
+							Return(errorsAssertion.ErrGeneric).
+							Once()
+
+						_, err := repo.GetAll(commonAssertion.EmptyCtx)
+
+						Expect(err).ShouldNot(HaveOccurred())
+					})
+				})
+			})
+		})
+
+		Context("Getting one item", func() {
+			When("Item is in cache", func() {
+				When("Succeeds", func() {
+					It("Should return an item from cache", func() {
+						idString := assertion.SampleID.String()
+						expectedItem := assertion.NewItemWithID(idString)
+						itemInBytes := assertion.ItemInBytes(expectedItem)
+						cacheMock.On("Get", idString).
+							Return(itemInBytes, nil).
+							Once()
+
+						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
+
+						Expect(err).ShouldNot(HaveOccurred())
+						Expect(item).To(Equal(expectedItem))
+					})
+				})
+				When("Fails", func() {
+					It("Should return the item from the database without caching it", func() {
+						idString := assertion.SampleID.String()
+						expectedItem := assertion.NewItemReference(idString)
+						cacheMock.On("Get", idString).
+							Return(nil, errorsAssertion.ErrGeneric).
+							Once()
+						databaseMock.On("Select", loadCtx, assertion.NewItemReference(idString)).
+							Return(nil).
+							Once()
+
+						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
+
+						Expect(err).ShouldNot(HaveOccurred())
+						Expect(item).To(Equal(expectedItem))
+						cacheMock.AssertNotCalled(GinkgoT(), "Set", idString, expectedItem)
+					})
+				})
+			})
+			When("Item is not in cache", func() {
+				When("Succeeds", func() {
+					It("Should return an item", func() {
+						idString := assertion.SampleID.String()
+						expectedItem := assertion.NewItemReference(idString)
+						cacheMock.On("Get", idString).
+							Return(nil, nil).
+							Once()
+						databaseMock.On("Select", loadCtx, assertion.NewItemReference(idString)).
+							Return(nil).
+							Once()
+						cacheMock.On("Set", idString, expectedItem).
+							Return(nil).
+							Once()
+
+						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
+
+						Expect(err).ShouldNot(HaveOccurred())
+						Expect(item).To(Equal(expectedItem))
+					})
+				})
+				When("Fails to get item from Database", func() {
+					It("Should return an error", func() {
+						idString := assertion.SampleID.String()
+						cacheMock.On("Get", idString).
+							Return(nil, nil).
+							Once()
+						databaseMock.On("Select", loadCtx, assertion.NewItemReference(idString)).
+							Return(errorsAssertion.ErrGeneric).
+							Once()
+
+						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
+
+						Expect(err).Should(HaveOccurred())
+						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+						Expect(item).To(BeNil())
+					})
+				})
+				When("Fails to set cache", func() {
+					It("Should return the item from the database", func() {
+						idString := assertion.SampleID.String()
+						expectedItem := assertion.NewItemReference(idString)
+						cacheMock.On("Get", idString).
+							Return(nil, nil).
+							Once()
+						databaseMock.On("Select", loadCtx, assertion.NewItemReference(idString)).
+							Return(nil).
+							Once()
+						cacheMock.On("Set", idString, expectedItem).
+							Return(errorsAssertion.ErrGeneric).
+							Once()
+
+						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
+
+						Expect(err).ShouldNot(HaveOccurred())
+						Expect(item).To(Equal(expectedItem))
+					})
+				})
+			})
+		})
+
+		Context("Creating an item", func() {
+			When("Succeeds", func() {
+				It("Should return an error", func() {
+					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
+					expectedItem := assertion.NewItemFromInput(inputItem)
+					cacheMock.On("Remove", AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("Create", commonAssertion.EmptyCtx, inputItem).
+						Return(nil).
+						Once()
+
+					item, err := repo.Insert(commonAssertion.EmptyCtx, inputItem)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(item.ID).NotTo(BeNil())
+					Expect(item).To(Equal(expectedItem))
+				})
+			})
+			When("Fails to insert item", func() {
+				It("Should return an error", func() {
+					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
+					cacheMock.On("Remove", AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("Create", commonAssertion.EmptyCtx, inputItem).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+
+					item, err := repo.Insert(commonAssertion.EmptyCtx, inputItem)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+					Expect(item).To(BeNil())
+				})
+			})
+			When("Fail to remove all cached items", func() {
+				It("Should insert the item anyway", func() {
+					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
+					cacheMock.On("Remove", AllItemsKey).
+						Return(errorsAssertion.ErrGeneric).
+						Twice()
+					databaseMock.On("Create", commonAssertion.EmptyCtx, inputItem).
+						Return(nil).
+						Once()
+
+					item, err := repo.Insert(commonAssertion.EmptyCtx, inputItem)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(item).To(Equal(inputItem))
+				})
+			})
+		})
+
+		Context("Updating an item", func() {
+			When("Succeeds", func() {
+				It("Should return nothing", func() {
+					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("Update", commonAssertion.EmptyCtx, assertion.SampleID, inputItem).
+						Return(nil).
+						Once()
+
+					err := repo.Update(commonAssertion.EmptyCtx, assertion.SampleID, inputItem)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Fail to update item on DB", func() {
+				It("Should return an error", func() {
+					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("Update", commonAssertion.EmptyCtx, assertion.SampleID, inputItem).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+
+					err := repo.Update(commonAssertion.EmptyCtx, assertion.SampleID, inputItem)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+			When("Fail to remove cached items", func() {
+				It("Should update the item anyway", func() {
+					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(errorsAssertion.ErrGeneric).
+						Twice()
+					databaseMock.On("Update", commonAssertion.EmptyCtx, assertion.SampleID, inputItem).
+						Return(nil).
+						Once()
+
+					err := repo.Update(commonAssertion.EmptyCtx, assertion.SampleID, inputItem)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+		})
+
+		Context("Patching an item", func() {
+			When("Succeeds", func() {
+				It("Should return nothing", func() {
+					item := assertion.NewItemWithID(assertion.SampleID.String())
+					columns := map[string]interface{}{"name": ""}
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("SetColumns", commonAssertion.EmptyCtx, item, columns).
+						Return(nil).
+						Once()
+
+					err := repo.Patch(commonAssertion.EmptyCtx, assertion.SampleID, item, columns)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Fail to remove cached items", func() {
+				It("Should update the columns anyway", func() {
+					item := assertion.NewItemWithID(assertion.SampleID.String())
+					columns := map[string]interface{}{"name": ""}
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(errorsAssertion.ErrGeneric).
+						Twice()
+					databaseMock.On("SetColumns", commonAssertion.EmptyCtx, item, columns).
+						Return(nil).
+						Once()
+
+					err := repo.Patch(commonAssertion.EmptyCtx, assertion.SampleID, item, columns)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Fail to update columns in DB", func() {
+				It("Should return an error", func() {
+					item := assertion.NewItemWithID(assertion.SampleID.String())
+					columns := map[string]interface{}{"name": ""}
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("SetColumns", commonAssertion.EmptyCtx, item, columns).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+
+					err := repo.Patch(commonAssertion.EmptyCtx, assertion.SampleID, item, columns)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+		})
+
+		Context("Deleting an item", func() {
+			When("Succeeds", func() {
+				It("Should return nothing", func() {
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("Delete", commonAssertion.EmptyCtx, assertion.SampleID, &assertion.Item{}).
+						Return(nil).
+						Once()
+
+					err := repo.Remove(commonAssertion.EmptyCtx, assertion.SampleID)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Fail to remove cached items", func() {
+				It("Should delete the item anyway", func() {
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(errorsAssertion.ErrGeneric).
+						Twice()
+					databaseMock.On("Delete", commonAssertion.EmptyCtx, assertion.SampleID, &assertion.Item{}).
+						Return(nil).
+						Once()
+
+					err := repo.Remove(commonAssertion.EmptyCtx, assertion.SampleID)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Fail to delete item from DB", func() {
+				It("Should return an error", func() {
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("Delete", commonAssertion.EmptyCtx, assertion.SampleID, &assertion.Item{}).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+
+					err := repo.Remove(commonAssertion.EmptyCtx, assertion.SampleID)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+		})
+
+		Context("Restoring an item", func() {
+			When("Succeeds", func() {
+				It("Should return nothing", func() {
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("Restore", commonAssertion.EmptyCtx, assertion.SampleID, &assertion.Item{}).
+						Return(nil).
+						Once()
+
+					err := repo.Restore(commonAssertion.EmptyCtx, assertion.SampleID)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Item is not deleted", func() {
+				It("Should return a not found error", func() {
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("Restore", commonAssertion.EmptyCtx, assertion.SampleID, &assertion.Item{}).
+						Return(errorsAssertion.ErrNotFound).
+						Once()
+
+					err := repo.Restore(commonAssertion.EmptyCtx, assertion.SampleID)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrNotFound))
+				})
+			})
+			When("Fail to remove cached items", func() {
+				It("Should restore the item anyway", func() {
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(errorsAssertion.ErrGeneric).
+						Twice()
+					databaseMock.On("Restore", commonAssertion.EmptyCtx, assertion.SampleID, &assertion.Item{}).
+						Return(nil).
+						Once()
+
+					err := repo.Restore(commonAssertion.EmptyCtx, assertion.SampleID)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+		})
+
+		Context("Purging an item", func() {
+			When("Succeeds", func() {
+				It("Should return nothing", func() {
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("Purge", commonAssertion.EmptyCtx, assertion.SampleID, &assertion.Item{}).
+						Return(nil).
+						Once()
+
+					err := repo.Purge(commonAssertion.EmptyCtx, assertion.SampleID)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Fail to purge item from DB", func() {
+				It("Should return an error", func() {
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("Purge", commonAssertion.EmptyCtx, assertion.SampleID, &assertion.Item{}).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+
+					err := repo.Purge(commonAssertion.EmptyCtx, assertion.SampleID)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+			When("Fail to remove cached items", func() {
+				It("Should purge the item anyway", func() {
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(errorsAssertion.ErrGeneric).
+						Twice()
+					databaseMock.On("Purge", commonAssertion.EmptyCtx, assertion.SampleID, &assertion.Item{}).
+						Return(nil).
+						Once()
+
+					err := repo.Purge(commonAssertion.EmptyCtx, assertion.SampleID)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+		})
+
+		Context("Purging deleted items", func() {
+			When("Succeeds", func() {
+				It("Should return nothing", func() {
+					deletedBefore := time.Now()
+					databaseMock.On("PurgeDeleted", commonAssertion.EmptyCtx, &assertion.Item{}, deletedBefore).
+						Return(nil).
+						Once()
+
+					err := repo.PurgeDeleted(commonAssertion.EmptyCtx, deletedBefore)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Fail to purge items from DB", func() {
+				It("Should return an error", func() {
+					deletedBefore := time.Now()
+					databaseMock.On("PurgeDeleted", commonAssertion.EmptyCtx, &assertion.Item{}, deletedBefore).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+
+					err := repo.PurgeDeleted(commonAssertion.EmptyCtx, deletedBefore)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+		})
+
+		Context("Inserting items in batch", func() {
+			When("Succeeds", func() {
+				It("Should invalidate the cached list once", func() {
+					items := []*assertion.Item{assertion.NewItemWithID(assertion.SampleID.String())}
+					cacheMock.On("Remove", AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("CreateBatch", commonAssertion.EmptyCtx, items, batch.Atomic).
+						Return(nil).
+						Once()
+
+					err := repo.InsertBatch(commonAssertion.EmptyCtx, items, batch.Atomic)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Fail to insert items in DB", func() {
+				It("Should return an error", func() {
+					items := []*assertion.Item{assertion.NewItemWithID(assertion.SampleID.String())}
+					cacheMock.On("Remove", AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("CreateBatch", commonAssertion.EmptyCtx, items, batch.BestEffort).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+
+					err := repo.InsertBatch(commonAssertion.EmptyCtx, items, batch.BestEffort)
+
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+			When("Fail to remove the cached list", func() {
+				It("Should insert the items anyway", func() {
+					items := []*assertion.Item{assertion.NewItemWithID(assertion.SampleID.String())}
+					cacheMock.On("Remove", AllItemsKey).
+						Return(errorsAssertion.ErrGeneric).
+						Twice()
+					databaseMock.On("CreateBatch", commonAssertion.EmptyCtx, items, batch.Atomic).
+						Return(nil).
+						Once()
+
+					err := repo.InsertBatch(commonAssertion.EmptyCtx, items, batch.Atomic)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+		})
+
+		Context("Upserting items in batch", func() {
+			When("Succeeds", func() {
+				It("Should invalidate the cached list and every item in a single call", func() {
+					items := assertion.ArrayOfItem
+					cacheMock.On("Remove",
+						AllItemsKey,
+						items[0].ID.String(),
+						items[1].ID.String(),
+						items[2].ID.String(),
+						items[3].ID.String(),
+					).
+						Return(nil).
+						Twice()
+					databaseMock.On("UpsertBatch", commonAssertion.EmptyCtx, items, batch.Atomic).
+						Return(nil).
+						Once()
+
+					err := repo.UpsertBatch(commonAssertion.EmptyCtx, items, batch.Atomic)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Fail to remove cached items", func() {
+				It("Should upsert the items anyway", func() {
+					items := []*assertion.Item{assertion.NewItemWithID(assertion.SampleID.String())}
+					cacheMock.On("Remove", AllItemsKey, assertion.SampleID.String()).
+						Return(errorsAssertion.ErrGeneric).
+						Twice()
+					databaseMock.On("UpsertBatch", commonAssertion.EmptyCtx, items, batch.Atomic).
+						Return(nil).
+						Once()
+
+					err := repo.UpsertBatch(commonAssertion.EmptyCtx, items, batch.Atomic)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+		})
+
+		Context("Removing items in batch", func() {
+			When("Succeeds", func() {
+				It("Should invalidate the cached list and every item in a single call", func() {
+					ids := []uuid.UUID{assertion.SampleID}
+					cacheMock.On("Remove", AllItemsKey, assertion.SampleID.String()).
+						Return(nil).
+						Twice()
+					databaseMock.On("DeleteBatch", commonAssertion.EmptyCtx, ids, &assertion.Item{}, batch.Atomic).
+						Return(nil).
+						Once()
+
+					err := repo.RemoveBatch(commonAssertion.EmptyCtx, ids, batch.Atomic)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Fail to delete items from DB", func() {
+				It("Should return an error", func() {
+					ids := []uuid.UUID{assertion.SampleID}
+					cacheMock.On("Remove", AllItemsKey, assertion.SampleID.String()).
+						Return(nil).
+						Twice()
+					databaseMock.On("DeleteBatch", commonAssertion.EmptyCtx, ids, &assertion.Item{}, batch.Atomic).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+
+					err := repo.RemoveBatch(commonAssertion.EmptyCtx, ids, batch.Atomic)
+
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+			When("Fail to remove cached items", func() {
+				It("Should delete the items anyway", func() {
+					ids := []uuid.UUID{assertion.SampleID}
+					cacheMock.On("Remove", AllItemsKey, assertion.SampleID.String()).
+						Return(errorsAssertion.ErrGeneric).
+						Twice()
+					databaseMock.On("DeleteBatch", commonAssertion.EmptyCtx, ids, &assertion.Item{}, batch.Atomic).
+						Return(nil).
+						Once()
+
+					err := repo.RemoveBatch(commonAssertion.EmptyCtx, ids, batch.Atomic)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+		})
+
+		Context("Streaming items", func() {
+			When("Succeeds", func() {
+				It("Should read the items from DB", func() {
+					item := &assertion.Item{}
+					databaseMock.On("Stream", commonAssertion.EmptyCtx, item, mock.AnythingOfType("func() error")).
+						Return(nil).
+						Once()
+
+					err := repo.Stream(commonAssertion.EmptyCtx, item, func() error { return nil })
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Fail to read items from DB", func() {
+				It("Should return an error", func() {
+					item := &assertion.Item{}
+					databaseMock.On("Stream", commonAssertion.EmptyCtx, item, mock.AnythingOfType("func() error")).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+
+					err := repo.Stream(commonAssertion.EmptyCtx, item, func() error { return nil })
+
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+		})
+
+		Context("Running a transaction", func() {
+			When("Succeeds", func() {
+				It("Should invalidate the keys of the writes again once it's committed", func() {
+					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
+					cacheMock.On("Remove", assertion.SampleID.String(), AllItemsKey).
+						Return(nil).
+						Twice()
+					databaseMock.On("Update", mock.Anything, assertion.SampleID, inputItem).
+						Return(nil).
+						Once()
+					databaseMock.On("Transaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+						Run(func(args mock.Arguments) {
+							fn := args.Get(1).(func(context.Context) error)
+							Expect(fn(args.Get(0).(context.Context))).To(Succeed())
+							cacheMock.AssertNumberOfCalls(GinkgoT(), "Remove", 1)
+						}).
+						Return(nil).
+						Once()
+
+					err := repo.Transaction(commonAssertion.EmptyCtx, func(ctx context.Context) error {
+						return repo.Update(ctx, assertion.SampleID, inputItem)
+					})
+
+					Expect(err).ShouldNot(HaveOccurred())
+					cacheMock.AssertNumberOfCalls(GinkgoT(), "Remove", 2)
+				})
+			})
+			When("The transaction fails", func() {
+				It("Should return the error of the DB", func() {
+					databaseMock.On("Transaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+
+					err := repo.Transaction(commonAssertion.EmptyCtx, func(context.Context) error { return nil })
+
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+		})
+
+		Context("Prefixing the cache keys", func() {
+			When("The repository has a key prefix", func() {
+				It("Should cache and invalidate the items under the prefix", func() {
+					prefixed := New[assertion.Item](
+						&DependenciesNode{
+							Database: databaseMock,
+							Cache:    cacheMock,
+						},
+						Config{Name: "test", KeyPrefix: "test:"},
+					)
+					ids := []uuid.UUID{assertion.SampleID}
+					cacheMock.On("Remove", "test:"+AllItemsKey, "test:"+assertion.SampleID.String()).
+						Return(nil).
+						Twice()
+					databaseMock.On("DeleteBatch", commonAssertion.EmptyCtx, ids, &assertion.Item{}, batch.Atomic).
+						Return(nil).
+						Once()
+
+					err := prefixed.RemoveBatch(commonAssertion.EmptyCtx, ids, batch.Atomic)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+		})
+	})
+})
+
+var _ = Describe("Repository on the memory storage", func() {
+	var repo Repository[assertion.Item]
+
+	BeforeEach(func() {
+		repo = New[assertion.Item](
+			&DependenciesNode{
+				Database: databaseMemory.New(),
+				Cache:    cacheMemory.New(cacheMemory.Config{}),
+			},
+			Config{Name: "test", KeyPrefix: "test:"},
+		)
+	})
+
+	When("An item is inserted, read and removed", func() {
+		It("Should read it from the database, then from the cache, until removed", func() {
+			item := assertion.NewItemWithID(assertion.SampleID.String())
+			_, err := repo.Insert(commonAssertion.EmptyCtx, item)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for range []int{1, 2} {
+				stored, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(stored.Name).To(Equal(assertion.SampleName))
+			}
+
+			Expect(repo.Remove(commonAssertion.EmptyCtx, assertion.SampleID)).To(Succeed())
+			_, err = repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
+			Expect(err).To(MatchError(errorsAssertion.ErrNotFound))
+
+			Expect(repo.Restore(commonAssertion.EmptyCtx, assertion.SampleID)).To(Succeed())
+			items, err := repo.GetAll(commonAssertion.EmptyCtx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(items).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,24 @@
+package service
+
+const (
+	FailedToGetAll             = "failed to get all"
+	FailedToGetByID            = "failed to get by id"
+	FailedToCreate             = "failed to create"
+	FailedToUpdate             = "failed to update"
+	FailedToPatch              = "failed to patch"
+	FailedToDelete             = "failed to delete"
+	FailedToRestore            = "failed to restore"
+	FailedToPurge              = "failed to purge"
+	FailedToParseUUID          = "failed to parse id to UUID"
+	FailedToValidate           = "failed to validate item"
+	FailedToPurgeDeleted       = "failed to purge deleted items"
+	FailedToCreateBatch        = "failed to create batch"
+	FailedToUpsertBatch        = "failed to upsert batch"
+	FailedToDeleteBatch        = "failed to delete batch"
+	FailedToExport             = "failed to export items"
+	FailedToImport             = "failed to import items"
+	FailedToEnqueueImport      = "failed to enqueue import job"
+	FailedToSubscribe          = "failed to subscribe to the change feed"
+	FailedToPublish            = "failed to publish change event"
+	FailedToValidateReferences = "failed to validate item references"
+)
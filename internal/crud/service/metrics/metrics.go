@@ -1,12 +1,14 @@
 package metrics
 
 import (
+	"fmt"
+
 	"app/internal/metric"
 )
 
 const (
 	NameProperty        = "error_count"
-	NamespaceProperty   = "service_b_gateway"
+	NamespaceFormat     = "service_%s_gateway"
 	DescriptionProperty = "Http requests received counter"
 
 	errorPropertyKey      = "error"
@@ -17,16 +19,17 @@ type Metrics struct {
 	ErrorCount metric.CounterVec
 }
 
-func Initialize() *Metrics {
+// Initialize returns the metrics of the service named name, like a for serviceA
+func Initialize(name string) *Metrics {
 	return &Metrics{
-		ErrorCount: metric.NewCounter(errorCountMetricProperties()),
+		ErrorCount: metric.NewCounter(errorCountMetricProperties(name)),
 	}
 }
 
-func errorCountMetricProperties() metric.Properties {
+func errorCountMetricProperties(name string) metric.Properties {
 	return metric.Properties{
 		Name:        NameProperty,
-		Namespace:   NamespaceProperty,
+		Namespace:   fmt.Sprintf(NamespaceFormat, name),
 		Description: DescriptionProperty,
 		Type:        metric.CounterVecType,
 		Properties:  []string{errorPropertyKey, statusCodePropertyKey},
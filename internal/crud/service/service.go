@@ -0,0 +1,776 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"io"
+	"net/http"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"app/internal/auth"
+	"app/internal/batch"
+	"app/internal/changefeed"
+	"app/internal/crud/repository"
+	"app/internal/crud/service/metrics"
+	"app/internal/entity"
+	"app/internal/errors"
+	"app/internal/identifier"
+	"app/internal/job"
+	"app/internal/logger"
+	"app/internal/messaging"
+	"app/internal/patch"
+	"app/internal/transfer"
+	"app/internal/validation"
+)
+
+const (
+	requestIDKey     = "requestID"
+	itemIDKey        = "itemID"
+	itemObjKey       = "item"
+	errorKey         = "error"
+	deletedBeforeKey = "deletedBefore"
+	batchModeKey     = "batchMode"
+	batchSizeKey     = "batchSize"
+	formatKey        = "format"
+	lineKey          = "line"
+	lastEventIDKey   = "lastEventID"
+	jobIDKey         = "jobID"
+)
+
+// itemEvents maps the change feed events to the domain events published on the message broker
+var itemEvents = map[changefeed.EventType]string{
+	changefeed.Created:  messaging.ItemCreated,
+	changefeed.Updated:  messaging.ItemUpdated,
+	changefeed.Deleted:  messaging.ItemDeleted,
+	changefeed.Restored: messaging.ItemRestored,
+}
+
+// Service validates the entities T, stores them with a repository.Repository and emits their changes
+type Service[T interface{}] interface {
+	GetAll(ctx context.Context) ([]*T, error)
+	GetOneByID(ctx context.Context, id string) (*T, error)
+	Create(ctx context.Context, item *T) (*T, error)
+	Update(ctx context.Context, id string, item *T) error
+	Patch(ctx context.Context, id string, contentType string, doc []byte) (*T, error)
+	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, id string) error
+	PurgeDeleted(ctx context.Context, retention time.Duration) error
+	CreateBatch(ctx context.Context, items []*T, mode batch.Mode) (*batch.Result, error)
+	UpsertBatch(ctx context.Context, items []*T, mode batch.Mode) (*batch.Result, error)
+	DeleteBatch(ctx context.Context, ids []uuid.UUID, mode batch.Mode) (*batch.Result, error)
+	Export(ctx context.Context, format transfer.Format, w io.Writer) error
+	Import(ctx context.Context, format transfer.Format, r io.Reader) (*transfer.ImportReport, error)
+	ImportAsync(ctx context.Context, format transfer.Format, r io.Reader) (*job.Job, error)
+	RunImportJob(ctx context.Context, j *job.Job, progress job.Progress) (interface{}, error)
+	Subscribe(ctx context.Context, filter changefeed.Filter, lastEventID string) (<-chan changefeed.Event, error)
+}
+
+// References checks that the entities referenced by an entity T exist, in other services for instance
+type References[T interface{}] interface {
+	// Columns are the columns holding references, a patch being checked only when it changes one of them
+	Columns() []string
+	// Check fails with a validation error when a referenced entity doesn't exist. checked memoises the lookups of
+	// a batch and is nil for a single entity
+	Check(ctx context.Context, item *T, checked map[uuid.UUID]bool) error
+}
+
+type DependenciesNode[T interface{}] struct {
+	Repository  repository.Repository[T]
+	Log         logger.Logger
+	IDGenerator identifier.Generator
+	// Events receives a change event for every item written, the change feed is disabled when nil
+	Events changefeed.Broker
+	// Messages receives a domain event for every item written, in the transaction of the change so the outbox
+	// stores the event if and only if the change is stored. No event is emitted when nil
+	Messages messaging.Publisher
+	// Jobs queues the imports run in the background, ImportAsync failing when nil
+	Jobs job.Queue
+	// References checks the references of the items written, they aren't checked when nil
+	References References[T]
+}
+
+type Config struct {
+	// Name identifies the service in the metrics, like a for serviceA
+	Name string
+	// EventSource is the CloudEvents source of the domain events of the service
+	EventSource string
+	// EventTopic is the topic the domain events of the items are published on
+	EventTopic string
+	// ImportJob is the type of the background jobs importing items
+	ImportJob string
+}
+
+type service[T interface{}, P entity.Entity[T]] struct {
+	deps    *DependenciesNode[T]
+	config  Config
+	metrics *metrics.Metrics
+}
+
+// New returns the Service of the entities T, P being inferred as *T
+func New[T interface{}, P entity.Entity[T]](deps *DependenciesNode[T], config Config) Service[T] {
+	return &service[T, P]{
+		deps:    deps,
+		config:  config,
+		metrics: metrics.Initialize(config.Name),
+	}
+}
+
+func (s *service[T, P]) GetAll(ctx context.Context) ([]*T, error) {
+	resp, err := s.deps.Repository.GetAll(ctx)
+	if err != nil {
+		s.handleError(ctx, err, FailedToGetAll, nil)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *service[T, P]) GetOneByID(ctx context.Context, id string) (*T, error) {
+	itemID, err := uuid.FromString(id)
+	if err != nil {
+		s.handleError(ctx, err, FailedToParseUUID, logrus.Fields{requestIDKey: id})
+		return nil, errors.ErrCreatingUUIDFromString
+	}
+
+	resp, err := s.deps.Repository.GetByID(ctx, itemID)
+	if err != nil {
+		s.handleError(ctx, err, FailedToGetByID, logrus.Fields{itemIDKey: itemID})
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *service[T, P]) Create(ctx context.Context, item *T) (*T, error) {
+	if err := validation.Struct(item); err != nil {
+		s.handleError(ctx, err, FailedToValidate, logrus.Fields{itemObjKey: item})
+		return nil, err
+	}
+	if P(item).GetID() != uuid.Nil {
+		s.handleError(ctx, errors.ErrClientSuppliedID, FailedToCreate, logrus.Fields{itemObjKey: item})
+		return nil, errors.ErrClientSuppliedID
+	}
+	if err := s.validateReferences(ctx, item, nil); err != nil {
+		s.handleError(ctx, err, FailedToValidateReferences, logrus.Fields{itemObjKey: item})
+		return nil, err
+	}
+	P(item).SetID(s.deps.IDGenerator.NewID())
+
+	var resp *T
+	err := s.inTransaction(ctx, func(ctx context.Context) (err error) {
+		if resp, err = s.deps.Repository.Insert(ctx, item); err != nil {
+			return err
+		}
+		return s.record(ctx, changefeed.Created, P(resp).GetID(), resp)
+	})
+	if err != nil {
+		s.handleError(ctx, err, FailedToCreate, logrus.Fields{itemObjKey: item})
+		return nil, err
+	}
+
+	s.publish(ctx, changefeed.Created, P(resp).GetID(), resp)
+	return resp, nil
+}
+
+func (s *service[T, P]) Update(ctx context.Context, id string, item *T) error {
+	itemID, err := uuid.FromString(id)
+	if err != nil {
+		s.handleError(ctx, err, FailedToParseUUID, logrus.Fields{requestIDKey: id})
+		return errors.ErrCreatingUUIDFromString
+	}
+
+	if err = validation.Struct(item); err != nil {
+		s.handleError(ctx, err, FailedToValidate, logrus.Fields{itemIDKey: itemID, itemObjKey: item})
+		return err
+	}
+
+	if err = s.validateReferences(ctx, item, nil); err != nil {
+		s.handleError(ctx, err, FailedToValidateReferences, logrus.Fields{itemIDKey: itemID, itemObjKey: item})
+		return err
+	}
+
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		if err := s.deps.Repository.Update(ctx, itemID, item); err != nil {
+			return err
+		}
+		return s.record(ctx, changefeed.Updated, itemID, item)
+	})
+	if err != nil {
+		s.handleError(ctx, err, FailedToUpdate, logrus.Fields{itemIDKey: itemID, itemObjKey: item})
+		return err
+	}
+
+	if s.deps.Events == nil {
+		return nil
+	}
+	updated, err := s.GetOneByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, changefeed.Updated, itemID, updated)
+	return nil
+}
+
+// Patch applies a JSON Merge Patch or JSON Patch document to the stored item and persists the changed columns
+func (s *service[T, P]) Patch(ctx context.Context, id string, contentType string, doc []byte) (*T, error) {
+	itemID, err := uuid.FromString(id)
+	if err != nil {
+		s.handleError(ctx, err, FailedToParseUUID, logrus.Fields{requestIDKey: id})
+		return nil, errors.ErrCreatingUUIDFromString
+	}
+
+	current, err := s.deps.Repository.GetByID(ctx, itemID)
+	if err != nil {
+		s.handleError(ctx, err, FailedToGetByID, logrus.Fields{itemIDKey: itemID})
+		return nil, err
+	}
+
+	item := new(T)
+	columns, err := patch.Apply(current, contentType, doc, item)
+	if err != nil {
+		s.handleError(ctx, err, FailedToPatch, logrus.Fields{itemIDKey: itemID})
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return current, nil
+	}
+
+	if err = validation.Struct(item); err != nil {
+		s.handleError(ctx, err, FailedToValidate, logrus.Fields{itemIDKey: itemID, itemObjKey: item})
+		return nil, err
+	}
+
+	if s.referencesChanged(columns) {
+		if err = s.validateReferences(ctx, item, nil); err != nil {
+			s.handleError(ctx, err, FailedToValidateReferences, logrus.Fields{itemIDKey: itemID, itemObjKey: item})
+			return nil, err
+		}
+	}
+
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		if err := s.deps.Repository.Patch(ctx, itemID, item, columns); err != nil {
+			return err
+		}
+		return s.record(ctx, changefeed.Updated, itemID, item)
+	})
+	if err != nil {
+		s.handleError(ctx, err, FailedToPatch, logrus.Fields{itemIDKey: itemID, itemObjKey: item})
+		return nil, err
+	}
+
+	patched, err := s.GetOneByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, changefeed.Updated, itemID, patched)
+	return patched, nil
+}
+
+func (s *service[T, P]) Delete(ctx context.Context, id string) error {
+	itemID, err := uuid.FromString(id)
+	if err != nil {
+		s.handleError(ctx, err, FailedToParseUUID, logrus.Fields{requestIDKey: id})
+		return errors.ErrCreatingUUIDFromString
+	}
+
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		if err := s.deps.Repository.Remove(ctx, itemID); err != nil {
+			return err
+		}
+		return s.record(ctx, changefeed.Deleted, itemID, nil)
+	})
+	if err != nil {
+		s.handleError(ctx, err, FailedToDelete, logrus.Fields{itemIDKey: itemID})
+		return err
+	}
+
+	s.publish(ctx, changefeed.Deleted, itemID, nil)
+	return nil
+}
+
+func (s *service[T, P]) Restore(ctx context.Context, id string) error {
+	itemID, err := uuid.FromString(id)
+	if err != nil {
+		s.handleError(ctx, err, FailedToParseUUID, logrus.Fields{requestIDKey: id})
+		return errors.ErrCreatingUUIDFromString
+	}
+
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		if err := s.deps.Repository.Restore(ctx, itemID); err != nil {
+			return err
+		}
+		return s.record(ctx, changefeed.Restored, itemID, nil)
+	})
+	if err != nil {
+		s.handleError(ctx, err, FailedToRestore, logrus.Fields{itemIDKey: itemID})
+		return err
+	}
+
+	s.publish(ctx, changefeed.Restored, itemID, nil)
+	return nil
+}
+
+func (s *service[T, P]) Purge(ctx context.Context, id string) error {
+	itemID, err := uuid.FromString(id)
+	if err != nil {
+		s.handleError(ctx, err, FailedToParseUUID, logrus.Fields{requestIDKey: id})
+		return errors.ErrCreatingUUIDFromString
+	}
+
+	if err = s.deps.Repository.Purge(ctx, itemID); err != nil {
+		s.handleError(ctx, err, FailedToPurge, logrus.Fields{itemIDKey: itemID})
+		return err
+	}
+
+	return nil
+}
+
+// PurgeDeleted permanently removes the items soft deleted longer than retention ago
+func (s *service[T, P]) PurgeDeleted(ctx context.Context, retention time.Duration) error {
+	deletedBefore := time.Now().UTC().Add(-retention)
+	if err := s.deps.Repository.PurgeDeleted(ctx, deletedBefore); err != nil {
+		s.handleError(ctx, err, FailedToPurgeDeleted, logrus.Fields{deletedBeforeKey: deletedBefore})
+		return err
+	}
+
+	return nil
+}
+
+// CreateBatch inserts items with server generated IDs, failing as a whole or item by item depending on mode
+func (s *service[T, P]) CreateBatch(ctx context.Context, items []*T, mode batch.Mode) (*batch.Result, error) {
+	fields := logrus.Fields{batchModeKey: mode, batchSizeKey: len(items)}
+	b := batch.New(mode, items)
+	if err := b.Validate(); err != nil {
+		s.handleError(ctx, err, FailedToValidate, fields)
+		return nil, err
+	}
+
+	checked := make(map[uuid.UUID]bool)
+	err := b.Each(func(item *T) error {
+		if P(item).GetID() != uuid.Nil {
+			return errors.ErrClientSuppliedID
+		}
+		if err := s.validateReferences(ctx, item, checked); err != nil {
+			return err
+		}
+		P(item).SetID(s.deps.IDGenerator.NewID())
+		return nil
+	})
+	if err == nil {
+		err = b.Apply(func(pending []*T) error {
+			ids := itemIDs[T, P](pending)
+			return s.applyBatch(ctx, always(changefeed.Created), ids, pending, func(ctx context.Context) error {
+				return s.deps.Repository.InsertBatch(ctx, pending, mode)
+			})
+		})
+	}
+	if err != nil {
+		s.handleError(ctx, err, FailedToCreateBatch, fields)
+		return nil, err
+	}
+
+	result := b.Result(http.StatusCreated, itemID[T, P])
+	s.publishBatch(ctx, always(changefeed.Created), result, items)
+	return result, nil
+}
+
+// UpsertBatch inserts the items that don't exist yet and replaces the others, items without ID are always inserted.
+// It's the only way a client chooses the ID of a new item, kept so an export can be imported back. An item with the
+// ID of a soft deleted one fails with errors.ErrItemDeleted, to be restored first
+func (s *service[T, P]) UpsertBatch(ctx context.Context, items []*T, mode batch.Mode) (*batch.Result, error) {
+	fields := logrus.Fields{batchModeKey: mode, batchSizeKey: len(items)}
+	b := batch.New(mode, items)
+	if err := b.Validate(); err != nil {
+		s.handleError(ctx, err, FailedToValidate, fields)
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(items))
+	checked := make(map[uuid.UUID]bool)
+	created := make(map[uuid.UUID]bool, len(items))
+	err := b.Each(func(item *T) error {
+		if err := s.validateReferences(ctx, item, checked); err != nil {
+			return err
+		}
+		id := P(item).GetID()
+		if id == uuid.Nil {
+			id = s.deps.IDGenerator.NewID()
+			P(item).SetID(id)
+			created[id] = true
+			return nil
+		}
+		if seen[id] {
+			return errors.ErrDuplicateBatchItem
+		}
+		seen[id] = true
+		exists, err := s.exists(ctx, id)
+		created[id] = !exists
+		return err
+	})
+	eventOf := func(id uuid.UUID) changefeed.EventType {
+		if created[id] {
+			return changefeed.Created
+		}
+		return changefeed.Updated
+	}
+	if err == nil {
+		err = b.Apply(func(pending []*T) error {
+			return s.applyBatch(ctx, eventOf, itemIDs[T, P](pending), pending, func(ctx context.Context) error {
+				return s.deps.Repository.UpsertBatch(ctx, pending, mode)
+			})
+		})
+	}
+	if err != nil {
+		s.handleError(ctx, err, FailedToUpsertBatch, fields)
+		return nil, err
+	}
+
+	result := b.Result(http.StatusOK, itemID[T, P])
+	s.publishBatch(ctx, eventOf, result, items)
+	return result, nil
+}
+
+// DeleteBatch soft deletes the items with the given IDs, failing as a whole or item by item depending on mode
+func (s *service[T, P]) DeleteBatch(ctx context.Context, ids []uuid.UUID, mode batch.Mode) (*batch.Result, error) {
+	fields := logrus.Fields{batchModeKey: mode, batchSizeKey: len(ids)}
+	b := batch.New(mode, ids)
+	if err := b.ValidateSize(); err != nil {
+		s.handleError(ctx, err, FailedToValidate, fields)
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	err := b.Each(func(id uuid.UUID) error {
+		if seen[id] {
+			return errors.ErrDuplicateBatchItem
+		}
+		seen[id] = true
+		return nil
+	})
+	if err == nil {
+		err = b.Apply(func(pending []uuid.UUID) error {
+			return s.applyBatch(ctx, always(changefeed.Deleted), pending, nil, func(ctx context.Context) error {
+				return s.deps.Repository.RemoveBatch(ctx, pending, mode)
+			})
+		})
+	}
+	if err != nil {
+		s.handleError(ctx, err, FailedToDeleteBatch, fields)
+		return nil, err
+	}
+
+	result := b.Result(http.StatusNoContent, func(id uuid.UUID) uuid.UUID {
+		return id
+	})
+	for _, item := range result.Items {
+		if item.Error == "" {
+			s.publish(ctx, changefeed.Deleted, ids[item.Index], nil)
+		}
+	}
+	return result, nil
+}
+
+// Export writes every item to w in the given format, without loading them all in memory
+func (s *service[T, P]) Export(ctx context.Context, format transfer.Format, w io.Writer) error {
+	encoder := transfer.NewEncoder(format, w)
+	item := new(T)
+	err := s.deps.Repository.Stream(ctx, item, func() error {
+		return encoder.Encode(item)
+	})
+	if err == nil {
+		err = encoder.Flush()
+	}
+	if err != nil {
+		s.handleError(ctx, err, FailedToExport, logrus.Fields{formatKey: format})
+		return err
+	}
+
+	return nil
+}
+
+// Import upserts the items read from r in best-effort batches, so an export can be imported back.
+// Rows that can't be decoded or stored are listed in the report while the others are imported
+func (s *service[T, P]) Import(ctx context.Context, format transfer.Format, r io.Reader) (*transfer.ImportReport, error) {
+	decoder := transfer.NewDecoder(format, r)
+	report := transfer.NewImportReport()
+	items := make([]*T, 0, transfer.ImportBatchSize)
+	lines := make([]int, 0, transfer.ImportBatchSize)
+
+	flush := func() error {
+		if len(items) == 0 {
+			return nil
+		}
+		result, err := s.UpsertBatch(ctx, items, batch.BestEffort)
+		if err != nil {
+			return err
+		}
+		report.AddResult(lines, result)
+		items, lines = items[:0], lines[:0]
+		return nil
+	}
+
+	for {
+		item := new(T)
+		err := decoder.Decode(item)
+		if err == io.EOF {
+			break
+		}
+
+		var lineErr *transfer.LineError
+		if stdErrors.As(err, &lineErr) {
+			report.AddError(lineErr.Line, lineErr)
+			continue
+		}
+		if err != nil {
+			s.handleError(ctx, err, FailedToImport, logrus.Fields{formatKey: format, lineKey: decoder.Line()})
+			return nil, err
+		}
+
+		items = append(items, item)
+		lines = append(lines, decoder.Line())
+		if len(items) < transfer.ImportBatchSize {
+			continue
+		}
+		if err = flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// importPayload is the input of an ImportJob
+type importPayload struct {
+	Format transfer.Format `json:"format"`
+	Data   []byte          `json:"data"`
+	// Principal is who uploaded the file, the job importing the rows on their behalf
+	Principal *auth.Principal `json:"principal,omitempty"`
+}
+
+// ImportAsync queues the import of the items read from r for the worker pool, which runs it with RunImportJob.
+// r is read whole, since the job outlives the request it's uploaded with, and fails with errors.ErrImportTooLarge
+// past transfer.MaxImportJobSize
+func (s *service[T, P]) ImportAsync(ctx context.Context, format transfer.Format, r io.Reader) (*job.Job, error) {
+	if s.deps.Jobs == nil {
+		s.handleError(ctx, errors.ErrJobsDisabled, FailedToEnqueueImport, nil)
+		return nil, errors.ErrJobsDisabled
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, transfer.MaxImportJobSize+1))
+	if err == nil && len(data) > transfer.MaxImportJobSize {
+		err = errors.ErrImportTooLarge
+	}
+	if err != nil {
+		s.handleError(ctx, err, FailedToEnqueueImport, logrus.Fields{formatKey: format})
+		return nil, err
+	}
+
+	payload := importPayload{Format: format, Data: data}
+	if principal, ok := auth.FromContext(ctx); ok {
+		payload.Principal = &principal
+	}
+	j, err := s.deps.Jobs.Enqueue(ctx, s.config.ImportJob, payload, job.Options{})
+	if err != nil {
+		s.handleError(ctx, err, FailedToEnqueueImport, logrus.Fields{formatKey: format})
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// RunImportJob runs an ImportJob, the share of the file read being its progress and the import report its result
+func (s *service[T, P]) RunImportJob(ctx context.Context, j *job.Job, progress job.Progress) (interface{}, error) {
+	var payload importPayload
+	if err := json.Unmarshal(j.Payload, &payload); err != nil {
+		s.handleError(ctx, err, FailedToImport, logrus.Fields{jobIDKey: j.ID})
+		return nil, err
+	}
+	if payload.Principal != nil {
+		ctx = auth.WithPrincipal(ctx, *payload.Principal)
+	}
+
+	r := job.NewProgressReader(bytes.NewReader(payload.Data), int64(len(payload.Data)), progress)
+	report, err := s.Import(ctx, payload.Format, r)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// validateReferences checks the references of item. checked memoises the lookups of a batch and may be nil for a
+// single item
+func (s *service[T, P]) validateReferences(ctx context.Context, item *T, checked map[uuid.UUID]bool) error {
+	if s.deps.References == nil {
+		return nil
+	}
+	return s.deps.References.Check(ctx, item, checked)
+}
+
+// referencesChanged tells whether the columns changed by a patch hold a reference
+func (s *service[T, P]) referencesChanged(columns map[string]interface{}) bool {
+	if s.deps.References == nil {
+		return false
+	}
+	for _, column := range s.deps.References.Columns() {
+		if _, ok := columns[column]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribe returns the changes of the items matching filter, resuming after lastEventID when it's set
+func (s *service[T, P]) Subscribe(ctx context.Context, filter changefeed.Filter, lastEventID string) (<-chan changefeed.Event, error) {
+	if s.deps.Events == nil {
+		s.handleError(ctx, errors.ErrChangeFeedDisabled, FailedToSubscribe, nil)
+		return nil, errors.ErrChangeFeedDisabled
+	}
+
+	events, err := s.deps.Events.Subscribe(ctx, filter, lastEventID)
+	if err != nil {
+		s.handleError(ctx, err, FailedToSubscribe, logrus.Fields{lastEventIDKey: lastEventID})
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// inTransaction runs fn in a transaction when domain events are recorded, so they are stored along with the change
+func (s *service[T, P]) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.deps.Messages == nil {
+		return fn(ctx)
+	}
+	return s.deps.Repository.Transaction(ctx, fn)
+}
+
+// record sends the domain event of a change to Messages, in the transaction of the change
+func (s *service[T, P]) record(ctx context.Context, eventType changefeed.EventType, id uuid.UUID, item *T) error {
+	if s.deps.Messages == nil {
+		return nil
+	}
+
+	var data interface{}
+	if item != nil {
+		data = item
+	}
+	event, err := messaging.NewEvent(s.config.EventSource, itemEvents[eventType], id.String(), data)
+	if err != nil {
+		return err
+	}
+	return s.deps.Messages.Publish(ctx, s.config.EventTopic, event)
+}
+
+// exists tells whether the item with the given ID is stored, only looked up when the changes emit events to tell the
+// created items from the updated ones
+func (s *service[T, P]) exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	if s.deps.Events == nil && s.deps.Messages == nil {
+		return false, nil
+	}
+	_, err := s.deps.Repository.GetByID(ctx, id)
+	if stdErrors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// applyBatch runs store and records the domain events of the batch in the same transaction, eventOf telling the type
+// of the event of an item. In best-effort mode the events of the failed items are left out and the *batch.Error of
+// store is returned once the others are committed
+func (s *service[T, P]) applyBatch(ctx context.Context, eventOf func(id uuid.UUID) changefeed.EventType,
+	ids []uuid.UUID, items []*T, store func(ctx context.Context) error) error {
+	var storeErr error
+	err := s.inTransaction(ctx, func(ctx context.Context) error {
+		storeErr = store(ctx)
+		var batchErr *batch.Error
+		if storeErr != nil && !stdErrors.As(storeErr, &batchErr) {
+			return storeErr
+		}
+
+		for i, id := range ids {
+			if batchErr != nil && batchErr.Errors[i] != nil {
+				continue
+			}
+			var item *T
+			if items != nil {
+				item = items[i]
+			}
+			if err := s.record(ctx, eventOf(id), id, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return storeErr
+}
+
+// publish sends a change event, a failure is only logged since the change itself is already stored
+func (s *service[T, P]) publish(ctx context.Context, eventType changefeed.EventType, id uuid.UUID, item *T) {
+	if s.deps.Events == nil {
+		return
+	}
+
+	var data interface{}
+	if item != nil {
+		data = item
+	}
+	event, err := changefeed.NewEvent(eventType, id, data)
+	if err == nil {
+		err = s.deps.Events.Publish(ctx, event)
+	}
+	if err != nil {
+		s.handleError(ctx, err, FailedToPublish, logrus.Fields{itemIDKey: id})
+	}
+}
+
+// publishBatch publishes a change event for every item of the batch that succeeded
+func (s *service[T, P]) publishBatch(ctx context.Context, eventOf func(id uuid.UUID) changefeed.EventType,
+	result *batch.Result, items []*T) {
+	for _, item := range result.Items {
+		if item.Error == "" {
+			id := P(items[item.Index]).GetID()
+			s.publish(ctx, eventOf(id), id, items[item.Index])
+		}
+	}
+}
+
+// always returns the event type of the items of a batch all changed the same way
+func always(eventType changefeed.EventType) func(id uuid.UUID) changefeed.EventType {
+	return func(uuid.UUID) changefeed.EventType {
+		return eventType
+	}
+}
+
+func (s *service[T, P]) handleError(ctx context.Context, err error, logMessage string, fields logrus.Fields) {
+	s.deps.Log.Error(ctx, err, logMessage, fields)
+	s.metrics.ErrorCount.Increment(errorKey, err.Error())
+}
+
+func itemIDs[T interface{}, P entity.Entity[T]](items []*T) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, P(item).GetID())
+	}
+	return ids
+}
+
+func itemID[T interface{}, P entity.Entity[T]](item *T) uuid.UUID {
+	if item == nil {
+		return uuid.Nil
+	}
+	return P(item).GetID()
+}
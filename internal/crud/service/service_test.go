@@ -0,0 +1,1383 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"app/internal/auth"
+	"app/internal/batch"
+	"app/internal/changefeed"
+	"app/internal/errors"
+	"app/internal/job"
+	"app/internal/messaging"
+	"app/internal/patch"
+	commonAssertion "app/internal/test/assertion/common"
+	assertion "app/internal/test/assertion/crud"
+	errorsAssertion "app/internal/test/assertion/errors"
+	changefeedMock "app/internal/test/mocks/changefeed"
+	repositoryMock "app/internal/test/mocks/crud/repository"
+	serviceMock "app/internal/test/mocks/crud/service"
+	identifierMock "app/internal/test/mocks/identifier"
+	jobMock "app/internal/test/mocks/job"
+	messagingMock "app/internal/test/mocks/messaging"
+	pkgMock "app/internal/test/mocks/pkg"
+	"app/internal/transfer"
+	"app/internal/validation"
+)
+
+var config = Config{
+	Name:        "test",
+	EventSource: "/test",
+	EventTopic:  "items",
+	ImportJob:   "items.import",
+}
+
+func TestService(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Service Suits")
+}
+
+var _ = Describe("Service", func() {
+	var (
+		logMock       *pkgMock.Logger
+		repoMock      *repositoryMock.Repository[assertion.Item]
+		generatorMock *identifierMock.Generator
+		s             Service[assertion.Item]
+	)
+
+	BeforeEach(func() {
+		logMock = pkgMock.NewLogger(GinkgoT())
+		repoMock = repositoryMock.NewRepository[assertion.Item](GinkgoT())
+		generatorMock = identifierMock.NewGenerator(GinkgoT())
+		s = New(
+			&DependenciesNode[assertion.Item]{
+				Log:         logMock,
+				Repository:  repoMock,
+				IDGenerator: generatorMock,
+			},
+			config,
+		)
+	})
+
+	Context("Testing CRUD Operations", func() {
+		Context("Getting All items", func() {
+			When("Request succeeds", func() {
+				It("Should return all items from DB", func() {
+					expectedItems := assertion.ArrayOfItem
+					repoMock.On("GetAll", commonAssertion.EmptyCtx).
+						Return(expectedItems, nil).
+						Once()
+
+					resp, err := s.GetAll(commonAssertion.EmptyCtx)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(resp).To(Equal(expectedItems))
+				})
+			})
+			When("DB is empty", func() {
+				It("Should an empty array", func() {
+					repoMock.On("GetAll", commonAssertion.EmptyCtx).
+						Return(nil, nil).
+						Once()
+
+					resp, err := s.GetAll(commonAssertion.EmptyCtx)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(resp).To(BeNil())
+				})
+			})
+			When("Request fails", func() {
+				It("Should return an error", func() {
+					repoMock.On("GetAll", commonAssertion.EmptyCtx).
+						Return(nil, errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On(
+						"Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToGetAll,
+						mock.Anything,
+					).Once()
+
+					resp, err := s.GetAll(commonAssertion.EmptyCtx)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+					Expect(resp).To(BeNil())
+				})
+			})
+		})
+
+		Context("Getting one item by ID", func() {
+			When("Request succeeds", func() {
+				It("Should return an item with given ID", func() {
+					idString := assertion.SampleID.String()
+					expectedItem := assertion.NewItemWithID(idString)
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(expectedItem, nil).
+						Once()
+
+					resp, err := s.GetOneByID(commonAssertion.EmptyCtx, assertion.SampleID.String())
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(resp).To(Equal(expectedItem))
+				})
+			})
+			When("Item is not found", func() {
+				It("Should return a not found error", func() {
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(nil, errorsAssertion.ErrNotFound).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrNotFound,
+						FailedToGetByID,
+						logrus.Fields{itemIDKey: assertion.SampleID},
+					).Once()
+
+					resp, err := s.GetOneByID(commonAssertion.EmptyCtx, assertion.SampleID.String())
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrNotFound))
+					Expect(resp).To(BeNil())
+				})
+			})
+			When("Fails to parse UUID from string", func() {
+				It("Should return an error", func() {
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						assertion.NewErrIncorrectIDLength(assertion.InvalidIDString),
+						FailedToParseUUID,
+						logrus.Fields{requestIDKey: assertion.InvalidIDString},
+					).Once()
+
+					resp, err := s.GetOneByID(commonAssertion.EmptyCtx, assertion.InvalidIDString)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrCreatingUUID))
+					Expect(resp).To(BeNil())
+				})
+			})
+		})
+
+		Context("Creating an item", func() {
+			When("Request succeeds", func() {
+				It("Should return the created object", func() {
+					itemInput := assertion.NewItemWithoutID()
+					expectedItem := assertion.NewItemWithID(assertion.SampleID.String())
+					generatorMock.On("NewID").
+						Return(assertion.SampleID).
+						Once()
+					repoMock.On("Insert", commonAssertion.EmptyCtx, expectedItem).
+						Return(expectedItem, nil).
+						Once()
+
+					resp, err := s.Create(commonAssertion.EmptyCtx, itemInput)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(resp).To(Equal(expectedItem))
+					Expect(resp.ID).To(Equal(assertion.SampleID))
+				})
+			})
+			When("Item is invalid", func() {
+				It("Should return every invalid field", func() {
+					itemInput := assertion.NewItemWithoutID()
+					itemInput.Name = ""
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						mock.Anything,
+						FailedToValidate,
+						logrus.Fields{itemObjKey: itemInput},
+					).Once()
+
+					resp, err := s.Create(commonAssertion.EmptyCtx, itemInput)
+
+					Expect(err).To(MatchError(errors.ErrValidation))
+					Expect(err.(*validation.Error).Fields).To(ConsistOf(
+						validation.FieldError{Field: "name", Rule: "required", Message: "is required"},
+					))
+					Expect(resp).To(BeNil())
+				})
+			})
+			When("Item is missing", func() {
+				It("Should return a validation error", func() {
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						mock.Anything,
+						FailedToValidate,
+						mock.Anything,
+					).Once()
+
+					resp, err := s.Create(commonAssertion.EmptyCtx, nil)
+
+					Expect(err).To(MatchError(errors.ErrValidation))
+					Expect(resp).To(BeNil())
+				})
+			})
+			When("Client supplies an ID", func() {
+				It("Should reject the item", func() {
+					itemInput := assertion.NewItemWithID(assertion.SampleID.String())
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errors.ErrClientSuppliedID,
+						FailedToCreate,
+						logrus.Fields{itemObjKey: itemInput},
+					).Once()
+
+					resp, err := s.Create(commonAssertion.EmptyCtx, itemInput)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errors.ErrClientSuppliedID))
+					Expect(resp).To(BeNil())
+					generatorMock.AssertNotCalled(GinkgoT(), "NewID")
+				})
+			})
+			When("Request fails", func() {
+				It("Should return an error", func() {
+					itemInput := assertion.NewItemWithoutID()
+					generatorMock.On("NewID").
+						Return(assertion.SampleID).
+						Once()
+					repoMock.On("Insert", commonAssertion.EmptyCtx, itemInput).
+						Return(nil, errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToCreate,
+						logrus.Fields{itemObjKey: itemInput},
+					).Once()
+
+					resp, err := s.Create(commonAssertion.EmptyCtx, itemInput)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+					Expect(resp).To(BeNil())
+				})
+			})
+		})
+
+		Context("Updating an item", func() {
+			When("Request succeeds", func() {
+				It("Should return nothing", func() {
+					idString := assertion.SampleID.String()
+					inputItem := assertion.NewItemWithID(idString)
+					repoMock.On("Update", commonAssertion.EmptyCtx, assertion.SampleID, inputItem).
+						Return(nil).
+						Once()
+
+					err := s.Update(commonAssertion.EmptyCtx, idString, inputItem)
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Request fails", func() {
+				It("Should return an error", func() {
+					idString := assertion.SampleID.String()
+					inputItem := assertion.NewItemWithID(idString)
+					repoMock.On("Update", commonAssertion.EmptyCtx, assertion.SampleID, inputItem).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToUpdate,
+						logrus.Fields{itemIDKey: assertion.SampleID, itemObjKey: inputItem},
+					).Once()
+
+					err := s.Update(commonAssertion.EmptyCtx, idString, inputItem)
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+			When("Fails to parse UUID from string", func() {
+				It("Should return an error", func() {
+					idString := assertion.InvalidIDString
+					inputItem := assertion.NewItemWithID(idString)
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						assertion.NewErrIncorrectIDLength(assertion.InvalidIDString),
+						FailedToParseUUID,
+						logrus.Fields{requestIDKey: idString},
+					).Once()
+
+					err := s.Update(commonAssertion.EmptyCtx, assertion.InvalidIDString, inputItem)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrCreatingUUID))
+				})
+			})
+		})
+
+		Context("Patching an item", func() {
+			When("Request succeeds", func() {
+				It("Should persist the changed columns and return the updated item", func() {
+					current := assertion.NewItemWithID(assertion.SampleID.String())
+					current.Description = "description"
+					patched := assertion.NewItemWithID(assertion.SampleID.String())
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(current, nil).
+						Once()
+					repoMock.On("Patch", commonAssertion.EmptyCtx, assertion.SampleID, patched, map[string]interface{}{"description": ""}).
+						Return(nil).
+						Once()
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(patched, nil).
+						Once()
+
+					resp, err := s.Patch(commonAssertion.EmptyCtx, assertion.SampleID.String(), patch.MergePatchContentType, []byte(`{"description":null}`))
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(resp).To(Equal(patched))
+				})
+			})
+			When("Patched item is invalid", func() {
+				It("Should return a validation error", func() {
+					current := assertion.NewItemWithID(assertion.SampleID.String())
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(current, nil).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						mock.Anything,
+						FailedToValidate,
+						mock.Anything,
+					).Once()
+
+					resp, err := s.Patch(commonAssertion.EmptyCtx, assertion.SampleID.String(), patch.MergePatchContentType, []byte(`{"name":null}`))
+
+					Expect(err).To(MatchError(errors.ErrValidation))
+					Expect(resp).To(BeNil())
+					repoMock.AssertNotCalled(GinkgoT(), "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				})
+			})
+			When("Patch does not change the item", func() {
+				It("Should return the current item", func() {
+					current := assertion.NewItemWithID(assertion.SampleID.String())
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(current, nil).
+						Once()
+
+					resp, err := s.Patch(commonAssertion.EmptyCtx, assertion.SampleID.String(), patch.JSONPatchContentType, []byte(`[]`))
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(resp).To(Equal(current))
+				})
+			})
+			When("Patch changes a read-only field", func() {
+				It("Should return an error", func() {
+					current := assertion.NewItemWithID(assertion.SampleID.String())
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(current, nil).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						mock.Anything,
+						FailedToPatch,
+						logrus.Fields{itemIDKey: assertion.SampleID},
+					).Once()
+
+					resp, err := s.Patch(commonAssertion.EmptyCtx, assertion.SampleID.String(), patch.MergePatchContentType, []byte(`{"createdBy":"john"}`))
+
+					Expect(err).To(MatchError(errors.ErrReadOnlyField))
+					Expect(resp).To(BeNil())
+				})
+			})
+			When("Item is not found", func() {
+				It("Should return a not found error", func() {
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(nil, errorsAssertion.ErrNotFound).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrNotFound,
+						FailedToGetByID,
+						logrus.Fields{itemIDKey: assertion.SampleID},
+					).Once()
+
+					resp, err := s.Patch(commonAssertion.EmptyCtx, assertion.SampleID.String(), patch.MergePatchContentType, []byte(`{}`))
+
+					Expect(err).To(Equal(errorsAssertion.ErrNotFound))
+					Expect(resp).To(BeNil())
+				})
+			})
+			When("Fails to persist the patch", func() {
+				It("Should return an error", func() {
+					current := assertion.NewItemWithID(assertion.SampleID.String())
+					patched := assertion.NewItemWithID(assertion.SampleID.String())
+					patched.Description = "description"
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(current, nil).
+						Once()
+					repoMock.On("Patch", commonAssertion.EmptyCtx, assertion.SampleID, patched, map[string]interface{}{"description": "description"}).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToPatch,
+						logrus.Fields{itemIDKey: assertion.SampleID, itemObjKey: patched},
+					).Once()
+
+					resp, err := s.Patch(commonAssertion.EmptyCtx, assertion.SampleID.String(), patch.MergePatchContentType, []byte(`{"description":"description"}`))
+
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+					Expect(resp).To(BeNil())
+				})
+			})
+		})
+
+		Context("Deleting an item", func() {
+			When("Request succeeds", func() {
+				It("Should return nothing", func() {
+					repoMock.On("Remove", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(nil).
+						Once()
+
+					err := s.Delete(commonAssertion.EmptyCtx, assertion.SampleID.String())
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Request fails", func() {
+				It("Should return an error", func() {
+					repoMock.On("Remove", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToDelete,
+						logrus.Fields{itemIDKey: assertion.SampleID},
+					).Once()
+
+					err := s.Delete(commonAssertion.EmptyCtx, assertion.SampleID.String())
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+			When("Fails to parse UUID from string", func() {
+				It("Should return an error", func() {
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						assertion.NewErrIncorrectIDLength(assertion.InvalidIDString),
+						FailedToParseUUID,
+						logrus.Fields{requestIDKey: assertion.InvalidIDString},
+					).Once()
+
+					err := s.Delete(commonAssertion.EmptyCtx, assertion.InvalidIDString)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrCreatingUUID))
+				})
+			})
+		})
+
+		Context("Restoring an item", func() {
+			When("Request succeeds", func() {
+				It("Should return nothing", func() {
+					repoMock.On("Restore", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(nil).
+						Once()
+
+					err := s.Restore(commonAssertion.EmptyCtx, assertion.SampleID.String())
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Item is not deleted", func() {
+				It("Should return a not found error", func() {
+					repoMock.On("Restore", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(errorsAssertion.ErrNotFound).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrNotFound,
+						FailedToRestore,
+						logrus.Fields{itemIDKey: assertion.SampleID},
+					).Once()
+
+					err := s.Restore(commonAssertion.EmptyCtx, assertion.SampleID.String())
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrNotFound))
+				})
+			})
+			When("Fails to parse UUID from string", func() {
+				It("Should return an error", func() {
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						assertion.NewErrIncorrectIDLength(assertion.InvalidIDString),
+						FailedToParseUUID,
+						logrus.Fields{requestIDKey: assertion.InvalidIDString},
+					).Once()
+
+					err := s.Restore(commonAssertion.EmptyCtx, assertion.InvalidIDString)
+
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrCreatingUUID))
+				})
+			})
+		})
+
+		Context("Purging an item", func() {
+			When("Request succeeds", func() {
+				It("Should return nothing", func() {
+					repoMock.On("Purge", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(nil).
+						Once()
+
+					err := s.Purge(commonAssertion.EmptyCtx, assertion.SampleID.String())
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Request fails", func() {
+				It("Should return an error", func() {
+					repoMock.On("Purge", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToPurge,
+						logrus.Fields{itemIDKey: assertion.SampleID},
+					).Once()
+
+					err := s.Purge(commonAssertion.EmptyCtx, assertion.SampleID.String())
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+		})
+
+		Context("Purging deleted items", func() {
+			When("Request succeeds", func() {
+				It("Should purge items deleted before the retention period", func() {
+					retention := 30 * 24 * time.Hour
+					repoMock.On("PurgeDeleted", commonAssertion.EmptyCtx, mock.MatchedBy(func(deletedBefore time.Time) bool {
+						return time.Since(deletedBefore) >= retention
+					})).
+						Return(nil).
+						Once()
+
+					err := s.PurgeDeleted(commonAssertion.EmptyCtx, retention)
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Request fails", func() {
+				It("Should return an error", func() {
+					repoMock.On("PurgeDeleted", commonAssertion.EmptyCtx, mock.AnythingOfType("time.Time")).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToPurgeDeleted,
+						mock.Anything,
+					).Once()
+
+					err := s.PurgeDeleted(commonAssertion.EmptyCtx, time.Hour)
+					Expect(err).Should(HaveOccurred())
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+		})
+
+		Context("Creating items in batch", func() {
+			When("Request succeeds", func() {
+				It("Should generate the IDs and report every item as created", func() {
+					items := []*assertion.Item{assertion.NewItemWithoutID()}
+					generatorMock.On("NewID").
+						Return(assertion.SampleID).
+						Once()
+					repoMock.On("InsertBatch", commonAssertion.EmptyCtx, items, batch.Atomic).
+						Return(nil).
+						Once()
+
+					result, err := s.CreateBatch(commonAssertion.EmptyCtx, items, batch.Atomic)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(result.Succeeded).To(Equal(1))
+					Expect(result.Items).To(ConsistOf(batch.ItemResult{
+						Index:  0,
+						ID:     assertion.SampleID.String(),
+						Status: http.StatusCreated,
+					}))
+				})
+			})
+			When("An item is invalid in atomic mode", func() {
+				It("Should fail the whole batch", func() {
+					invalid := assertion.NewItemWithoutID()
+					invalid.Name = ""
+					items := []*assertion.Item{assertion.NewItemWithoutID(), invalid}
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						mock.Anything,
+						FailedToValidate,
+						logrus.Fields{batchModeKey: batch.Atomic, batchSizeKey: 2},
+					).Once()
+
+					result, err := s.CreateBatch(commonAssertion.EmptyCtx, items, batch.Atomic)
+
+					Expect(err).To(MatchError(errors.ErrValidation))
+					Expect(err.(*validation.Error).Fields).To(ConsistOf(
+						validation.FieldError{Field: "items[1].name", Rule: "required", Message: "is required"},
+					))
+					Expect(result).To(BeNil())
+				})
+			})
+			When("An item supplies its ID in best-effort mode", func() {
+				It("Should create the other items", func() {
+					valid := assertion.NewItemWithoutID()
+					items := []*assertion.Item{assertion.NewItemWithID(assertion.SampleID.String()), valid}
+					generatorMock.On("NewID").
+						Return(assertion.SampleID).
+						Once()
+					repoMock.On("InsertBatch", commonAssertion.EmptyCtx, []*assertion.Item{valid}, batch.BestEffort).
+						Return(nil).
+						Once()
+
+					result, err := s.CreateBatch(commonAssertion.EmptyCtx, items, batch.BestEffort)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(result.Succeeded).To(Equal(1))
+					Expect(result.Failed).To(Equal(1))
+					Expect(result.Items[0].Status).To(Equal(http.StatusBadRequest))
+				})
+			})
+			When("Request fails", func() {
+				It("Should return an error", func() {
+					items := []*assertion.Item{assertion.NewItemWithoutID()}
+					generatorMock.On("NewID").
+						Return(assertion.SampleID).
+						Once()
+					repoMock.On("InsertBatch", commonAssertion.EmptyCtx, items, batch.Atomic).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToCreateBatch,
+						logrus.Fields{batchModeKey: batch.Atomic, batchSizeKey: 1},
+					).Once()
+
+					result, err := s.CreateBatch(commonAssertion.EmptyCtx, items, batch.Atomic)
+
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+					Expect(result).To(BeNil())
+				})
+			})
+		})
+
+		Context("Upserting items in batch", func() {
+			When("Request succeeds", func() {
+				It("Should only generate IDs for new items", func() {
+					existing := assertion.NewItemWithID(assertion.SampleID.String())
+					created := assertion.NewItemWithoutID()
+					items := []*assertion.Item{existing, created}
+					newID := assertion.ArrayOfItem[0].ID
+					generatorMock.On("NewID").
+						Return(newID).
+						Once()
+					repoMock.On("UpsertBatch", commonAssertion.EmptyCtx, items, batch.Atomic).
+						Return(nil).
+						Once()
+
+					result, err := s.UpsertBatch(commonAssertion.EmptyCtx, items, batch.Atomic)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(result.Items[0].ID).To(Equal(assertion.SampleID.String()))
+					Expect(result.Items[1].ID).To(Equal(newID.String()))
+				})
+			})
+			When("An item is repeated in atomic mode", func() {
+				It("Should return a duplicate item error", func() {
+					items := []*assertion.Item{
+						assertion.NewItemWithID(assertion.SampleID.String()),
+						assertion.NewItemWithID(assertion.SampleID.String()),
+					}
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						mock.Anything,
+						FailedToUpsertBatch,
+						logrus.Fields{batchModeKey: batch.Atomic, batchSizeKey: 2},
+					).Once()
+
+					result, err := s.UpsertBatch(commonAssertion.EmptyCtx, items, batch.Atomic)
+
+					Expect(err).To(MatchError(errors.ErrDuplicateBatchItem))
+					Expect(result).To(BeNil())
+				})
+			})
+		})
+
+		Context("Deleting items in batch", func() {
+			When("Some items are missing in best-effort mode", func() {
+				It("Should report the missing items as not found", func() {
+					ids := []uuid.UUID{assertion.SampleID, assertion.ArrayOfItem[0].ID}
+					batchErr := batch.NewError()
+					batchErr.Add(1, gorm.ErrRecordNotFound)
+					repoMock.On("RemoveBatch", commonAssertion.EmptyCtx, ids, batch.BestEffort).
+						Return(batchErr).
+						Once()
+
+					result, err := s.DeleteBatch(commonAssertion.EmptyCtx, ids, batch.BestEffort)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(result.Items).To(Equal([]batch.ItemResult{
+						{Index: 0, ID: assertion.SampleID.String(), Status: http.StatusNoContent},
+						{
+							Index:  1,
+							ID:     assertion.ArrayOfItem[0].ID.String(),
+							Status: http.StatusNotFound,
+							Error:  gorm.ErrRecordNotFound.Error(),
+						},
+					}))
+				})
+			})
+			When("Batch is empty", func() {
+				It("Should return a validation error", func() {
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						mock.Anything,
+						FailedToValidate,
+						logrus.Fields{batchModeKey: batch.Atomic, batchSizeKey: 0},
+					).Once()
+
+					result, err := s.DeleteBatch(commonAssertion.EmptyCtx, nil, batch.Atomic)
+
+					Expect(err).To(MatchError(errors.ErrValidation))
+					Expect(result).To(BeNil())
+				})
+			})
+		})
+
+		Context("Checking references", func() {
+			var (
+				referencesMock *serviceMock.References[assertion.Item]
+				withReferences Service[assertion.Item]
+			)
+
+			BeforeEach(func() {
+				referencesMock = serviceMock.NewReferences[assertion.Item](GinkgoT())
+				withReferences = New(
+					&DependenciesNode[assertion.Item]{
+						Log:         logMock,
+						Repository:  repoMock,
+						IDGenerator: generatorMock,
+						References:  referencesMock,
+					},
+					config,
+				)
+			})
+
+			When("A referenced entity doesn't exist", func() {
+				It("Should not create the item", func() {
+					itemInput := assertion.NewItemWithoutID()
+					referencesMock.On("Check", commonAssertion.EmptyCtx, itemInput, map[uuid.UUID]bool(nil)).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToValidateReferences,
+						logrus.Fields{itemObjKey: itemInput},
+					).Once()
+
+					resp, err := withReferences.Create(commonAssertion.EmptyCtx, itemInput)
+
+					Expect(err).To(MatchError(errorsAssertion.ErrGeneric))
+					Expect(resp).To(BeNil())
+					repoMock.AssertNotCalled(GinkgoT(), "Insert", mock.Anything, mock.Anything)
+				})
+			})
+			When("A patch changes a reference", func() {
+				It("Should check the references of the patched item", func() {
+					current := assertion.NewItemWithID(assertion.SampleID.String())
+					patched := assertion.NewItemWithID(assertion.SampleID.String())
+					patched.Description = "reference"
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(current, nil).
+						Once()
+					referencesMock.On("Columns").
+						Return([]string{"description"}).
+						Once()
+					referencesMock.On("Check", commonAssertion.EmptyCtx, patched, map[uuid.UUID]bool(nil)).
+						Return(nil).
+						Once()
+					repoMock.On("Patch", commonAssertion.EmptyCtx, assertion.SampleID, patched, mock.Anything).
+						Return(nil).
+						Once()
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(patched, nil).
+						Once()
+
+					resp, err := withReferences.Patch(commonAssertion.EmptyCtx, assertion.SampleID.String(),
+						patch.MergePatchContentType, []byte(`{"description":"reference"}`))
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(resp).To(Equal(patched))
+				})
+			})
+			When("A patch leaves the references", func() {
+				It("Should not check them", func() {
+					current := assertion.NewItemWithID(assertion.SampleID.String())
+					patched := assertion.NewItemWithID(assertion.SampleID.String())
+					patched.Name = "renamed"
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(current, nil).
+						Once()
+					referencesMock.On("Columns").
+						Return([]string{"description"}).
+						Once()
+					repoMock.On("Patch", commonAssertion.EmptyCtx, assertion.SampleID, patched, mock.Anything).
+						Return(nil).
+						Once()
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(patched, nil).
+						Once()
+
+					_, err := withReferences.Patch(commonAssertion.EmptyCtx, assertion.SampleID.String(),
+						patch.MergePatchContentType, []byte(`{"name":"renamed"}`))
+
+					Expect(err).ShouldNot(HaveOccurred())
+					referencesMock.AssertNotCalled(GinkgoT(), "Check", mock.Anything, mock.Anything, mock.Anything)
+				})
+			})
+			When("A batch is created", func() {
+				It("Should share the lookups of its items", func() {
+					items := []*assertion.Item{assertion.NewItemWithoutID(), assertion.NewItemWithoutID()}
+					var lookups []map[uuid.UUID]bool
+					referencesMock.On("Check", commonAssertion.EmptyCtx, mock.Anything, mock.Anything).
+						Run(func(args mock.Arguments) {
+							lookups = append(lookups, args.Get(2).(map[uuid.UUID]bool))
+						}).
+						Return(nil).
+						Twice()
+					generatorMock.On("NewID").
+						Return(assertion.SampleID).
+						Twice()
+					repoMock.On("InsertBatch", commonAssertion.EmptyCtx, items, batch.Atomic).
+						Return(nil).
+						Once()
+
+					result, err := withReferences.CreateBatch(commonAssertion.EmptyCtx, items, batch.Atomic)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(result.Succeeded).To(Equal(2))
+					Expect(lookups).To(HaveLen(2))
+					Expect(lookups[0]).ToNot(BeNil())
+					lookups[0][assertion.SampleID] = true
+					Expect(lookups[1]).To(HaveKey(assertion.SampleID))
+				})
+			})
+		})
+
+		Context("Emitting domain events", func() {
+			var (
+				messagesMock *messagingMock.Publisher
+				withMessages Service[assertion.Item]
+			)
+
+			BeforeEach(func() {
+				messagesMock = messagingMock.NewPublisher(GinkgoT())
+				withMessages = New(
+					&DependenciesNode[assertion.Item]{
+						Log:         logMock,
+						Repository:  repoMock,
+						IDGenerator: generatorMock,
+						Messages:    messagesMock,
+					},
+					config,
+				)
+				repoMock.On("Transaction", commonAssertion.EmptyCtx, mock.Anything).
+					Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					}).
+					Once()
+			})
+
+			When("An item is created", func() {
+				It("Should emit an item.created CloudEvent holding the item", func() {
+					itemInput := assertion.NewItemWithoutID()
+					expectedItem := assertion.NewItemWithID(assertion.SampleID.String())
+					generatorMock.On("NewID").
+						Return(assertion.SampleID).
+						Once()
+					repoMock.On("Insert", commonAssertion.EmptyCtx, expectedItem).
+						Return(expectedItem, nil).
+						Once()
+					messagesMock.On("Publish", commonAssertion.EmptyCtx, config.EventTopic, mock.MatchedBy(func(event messaging.Event) bool {
+						return event.SpecVersion == messaging.SpecVersion &&
+							event.Source == config.EventSource &&
+							event.Type == messaging.ItemCreated &&
+							event.Subject == assertion.SampleID.String() &&
+							event.DataContentType == messaging.JSONContentType
+					})).
+						Return(nil).
+						Once()
+
+					_, err := withMessages.Create(commonAssertion.EmptyCtx, itemInput)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("An item is deleted", func() {
+				It("Should emit an item.deleted CloudEvent without data", func() {
+					repoMock.On("Remove", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(nil).
+						Once()
+					messagesMock.On("Publish", commonAssertion.EmptyCtx, config.EventTopic, mock.MatchedBy(func(event messaging.Event) bool {
+						return event.Type == messaging.ItemDeleted && event.Subject == assertion.SampleID.String() && event.Data == nil
+					})).
+						Return(nil).
+						Once()
+
+					err := withMessages.Delete(commonAssertion.EmptyCtx, assertion.SampleID.String())
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Recording the event fails", func() {
+				It("Should roll back the change and return the error", func() {
+					repoMock.On("Remove", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(nil).
+						Once()
+					messagesMock.On("Publish", commonAssertion.EmptyCtx, config.EventTopic, mock.Anything).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToDelete,
+						logrus.Fields{itemIDKey: assertion.SampleID},
+					).Once()
+
+					err := withMessages.Delete(commonAssertion.EmptyCtx, assertion.SampleID.String())
+
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+			When("Some items of a best-effort batch fail", func() {
+				It("Should only emit the events of the stored items", func() {
+					failedID := uuid.NewV4()
+					ids := []uuid.UUID{assertion.SampleID, failedID}
+					batchErr := batch.NewError()
+					batchErr.Add(1, errorsAssertion.ErrGeneric)
+					repoMock.On("RemoveBatch", commonAssertion.EmptyCtx, ids, batch.BestEffort).
+						Return(batchErr).
+						Once()
+					messagesMock.On("Publish", commonAssertion.EmptyCtx, config.EventTopic, mock.MatchedBy(func(event messaging.Event) bool {
+						return event.Type == messaging.ItemDeleted && event.Subject == assertion.SampleID.String()
+					})).
+						Return(nil).
+						Once()
+
+					result, err := withMessages.DeleteBatch(commonAssertion.EmptyCtx, ids, batch.BestEffort)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(result.Failed).To(Equal(1))
+				})
+			})
+		})
+
+		Context("Publishing changes", func() {
+			var (
+				eventsMock *changefeedMock.Broker
+				withEvents Service[assertion.Item]
+			)
+
+			BeforeEach(func() {
+				eventsMock = changefeedMock.NewBroker(GinkgoT())
+				withEvents = New(
+					&DependenciesNode[assertion.Item]{
+						Log:         logMock,
+						Repository:  repoMock,
+						IDGenerator: generatorMock,
+						Events:      eventsMock,
+					},
+					config,
+				)
+			})
+
+			When("An item is created", func() {
+				It("Should publish a created event holding the item", func() {
+					itemInput := assertion.NewItemWithoutID()
+					expectedItem := assertion.NewItemWithID(assertion.SampleID.String())
+					generatorMock.On("NewID").
+						Return(assertion.SampleID).
+						Once()
+					repoMock.On("Insert", commonAssertion.EmptyCtx, expectedItem).
+						Return(expectedItem, nil).
+						Once()
+					eventsMock.On("Publish", commonAssertion.EmptyCtx, mock.MatchedBy(func(event changefeed.Event) bool {
+						return event.Type == changefeed.Created && event.ItemID == assertion.SampleID && len(event.Data) > 0
+					})).
+						Return(nil).
+						Once()
+
+					_, err := withEvents.Create(commonAssertion.EmptyCtx, itemInput)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("An item is updated", func() {
+				It("Should publish an updated event holding the stored item", func() {
+					inputItem := assertion.NewItemWithoutID()
+					stored := assertion.NewItemWithID(assertion.SampleID.String())
+					stored.Description = "stored"
+					repoMock.On("Update", commonAssertion.EmptyCtx, assertion.SampleID, inputItem).
+						Return(nil).
+						Once()
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(stored, nil).
+						Once()
+					eventsMock.On("Publish", commonAssertion.EmptyCtx, mock.MatchedBy(func(event changefeed.Event) bool {
+						return event.Type == changefeed.Updated && string(event.Data) == string(assertion.ItemInBytes(stored))
+					})).
+						Return(nil).
+						Once()
+
+					err := withEvents.Update(commonAssertion.EmptyCtx, assertion.SampleID.String(), inputItem)
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Items are upserted", func() {
+				It("Should publish a created event for the new items and an updated one for the others", func() {
+					existing := assertion.NewItemWithID(assertion.SampleID.String())
+					missing := assertion.NewItemWithID(assertion.ArrayOfItem[1].ID.String())
+					withoutID := assertion.NewItemWithoutID()
+					newID := assertion.ArrayOfItem[0].ID
+					generatorMock.On("NewID").
+						Return(newID).
+						Once()
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, existing.ID).
+						Return(existing, nil).
+						Once()
+					repoMock.On("GetByID", commonAssertion.EmptyCtx, missing.ID).
+						Return(nil, gorm.ErrRecordNotFound).
+						Once()
+					repoMock.On("UpsertBatch", commonAssertion.EmptyCtx, mock.Anything, batch.Atomic).
+						Return(nil).
+						Once()
+					published := map[uuid.UUID]changefeed.EventType{}
+					eventsMock.On("Publish", commonAssertion.EmptyCtx, mock.Anything).
+						Run(func(args mock.Arguments) {
+							event := args.Get(1).(changefeed.Event)
+							published[event.ItemID] = event.Type
+						}).
+						Return(nil).
+						Times(3)
+
+					_, err := withEvents.UpsertBatch(commonAssertion.EmptyCtx,
+						[]*assertion.Item{existing, missing, withoutID}, batch.Atomic)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(published).To(Equal(map[uuid.UUID]changefeed.EventType{
+						existing.ID: changefeed.Updated,
+						missing.ID:  changefeed.Created,
+						newID:       changefeed.Created,
+					}))
+				})
+			})
+			When("An item is deleted", func() {
+				It("Should publish a deleted event", func() {
+					repoMock.On("Remove", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(nil).
+						Once()
+					eventsMock.On("Publish", commonAssertion.EmptyCtx, changefeed.Event{
+						Type:   changefeed.Deleted,
+						ItemID: assertion.SampleID,
+					}).
+						Return(nil).
+						Once()
+
+					err := withEvents.Delete(commonAssertion.EmptyCtx, assertion.SampleID.String())
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("Publishing fails", func() {
+				It("Should only log the error", func() {
+					repoMock.On("Restore", commonAssertion.EmptyCtx, assertion.SampleID).
+						Return(nil).
+						Once()
+					eventsMock.On("Publish", commonAssertion.EmptyCtx, mock.Anything).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToPublish,
+						logrus.Fields{itemIDKey: assertion.SampleID},
+					).Once()
+
+					err := withEvents.Restore(commonAssertion.EmptyCtx, assertion.SampleID.String())
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("A client subscribes", func() {
+				It("Should return the events of the broker", func() {
+					filter := changefeed.Filter{Types: []changefeed.EventType{changefeed.Created}}
+					events := make(<-chan changefeed.Event)
+					eventsMock.On("Subscribe", commonAssertion.EmptyCtx, filter, "").
+						Return(events, nil).
+						Once()
+
+					resp, err := withEvents.Subscribe(commonAssertion.EmptyCtx, filter, "")
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(resp).To(Equal(events))
+				})
+			})
+			When("The change feed is disabled", func() {
+				It("Should return an error", func() {
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errors.ErrChangeFeedDisabled,
+						FailedToSubscribe,
+						logrus.Fields(nil),
+					).Once()
+
+					resp, err := s.Subscribe(commonAssertion.EmptyCtx, changefeed.Filter{}, "")
+
+					Expect(err).To(Equal(errors.ErrChangeFeedDisabled))
+					Expect(resp).To(BeNil())
+				})
+			})
+		})
+
+		Context("Exporting items", func() {
+			When("Request succeeds", func() {
+				It("Should write every item as a line", func() {
+					repoMock.On("Stream", commonAssertion.EmptyCtx, mock.AnythingOfType("*crud.Item"), mock.AnythingOfType("func() error")).
+						Run(func(args mock.Arguments) {
+							item := args.Get(1).(*assertion.Item)
+							each := args.Get(2).(func() error)
+							for _, stored := range assertion.ArrayOfItem[:2] {
+								*item = *stored
+								Expect(each()).To(Succeed())
+							}
+						}).
+						Return(nil).
+						Once()
+
+					var buf bytes.Buffer
+					err := s.Export(commonAssertion.EmptyCtx, transfer.NDJSON, &buf)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(buf.String()).To(Equal(
+						string(assertion.ItemInBytes(assertion.ArrayOfItem[0])) + "\n" +
+							string(assertion.ItemInBytes(assertion.ArrayOfItem[1])) + "\n",
+					))
+				})
+			})
+			When("Request fails", func() {
+				It("Should return an error", func() {
+					repoMock.On("Stream", commonAssertion.EmptyCtx, mock.Anything, mock.Anything).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToExport,
+						logrus.Fields{formatKey: transfer.CSV},
+					).Once()
+
+					err := s.Export(commonAssertion.EmptyCtx, transfer.CSV, &bytes.Buffer{})
+
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+				})
+			})
+		})
+
+		Context("Importing items", func() {
+			When("Some rows are invalid", func() {
+				It("Should import the other rows and report the invalid ones by line", func() {
+					file := strings.NewReader(fmt.Sprintf("id,name\n%s,first\n,\n,last\n", assertion.SampleID))
+					newID := assertion.ArrayOfItem[0].ID
+					generatorMock.On("NewID").
+						Return(newID).
+						Once()
+					repoMock.On("UpsertBatch", commonAssertion.EmptyCtx, mock.MatchedBy(func(items []*assertion.Item) bool {
+						return len(items) == 2 && items[0].ID == assertion.SampleID && items[1].ID == newID
+					}), batch.BestEffort).
+						Return(nil).
+						Once()
+
+					report, err := s.Import(commonAssertion.EmptyCtx, transfer.CSV, file)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(report.Imported).To(Equal(2))
+					Expect(report.Failed).To(Equal(1))
+					Expect(report.Errors).To(HaveLen(1))
+					Expect(report.Errors[0].Line).To(Equal(3))
+					Expect(report.Errors[0].Fields).To(ConsistOf(
+						validation.FieldError{Field: "name", Rule: "required", Message: "is required"},
+					))
+				})
+			})
+			When("A row can't be decoded", func() {
+				It("Should report it without storing anything", func() {
+					file := strings.NewReader("{\"name\":\n")
+
+					report, err := s.Import(commonAssertion.EmptyCtx, transfer.NDJSON, file)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(report.Imported).To(BeZero())
+					Expect(report.Errors).To(HaveLen(1))
+					Expect(report.Errors[0].Line).To(Equal(1))
+				})
+			})
+			When("Storing a batch fails", func() {
+				It("Should return an error", func() {
+					file := strings.NewReader(fmt.Sprintf("{\"id\":\"%s\",\"name\":\"first\"}\n", assertion.SampleID))
+					repoMock.On("UpsertBatch", commonAssertion.EmptyCtx, mock.Anything, batch.BestEffort).
+						Return(errorsAssertion.ErrGeneric).
+						Once()
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errorsAssertion.ErrGeneric,
+						FailedToUpsertBatch,
+						mock.Anything,
+					).Once()
+
+					report, err := s.Import(commonAssertion.EmptyCtx, transfer.NDJSON, file)
+
+					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
+					Expect(report).To(BeNil())
+				})
+			})
+		})
+
+		Context("Importing items in the background", func() {
+			var (
+				jobsMock *jobMock.Queue
+				withJobs Service[assertion.Item]
+				line     string
+			)
+
+			BeforeEach(func() {
+				jobsMock = jobMock.NewQueue(GinkgoT())
+				withJobs = New(
+					&DependenciesNode[assertion.Item]{
+						Log:         logMock,
+						Repository:  repoMock,
+						IDGenerator: generatorMock,
+						Jobs:        jobsMock,
+					},
+					config,
+				)
+				line = fmt.Sprintf("{\"id\":\"%s\",\"name\":\"first\"}\n", assertion.SampleID)
+			})
+
+			When("The import is enqueued", func() {
+				It("Should queue a job holding the file", func() {
+					queued := &job.Job{ID: uuid.NewV4(), Type: config.ImportJob, Status: job.Queued}
+					jobsMock.On("Enqueue", commonAssertion.EmptyCtx, config.ImportJob,
+						importPayload{Format: transfer.NDJSON, Data: []byte(line)}, job.Options{}).
+						Return(queued, nil).
+						Once()
+
+					j, err := withJobs.ImportAsync(commonAssertion.EmptyCtx, transfer.NDJSON, strings.NewReader(line))
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(j).To(Equal(queued))
+				})
+			})
+			When("The import is enqueued by a consumer", func() {
+				It("Should queue the consumer along with the file", func() {
+					principal := auth.Principal{Subject: "alice", Groups: []string{"editors"}}
+					ctx := auth.WithPrincipal(commonAssertion.EmptyCtx, principal)
+					queued := &job.Job{ID: uuid.NewV4(), Type: config.ImportJob, Status: job.Queued}
+					jobsMock.On("Enqueue", ctx, config.ImportJob,
+						importPayload{Format: transfer.NDJSON, Data: []byte(line), Principal: &principal}, job.Options{}).
+						Return(queued, nil).
+						Once()
+
+					_, err := withJobs.ImportAsync(ctx, transfer.NDJSON, strings.NewReader(line))
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+			When("The file is too large", func() {
+				It("Should return an error", func() {
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errors.ErrImportTooLarge,
+						FailedToEnqueueImport,
+						mock.Anything,
+					).Once()
+					file := bytes.NewReader(make([]byte, transfer.MaxImportJobSize+1))
+
+					j, err := withJobs.ImportAsync(commonAssertion.EmptyCtx, transfer.NDJSON, file)
+
+					Expect(err).To(Equal(errors.ErrImportTooLarge))
+					Expect(j).To(BeNil())
+				})
+			})
+			When("Background jobs are disabled", func() {
+				It("Should return an error", func() {
+					logMock.On("Error",
+						commonAssertion.EmptyCtx,
+						errors.ErrJobsDisabled,
+						FailedToEnqueueImport,
+						mock.Anything,
+					).Once()
+
+					j, err := s.ImportAsync(commonAssertion.EmptyCtx, transfer.NDJSON, strings.NewReader(line))
+
+					Expect(err).To(Equal(errors.ErrJobsDisabled))
+					Expect(j).To(BeNil())
+				})
+			})
+			When("The job runs", func() {
+				It("Should import the file and report its progress", func() {
+					payload, err := json.Marshal(importPayload{Format: transfer.NDJSON, Data: []byte(line)})
+					Expect(err).ShouldNot(HaveOccurred())
+					repoMock.On("UpsertBatch", commonAssertion.EmptyCtx, mock.Anything, batch.BestEffort).
+						Return(nil).
+						Once()
+					var progress []int
+
+					result, err := withJobs.RunImportJob(commonAssertion.EmptyCtx, &job.Job{Payload: payload},
+						func(percent int) {
+							progress = append(progress, percent)
+						})
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(result.(*transfer.ImportReport).Imported).To(Equal(1))
+					Expect(progress).To(ContainElement(99))
+				})
+			})
+			When("The job of a consumer runs", func() {
+				It("Should import the file on behalf of the consumer", func() {
+					principal := auth.Principal{Subject: "alice"}
+					payload, err := json.Marshal(importPayload{Format: transfer.NDJSON, Data: []byte(line),
+						Principal: &principal})
+					Expect(err).ShouldNot(HaveOccurred())
+					repoMock.On("UpsertBatch", mock.MatchedBy(func(ctx context.Context) bool {
+						return auth.Subject(ctx) == principal.Subject
+					}), mock.Anything, batch.BestEffort).
+						Return(nil).
+						Once()
+
+					_, err = withJobs.RunImportJob(commonAssertion.EmptyCtx, &job.Job{Payload: payload}, func(int) {})
+
+					Expect(err).ShouldNot(HaveOccurred())
+				})
+			})
+		})
+	})
+})
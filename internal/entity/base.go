@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	IDColumn        = "id"
+	CreatedAtColumn = "created_at"
+	UpdatedAtColumn = "updated_at"
+	DeletedAtColumn = "deleted_at"
+	CreatedByColumn = "created_by"
+	UpdatedByColumn = "updated_by"
+	VersionColumn   = "version"
+)
+
+// ImmutableColumns are never overwritten by an update request
+var ImmutableColumns = []string{
+	IDColumn,
+	CreatedAtColumn,
+	CreatedByColumn,
+	DeletedAtColumn,
+	VersionColumn,
+}
+
+// ReadOnlyColumns are managed by the server and can't be changed by the client
+var ReadOnlyColumns = append([]string{
+	UpdatedAtColumn,
+	UpdatedByColumn,
+}, ImmutableColumns...)
+
+// Entity constrains the generic layers to pointers to the domain entities embedding Base, P being *T
+type Entity[T interface{}] interface {
+	*T
+	GetID() uuid.UUID
+	SetID(id uuid.UUID)
+}
+
+// Auditable is implemented by entities that keep track of who changed them and when
+type Auditable interface {
+	Created(by string, at time.Time)
+	Updated(by string, at time.Time)
+}
+
+// Base holds the identity and audit metadata shared by every domain entity.
+// DeletedAt enables gorm soft deletion, so deleted rows are hidden from queries until purged
+type Base struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `json:"deletedAt" gorm:"index" swaggertype:"string" format:"date-time"`
+	CreatedBy string         `json:"createdBy"`
+	UpdatedBy string         `json:"updatedBy"`
+	Version   int64          `json:"version"`
+}
+
+// Created stamps the creation metadata, discarding any value sent by the client
+func (b *Base) Created(by string, at time.Time) {
+	b.CreatedAt = at
+	b.CreatedBy = by
+	b.UpdatedAt = at
+	b.UpdatedBy = by
+	b.DeletedAt = gorm.DeletedAt{}
+	b.Version = 1
+}
+
+// Updated stamps the modification metadata
+func (b *Base) Updated(by string, at time.Time) {
+	b.UpdatedAt = at
+	b.UpdatedBy = by
+}
+
+func (b *Base) GetID() uuid.UUID {
+	return b.ID
+}
+
+func (b *Base) SetID(id uuid.UUID) {
+	b.ID = id
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+func TestEntity(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Entity Suits")
+}
+
+var _ = Describe("Base", func() {
+	var (
+		now     = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		deleted = gorm.DeletedAt{Time: now.Add(-time.Hour), Valid: true}
+	)
+
+	Context("Stamping audit metadata", func() {
+		When("Entity is created", func() {
+			It("Should overwrite creation and modification fields", func() {
+				b := &Base{CreatedBy: "forged", Version: 42, DeletedAt: deleted}
+
+				b.Created("john", now)
+
+				Expect(b.CreatedAt).To(Equal(now))
+				Expect(b.UpdatedAt).To(Equal(now))
+				Expect(b.CreatedBy).To(Equal("john"))
+				Expect(b.UpdatedBy).To(Equal("john"))
+				Expect(b.DeletedAt.Valid).To(BeFalse())
+				Expect(b.Version).To(Equal(int64(1)))
+			})
+		})
+		When("Entity is updated", func() {
+			It("Should only overwrite modification fields", func() {
+				b := &Base{CreatedAt: now, CreatedBy: "john", Version: 1}
+
+				b.Updated("jane", now.Add(time.Minute))
+
+				Expect(b.CreatedAt).To(Equal(now))
+				Expect(b.CreatedBy).To(Equal("john"))
+				Expect(b.UpdatedAt).To(Equal(now.Add(time.Minute)))
+				Expect(b.UpdatedBy).To(Equal("jane"))
+				Expect(b.Version).To(Equal(int64(1)))
+			})
+		})
+	})
+})
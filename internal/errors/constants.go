@@ -4,4 +4,23 @@ import "errors"
 
 var (
 	ErrCreatingUUIDFromString = errors.New("failed to create UUID from string")
+	ErrClientSuppliedID       = errors.New("id is assigned by the server and must not be provided")
+	ErrUnsupportedPatchType   = errors.New("unsupported patch content type")
+	ErrInvalidPatch           = errors.New("invalid patch document")
+	ErrReadOnlyField          = errors.New("patch changes a read-only field")
+	ErrValidation             = errors.New("payload validation failed")
+	ErrInvalidParameter       = errors.New("invalid request parameter")
+	ErrInvalidBatchMode       = errors.New("batch mode must be either atomic or best-effort")
+	ErrDuplicateBatchItem     = errors.New("item appears more than once in the batch")
+	ErrDuplicateKey           = errors.New("an item with this id already exists")
+	ErrItemDeleted            = errors.New("the item with this id is deleted, restore it before replacing it")
+	ErrUnsupportedFormat      = errors.New("format must be either ndjson or csv")
+	ErrMissingFile            = errors.New("multipart request has no file field")
+	ErrImportTooLarge         = errors.New("the file is too large to be imported in the background")
+	ErrDependencyUnavailable  = errors.New("a service this request depends on is unavailable")
+	ErrCacheUnavailable       = errors.New("the cache is unavailable")
+	ErrChangeFeedDisabled     = errors.New("the change feed is not enabled")
+	ErrJobNotFound            = errors.New("job not found")
+	ErrJobFinished            = errors.New("job already finished")
+	ErrJobsDisabled           = errors.New("background jobs are not enabled")
 )
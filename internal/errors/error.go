@@ -1,20 +1,47 @@
 package errors
 
 import (
+	"errors"
 	"net/http"
 
 	"gorm.io/gorm"
 )
 
-var errorStatusMap = map[error]int{
-	gorm.ErrRecordNotFound:     http.StatusNotFound,
-	gorm.ErrPrimaryKeyRequired: http.StatusBadRequest,
+// errorStatuses maps the errors to their http status. An error wrapping several of them gets the status of the
+// first one listed
+var errorStatuses = []struct {
+	err    error
+	status int
+}{
+	{gorm.ErrRecordNotFound, http.StatusNotFound},
+	{gorm.ErrPrimaryKeyRequired, http.StatusBadRequest},
+	{ErrCreatingUUIDFromString, http.StatusBadRequest},
+	{ErrClientSuppliedID, http.StatusBadRequest},
+	{ErrUnsupportedPatchType, http.StatusUnsupportedMediaType},
+	{ErrInvalidPatch, http.StatusBadRequest},
+	{ErrReadOnlyField, http.StatusUnprocessableEntity},
+	{ErrValidation, http.StatusUnprocessableEntity},
+	{ErrInvalidParameter, http.StatusBadRequest},
+	{ErrInvalidBatchMode, http.StatusBadRequest},
+	{ErrDuplicateBatchItem, http.StatusUnprocessableEntity},
+	{ErrDuplicateKey, http.StatusConflict},
+	{ErrItemDeleted, http.StatusConflict},
+	{ErrUnsupportedFormat, http.StatusUnsupportedMediaType},
+	{ErrMissingFile, http.StatusBadRequest},
+	{ErrImportTooLarge, http.StatusRequestEntityTooLarge},
+	{ErrDependencyUnavailable, http.StatusServiceUnavailable},
+	{ErrChangeFeedDisabled, http.StatusServiceUnavailable},
+	{ErrJobNotFound, http.StatusNotFound},
+	{ErrJobFinished, http.StatusConflict},
+	{ErrJobsDisabled, http.StatusServiceUnavailable},
 }
 
+// GetStatus returns the http status mapped to err, also matching errors that wrap a mapped one
 func GetStatus(err error) int {
-	status, ok := errorStatusMap[err]
-	if !ok {
-		return http.StatusInternalServerError
+	for _, mapped := range errorStatuses {
+		if errors.Is(err, mapped.err) {
+			return mapped.status
+		}
 	}
-	return status
+	return http.StatusInternalServerError
 }
@@ -1,11 +1,13 @@
 package errors
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
 	"gorm.io/gorm"
 
 	assertionErrors "app/internal/test/assertion/errors"
@@ -32,6 +34,43 @@ var _ = Describe("Error", func() {
 				Expect(status).To(Equal(http.StatusBadRequest))
 			})
 		})
+		When("User sent an ID on create", func() {
+			It("Should return status bad request", func() {
+				status := GetStatus(ErrClientSuppliedID)
+
+				Expect(status).To(Equal(http.StatusBadRequest))
+			})
+		})
+		When("Payload fails validation", func() {
+			It("Should return status unprocessable entity", func() {
+				status := GetStatus(ErrValidation)
+
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			})
+		})
+		When("User sent an invalid path parameter", func() {
+			It("Should return status bad request", func() {
+				status := GetStatus(ErrInvalidParameter)
+
+				Expect(status).To(Equal(http.StatusBadRequest))
+			})
+		})
+		When("Error wraps a mapped error", func() {
+			It("Should return the status of the wrapped error", func() {
+				status := GetStatus(fmt.Errorf("%w: name", ErrReadOnlyField))
+
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			})
+		})
+		When("Error wraps several mapped errors", func() {
+			It("Should always return the status of the first one mapped", func() {
+				err := wrapped{ErrDuplicateKey, ErrValidation}
+
+				for i := 0; i < 10; i++ {
+					Expect(GetStatus(err)).To(Equal(http.StatusUnprocessableEntity))
+				}
+			})
+		})
 		When("Error is not mapped", func() {
 			It("Should return status internal server error", func() {
 				status := GetStatus(assertionErrors.ErrGeneric)
@@ -40,4 +79,43 @@ var _ = Describe("Error", func() {
 			})
 		})
 	})
+
+	Context("Getting gRPC code from error", func() {
+		When("A record is not found", func() {
+			It("Should return code not found", func() {
+				Expect(GetCode(gorm.ErrRecordNotFound)).To(Equal(codes.NotFound))
+			})
+		})
+		When("Payload fails validation", func() {
+			It("Should return code invalid argument", func() {
+				Expect(GetCode(ErrValidation)).To(Equal(codes.InvalidArgument))
+			})
+		})
+		When("A dependency is unavailable", func() {
+			It("Should return code unavailable", func() {
+				Expect(GetCode(fmt.Errorf("%w: timeout", ErrDependencyUnavailable))).To(Equal(codes.Unavailable))
+			})
+		})
+		When("Error is not mapped", func() {
+			It("Should return code internal", func() {
+				Expect(GetCode(assertionErrors.ErrGeneric)).To(Equal(codes.Internal))
+			})
+		})
+	})
 })
+
+// wrapped is an error wrapping every error it holds
+type wrapped []error
+
+func (w wrapped) Error() string {
+	return fmt.Sprint([]error(w))
+}
+
+func (w wrapped) Is(target error) bool {
+	for _, err := range w {
+		if err == target {
+			return true
+		}
+	}
+	return false
+}
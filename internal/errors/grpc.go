@@ -0,0 +1,29 @@
+package errors
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
+
+var statusCodeMap = map[int]codes.Code{
+	http.StatusBadRequest:           codes.InvalidArgument,
+	http.StatusUnauthorized:         codes.Unauthenticated,
+	http.StatusForbidden:            codes.PermissionDenied,
+	http.StatusNotFound:             codes.NotFound,
+	http.StatusConflict:             codes.AlreadyExists,
+	http.StatusPreconditionFailed:   codes.FailedPrecondition,
+	http.StatusUnsupportedMediaType: codes.InvalidArgument,
+	http.StatusUnprocessableEntity:  codes.InvalidArgument,
+	http.StatusTooManyRequests:      codes.ResourceExhausted,
+	http.StatusServiceUnavailable:   codes.Unavailable,
+	http.StatusGatewayTimeout:       codes.DeadlineExceeded,
+}
+
+// GetCode returns the gRPC code matching the http status mapped to err, so both APIs report errors alike
+func GetCode(err error) codes.Code {
+	if code, ok := statusCodeMap[GetStatus(err)]; ok {
+		return code
+	}
+	return codes.Internal
+}
@@ -0,0 +1,85 @@
+// Package health tells whether a replica is ready to serve. The dependencies it can run without, like the cache, are
+// reported as degraded, only those required making the replica not ready
+package health
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ReadyPath is the route of the readiness probe
+	ReadyPath = "/ready"
+
+	StatusReady    = "ready"
+	StatusDegraded = "degraded"
+	StatusNotReady = "not ready"
+)
+
+// Probe tells whether a dependency is degraded
+type Probe interface {
+	Degraded() bool
+}
+
+// Report is the state of the replica, listing its degraded dependencies
+type Report struct {
+	Status   string   `json:"status"`
+	Degraded []string `json:"degraded,omitempty"`
+}
+
+type Readiness interface {
+	Report() Report
+	// Handle answers the readiness probe, with 503 Service Unavailable when a required dependency is degraded
+	Handle(c *gin.Context)
+}
+
+type DependenciesNode struct {
+	// Probes are the dependencies checked, by name
+	Probes map[string]Probe
+}
+
+type Config struct {
+	// Required names the probes making the replica not ready while degraded
+	Required []string
+}
+
+type readiness struct {
+	deps     *DependenciesNode
+	required map[string]bool
+}
+
+func New(deps *DependenciesNode, config Config) Readiness {
+	required := make(map[string]bool, len(config.Required))
+	for _, name := range config.Required {
+		required[name] = true
+	}
+	return &readiness{deps: deps, required: required}
+}
+
+func (r *readiness) Report() Report {
+	report := Report{Status: StatusReady}
+	for name, probe := range r.deps.Probes {
+		if !probe.Degraded() {
+			continue
+		}
+		report.Degraded = append(report.Degraded, name)
+		if r.required[name] {
+			report.Status = StatusNotReady
+		} else if report.Status == StatusReady {
+			report.Status = StatusDegraded
+		}
+	}
+	sort.Strings(report.Degraded)
+	return report
+}
+
+func (r *readiness) Handle(c *gin.Context) {
+	report := r.Report()
+	if report.Status == StatusNotReady {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,75 @@
+package health_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"app/internal/health"
+)
+
+func TestHealth(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Health Suits")
+}
+
+// probe is degraded as it's set
+type probe bool
+
+func (p probe) Degraded() bool {
+	return bool(p)
+}
+
+var _ = Describe("Readiness", func() {
+	serve := func(readiness health.Readiness) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.GET(health.ReadyPath, readiness.Handle)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, health.ReadyPath, nil))
+		return recorder
+	}
+
+	When("no dependency is degraded", func() {
+		It("Should be ready", func() {
+			readiness := health.New(&health.DependenciesNode{
+				Probes: map[string]health.Probe{"cache": probe(false)},
+			}, health.Config{})
+
+			recorder := serve(readiness)
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(MatchJSON(`{"status": "ready"}`))
+		})
+	})
+
+	When("an optional dependency is degraded", func() {
+		It("Should stay ready, reporting it", func() {
+			readiness := health.New(&health.DependenciesNode{
+				Probes: map[string]health.Probe{"cache": probe(true)},
+			}, health.Config{})
+
+			recorder := serve(readiness)
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(MatchJSON(`{"status": "degraded", "degraded": ["cache"]}`))
+		})
+	})
+
+	When("a required dependency is degraded", func() {
+		It("Should not be ready", func() {
+			readiness := health.New(&health.DependenciesNode{
+				Probes: map[string]health.Probe{"cache": probe(true)},
+			}, health.Config{Required: []string{"cache"}})
+
+			recorder := serve(readiness)
+
+			Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(recorder.Body.String()).To(MatchJSON(`{"status": "not ready", "degraded": ["cache"]}`))
+		})
+	})
+})
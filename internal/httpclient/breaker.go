@@ -0,0 +1,79 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	closed breakerState = iota
+	open
+	halfOpen
+)
+
+// breaker is a consecutive failures circuit breaker
+type breaker struct {
+	mu       sync.Mutex
+	cfg      BreakerConfig
+	state    breakerState
+	failures int
+	openedAt time.Time
+	now      func() time.Time
+}
+
+func newBreaker(cfg BreakerConfig) *breaker {
+	return &breaker{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// allow tells whether a request may be sent. Once the open timeout elapses a single trial request is allowed
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case open:
+		if b.now().Sub(b.openedAt) < b.cfg.OpenTimeout {
+			return false
+		}
+		b.state = halfOpen
+		return true
+	case halfOpen:
+		return false
+	}
+	return true
+}
+
+// release gives back the trial request of a half-open breaker whose outcome wasn't recorded, letting the next
+// request try again
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == halfOpen {
+		b.state = open
+	}
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = closed
+	b.failures = 0
+}
+
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == halfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.state = open
+		b.openedAt = b.now()
+	}
+}
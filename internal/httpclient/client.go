@@ -0,0 +1,192 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/internal/trace"
+)
+
+const (
+	contentTypeHeader = "Content-Type"
+	acceptHeader      = "Accept"
+	retryAfterHeader  = "Retry-After"
+
+	jsonContentType = "application/json"
+	acceptedTypes   = jsonContentType + ", " + ProblemContentType
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit breaker is open
+var ErrCircuitOpen = stdErrors.New("circuit breaker is open")
+
+// Client sends JSON requests to a service, retrying idempotent requests that fail with a network error
+// or a 429, 502, 503 or 504 status. Every failure counts towards opening the circuit breaker
+type Client struct {
+	cfg     Config
+	http    *http.Client
+	breaker *breaker
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+func New(cfg Config) *Client {
+	cfg = cfg.withDefaults()
+	return &Client{
+		cfg:     cfg,
+		http:    &http.Client{},
+		breaker: newBreaker(cfg.Breaker),
+		sleep:   sleep,
+	}
+}
+
+// Do sends body as JSON to the path relative to the base URL and decodes the response into out.
+// Error responses are returned as a *Problem
+func (c *Client) Do(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	attempts := 1
+	if isIdempotent(method) {
+		attempts = c.cfg.Retry.MaxAttempts
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if sleepErr := c.sleep(ctx, c.backoff(attempt, err)); sleepErr != nil {
+				return sleepErr
+			}
+		}
+
+		var retry bool
+		retry, err = c.attempt(ctx, method, path, payload, out)
+		if !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// attempt sends the request once, telling whether it can be retried
+func (c *Client) attempt(ctx context.Context, method, path string, payload []byte, out interface{}) (bool, error) {
+	if !c.breaker.allow() {
+		return false, ErrCircuitOpen
+	}
+	// A request failing before it's sent records no outcome, the trial it may hold being given back
+	defer c.breaker.release()
+
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	req, err := c.newRequest(ctx, method, path, payload)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		c.breaker.failure()
+		return ctx.Err() == nil || stdErrors.Is(ctx.Err(), context.DeadlineExceeded), err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		c.breaker.failure()
+	} else {
+		c.breaker.success()
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return isRetryable(resp.StatusCode), withRetryAfter(decodeProblem(resp), resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return false, nil
+	}
+	return false, json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, payload []byte) (*http.Request, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.cfg.BaseURL, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(acceptHeader, acceptedTypes)
+	if payload != nil {
+		req.Header.Set(contentTypeHeader, jsonContentType)
+	}
+	if info, ok := trace.FromContext(ctx); ok {
+		info.Inject(req.Header)
+	}
+	return req, nil
+}
+
+// backoff returns a random delay up to BaseDelay * 2^attempt capped by MaxDelay,
+// unless the last response asked to wait with a Retry-After header
+func (c *Client) backoff(attempt int, lastErr error) time.Duration {
+	var problem *Problem
+	if stdErrors.As(lastErr, &problem) && problem.retryAfter > 0 {
+		return problem.retryAfter
+	}
+
+	ceiling := c.cfg.Retry.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if delay := c.cfg.Retry.BaseDelay << shift; delay > 0 && delay < ceiling {
+			ceiling = delay
+		}
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// withRetryAfter keeps the delay requested by the Retry-After header, in seconds, of a 429 or 503 response
+func withRetryAfter(problem *Problem, resp *http.Response) *Problem {
+	if seconds, err := strconv.Atoi(resp.Header.Get(retryAfterHeader)); err == nil && seconds >= 0 {
+		problem.retryAfter = time.Duration(seconds) * time.Second
+	}
+	return problem
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func isRetryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"app/internal/trace"
+	"app/internal/validation"
+)
+
+func TestHTTPClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HTTP Client Suits")
+}
+
+type payload struct {
+	Name string `json:"name"`
+}
+
+var _ = Describe("HTTP Client", func() {
+	var (
+		server   *httptest.Server
+		handler  http.HandlerFunc
+		requests int32
+		slept    []time.Duration
+		c        *Client
+	)
+
+	BeforeEach(func() {
+		requests = 0
+		slept = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			handler(w, r)
+		}))
+		c = New(Config{
+			BaseURL: server.URL,
+			Breaker: BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute},
+		})
+		c.sleep = func(_ context.Context, d time.Duration) error {
+			slept = append(slept, d)
+			return nil
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("Sending a request", func() {
+		When("Request succeeds", func() {
+			It("Should decode the response", func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					Expect(r.Header.Get(acceptHeader)).To(Equal(acceptedTypes))
+					_ = json.NewEncoder(w).Encode(payload{Name: "sample"})
+				}
+				out := &payload{}
+
+				err := c.Do(context.Background(), http.MethodGet, "/items", nil, out)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(out.Name).To(Equal("sample"))
+			})
+		})
+		When("The context holds trace info", func() {
+			It("Should propagate the request ID and trace context", func() {
+				info := trace.Info{
+					RequestID:   "request-id",
+					TraceParent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+				}
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					Expect(trace.FromHeader(r.Header)).To(Equal(info))
+					w.WriteHeader(http.StatusNoContent)
+				}
+
+				err := c.Do(trace.WithInfo(context.Background(), info), http.MethodDelete, "/items/1", nil, nil)
+
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+		When("The service returns a problem", func() {
+			It("Should return it with its fields", func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set(contentTypeHeader, ProblemContentType)
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					_ = json.NewEncoder(w).Encode(Problem{
+						Title:  "Unprocessable Entity",
+						Status: http.StatusUnprocessableEntity,
+						Fields: []validation.FieldError{{Field: "name", Rule: "required", Message: "is required"}},
+					})
+				}
+
+				err := c.Do(context.Background(), http.MethodPost, "/items", payload{}, nil)
+
+				Expect(HasStatus(err, http.StatusUnprocessableEntity)).To(BeTrue())
+				Expect(err.(*Problem).Fields).To(HaveLen(1))
+				Expect(requests).To(Equal(int32(1)))
+			})
+		})
+		When("The service returns a plain error", func() {
+			It("Should use its message as detail", func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message":"record not found"}`))
+				}
+
+				err := c.Do(context.Background(), http.MethodGet, "/items/1", nil, nil)
+
+				Expect(IsNotFound(err)).To(BeTrue())
+				Expect(err.(*Problem).Detail).To(Equal("record not found"))
+			})
+		})
+	})
+
+	Context("Retrying a request", func() {
+		When("An idempotent request gets a 503", func() {
+			It("Should retry it until it succeeds", func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					if atomic.LoadInt32(&requests) == 1 {
+						w.Header().Set(retryAfterHeader, "1")
+						w.WriteHeader(http.StatusServiceUnavailable)
+						return
+					}
+					w.WriteHeader(http.StatusNoContent)
+				}
+
+				err := c.Do(context.Background(), http.MethodGet, "/items", nil, nil)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(requests).To(Equal(int32(2)))
+				Expect(slept).To(Equal([]time.Duration{time.Second}))
+			})
+		})
+		When("A POST request gets a 503", func() {
+			It("Should not retry it", func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+
+				err := c.Do(context.Background(), http.MethodPost, "/items", payload{}, nil)
+
+				Expect(HasStatus(err, http.StatusServiceUnavailable)).To(BeTrue())
+				Expect(requests).To(Equal(int32(1)))
+			})
+		})
+		When("A request gets a 400", func() {
+			It("Should not retry it", func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+				}
+
+				err := c.Do(context.Background(), http.MethodGet, "/items", nil, nil)
+
+				Expect(HasStatus(err, http.StatusBadRequest)).To(BeTrue())
+				Expect(requests).To(Equal(int32(1)))
+			})
+		})
+	})
+
+	Context("Opening the circuit breaker", func() {
+		When("Requests keep failing", func() {
+			It("Should stop sending them until the open timeout elapses", func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				now := time.Now()
+				c.breaker.now = func() time.Time { return now }
+
+				_ = c.Do(context.Background(), http.MethodPost, "/items", payload{}, nil)
+				_ = c.Do(context.Background(), http.MethodPost, "/items", payload{}, nil)
+				err := c.Do(context.Background(), http.MethodPost, "/items", payload{}, nil)
+
+				Expect(err).To(Equal(ErrCircuitOpen))
+				Expect(requests).To(Equal(int32(2)))
+
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				}
+				now = now.Add(time.Minute)
+
+				Expect(c.Do(context.Background(), http.MethodPost, "/items", payload{}, nil)).To(Succeed())
+				Expect(c.Do(context.Background(), http.MethodPost, "/items", payload{}, nil)).To(Succeed())
+				Expect(requests).To(Equal(int32(4)))
+			})
+		})
+
+		When("The trial request can't be built", func() {
+			It("Should let the next request try again", func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				now := time.Now()
+				c.breaker.now = func() time.Time { return now }
+
+				_ = c.Do(context.Background(), http.MethodPost, "/items", payload{}, nil)
+				_ = c.Do(context.Background(), http.MethodPost, "/items", payload{}, nil)
+
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				}
+				now = now.Add(time.Minute)
+
+				Expect(c.Do(context.Background(), "BAD METHOD", "/items", payload{}, nil)).NotTo(Succeed())
+				Expect(c.Do(context.Background(), http.MethodPost, "/items", payload{}, nil)).To(Succeed())
+				Expect(requests).To(Equal(int32(3)))
+			})
+		})
+	})
+})
@@ -0,0 +1,57 @@
+package httpclient
+
+import "time"
+
+const (
+	defaultTimeout          = 5 * time.Second
+	defaultMaxAttempts      = 3
+	defaultBaseDelay        = 100 * time.Millisecond
+	defaultMaxDelay         = 2 * time.Second
+	defaultFailureThreshold = 5
+	defaultOpenTimeout      = 30 * time.Second
+)
+
+type Config struct {
+	BaseURL string
+	// Timeout bounds every attempt, the caller's context bounds the whole call
+	Timeout time.Duration
+	Retry   RetryConfig
+	Breaker BreakerConfig
+}
+
+// RetryConfig sets up the exponential backoff with full jitter used between attempts
+type RetryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// BreakerConfig sets up the circuit breaker, which opens after FailureThreshold consecutive
+// failures and lets a single trial request through once OpenTimeout has elapsed
+type BreakerConfig struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+// withDefaults fills every zero value with its default
+func (c Config) withDefaults() Config {
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.Retry.MaxAttempts <= 0 {
+		c.Retry.MaxAttempts = defaultMaxAttempts
+	}
+	if c.Retry.BaseDelay <= 0 {
+		c.Retry.BaseDelay = defaultBaseDelay
+	}
+	if c.Retry.MaxDelay <= 0 {
+		c.Retry.MaxDelay = defaultMaxDelay
+	}
+	if c.Breaker.FailureThreshold <= 0 {
+		c.Breaker.FailureThreshold = defaultFailureThreshold
+	}
+	if c.Breaker.OpenTimeout <= 0 {
+		c.Breaker.OpenTimeout = defaultOpenTimeout
+	}
+	return c
+}
@@ -0,0 +1,76 @@
+package httpclient
+
+import (
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"app/internal/validation"
+)
+
+const (
+	ProblemContentType = "application/problem+json"
+
+	maxProblemSize = 64 * 1024
+	problemFormat  = "%d %s"
+	detailFormat   = "%d %s: %s"
+)
+
+// Problem is an RFC 7807 problem details error returned by a service.
+// Fields holds the invalid fields of a validation error, whether it's sent as problem+json or as a validation.Error
+type Problem struct {
+	Type     string                  `json:"type,omitempty"`
+	Title    string                  `json:"title,omitempty"`
+	Status   int                     `json:"status"`
+	Detail   string                  `json:"detail,omitempty"`
+	Instance string                  `json:"instance,omitempty"`
+	Fields   []validation.FieldError `json:"fields,omitempty"`
+
+	retryAfter time.Duration
+}
+
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return fmt.Sprintf(problemFormat, p.Status, p.Title)
+	}
+	return fmt.Sprintf(detailFormat, p.Status, p.Title, p.Detail)
+}
+
+// decodeProblem reads the error response of a service. Bodies that aren't problem+json are decoded
+// as well as possible, so a Problem always carries the status of the response
+func decodeProblem(resp *http.Response) *Problem {
+	var body struct {
+		Problem
+		Message string `json:"message"`
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxProblemSize))
+	if err == nil {
+		_ = json.Unmarshal(data, &body)
+	}
+
+	problem := body.Problem
+	if problem.Status == 0 {
+		problem.Status = resp.StatusCode
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+	if problem.Detail == "" {
+		problem.Detail = body.Message
+	}
+	return &problem
+}
+
+// IsNotFound tells whether err is a Problem with a 404 Not Found status
+func IsNotFound(err error) bool {
+	return HasStatus(err, http.StatusNotFound)
+}
+
+// HasStatus tells whether err is or wraps a Problem with the given status
+func HasStatus(err error, status int) bool {
+	var problem *Problem
+	return stdErrors.As(err, &problem) && problem.Status == status
+}
@@ -0,0 +1,35 @@
+package identifier
+
+import (
+	"fmt"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	UUIDv4Strategy = "uuidv4"
+	UUIDv7Strategy = "uuidv7"
+	ULIDStrategy   = "ulid"
+
+	DefaultStrategy = UUIDv4Strategy
+
+	unknownStrategyErr = "unknown id generation strategy: %s"
+)
+
+// Generator abstracts how entity IDs are assigned by the service layer
+type Generator interface {
+	NewID() uuid.UUID
+}
+
+// New returns the Generator implementation for the given strategy
+func New(strategy string) (Generator, error) {
+	switch strategy {
+	case "", UUIDv4Strategy:
+		return NewUUIDv4Generator(), nil
+	case UUIDv7Strategy:
+		return NewUUIDv7Generator(), nil
+	case ULIDStrategy:
+		return NewULIDGenerator(), nil
+	}
+	return nil, fmt.Errorf(unknownStrategyErr, strategy)
+}
@@ -0,0 +1,114 @@
+package identifier
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestIdentifier(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Identifier Suits")
+}
+
+var _ = Describe("Identifier", func() {
+	Context("Building a generator", func() {
+		When("Strategy is known", func() {
+			It("Should return the matching generator", func() {
+				for strategy, expected := range map[string]Generator{
+					"":             &uuidV4{},
+					UUIDv4Strategy: &uuidV4{},
+					UUIDv7Strategy: &uuidV7{},
+					ULIDStrategy:   &ulid{},
+				} {
+					generator, err := New(strategy)
+
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(generator).To(BeAssignableToTypeOf(expected))
+				}
+			})
+		})
+		When("Strategy is unknown", func() {
+			It("Should return an error", func() {
+				generator, err := New("snowflake")
+
+				Expect(err).Should(HaveOccurred())
+				Expect(generator).To(BeNil())
+			})
+		})
+	})
+
+	Context("Generating IDs", func() {
+		When("Using UUIDv4", func() {
+			It("Should return a version 4 UUID", func() {
+				id := NewUUIDv4Generator().NewID()
+
+				Expect(id.Version()).To(Equal(byte(uuid.V4)))
+				Expect(id.Variant()).To(Equal(uuid.VariantRFC4122))
+			})
+		})
+		When("Using UUIDv7", func() {
+			It("Should return a version 7 UUID", func() {
+				id := NewUUIDv7Generator().NewID()
+
+				Expect(id.Version()).To(Equal(byte(7)))
+				Expect(id.Variant()).To(Equal(uuid.VariantRFC4122))
+			})
+			It("Should be ordered by creation time", func() {
+				now := time.Now()
+				generator := &uuidV7{now: func() time.Time { return now }}
+				first := generator.NewID()
+				generator.now = func() time.Time { return now.Add(time.Millisecond) }
+				second := generator.NewID()
+
+				Expect(bytes.Compare(first.Bytes(), second.Bytes())).To(Equal(-1))
+			})
+		})
+		When("Using ULID", func() {
+			It("Should be monotonic within the same millisecond", func() {
+				now := time.Now()
+				generator := &ulid{now: func() time.Time { return now }}
+				previous := generator.NewID()
+				for i := 0; i < 100; i++ {
+					next := generator.NewID()
+
+					Expect(bytes.Compare(previous.Bytes(), next.Bytes())).To(Equal(-1))
+					previous = next
+				}
+			})
+			It("Should wait for the next millisecond when the random part overflows", func() {
+				now := time.UnixMilli(1672531200000)
+				generator := &ulid{now: func() time.Time { return now }}
+				generator.sleep = func(d time.Duration) { now = now.Add(d) }
+				generator.NewID()
+				for i := 6; i < len(generator.last); i++ {
+					generator.last[i] = 0xff
+				}
+				previous := generator.last
+
+				next := generator.NewID()
+
+				Expect(bytes.Compare(previous.Bytes(), next.Bytes())).To(Equal(-1))
+				Expect(next.Bytes()[:6]).To(Equal([]byte{0x01, 0x85, 0x6a, 0xa0, 0xc8, 0x01}))
+			})
+			It("Should stay ordered when the clock goes backwards", func() {
+				now := time.Now()
+				generator := &ulid{now: func() time.Time { return now }}
+				previous := generator.NewID()
+				now = now.Add(-time.Second)
+
+				Expect(bytes.Compare(previous.Bytes(), generator.NewID().Bytes())).To(Equal(-1))
+			})
+			It("Should start with the timestamp in milliseconds", func() {
+				now := time.UnixMilli(1672531200000)
+				id := (&ulid{now: func() time.Time { return now }}).NewID()
+
+				Expect(id.Bytes()[:6]).To(Equal([]byte{0x01, 0x85, 0x6a, 0xa0, 0xc8, 0x00}))
+			})
+		})
+	})
+})
@@ -0,0 +1,62 @@
+package identifier
+
+import (
+	"bytes"
+	"crypto/rand"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+type ulid struct {
+	mu    sync.Mutex
+	now   func() time.Time
+	sleep func(time.Duration)
+	last  uuid.UUID
+}
+
+// NewULIDGenerator returns a Generator of monotonic ULIDs. The 128 bits of a ULID are stored
+// as an uuid.UUID so they fit the existing uuid columns and keep lexicographic ordering
+func NewULIDGenerator() Generator {
+	return &ulid{
+		now:   time.Now,
+		sleep: time.Sleep,
+	}
+}
+
+// NewID lays out a 48 bits unix timestamp in milliseconds followed by 80 random bits. IDs generated
+// within the same millisecond, or while the clock is behind the previous one, increment the random part of the
+// previous one to remain sortable. When it overflows, NewID waits for the next millisecond
+func (g *ulid) NewID() uuid.UUID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for {
+		var id uuid.UUID
+		putTimestamp(id[:], g.now())
+		if bytes.Compare(id[:6], g.last[:6]) > 0 {
+			_, _ = rand.Read(id[6:])
+			g.last = id
+			return id
+		}
+
+		next := g.last
+		if increment(next[6:]) {
+			g.last = next
+			return next
+		}
+		g.sleep(time.Millisecond)
+	}
+}
+
+// increment adds one to the big endian number in b, reporting false when it overflows
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
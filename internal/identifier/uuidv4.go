@@ -0,0 +1,16 @@
+package identifier
+
+import (
+	uuid "github.com/satori/go.uuid"
+)
+
+type uuidV4 struct{}
+
+// NewUUIDv4Generator returns a Generator of random (version 4) UUIDs
+func NewUUIDv4Generator() Generator {
+	return &uuidV4{}
+}
+
+func (g *uuidV4) NewID() uuid.UUID {
+	return uuid.NewV4()
+}
@@ -0,0 +1,44 @@
+package identifier
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	uuidV7Version = 0x70
+	uuidV7Variant = 0x80
+)
+
+type uuidV7 struct {
+	now func() time.Time
+}
+
+// NewUUIDv7Generator returns a Generator of time-ordered (version 7) UUIDs
+func NewUUIDv7Generator() Generator {
+	return &uuidV7{
+		now: time.Now,
+	}
+}
+
+// NewID lays out a 48 bits unix timestamp in milliseconds followed by the version, the variant and random bits
+func (g *uuidV7) NewID() uuid.UUID {
+	var id uuid.UUID
+	putTimestamp(id[:], g.now())
+	_, _ = rand.Read(id[6:])
+
+	id[6] = (id[6] & 0x0f) | uuidV7Version
+	id[8] = (id[8] & 0x3f) | uuidV7Variant
+
+	return id
+}
+
+// putTimestamp writes the unix time in milliseconds into the first 6 bytes of b
+func putTimestamp(b []byte, t time.Time) {
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(t.UnixMilli()))
+	copy(b[:6], ts[2:])
+}
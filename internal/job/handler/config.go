@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"app/api/middleware"
+	"app/internal/validation"
+)
+
+func (h *Handler) GetRouter() *gin.Engine {
+	return h.deps.Router
+}
+
+func (h *Handler) RegisterRoutes() {
+	h.registerApi()
+}
+
+func (h *Handler) registerApi() {
+	apiGroup := h.deps.Router.Group("/api")
+	{
+		vGroup := apiGroup.Group("/v1")
+		vGroup.Use(middleware.NewParamsMiddleware(map[string]string{ParamID: validation.UUIDRule}).HandleFunc())
+		{
+			vGroup.GET("/jobs/:id", h.Find)
+			vGroup.DELETE("/jobs/:id", h.Cancel)
+		}
+	}
+}
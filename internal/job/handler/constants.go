@@ -0,0 +1,12 @@
+package handler
+
+const (
+	ParamID = "id"
+
+	HeaderRetryAfter = "Retry-After"
+
+	// retryAfterSeconds is how long clients are told to wait before polling a pending job again
+	retryAfterSeconds = "1"
+
+	locationFormat = "/api/v1/jobs/%s"
+)
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"app/internal/errors"
+	"app/internal/job"
+)
+
+type DependenciesNode struct {
+	Queue  job.Queue
+	Router *gin.Engine
+}
+
+type Handler struct {
+	deps *DependenciesNode
+}
+
+func New(deps *DependenciesNode) *Handler {
+	handler := &Handler{
+		deps: deps,
+	}
+	handler.RegisterRoutes()
+	return handler
+}
+
+// Location returns the URL of the status of the job with id, for the endpoints starting one
+func Location(id fmt.Stringer) string {
+	return fmt.Sprintf(locationFormat, id)
+}
+
+// Find godoc
+// @Summary     Shows a job
+// @Description Returns the status of a background job. A queued or running job is answered with 202 Accepted and a Retry-After header telling when to poll again, a done job with 200 OK and its result or error. A job is only returned to the consumer who enqueued it and to the admins, the others getting 404 Not Found
+// @Tags        job
+// @Produce     json
+// @Param       id  path     string true "Job ID"
+// @Success     200 {object} job.Job
+// @Success     202 {object} job.Job
+// @Header      202 {string} Retry-After "Seconds to wait before polling again"
+// @Failure     400 {object} error
+// @Failure     404 {object} error
+// @Failure     500 {object} error
+// @Router      /jobs/{id} [get]
+func (h *Handler) Find(c *gin.Context) {
+	ctx := c.Request.Context()
+	j, err := h.deps.Queue.Get(ctx, c.Param(ParamID))
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	h.respond(c, j)
+}
+
+// Cancel godoc
+// @Summary     Cancels a job
+// @Description Cancels a queued job right away, answering 200 OK, or asks the worker of a running job to stop it, answering 202 Accepted until the job is polled as cancelled. Only the consumer who enqueued the job and the admins can cancel it
+// @Tags        job
+// @Produce     json
+// @Param       id  path     string true "Job ID"
+// @Success     200 {object} job.Job
+// @Success     202 {object} job.Job
+// @Failure     400 {object} error
+// @Failure     404 {object} error
+// @Failure     409 {object} error
+// @Failure     500 {object} error
+// @Router      /jobs/{id} [delete]
+func (h *Handler) Cancel(c *gin.Context) {
+	ctx := c.Request.Context()
+	j, err := h.deps.Queue.Cancel(ctx, c.Param(ParamID))
+	if err != nil {
+		c.JSON(errors.GetStatus(err), err)
+		return
+	}
+
+	h.respond(c, j)
+}
+
+// respond answers 200 OK once j is done, 202 Accepted with a Retry-After header otherwise
+func (h *Handler) respond(c *gin.Context, j *job.Job) {
+	if j.Done() {
+		c.JSON(http.StatusOK, j)
+		return
+	}
+
+	c.Header(HeaderRetryAfter, retryAfterSeconds)
+	c.JSON(http.StatusAccepted, j)
+}
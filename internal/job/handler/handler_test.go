@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/mock"
+
+	"app/internal/errors"
+	"app/internal/job"
+	jobMocks "app/internal/test/mocks/job"
+)
+
+func TestHandler(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Job Handler Suits")
+}
+
+var _ = Describe("Job Handler", func() {
+	var (
+		router    *gin.Engine
+		w         *httptest.ResponseRecorder
+		queueMock *jobMocks.Queue
+		id        uuid.UUID
+		url       string
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		w = httptest.NewRecorder()
+		_, router = gin.CreateTestContext(w)
+		queueMock = jobMocks.NewQueue(GinkgoT())
+		id = uuid.NewV4()
+		url = Location(id)
+		New(&DependenciesNode{
+			Queue:  queueMock,
+			Router: router,
+		})
+	})
+
+	serve := func(method string) *job.Job {
+		request, err := http.NewRequest(method, url, nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, request)
+
+		var resp *job.Job
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+
+	Context("GET", func() {
+		When("the job is running", func() {
+			It("Should return 202 Accepted and when to poll again", func() {
+				queueMock.On("Get", mock.Anything, id.String()).
+					Return(&job.Job{ID: id, Status: job.Running, Progress: 40}, nil)
+
+				resp := serve(http.MethodGet)
+
+				Expect(w.Code).To(Equal(http.StatusAccepted))
+				Expect(w.Header().Get(HeaderRetryAfter)).To(Equal(retryAfterSeconds))
+				Expect(resp.Progress).To(Equal(40))
+			})
+		})
+		When("the job is done", func() {
+			It("Should return 200 OK with the result", func() {
+				queueMock.On("Get", mock.Anything, id.String()).
+					Return(&job.Job{ID: id, Status: job.Succeeded, Result: json.RawMessage(`{"imported":1}`)}, nil)
+
+				resp := serve(http.MethodGet)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Header().Get(HeaderRetryAfter)).To(BeEmpty())
+				Expect(resp.Result).To(MatchJSON(`{"imported":1}`))
+			})
+		})
+		When("the job is unknown", func() {
+			It("Should return 404 Not Found", func() {
+				queueMock.On("Get", mock.Anything, id.String()).Return(nil, errors.ErrJobNotFound)
+
+				serve(http.MethodGet)
+
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+		When("the id isn't a UUID", func() {
+			It("Should return 400 Bad Request", func() {
+				url = "/api/v1/jobs/invalid"
+
+				serve(http.MethodGet)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Context("DELETE", func() {
+		When("the job was queued", func() {
+			It("Should return 200 OK", func() {
+				queueMock.On("Cancel", mock.Anything, id.String()).
+					Return(&job.Job{ID: id, Status: job.Cancelled}, nil)
+
+				resp := serve(http.MethodDelete)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(resp.Status).To(Equal(job.Cancelled))
+			})
+		})
+		When("the job is running", func() {
+			It("Should return 202 Accepted until the worker stops it", func() {
+				queueMock.On("Cancel", mock.Anything, id.String()).
+					Return(&job.Job{ID: id, Status: job.Running}, nil)
+
+				serve(http.MethodDelete)
+
+				Expect(w.Code).To(Equal(http.StatusAccepted))
+			})
+		})
+		When("the job is done", func() {
+			It("Should return 409 Conflict", func() {
+				queueMock.On("Cancel", mock.Anything, id.String()).Return(nil, errors.ErrJobFinished)
+
+				serve(http.MethodDelete)
+
+				Expect(w.Code).To(Equal(http.StatusConflict))
+			})
+		})
+	})
+})
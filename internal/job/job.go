@@ -0,0 +1,52 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// Status is the stage a job is at, a job being done once it succeeded, failed or was cancelled
+type Status string
+
+const (
+	Queued    Status = "queued"
+	Running   Status = "running"
+	Succeeded Status = "succeeded"
+	Failed    Status = "failed"
+	Cancelled Status = "cancelled"
+)
+
+// Job is an operation run by the worker pool outside of the request that enqueued it
+type Job struct {
+	ID       uuid.UUID `json:"id"`
+	Type     string    `json:"type"`
+	Priority int       `json:"priority"`
+	// Owner is the consumer who enqueued the job, only them and the admins following it
+	Owner string `json:"owner,omitempty"`
+	// Payload is the input of the handler of Type, it's never sent back to clients
+	Payload     json.RawMessage `json:"-"`
+	Status      Status          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"maxAttempts"`
+	// Progress is the percentage of the current attempt reported by the handler
+	Progress int             `json:"progress"`
+	Result   json.RawMessage `json:"result,omitempty" swaggertype:"object"`
+	Error    string          `json:"error,omitempty"`
+	// Timeout bounds every attempt, in nanoseconds
+	Timeout time.Duration `json:"timeout" swaggertype:"integer"`
+	// RunAt is when the job is due, later than CreatedAt when an attempt is retried
+	RunAt      time.Time  `json:"runAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	// Lease identifies the hand out of the job by Store.Dequeue, for the worker running it to renew it
+	Lease uuid.UUID `json:"-"`
+}
+
+// Done tells whether the job reached a final status
+func (j *Job) Done() bool {
+	return j.Status == Succeeded || j.Status == Failed || j.Status == Cancelled
+}
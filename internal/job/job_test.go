@@ -0,0 +1,465 @@
+package job
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/mock"
+
+	"app/internal/auth"
+	"app/internal/errors"
+	errorsAssertion "app/internal/test/assertion/errors"
+	pkgMock "app/internal/test/mocks/pkg"
+)
+
+func TestJob(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Job Suits")
+}
+
+const jobType = "test"
+
+type payload struct {
+	Value int `json:"value"`
+}
+
+// memoryStore is a Store safe for the concurrent workers of a test, handing out the due jobs by priority. Its
+// leases never expire unless lost
+type memoryStore struct {
+	mu        sync.Mutex
+	jobs      map[uuid.UUID]Job
+	queued    []uuid.UUID
+	cancelled map[uuid.UUID]bool
+	lost      map[uuid.UUID]bool
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		jobs:      make(map[uuid.UUID]Job),
+		cancelled: make(map[uuid.UUID]bool),
+		lost:      make(map[uuid.UUID]bool),
+	}
+}
+
+func (m *memoryStore) Enqueue(_ context.Context, job *Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = *job
+	m.queued = append(m.queued, job.ID)
+	return nil
+}
+
+func (m *memoryStore) Dequeue(context.Context, time.Duration) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	next := -1
+	for i, id := range m.queued {
+		job := m.jobs[id]
+		if job.RunAt.After(time.Now()) {
+			continue
+		}
+		if next < 0 || job.Priority > m.jobs[m.queued[next]].Priority {
+			next = i
+		}
+	}
+	if next < 0 {
+		return nil, nil
+	}
+
+	job := m.jobs[m.queued[next]]
+	m.queued = append(m.queued[:next], m.queued[next+1:]...)
+	if job.Done() {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+func (m *memoryStore) Renew(_ context.Context, job *Job, _ time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.lost[job.ID], nil
+}
+
+// lose has the lease of the job with id expire
+func (m *memoryStore) lose(id uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lost[id] = true
+}
+
+func (m *memoryStore) Get(_ context.Context, id uuid.UUID) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, errors.ErrJobNotFound
+	}
+	return &job, nil
+}
+
+func (m *memoryStore) Save(_ context.Context, job *Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	saved := *job
+	saved.Payload = m.jobs[job.ID].Payload
+	m.jobs[job.ID] = saved
+	return nil
+}
+
+func (m *memoryStore) RequestCancel(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cancelled[id] = true
+	return nil
+}
+
+func (m *memoryStore) CancelRequested(_ context.Context, id uuid.UUID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cancelled[id], nil
+}
+
+var _ = Describe("Job", func() {
+	var (
+		ctx     context.Context
+		cancel  context.CancelFunc
+		store   *memoryStore
+		q       Queue
+		logMock *pkgMock.Logger
+		done    chan struct{}
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		store = newMemoryStore()
+		q = NewQueue(store)
+		logMock = pkgMock.NewLogger(GinkgoT())
+		logMock.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		done = make(chan struct{})
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	run := func(handler Handler) {
+		p := NewPool(&DependenciesNode{
+			Store: store,
+			Log:   logMock,
+		}, Config{
+			Workers:         2,
+			PollInterval:    10 * time.Millisecond,
+			ShutdownTimeout: time.Second,
+			Lease:           30 * time.Millisecond,
+		}).(*pool)
+		// retries are due right away
+		p.now = func() time.Time {
+			return time.Now().UTC().Add(-time.Hour)
+		}
+		p.Handle(jobType, handler)
+
+		go func(ctx context.Context, done chan<- struct{}) {
+			defer close(done)
+			p.Run(ctx)
+		}(ctx, done)
+	}
+
+	status := func(id string) func() Status {
+		return func() Status {
+			j, err := q.Get(ctx, id)
+			Expect(err).ShouldNot(HaveOccurred())
+			return j.Status
+		}
+	}
+
+	Context("Enqueuing a job", func() {
+		It("Should queue it with the default options", func() {
+			j, err := q.Enqueue(ctx, jobType, payload{Value: 1}, Options{Priority: 1000})
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(j.Status).To(Equal(Queued))
+			Expect(j.Priority).To(Equal(MaxPriority))
+			Expect(j.MaxAttempts).To(Equal(DefaultMaxAttempts))
+			Expect(j.Timeout).To(Equal(DefaultTimeout))
+
+			stored, err := q.Get(ctx, j.ID.String())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stored.Payload).To(MatchJSON(`{"value":1}`))
+		})
+	})
+
+	Context("Getting a job", func() {
+		When("the id isn't a UUID", func() {
+			It("Should return an error", func() {
+				_, err := q.Get(ctx, "invalid")
+
+				Expect(err).To(MatchError(errors.ErrCreatingUUIDFromString))
+			})
+		})
+		When("the job was enqueued by another consumer", func() {
+			It("Should report it as unknown", func() {
+				j, err := q.Enqueue(auth.WithPrincipal(ctx, auth.Principal{Subject: "alice"}), jobType, payload{},
+					Options{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(j.Owner).To(Equal("alice"))
+
+				_, err = q.Get(auth.WithPrincipal(ctx, auth.Principal{Subject: "bob"}), j.ID.String())
+				Expect(err).To(MatchError(errors.ErrJobNotFound))
+				_, err = q.Get(ctx, j.ID.String())
+				Expect(err).To(MatchError(errors.ErrJobNotFound))
+				_, err = q.Cancel(auth.WithPrincipal(ctx, auth.Principal{Subject: "bob"}), j.ID.String())
+				Expect(err).To(MatchError(errors.ErrJobNotFound))
+			})
+		})
+		When("the caller is an admin", func() {
+			It("Should return the job of any consumer", func() {
+				j, err := q.Enqueue(auth.WithPrincipal(ctx, auth.Principal{Subject: "alice"}), jobType, payload{},
+					Options{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				admin := auth.WithPrincipal(ctx, auth.Principal{Subject: "bob", Groups: []string{auth.AdminGroup}})
+				found, err := q.Get(admin, j.ID.String())
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(found.ID).To(Equal(j.ID))
+			})
+		})
+	})
+
+	Context("Cancelling a job", func() {
+		When("the job is queued", func() {
+			It("Should cancel it right away", func() {
+				j, err := q.Enqueue(ctx, jobType, payload{}, Options{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				cancelled, err := q.Cancel(ctx, j.ID.String())
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(cancelled.Status).To(Equal(Cancelled))
+				job, err := store.Dequeue(ctx, time.Minute)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(job).To(BeNil())
+			})
+		})
+		When("the job is running", func() {
+			It("Should have the handler stopped", func() {
+				started := make(chan struct{})
+				run(func(ctx context.Context, _ *Job, _ Progress) (interface{}, error) {
+					close(started)
+					<-ctx.Done()
+					return nil, ctx.Err()
+				})
+				j, err := q.Enqueue(ctx, jobType, payload{}, Options{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Eventually(started).Should(BeClosed())
+
+				running, err := q.Cancel(ctx, j.ID.String())
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(running.Status).To(Equal(Running))
+				Eventually(status(j.ID.String())).Should(Equal(Cancelled))
+			})
+		})
+		When("the job is done", func() {
+			It("Should return an error", func() {
+				run(func(context.Context, *Job, Progress) (interface{}, error) {
+					return nil, nil
+				})
+				j, err := q.Enqueue(ctx, jobType, payload{}, Options{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Eventually(status(j.ID.String())).Should(Equal(Succeeded))
+
+				_, err = q.Cancel(ctx, j.ID.String())
+
+				Expect(err).To(MatchError(errors.ErrJobFinished))
+			})
+		})
+	})
+
+	Context("Running jobs", func() {
+		When("the handler succeeds", func() {
+			It("Should store its result and progress", func() {
+				run(func(_ context.Context, j *Job, progress Progress) (interface{}, error) {
+					progress(50)
+					return map[string]int{"value": len(j.Payload)}, nil
+				})
+				j, err := q.Enqueue(ctx, jobType, payload{Value: 1}, Options{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Eventually(status(j.ID.String())).Should(Equal(Succeeded))
+				finished, err := q.Get(ctx, j.ID.String())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(finished.Attempts).To(Equal(1))
+				Expect(finished.Progress).To(Equal(100))
+				Expect(finished.Result).To(MatchJSON(`{"value":11}`))
+				Expect(finished.FinishedAt).ToNot(BeNil())
+			})
+		})
+		When("an attempt fails", func() {
+			It("Should retry the job", func() {
+				var calls int32
+				run(func(context.Context, *Job, Progress) (interface{}, error) {
+					if atomic.AddInt32(&calls, 1) == 1 {
+						return nil, errorsAssertion.ErrGeneric
+					}
+					return nil, nil
+				})
+				j, err := q.Enqueue(ctx, jobType, payload{}, Options{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Eventually(status(j.ID.String())).Should(Equal(Succeeded))
+				finished, err := q.Get(ctx, j.ID.String())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(finished.Attempts).To(Equal(2))
+			})
+		})
+		When("every attempt fails", func() {
+			It("Should fail the job after its last attempt", func() {
+				var calls int32
+				run(func(context.Context, *Job, Progress) (interface{}, error) {
+					atomic.AddInt32(&calls, 1)
+					return nil, errorsAssertion.ErrGeneric
+				})
+				j, err := q.Enqueue(ctx, jobType, payload{}, Options{MaxAttempts: 2})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Eventually(status(j.ID.String())).Should(Equal(Failed))
+				finished, err := q.Get(ctx, j.ID.String())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(finished.Error).To(Equal(errorsAssertion.ErrGeneric.Error()))
+				Expect(atomic.LoadInt32(&calls)).To(Equal(int32(2)))
+			})
+		})
+		When("an attempt times out", func() {
+			It("Should fail it with a timeout", func() {
+				run(func(ctx context.Context, _ *Job, _ Progress) (interface{}, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				})
+				j, err := q.Enqueue(ctx, jobType, payload{}, Options{MaxAttempts: 1, Timeout: 20 * time.Millisecond})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Eventually(status(j.ID.String())).Should(Equal(Failed))
+				finished, err := q.Get(ctx, j.ID.String())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(finished.Error).To(Equal(ErrTimedOut.Error()))
+			})
+		})
+		When("no handler is registered for the job type", func() {
+			It("Should fail the job", func() {
+				run(func(context.Context, *Job, Progress) (interface{}, error) {
+					return nil, nil
+				})
+				j, err := q.Enqueue(ctx, "unknown", payload{}, Options{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Eventually(status(j.ID.String())).Should(Equal(Failed))
+			})
+		})
+		When("the lease of the job expired", func() {
+			It("Should stop the attempt and leave the job to its new worker", func() {
+				started := make(chan struct{})
+				stopped := make(chan struct{})
+				run(func(ctx context.Context, _ *Job, _ Progress) (interface{}, error) {
+					close(started)
+					<-ctx.Done()
+					close(stopped)
+					return nil, ctx.Err()
+				})
+				j, err := q.Enqueue(ctx, jobType, payload{}, Options{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Eventually(started).Should(BeClosed())
+
+				store.lose(j.ID)
+
+				Eventually(stopped).Should(BeClosed())
+				Consistently(status(j.ID.String()), 50*time.Millisecond).Should(Equal(Running))
+			})
+
+			It("Should not store the progress reported once the attempt stopped", func() {
+				started := make(chan struct{})
+				reported := make(chan struct{})
+				run(func(ctx context.Context, _ *Job, progress Progress) (interface{}, error) {
+					close(started)
+					<-ctx.Done()
+					progress(80)
+					close(reported)
+					return nil, ctx.Err()
+				})
+				j, err := q.Enqueue(ctx, jobType, payload{}, Options{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Eventually(started).Should(BeClosed())
+
+				store.lose(j.ID)
+
+				Eventually(reported).Should(BeClosed())
+				Consistently(func() int {
+					running, err := q.Get(ctx, j.ID.String())
+					Expect(err).ShouldNot(HaveOccurred())
+					return running.Progress
+				}, 50*time.Millisecond).Should(BeZero())
+			})
+		})
+		When("the worker of the job stopped during its last attempt", func() {
+			It("Should fail the job", func() {
+				var calls int32
+				run(func(context.Context, *Job, Progress) (interface{}, error) {
+					atomic.AddInt32(&calls, 1)
+					return nil, nil
+				})
+				abandoned := &Job{ID: uuid.NewV4(), Type: jobType, Status: Running, Attempts: 1, MaxAttempts: 1,
+					Timeout: time.Minute}
+				Expect(store.Enqueue(ctx, abandoned)).Should(Succeed())
+
+				Eventually(status(abandoned.ID.String())).Should(Equal(Failed))
+				finished, err := q.Get(ctx, abandoned.ID.String())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(finished.Error).To(Equal(ErrAbandoned.Error()))
+				Expect(atomic.LoadInt32(&calls)).To(BeZero())
+			})
+		})
+		When("the pool is shut down", func() {
+			It("Should wait for the running jobs", func() {
+				started := make(chan struct{})
+				release := make(chan struct{})
+				run(func(context.Context, *Job, Progress) (interface{}, error) {
+					close(started)
+					<-release
+					return nil, nil
+				})
+				j, err := q.Enqueue(ctx, jobType, payload{}, Options{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Eventually(started).Should(BeClosed())
+
+				cancel()
+				Consistently(done, 100*time.Millisecond).ShouldNot(BeClosed())
+				close(release)
+
+				Eventually(done).Should(BeClosed())
+				Expect(status(j.ID.String())()).To(Equal(Succeeded))
+			})
+		})
+	})
+
+	Context("Reading the input of a job", func() {
+		It("Should report the share read", func() {
+			var reported []int
+			r := NewProgressReader(strings.NewReader("0123456789"), 10, func(percent int) {
+				reported = append(reported, percent)
+			})
+			buf := make([]byte, 5)
+
+			_, _ = r.Read(buf)
+			_, _ = r.Read(buf)
+
+			Expect(reported).To(Equal([]int{49, 99}))
+		})
+	})
+})
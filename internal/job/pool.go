@@ -0,0 +1,344 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+
+	"app/internal/backoff"
+	"app/internal/job/metrics"
+	"app/internal/logger"
+)
+
+const (
+	DefaultWorkers         = 4
+	DefaultPollInterval    = time.Second
+	DefaultShutdownTimeout = 30 * time.Second
+	DefaultLease           = 30 * time.Second
+
+	FailedToDequeue     = "failed to dequeue job"
+	FailedToSave        = "failed to save job"
+	FailedToRun         = "failed to run job"
+	FailedToCheckCancel = "failed to check whether the job was cancelled"
+	FailedToRenewLease  = "failed to renew the lease of the job"
+
+	jobIDKey   = "jobID"
+	jobTypeKey = "jobType"
+	attemptKey = "attempt"
+
+	retriedOutcome     = "retried"
+	interruptedOutcome = "interrupted"
+	lostOutcome        = "lost"
+
+	// renewalsPerLease is how many times a lease is renewed before it expires, so a renewal failing now and then
+	// doesn't lose it
+	renewalsPerLease = 3
+)
+
+var (
+	ErrUnknownType = stdErrors.New("no handler is registered for the job type")
+	ErrTimedOut    = stdErrors.New("job attempt timed out")
+	ErrLeaseLost   = stdErrors.New("job lease expired, another worker runs the job")
+	ErrAbandoned   = stdErrors.New("job worker stopped during the last attempt")
+
+	errCancelRequested = stdErrors.New("job cancel requested")
+)
+
+// retries spaces out the attempts of a failing job
+var retries = backoff.Exponential{Min: time.Second, Max: 5 * time.Minute}
+
+// Progress reports the percentage of the work done by a handler, from the goroutine running it
+type Progress func(percent int)
+
+// Handler runs an attempt of a job, returning the job result or an error to have the job retried. ctx is
+// cancelled when the attempt times out, when the job is cancelled or when the pool is shut down
+type Handler func(ctx context.Context, job *Job, progress Progress) (interface{}, error)
+
+// Pool runs the queued jobs with a fixed number of workers
+type Pool interface {
+	// Handle registers the handler of jobType, it must be called before Run
+	Handle(jobType string, handler Handler)
+	// Run runs the due jobs until ctx is done, then waits for the jobs running
+	Run(ctx context.Context)
+}
+
+type Config struct {
+	Workers int
+	// PollInterval is how often an idle worker looks for a due job, and a busy one checks whether its job
+	// was cancelled
+	PollInterval time.Duration
+	// ShutdownTimeout is how long the jobs running when Run's ctx is done are given to finish, before being
+	// interrupted and queued again
+	ShutdownTimeout time.Duration
+	// Lease is how long a job is held by its worker without renewing it. The job of a worker that stopped, such
+	// as a crashed replica, is run again by another worker once its lease expires
+	Lease time.Duration
+}
+
+type DependenciesNode struct {
+	Store Store
+	Log   logger.Logger
+}
+
+type pool struct {
+	deps     *DependenciesNode
+	config   Config
+	metrics  *metrics.Metrics
+	handlers map[string]Handler
+	now      func() time.Time
+	sleep    func(ctx context.Context, d time.Duration)
+}
+
+// NewPool returns a Pool running a job at most MaxAttempts times, a failed attempt being retried after an
+// exponential backoff. An attempt cut short by the worker stopping counts as failed
+func NewPool(deps *DependenciesNode, config Config) Pool {
+	if config.Workers <= 0 {
+		config.Workers = DefaultWorkers
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	if config.ShutdownTimeout <= 0 {
+		config.ShutdownTimeout = DefaultShutdownTimeout
+	}
+	if config.Lease <= 0 {
+		config.Lease = DefaultLease
+	}
+
+	return &pool{
+		deps:     deps,
+		config:   config,
+		metrics:  metrics.Initialize(),
+		handlers: make(map[string]Handler),
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+		sleep: backoff.Sleep,
+	}
+}
+
+func (p *pool) Handle(jobType string, handler Handler) {
+	p.handlers[jobType] = handler
+}
+
+func (p *pool) Run(ctx context.Context) {
+	// the jobs outlive ctx by ShutdownTimeout at most, so the jobs running get a chance to finish
+	jobs, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go func() {
+		select {
+		case <-jobs.Done():
+		case <-ctx.Done():
+			select {
+			case <-jobs.Done():
+			case <-time.After(p.config.ShutdownTimeout):
+				stopJobs()
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx, jobs)
+		}()
+	}
+	wg.Wait()
+}
+
+// work runs the due jobs one at a time until ctx is done, jobs being the context of the attempts
+func (p *pool) work(ctx, jobs context.Context) {
+	for ctx.Err() == nil {
+		job, err := p.deps.Store.Dequeue(jobs, p.config.Lease)
+		if err != nil {
+			p.deps.Log.Error(ctx, err, FailedToDequeue, nil)
+		}
+		if job == nil {
+			p.sleep(ctx, p.config.PollInterval)
+			continue
+		}
+		p.execute(jobs, job)
+	}
+}
+
+// execute runs an attempt of job and stores its outcome
+func (p *pool) execute(jobs context.Context, job *Job) {
+	fields := logrus.Fields{jobIDKey: job.ID, jobTypeKey: job.Type, attemptKey: job.Attempts + 1}
+
+	handler, ok := p.handlers[job.Type]
+	if !ok {
+		p.deps.Log.Error(jobs, ErrUnknownType, FailedToRun, fields)
+		p.finish(jobs, job, Failed, nil, ErrUnknownType, fields)
+		return
+	}
+	if p.cancelRequested(jobs, job.ID, fields) {
+		p.finish(jobs, job, Cancelled, nil, nil, fields)
+		return
+	}
+	// a running job was queued again when its worker stopped, which ends the job after its last attempt
+	if job.Status == Running && job.Attempts >= job.MaxAttempts {
+		p.deps.Log.Error(jobs, ErrAbandoned, FailedToRun, fields)
+		p.finish(jobs, job, Failed, nil, ErrAbandoned, fields)
+		return
+	}
+
+	now := p.now()
+	job.Status = Running
+	job.Attempts++
+	job.Progress = 0
+	job.Error = ""
+	job.StartedAt = &now
+	job.UpdatedAt = now
+	p.save(jobs, job, fields)
+
+	attempt, stop := context.WithTimeout(jobs, job.Timeout)
+	defer stop()
+	// interruption is read once watched is closed
+	var interruption error
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		if interruption = p.watch(attempt, job, fields); interruption != nil {
+			stop()
+		}
+	}()
+
+	progress := func(percent int) {
+		// a handler reporting after its attempt ended would overwrite the state of what followed it
+		if attempt.Err() != nil {
+			return
+		}
+		if percent < 0 {
+			percent = 0
+		}
+		if percent > 100 {
+			percent = 100
+		}
+		if percent == job.Progress {
+			return
+		}
+		job.Progress = percent
+		job.UpdatedAt = p.now()
+		p.save(jobs, job, fields)
+	}
+
+	result, err := handler(attempt, job, progress)
+	timedOut := attempt.Err() == context.DeadlineExceeded
+	stop()
+	<-watched
+
+	switch {
+	case interruption == ErrLeaseLost:
+		// the job is left to the worker it was handed out to again
+		p.deps.Log.Error(jobs, ErrLeaseLost, FailedToRun, fields)
+		p.metrics.Processed.Increment(job.Type, lostOutcome)
+	case interruption == errCancelRequested:
+		p.finish(jobs, job, Cancelled, nil, nil, fields)
+	case jobs.Err() != nil:
+		// interrupted by the shutdown, the attempt doesn't count
+		job.Attempts--
+		p.requeue(job, p.now(), nil, interruptedOutcome, fields)
+	case err == nil:
+		job.Progress = 100
+		p.finish(jobs, job, Succeeded, result, nil, fields)
+	default:
+		if timedOut {
+			err = ErrTimedOut
+		}
+		p.deps.Log.Error(jobs, err, FailedToRun, fields)
+		if job.Attempts >= job.MaxAttempts {
+			p.finish(jobs, job, Failed, nil, err, fields)
+			return
+		}
+		p.requeue(job, p.now().Add(retries.Delay(job.Attempts)), err, retriedOutcome, fields)
+	}
+}
+
+// watch polls the cancellation of job and renews its lease until ctx is done. It returns errCancelRequested or
+// ErrLeaseLost when the attempt must stop, nil once ctx is done
+func (p *pool) watch(ctx context.Context, job *Job, fields logrus.Fields) error {
+	ticker := time.NewTicker(p.config.PollInterval)
+	defer ticker.Stop()
+	renewal := time.NewTicker(p.config.Lease / renewalsPerLease)
+	defer renewal.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if p.cancelRequested(ctx, job.ID, fields) {
+				return errCancelRequested
+			}
+		case <-renewal.C:
+			renewed, err := p.deps.Store.Renew(ctx, job, p.config.Lease)
+			if err != nil {
+				if ctx.Err() == nil {
+					p.deps.Log.Error(ctx, err, FailedToRenewLease, fields)
+				}
+				continue
+			}
+			if !renewed {
+				return ErrLeaseLost
+			}
+		}
+	}
+}
+
+func (p *pool) cancelRequested(ctx context.Context, id uuid.UUID, fields logrus.Fields) bool {
+	requested, err := p.deps.Store.CancelRequested(ctx, id)
+	if err != nil && ctx.Err() == nil {
+		p.deps.Log.Error(ctx, err, FailedToCheckCancel, fields)
+	}
+	return requested
+}
+
+// finish stores the final status of job, result being marshalled as the job result
+func (p *pool) finish(ctx context.Context, job *Job, status Status, result interface{}, err error,
+	fields logrus.Fields) {
+	now := p.now()
+	job.Status = status
+	job.UpdatedAt = now
+	job.FinishedAt = &now
+	if err != nil {
+		job.Error = err.Error()
+	}
+	if result != nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			p.deps.Log.Error(ctx, err, FailedToSave, fields)
+		}
+		job.Result = data
+	}
+
+	p.metrics.Processed.Increment(job.Type, string(status))
+	p.save(ctx, job, fields)
+}
+
+// requeue queues job again to run at runAt. It's stored without ctx, which may be done on shutdown
+func (p *pool) requeue(job *Job, runAt time.Time, err error, outcome string, fields logrus.Fields) {
+	ctx := context.Background()
+	job.Status = Queued
+	job.RunAt = runAt
+	job.UpdatedAt = p.now()
+	if err != nil {
+		job.Error = err.Error()
+	}
+
+	p.metrics.Processed.Increment(job.Type, outcome)
+	if err = p.deps.Store.Enqueue(ctx, job); err != nil {
+		p.deps.Log.Error(ctx, err, FailedToSave, fields)
+	}
+}
+
+func (p *pool) save(ctx context.Context, job *Job, fields logrus.Fields) {
+	if err := p.deps.Store.Save(context.Background(), job); err != nil {
+		p.deps.Log.Error(ctx, err, FailedToSave, fields)
+	}
+}
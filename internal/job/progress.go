@@ -0,0 +1,30 @@
+package job
+
+import "io"
+
+type progressReader struct {
+	r        io.Reader
+	size     int64
+	read     int64
+	progress Progress
+}
+
+// NewProgressReader returns a reader reporting the share of the size bytes of r read so far, for handlers
+// whose work is reading their input
+func NewProgressReader(r io.Reader, size int64, progress Progress) io.Reader {
+	return &progressReader{
+		r:        r,
+		size:     size,
+		progress: progress,
+	}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	if p.size > 0 {
+		// the last percent is reported once the handler is done with what it read
+		p.progress(int(p.read * 99 / p.size))
+	}
+	return n, err
+}
@@ -0,0 +1,147 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/auth"
+	"app/internal/errors"
+)
+
+const (
+	DefaultMaxAttempts = 3
+	DefaultTimeout     = 15 * time.Minute
+
+	MinPriority = -100
+	MaxPriority = 100
+)
+
+// Options tune how a job is run, the zero value running it once due with the default attempts and timeout
+type Options struct {
+	// Priority orders the due jobs, higher first, from MinPriority to MaxPriority
+	Priority    int
+	MaxAttempts int
+	Timeout     time.Duration
+}
+
+// Queue is how the service layer hands jobs to the worker pool and follows them
+type Queue interface {
+	// Enqueue queues a job of jobType owned by the consumer of ctx, payload being marshalled as the input of its
+	// handler
+	Enqueue(ctx context.Context, jobType string, payload interface{}, options Options) (*Job, error)
+	// Get returns the job with id, failing with errors.ErrJobNotFound when the consumer of ctx is neither its owner
+	// nor an admin
+	Get(ctx context.Context, id string) (*Job, error)
+	// Cancel cancels a queued job right away and asks the worker of a running one to stop it, in which case
+	// the job returned is still running. It fails with errors.ErrJobFinished when the job is done
+	Cancel(ctx context.Context, id string) (*Job, error)
+}
+
+type queue struct {
+	store Store
+	now   func() time.Time
+}
+
+func NewQueue(store Store) Queue {
+	return &queue{
+		store: store,
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+func (q *queue) Enqueue(ctx context.Context, jobType string, payload interface{}, options Options) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultMaxAttempts
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.Priority < MinPriority {
+		options.Priority = MinPriority
+	}
+	if options.Priority > MaxPriority {
+		options.Priority = MaxPriority
+	}
+
+	now := q.now()
+	job := &Job{
+		ID:          uuid.NewV4(),
+		Type:        jobType,
+		Priority:    options.Priority,
+		Payload:     data,
+		Status:      Queued,
+		MaxAttempts: options.MaxAttempts,
+		Timeout:     options.Timeout,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if principal, ok := auth.FromContext(ctx); ok {
+		job.Owner = principal.Subject
+	}
+	if err = q.store.Enqueue(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (q *queue) Get(ctx context.Context, id string) (*Job, error) {
+	jobID, err := uuid.FromString(id)
+	if err != nil {
+		return nil, errors.ErrCreatingUUIDFromString
+	}
+	job, err := q.store.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	// someone else's job is reported as unknown, so its id can't be probed
+	if !visible(ctx, job) {
+		return nil, errors.ErrJobNotFound
+	}
+	return job, nil
+}
+
+func (q *queue) Cancel(ctx context.Context, id string) (*Job, error) {
+	job, err := q.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Done() {
+		return nil, errors.ErrJobFinished
+	}
+
+	// flagged first, so a worker dequeuing the job meanwhile cancels it instead of running it
+	if err = q.store.RequestCancel(ctx, job.ID); err != nil {
+		return nil, err
+	}
+	if job.Status != Queued {
+		return job, nil
+	}
+
+	now := q.now()
+	job.Status = Cancelled
+	job.UpdatedAt = now
+	job.FinishedAt = &now
+	if err = q.store.Save(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// visible tells whether the consumer of ctx may follow job
+func visible(ctx context.Context, job *Job) bool {
+	if job.Owner == "" {
+		return true
+	}
+	principal, ok := auth.FromContext(ctx)
+	return ok && (principal.Subject == job.Owner || principal.HasGroup(auth.AdminGroup))
+}
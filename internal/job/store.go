@@ -0,0 +1,31 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// Store persists the jobs and hands out the queued ones by priority
+type Store interface {
+	// Enqueue stores job with its payload and queues it until job.RunAt. A leased job, enqueued again by its
+	// worker, fails with ErrLeaseLost once its lease was handed out again, leaving the job to its new worker
+	Enqueue(ctx context.Context, job *Job) error
+	// Dequeue takes the due job of highest priority off the queue, the oldest first among equal priorities, and
+	// leases it to the caller for lease. A job whose lease expired, its worker having stopped without finishing it,
+	// is queued again. It returns nil when no job is due
+	Dequeue(ctx context.Context, lease time.Duration) (*Job, error)
+	// Renew extends the lease of job by lease, telling false when it expired and the job was queued again.
+	// Enqueuing the job again or saving it done ends its lease
+	Renew(ctx context.Context, job *Job, lease time.Duration) (bool, error)
+	// Get returns the job with id, failing with errors.ErrJobNotFound when it's unknown or expired
+	Get(ctx context.Context, id uuid.UUID) (*Job, error)
+	// Save stores the state of job, leaving its payload as enqueued. Like Enqueue, it fails with ErrLeaseLost once
+	// the lease of a leased job was handed out again
+	Save(ctx context.Context, job *Job) error
+	// RequestCancel flags the job with id, for the worker running it to stop
+	RequestCancel(ctx context.Context, id uuid.UUID) error
+	// CancelRequested tells whether the job with id was flagged by RequestCancel
+	CancelRequested(ctx context.Context, id uuid.UUID) (bool, error)
+}
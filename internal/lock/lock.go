@@ -0,0 +1,10 @@
+package lock
+
+import "context"
+
+// Locker holds locks shared by the replicas of a service, so a single replica at a time runs what a lock guards
+type Locker interface {
+	// Hold runs fn while holding the lock named key, unless another replica holds it, telling whether the lock
+	// was acquired. The ctx given to fn is cancelled when the lock is lost, and the lock released once fn returns
+	Hold(ctx context.Context, key string, fn func(ctx context.Context)) (bool, error)
+}
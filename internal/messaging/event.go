@@ -0,0 +1,60 @@
+package messaging
+
+import (
+	"encoding/json"
+	"time"
+
+	"app/internal/identifier"
+)
+
+const (
+	SpecVersion     = "1.0"
+	JSONContentType = "application/json"
+
+	ItemCreated  = "item.created"
+	ItemUpdated  = "item.updated"
+	ItemDeleted  = "item.deleted"
+	ItemRestored = "item.restored"
+)
+
+var ids = identifier.NewULIDGenerator()
+
+// Event is a CloudEvents 1.0 envelope in the JSON structured format
+type Event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// NewEvent returns an event of the given type about subject, with data encoded as JSON when not nil
+func NewEvent(source, eventType, subject string, data interface{}) (Event, error) {
+	event := Event{
+		SpecVersion: SpecVersion,
+		ID:          ids.NewID().String(),
+		Source:      source,
+		Type:        eventType,
+		Subject:     subject,
+		Time:        time.Now().UTC(),
+	}
+	if data == nil {
+		return event, nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+	event.DataContentType = JSONContentType
+	event.Data = raw
+	return event, nil
+}
+
+// Decode unmarshals the data of the event into obj
+func (e Event) Decode(obj interface{}) error {
+	return json.Unmarshal(e.Data, obj)
+}
@@ -0,0 +1,34 @@
+package messaging
+
+import "context"
+
+// Message is an event received by a consumer group. It must be acknowledged once handled,
+// a negative acknowledgement asking the broker to deliver it again
+type Message struct {
+	Topic string
+	Event Event
+	// Attempt counts the deliveries of the message, starting at 1
+	Attempt int
+
+	ack  func(ctx context.Context) error
+	nack func(ctx context.Context) error
+}
+
+// NewMessage is used by the Subscriber implementations to wrap the events they receive
+func NewMessage(topic string, event Event, attempt int, ack, nack func(ctx context.Context) error) *Message {
+	return &Message{
+		Topic:   topic,
+		Event:   event,
+		Attempt: attempt,
+		ack:     ack,
+		nack:    nack,
+	}
+}
+
+func (m *Message) Ack(ctx context.Context) error {
+	return m.ack(ctx)
+}
+
+func (m *Message) Nack(ctx context.Context) error {
+	return m.nack(ctx)
+}
@@ -0,0 +1,18 @@
+package messaging
+
+import "context"
+
+type Publisher interface {
+	Publish(ctx context.Context, topic string, event Event) error
+}
+
+// Subscriber delivers the events of a topic to consumer groups, each message reaching a single member of a group.
+// The channel is closed once ctx is done
+type Subscriber interface {
+	Subscribe(ctx context.Context, topic, group string) (<-chan *Message, error)
+}
+
+type Broker interface {
+	Publisher
+	Subscriber
+}
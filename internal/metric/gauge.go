@@ -0,0 +1,27 @@
+package metric
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type GaugeVec interface {
+	Set(value float64, labels ...string)
+	Reset()
+}
+
+func newGaugeVec(metric *prometheus.GaugeVec) GaugeVec {
+	return &gaugeVec{
+		metric: metric,
+	}
+}
+
+type gaugeVec struct {
+	metric *prometheus.GaugeVec
+}
+
+func (g *gaugeVec) Set(value float64, labels ...string) {
+	g.metric.WithLabelValues(labels...).Set(value)
+}
+
+// Reset drops every label combination, so the ones not set again disappear instead of keeping a stale value
+func (g *gaugeVec) Reset() {
+	g.metric.Reset()
+}
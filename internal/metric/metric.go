@@ -1,10 +1,18 @@
 package metric
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"errors"
+	"log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 const (
 	CounterVecType   = "counter_vec"
 	HistogramVecType = "histogram_vec"
+	GaugeVecType     = "gauge_vec"
+
+	failedToRegister = "failed to register metric %s: %v\n"
 )
 
 type Properties struct {
@@ -16,28 +24,61 @@ type Properties struct {
 	Properties  []string
 }
 
+// NewCounter returns the counter of p, registered so it's served on /metrics. Like NewHistogram and NewGauge, it
+// returns the counter already registered under the same name, so the components built more than once share it
 func NewCounter(p Properties) CounterVec {
 	return newCounterVec(
-		prometheus.NewCounterVec(
+		register(p, prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:      p.Name,
 				Namespace: p.Namespace,
 				Help:      p.Description,
 			},
 			p.Properties,
-		),
+		)),
 	)
 }
 
 func NewHistogram(p Properties) HistogramVec {
 	return newHistogramVec(
-		prometheus.NewHistogramVec(
+		register(p, prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:      p.Name,
 				Namespace: p.Namespace,
 				Help:      p.Description,
 			},
 			p.Properties,
-		),
+		)),
+	)
+}
+
+func NewGauge(p Properties) GaugeVec {
+	return newGaugeVec(
+		register(p, prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:      p.Name,
+				Namespace: p.Namespace,
+				Help:      p.Description,
+			},
+			p.Properties,
+		)),
 	)
 }
+
+// register registers collector with the default registry, returning the collector registered before it under the
+// same name if any. A metric failing to register is only logged, its values being dropped
+func register[C prometheus.Collector](p Properties, collector C) C {
+	err := prometheus.Register(collector)
+	if err == nil {
+		return collector
+	}
+
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		if existing, ok := registered.ExistingCollector.(C); ok {
+			return existing
+		}
+	}
+	log.Printf(failedToRegister, prometheus.BuildFQName(p.Namespace, "", p.Name), err)
+	return collector
+}
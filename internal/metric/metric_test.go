@@ -0,0 +1,60 @@
+package metric_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"app/internal/metric"
+)
+
+func TestMetric(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Metric Suits")
+}
+
+var _ = Describe("Metric", func() {
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+		promhttp.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		return recorder.Body.String()
+	}
+
+	properties := func(name, metricType string) metric.Properties {
+		return metric.Properties{
+			Name:        name,
+			Namespace:   "metric_test",
+			Description: "Metric of the tests",
+			Type:        metricType,
+			Properties:  []string{"label"},
+		}
+	}
+
+	When("A metric is built", func() {
+		It("Should be served on /metrics", func() {
+			metric.NewCounter(properties("served_count", metric.CounterVecType)).Increment("counter")
+			metric.NewGauge(properties("served_gauge", metric.GaugeVecType)).Set(2, "gauge")
+			metric.NewHistogram(properties("served_seconds", metric.HistogramVecType)).Observe(0.5, "histogram")
+
+			body := scrape()
+
+			Expect(body).To(ContainSubstring(`metric_test_served_count{label="counter"} 1`))
+			Expect(body).To(ContainSubstring(`metric_test_served_gauge{label="gauge"} 2`))
+			Expect(body).To(ContainSubstring(`metric_test_served_seconds_count{label="histogram"} 1`))
+		})
+	})
+
+	When("A metric is built twice", func() {
+		It("Should share the registered one", func() {
+			metric.NewCounter(properties("shared_count", metric.CounterVecType)).Increment("counter")
+			metric.NewCounter(properties("shared_count", metric.CounterVecType)).Increment("counter")
+
+			Expect(scrape()).To(ContainSubstring(`metric_test_shared_count{label="counter"} 2`))
+		})
+	})
+})
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"app/internal/metric"
+)
+
+const (
+	NamespaceProperty = "outbox_relay"
+
+	BacklogNameProperty        = "backlog_size"
+	BacklogDescriptionProperty = "Number of events waiting in the outbox"
+
+	LagNameProperty        = "lag_in_seconds"
+	LagDescriptionProperty = "Age of the oldest event waiting in the outbox in seconds"
+
+	FailureNameProperty        = "failure_count"
+	FailureDescriptionProperty = "Events the relay failed to publish"
+
+	DeadNameProperty        = "dead_count"
+	DeadDescriptionProperty = "Events the relay gave up after their last attempt"
+
+	topicPropertyKey = "topic"
+)
+
+type Metrics struct {
+	Backlog  metric.GaugeVec
+	Lag      metric.GaugeVec
+	Failures metric.CounterVec
+	Dead     metric.CounterVec
+}
+
+func Initialize() *Metrics {
+	return &Metrics{
+		Backlog:  metric.NewGauge(gaugeMetricProperties(BacklogNameProperty, BacklogDescriptionProperty)),
+		Lag:      metric.NewGauge(gaugeMetricProperties(LagNameProperty, LagDescriptionProperty)),
+		Failures: metric.NewCounter(counterMetricProperties(FailureNameProperty, FailureDescriptionProperty)),
+		Dead:     metric.NewCounter(counterMetricProperties(DeadNameProperty, DeadDescriptionProperty)),
+	}
+}
+
+func gaugeMetricProperties(name, description string) metric.Properties {
+	return metric.Properties{
+		Name:        name,
+		Namespace:   NamespaceProperty,
+		Description: description,
+		Type:        metric.GaugeVecType,
+		Properties:  []string{topicPropertyKey},
+	}
+}
+
+func counterMetricProperties(name, description string) metric.Properties {
+	return metric.Properties{
+		Name:        name,
+		Namespace:   NamespaceProperty,
+		Description: description,
+		Type:        metric.CounterVecType,
+		Properties:  []string{topicPropertyKey},
+	}
+}
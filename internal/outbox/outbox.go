@@ -0,0 +1,40 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"app/internal/messaging"
+	"app/internal/storage"
+)
+
+type outbox struct {
+	database storage.Database
+}
+
+// New returns a messaging.Publisher storing the events in the outbox table instead of sending them. Called with
+// the ctx of a storage.Database transaction, the event is stored if and only if the rest of the transaction is,
+// the Relay publishing it to the message broker once committed
+func New(database storage.Database) messaging.Publisher {
+	return &outbox{
+		database: database,
+	}
+}
+
+func (o *outbox) Publish(ctx context.Context, topic string, event messaging.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	return o.database.Create(ctx, &Record{
+		ID:            event.ID,
+		Topic:         topic,
+		AggregateID:   event.Subject,
+		Event:         data,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	})
+}
@@ -0,0 +1,222 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+
+	"app/internal/messaging"
+	commonAssertion "app/internal/test/assertion/common"
+	errorsAssertion "app/internal/test/assertion/errors"
+	messagingMock "app/internal/test/mocks/messaging"
+	pkgMock "app/internal/test/mocks/pkg"
+	storageMock "app/internal/test/mocks/storage"
+)
+
+func TestOutbox(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Outbox Suits")
+}
+
+const topic = "items"
+
+var now = time.Date(2022, 11, 10, 12, 0, 0, 0, time.UTC)
+
+func newRecord(id, aggregateID string) *Record {
+	event, err := messaging.NewEvent("/test", messaging.ItemUpdated, aggregateID, nil)
+	Expect(err).ShouldNot(HaveOccurred())
+	event.ID = id
+	data, err := json.Marshal(event)
+	Expect(err).ShouldNot(HaveOccurred())
+	return &Record{ID: id, Topic: topic, AggregateID: aggregateID, Event: data, NextAttemptAt: now}
+}
+
+func inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+var _ = Describe("Outbox", func() {
+	var (
+		databaseMock  *storageMock.Database
+		publisherMock *messagingMock.Publisher
+		logMock       *pkgMock.Logger
+	)
+
+	BeforeEach(func() {
+		databaseMock = storageMock.NewDatabase(GinkgoT())
+		publisherMock = messagingMock.NewPublisher(GinkgoT())
+		logMock = pkgMock.NewLogger(GinkgoT())
+	})
+
+	Context("Storing an event", func() {
+		When("Event is published to the outbox", func() {
+			It("Should store it as a pending record of its aggregate", func() {
+				event, err := messaging.NewEvent("/test", messaging.ItemCreated, "aggregate", nil)
+				Expect(err).ShouldNot(HaveOccurred())
+				databaseMock.On("Create", commonAssertion.EmptyCtx, mock.MatchedBy(func(record *Record) bool {
+					return record.ID == event.ID &&
+						record.Topic == topic &&
+						record.AggregateID == "aggregate" &&
+						record.PublishedAt == nil &&
+						len(record.Event) > 0
+				})).
+					Return(nil).
+					Once()
+
+				err = New(databaseMock).Publish(commonAssertion.EmptyCtx, topic, event)
+
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+	})
+
+	Context("Relaying events", func() {
+		var r *relay
+
+		BeforeEach(func() {
+			r = NewRelay(&DependenciesNode{
+				Database:  databaseMock,
+				Publisher: publisherMock,
+				Log:       logMock,
+			}, Config{BatchSize: 10, Retention: time.Hour}).(*relay)
+			r.now = func() time.Time { return now }
+
+			databaseMock.On("Transaction", commonAssertion.EmptyCtx, mock.Anything).
+				Return(inTransaction).
+				Once()
+			databaseMock.On("Raw", commonAssertion.EmptyCtx, statsQuery, mock.Anything).
+				Run(func(args mock.Arguments) {
+					*args.Get(2).(*[]topicStats) = []topicStats{{Topic: topic, Pending: 1, Oldest: now.Add(-time.Minute)}}
+				}).
+				Return(nil).
+				Once()
+			databaseMock.On("Raw", commonAssertion.EmptyCtx, fmt.Sprintf(cleanupQuery, 3600), mock.Anything).
+				Return(nil).
+				Once()
+		})
+
+		expectLock := func(locked bool) {
+			databaseMock.On("Raw", commonAssertion.EmptyCtx, lockQuery, mock.Anything).
+				Run(func(args mock.Arguments) {
+					*args.Get(2).(*bool) = locked
+				}).
+				Return(nil).
+				Once()
+		}
+
+		expectPending := func(records ...*Record) {
+			databaseMock.On("Raw", commonAssertion.EmptyCtx, fmt.Sprintf(pendingQuery, 10), mock.Anything).
+				Run(func(args mock.Arguments) {
+					*args.Get(2).(*[]*Record) = records
+				}).
+				Return(nil).
+				Once()
+		}
+
+		expectPublished := func(id string) {
+			publisherMock.On("Publish", commonAssertion.EmptyCtx, topic, mock.MatchedBy(func(event messaging.Event) bool {
+				return event.ID == id
+			})).
+				Return(nil).
+				Once()
+			databaseMock.On("SetColumns", commonAssertion.EmptyCtx, mock.MatchedBy(func(record *Record) bool {
+				return record.ID == id
+			}), map[string]interface{}{publishedAtColumn: now}).
+				Return(nil).
+				Once()
+		}
+
+		When("Events are pending", func() {
+			It("Should publish them and mark them as published", func() {
+				expectLock(true)
+				expectPending(newRecord("01", "first"), newRecord("02", "second"))
+				expectPublished("01")
+				expectPublished("02")
+
+				err := r.RelayPending(commonAssertion.EmptyCtx)
+
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+		When("An event fails to be published", func() {
+			It("Should schedule a retry and hold the later events of its aggregate", func() {
+				expectLock(true)
+				expectPending(newRecord("01", "first"), newRecord("02", "second"), newRecord("03", "first"))
+				publisherMock.On("Publish", commonAssertion.EmptyCtx, topic, mock.MatchedBy(func(event messaging.Event) bool {
+					return event.ID == "01"
+				})).
+					Return(errorsAssertion.ErrGeneric).
+					Once()
+				logMock.On("Error",
+					commonAssertion.EmptyCtx,
+					errorsAssertion.ErrGeneric,
+					FailedToPublishRecord,
+					logrus.Fields{recordIDKey: "01", topicKey: topic, attemptsKey: 1},
+				).Once()
+				databaseMock.On("SetColumns", commonAssertion.EmptyCtx, mock.Anything, map[string]interface{}{
+					attemptsColumn:      1,
+					nextAttemptAtColumn: now.Add(retries.Min),
+					lastErrorColumn:     errorsAssertion.ErrGeneric.Error(),
+				}).
+					Return(nil).
+					Once()
+				expectPublished("02")
+
+				err := r.RelayPending(commonAssertion.EmptyCtx)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				publisherMock.AssertNumberOfCalls(GinkgoT(), "Publish", 2)
+			})
+		})
+		When("An event fails its last attempt", func() {
+			It("Should give it up without holding the later events of its aggregate", func() {
+				r.config.MaxAttempts = 3
+				poison := newRecord("01", "first")
+				poison.Attempts = 2
+				expectLock(true)
+				expectPending(poison, newRecord("02", "first"))
+				publisherMock.On("Publish", commonAssertion.EmptyCtx, topic, mock.MatchedBy(func(event messaging.Event) bool {
+					return event.ID == "01"
+				})).
+					Return(errorsAssertion.ErrGeneric).
+					Once()
+				logMock.On("Error",
+					commonAssertion.EmptyCtx,
+					errorsAssertion.ErrGeneric,
+					GaveUpRecord,
+					logrus.Fields{recordIDKey: "01", topicKey: topic, attemptsKey: 3},
+				).Once()
+				databaseMock.On("SetColumns", commonAssertion.EmptyCtx, poison, map[string]interface{}{
+					attemptsColumn:  3,
+					lastErrorColumn: errorsAssertion.ErrGeneric.Error(),
+					deadAtColumn:    now,
+				}).
+					Return(nil).
+					Once()
+				expectPublished("02")
+
+				err := r.RelayPending(commonAssertion.EmptyCtx)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				publisherMock.AssertNumberOfCalls(GinkgoT(), "Publish", 2)
+			})
+		})
+		When("Another replica is relaying", func() {
+			It("Should not publish anything", func() {
+				expectLock(false)
+
+				err := r.RelayPending(commonAssertion.EmptyCtx)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				publisherMock.AssertNotCalled(GinkgoT(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+			})
+		})
+	})
+})
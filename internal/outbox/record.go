@@ -0,0 +1,34 @@
+package outbox
+
+import "time"
+
+const (
+	tableName = "outbox"
+
+	publishedAtColumn   = "published_at"
+	attemptsColumn      = "attempts"
+	nextAttemptAtColumn = "next_attempt_at"
+	lastErrorColumn     = "last_error"
+	deadAtColumn        = "dead_at"
+)
+
+// Record is an event stored in the outbox table until the relay publishes it. Its ID is the ID of the event,
+// a ULID, so sorting the records by ID follows the order they were written in
+type Record struct {
+	ID          string `gorm:"primaryKey"`
+	Topic       string
+	AggregateID string `gorm:"index"`
+	Event       []byte `gorm:"type:jsonb"`
+	CreatedAt   time.Time
+	// Attempts counts the failed publications, the next one being delayed until NextAttemptAt
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	PublishedAt   *time.Time `gorm:"index"`
+	// DeadAt is when the relay gave up the event after its last attempt
+	DeadAt *time.Time
+}
+
+func (Record) TableName() string {
+	return tableName
+}
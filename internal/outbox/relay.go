@@ -0,0 +1,223 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"app/internal/backoff"
+	"app/internal/logger"
+	"app/internal/messaging"
+	"app/internal/outbox/metrics"
+	"app/internal/storage"
+)
+
+const (
+	// lockQuery makes a single replica relay at a time, so the events of an aggregate keep their order
+	lockQuery = "SELECT pg_try_advisory_xact_lock(hashtext('outbox')) AS locked"
+	// pendingQuery selects the events due, leaving out those of the aggregates holding an earlier event waiting for
+	// its retry so the waiting events can't fill the batches. The dead events don't hold their aggregate
+	pendingQuery = "SELECT * FROM outbox o WHERE published_at IS NULL AND dead_at IS NULL " +
+		"AND next_attempt_at <= now() AND NOT EXISTS (SELECT 1 FROM outbox w WHERE w.aggregate_id = o.aggregate_id " +
+		"AND w.id < o.id AND w.published_at IS NULL AND w.dead_at IS NULL AND w.next_attempt_at > now()) " +
+		"ORDER BY id LIMIT %d"
+	statsQuery = "SELECT topic, count(*) AS pending, min(created_at) AS oldest FROM outbox " +
+		"WHERE published_at IS NULL AND dead_at IS NULL GROUP BY topic"
+	cleanupQuery = "DELETE FROM outbox WHERE published_at < now() - interval '%d seconds' RETURNING id"
+
+	FailedToRelay         = "failed to relay outbox events"
+	FailedToPublishRecord = "failed to publish outbox event"
+	GaveUpRecord          = "gave up publishing outbox event"
+	FailedToReportBacklog = "failed to report outbox backlog"
+	FailedToCleanUp       = "failed to clean up outbox"
+
+	recordIDKey = "recordID"
+	topicKey    = "topic"
+	attemptsKey = "attempts"
+)
+
+// retries spaces out the attempts of an event failing to be published
+var retries = backoff.Exponential{Min: time.Second, Max: 5 * time.Minute}
+
+// Relay publishes the events stored by the outbox to the message broker
+type Relay interface {
+	// Run relays the pending events every interval until ctx is done
+	Run(ctx context.Context)
+	// RelayPending publishes a batch of pending events, then reports the backlog and removes the events
+	// published longer than the retention ago
+	RelayPending(ctx context.Context) error
+}
+
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+	// Retention is how long published events are kept, for troubleshooting
+	Retention time.Duration
+	// MaxAttempts is how many times an event is published before it's given up as dead, kept in the table without
+	// holding the later events of its aggregate anymore. Events are retried forever when it's 0
+	MaxAttempts int
+}
+
+type DependenciesNode struct {
+	Database  storage.Database
+	Publisher messaging.Publisher
+	Log       logger.Logger
+}
+
+type relay struct {
+	deps    *DependenciesNode
+	config  Config
+	metrics *metrics.Metrics
+	now     func() time.Time
+}
+
+// NewRelay returns a Relay publishing the events at least once, in the order they were stored for a given
+// aggregate. A failed event is retried with an exponential backoff, the later events of its aggregate waiting for it
+// until it's published or dead
+func NewRelay(deps *DependenciesNode, config Config) Relay {
+	return &relay{
+		deps:    deps,
+		config:  config,
+		metrics: metrics.Initialize(),
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+type topicStats struct {
+	Topic   string
+	Pending int64
+	Oldest  time.Time
+}
+
+func (r *relay) Run(ctx context.Context) {
+	if r.config.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// failures are logged, the next tick retries
+			_ = r.RelayPending(ctx)
+		}
+	}
+}
+
+func (r *relay) RelayPending(ctx context.Context) error {
+	if err := r.deps.Database.Transaction(ctx, r.relayBatch); err != nil {
+		r.deps.Log.Error(ctx, err, FailedToRelay, nil)
+		return err
+	}
+
+	r.reportBacklog(ctx)
+	r.cleanUp(ctx)
+	return nil
+}
+
+// relayBatch publishes the pending events while holding the relay lock, the records being updated in the same
+// transaction. An event published right before a failed commit is published again by the next batch
+func (r *relay) relayBatch(ctx context.Context) error {
+	var locked bool
+	if err := r.deps.Database.Raw(ctx, lockQuery, &locked); err != nil {
+		return err
+	}
+	if !locked {
+		return nil
+	}
+
+	var records []*Record
+	if err := r.deps.Database.Raw(ctx, fmt.Sprintf(pendingQuery, r.config.BatchSize), &records); err != nil {
+		return err
+	}
+
+	now := r.now()
+	blocked := make(map[string]bool)
+	for _, record := range records {
+		if blocked[record.AggregateID] {
+			continue
+		}
+
+		done, err := r.publish(ctx, record, now)
+		if err != nil {
+			return err
+		}
+		if !done {
+			blocked[record.AggregateID] = true
+		}
+	}
+	return nil
+}
+
+// publish sends the event of record and stores the outcome, telling whether the event is done with, published or
+// dead. It only fails when the outcome can't be stored
+func (r *relay) publish(ctx context.Context, record *Record, now time.Time) (bool, error) {
+	var event messaging.Event
+	err := json.Unmarshal(record.Event, &event)
+	if err == nil {
+		err = r.deps.Publisher.Publish(ctx, record.Topic, event)
+	}
+	if err == nil {
+		return true, r.deps.Database.SetColumns(ctx, record, map[string]interface{}{
+			publishedAtColumn: now,
+		})
+	}
+
+	attempts := record.Attempts + 1
+	fields := logrus.Fields{
+		recordIDKey: record.ID,
+		topicKey:    record.Topic,
+		attemptsKey: attempts,
+	}
+	r.metrics.Failures.Increment(record.Topic)
+	if r.config.MaxAttempts > 0 && attempts >= r.config.MaxAttempts {
+		r.metrics.Dead.Increment(record.Topic)
+		r.deps.Log.Error(ctx, err, GaveUpRecord, fields)
+		return true, r.deps.Database.SetColumns(ctx, record, map[string]interface{}{
+			attemptsColumn:  attempts,
+			lastErrorColumn: err.Error(),
+			deadAtColumn:    now,
+		})
+	}
+
+	r.deps.Log.Error(ctx, err, FailedToPublishRecord, fields)
+	return false, r.deps.Database.SetColumns(ctx, record, map[string]interface{}{
+		attemptsColumn:      attempts,
+		nextAttemptAtColumn: now.Add(retries.Delay(attempts)),
+		lastErrorColumn:     err.Error(),
+	})
+}
+
+// reportBacklog sets the number of pending events and the age of the oldest one for every topic
+func (r *relay) reportBacklog(ctx context.Context) {
+	var stats []topicStats
+	if err := r.deps.Database.Raw(ctx, statsQuery, &stats); err != nil {
+		r.deps.Log.Error(ctx, err, FailedToReportBacklog, nil)
+		return
+	}
+
+	now := r.now()
+	r.metrics.Backlog.Reset()
+	r.metrics.Lag.Reset()
+	for _, topic := range stats {
+		r.metrics.Backlog.Set(float64(topic.Pending), topic.Topic)
+		r.metrics.Lag.Set(now.Sub(topic.Oldest).Seconds(), topic.Topic)
+	}
+}
+
+func (r *relay) cleanUp(ctx context.Context) {
+	var removed []string
+	query := fmt.Sprintf(cleanupQuery, int64(r.config.Retention.Seconds()))
+	if err := r.deps.Database.Raw(ctx, query, &removed); err != nil {
+		r.deps.Log.Error(ctx, err, FailedToCleanUp, nil)
+	}
+}
@@ -0,0 +1,157 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"gorm.io/gorm/schema"
+
+	"app/internal/entity"
+	"app/internal/errors"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+
+	wrapErrFormat = "%w: %v"
+	fieldErr      = "%w: %s"
+)
+
+var schemaCache = &sync.Map{}
+
+// Apply applies the patch document to current and decodes the result into target, which must be a pointer
+// to the same entity type. It returns the changed columns along with their new values, ready to be
+// persisted as a column level update
+func Apply(current interface{}, contentType string, doc []byte, target interface{}) (map[string]interface{}, error) {
+	original, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := apply(original, contentType, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(patched, target); err != nil {
+		return nil, fmt.Errorf(wrapErrFormat, errors.ErrInvalidPatch, err)
+	}
+
+	return changedColumns(original, patched, target)
+}
+
+func apply(original []byte, contentType string, doc []byte) ([]byte, error) {
+	var (
+		patched []byte
+		err     error
+	)
+
+	switch contentType {
+	case MergePatchContentType:
+		patched, err = jsonpatch.MergePatch(original, doc)
+	case JSONPatchContentType:
+		var operations jsonpatch.Patch
+		operations, err = jsonpatch.DecodePatch(doc)
+		if err == nil {
+			patched, err = operations.Apply(original)
+		}
+	default:
+		return nil, errors.ErrUnsupportedPatchType
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf(wrapErrFormat, errors.ErrInvalidPatch, err)
+	}
+	return patched, nil
+}
+
+// changedColumns compares the top level fields of both documents and maps the changed ones to their db columns
+func changedColumns(original, patched []byte, target interface{}) (map[string]interface{}, error) {
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(original, &before); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patched, &after); err != nil {
+		return nil, fmt.Errorf(wrapErrFormat, errors.ErrInvalidPatch, err)
+	}
+
+	fields, err := fieldsByJSONName(target)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]interface{})
+	for name, value := range after {
+		if equal(before[name], value) {
+			continue
+		}
+		if err = appendColumn(columns, fields, name, target); err != nil {
+			return nil, err
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; ok {
+			continue
+		}
+		if err = appendColumn(columns, fields, name, target); err != nil {
+			return nil, err
+		}
+	}
+	return columns, nil
+}
+
+// equal compares two json values semantically, ignoring formatting differences
+func equal(a, b json.RawMessage) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func appendColumn(columns map[string]interface{}, fields map[string]*schema.Field, name string, target interface{}) error {
+	field, ok := fields[name]
+	if !ok {
+		return fmt.Errorf(fieldErr, errors.ErrInvalidPatch, name)
+	}
+	if isReadOnly(field.DBName) {
+		return fmt.Errorf(fieldErr, errors.ErrReadOnlyField, name)
+	}
+
+	columns[field.DBName] = fieldValue(field, target)
+	return nil
+}
+
+func fieldsByJSONName(target interface{}) (map[string]*schema.Field, error) {
+	s, err := schema.Parse(target, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]*schema.Field, len(s.Fields))
+	for _, field := range s.Fields {
+		name := jsonName(field)
+		if name == "" || field.DBName == "" {
+			continue
+		}
+		fields[name] = field
+	}
+	return fields, nil
+}
+
+func isReadOnly(column string) bool {
+	for _, readOnly := range entity.ReadOnlyColumns {
+		if column == readOnly {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package patch
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/entity"
+	"app/internal/errors"
+)
+
+func TestPatch(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Patch Suits")
+}
+
+type item struct {
+	entity.Base
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Internal    string `json:"-"`
+}
+
+var _ = Describe("Patch", func() {
+	var current *item
+
+	BeforeEach(func() {
+		current = &item{
+			Base:        entity.Base{ID: uuid.NewV4(), Version: 3},
+			Name:        "name",
+			Description: "description",
+		}
+	})
+
+	Context("Applying a JSON Merge Patch", func() {
+		When("Patch clears a field", func() {
+			It("Should return the cleared column", func() {
+				target := &item{}
+
+				columns, err := Apply(current, MergePatchContentType, []byte(`{"description":null}`), target)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(columns).To(Equal(map[string]interface{}{"description": ""}))
+				Expect(target.ID).To(Equal(current.ID))
+				Expect(target.Name).To(Equal(current.Name))
+				Expect(target.Description).To(BeEmpty())
+			})
+		})
+		When("Patch does not change anything", func() {
+			It("Should return no columns", func() {
+				columns, err := Apply(current, MergePatchContentType, []byte(`{"name":"name"}`), &item{})
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(columns).To(BeEmpty())
+			})
+		})
+		When("Patch changes a read-only field", func() {
+			It("Should return a read-only field error", func() {
+				_, err := Apply(current, MergePatchContentType, []byte(`{"version":10}`), &item{})
+
+				Expect(err).To(MatchError(errors.ErrReadOnlyField))
+			})
+		})
+		When("Patch adds an unknown field", func() {
+			It("Should return an invalid patch error", func() {
+				_, err := Apply(current, MergePatchContentType, []byte(`{"color":"blue"}`), &item{})
+
+				Expect(err).To(MatchError(errors.ErrInvalidPatch))
+			})
+		})
+	})
+
+	Context("Applying a JSON Patch", func() {
+		When("Operations are valid", func() {
+			It("Should return the changed columns", func() {
+				target := &item{}
+				doc := []byte(`[
+					{"op":"test","path":"/name","value":"name"},
+					{"op":"replace","path":"/name","value":"new name"},
+					{"op":"replace","path":"/description","value":""}
+				]`)
+
+				columns, err := Apply(current, JSONPatchContentType, doc, target)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(columns).To(Equal(map[string]interface{}{"name": "new name", "description": ""}))
+			})
+		})
+		When("A test operation fails", func() {
+			It("Should return an invalid patch error", func() {
+				doc := []byte(`[{"op":"test","path":"/name","value":"other"}]`)
+
+				_, err := Apply(current, JSONPatchContentType, doc, &item{})
+
+				Expect(err).To(MatchError(errors.ErrInvalidPatch))
+			})
+		})
+		When("Operation removes the ID", func() {
+			It("Should return a read-only field error", func() {
+				doc := []byte(`[{"op":"remove","path":"/id"}]`)
+
+				_, err := Apply(current, JSONPatchContentType, doc, &item{})
+
+				Expect(err).To(MatchError(errors.ErrReadOnlyField))
+			})
+		})
+	})
+
+	Context("Applying an unsupported patch", func() {
+		It("Should return an unsupported patch type error", func() {
+			_, err := Apply(current, "application/json", []byte(`{}`), &item{})
+
+			Expect(err).To(Equal(errors.ErrUnsupportedPatchType))
+		})
+	})
+})
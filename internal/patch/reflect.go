@@ -0,0 +1,32 @@
+package patch
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+const (
+	jsonTag          = "json"
+	jsonTagSeparator = ","
+	jsonIgnoreTag    = "-"
+)
+
+func jsonName(field *schema.Field) string {
+	tag := field.StructField.Tag.Get(jsonTag)
+	name := strings.Split(tag, jsonTagSeparator)[0]
+	if name == jsonIgnoreTag {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func fieldValue(field *schema.Field, target interface{}) interface{} {
+	value, _ := field.ValueOf(context.Background(), reflect.Indirect(reflect.ValueOf(target)))
+	return value
+}
@@ -0,0 +1,238 @@
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	goExtension       = ".go"
+	templateExtension = ".tmpl"
+	servicePrefix     = "service"
+
+	filePermissions      = 0o644
+	directoryPermissions = 0o755
+
+	failedToRender = "failed to render %s: %w"
+	failedToFormat = "failed to format %s: %w"
+	failedToWrite  = "failed to write %s: %w"
+)
+
+var ErrFileExists = errors.New("refusing to overwrite existing files")
+
+//go:embed templates
+var templates embed.FS
+
+// layout maps the templates to the path of the files they render, relative to the root of the module
+var layout = []struct {
+	template string
+	path     string
+}{
+	{"main.go", "cmd/{{.Service}}/main.go"},
+	{"module.go", "internal/{{.Service}}/{{.Service}}.go"},
+	{"domain.go", "internal/{{.Service}}/domain/{{.File}}.go"},
+	{"domain_test.go", "internal/{{.Service}}/domain/{{.File}}_test.go"},
+	{"repository.go", "internal/{{.Service}}/repository/repository.go"},
+	{"service.go", "internal/{{.Service}}/service/service.go"},
+	{"handler.go", "internal/{{.Service}}/handler/handler.go"},
+	{"handler_config.go", "internal/{{.Service}}/handler/config.go"},
+	{"handler_constants.go", "internal/{{.Service}}/handler/constants.go"},
+	{"handler_docs.go", "internal/{{.Service}}/handler/docs.go"},
+	{"handler_test.go", "internal/{{.Service}}/handler/handler_test.go"},
+	{"assertion_domain.go", "internal/test/assertion/{{.Service}}/domain.go"},
+	{"assertion_service.go", "internal/test/assertion/{{.Service}}/service.go"},
+	{"docker.yaml", "build/docker/{{.Docker}}.yaml"},
+	{"service.env", "build/services/{{.Service}}/service.env"},
+}
+
+// File is a file generated for the service, Path being relative to the root of the module
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// Scaffolder generates the packages, tests, main and docker files of a new service backed by the generic crud
+// layers, whose mocks and metrics the service shares
+type Scaffolder interface {
+	// Generate renders the files of the service described by spec
+	Generate(spec Spec) ([]File, error)
+	// Write writes the files under the root of the module, failing with ErrFileExists without writing any of
+	// them when one already exists. It only checks them in dry-run mode
+	Write(files []File) error
+}
+
+type Config struct {
+	// Root is the root of the module, holding go.mod
+	Root   string
+	DryRun bool
+}
+
+type scaffolder struct {
+	config    Config
+	templates *template.Template
+}
+
+func New(config Config) Scaffolder {
+	return &scaffolder{
+		config:    config,
+		templates: template.Must(template.ParseFS(templates, "templates/*"+templateExtension)),
+	}
+}
+
+func (s *scaffolder) Generate(spec Spec) ([]File, error) {
+	if spec.Port == 0 {
+		spec.Port = DefaultPort
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	data := newData(spec)
+
+	files := make([]File, 0, len(layout))
+	for _, entry := range layout {
+		rendered, err := render(template.Must(template.New(entry.template).Parse(entry.path)), data)
+		if err != nil {
+			return nil, fmt.Errorf(failedToRender, entry.path, err)
+		}
+		path := string(rendered)
+		content, err := render(s.templates.Lookup(entry.template+templateExtension), data)
+		if err != nil {
+			return nil, fmt.Errorf(failedToRender, path, err)
+		}
+		if strings.HasSuffix(path, goExtension) {
+			content, err = format.Source(content)
+			if err != nil {
+				return nil, fmt.Errorf(failedToFormat, path, err)
+			}
+		}
+		files = append(files, File{Path: path, Content: content})
+	}
+	return files, nil
+}
+
+func (s *scaffolder) Write(files []File) error {
+	var existing []string
+	for _, file := range files {
+		_, err := os.Stat(filepath.Join(s.config.Root, file.Path))
+		if err == nil {
+			existing = append(existing, file.Path)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("%w: %s", ErrFileExists, strings.Join(existing, ", "))
+	}
+	if s.config.DryRun {
+		return nil
+	}
+
+	for _, file := range files {
+		path := filepath.Join(s.config.Root, file.Path)
+		if err := os.MkdirAll(filepath.Dir(path), directoryPermissions); err != nil {
+			return fmt.Errorf(failedToWrite, file.Path, err)
+		}
+		if err := os.WriteFile(path, file.Content, filePermissions); err != nil {
+			return fmt.Errorf(failedToWrite, file.Path, err)
+		}
+	}
+	return nil
+}
+
+func render(t *template.Template, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// data is what the templates are rendered with, the names of the service being derived from the Spec the way
+// serviceA and serviceB are named
+type data struct {
+	// Service is the name of the packages, like serviceC
+	Service string
+	// Title is the service in words, like Service C
+	Title string
+	// Container is the prefix of the docker containers and the consumer group, like service-c
+	Container string
+	// Docker is the name of the docker compose file, like servicec
+	Docker string
+	// Database is the postgres database, like ServiceC
+	Database string
+	// Metrics identifies the service in the metrics, like c
+	Metrics string
+	// Entity is the domain type, like ItemC
+	Entity string
+	// Var is the entity as a variable or a swagger tag, like itemC
+	Var string
+	// File is the name of the file of the domain type, like itemC
+	File string
+	// Resource is the path of the items under /api/v1, like item-cs
+	Resource string
+	Port     int
+	Fields   []fieldData
+	UsesTime bool
+	UsesUUID bool
+}
+
+type fieldData struct {
+	Name   string
+	GoName string
+	Type   string
+	Rules  string
+	Sample string
+}
+
+func newData(spec Spec) data {
+	metrics := spec.Service
+	if trimmed := strings.TrimPrefix(spec.Service, servicePrefix); trimmed != "" && trimmed != spec.Service {
+		metrics = trimmed
+	}
+	resource := spec.Resource
+	if resource == "" {
+		resource = join(spec.Entity, "-") + "s"
+	}
+
+	titleWords := words(spec.Service)
+	for i, word := range titleWords {
+		titleWords[i] = upperFirst(word)
+	}
+
+	d := data{
+		Service:   spec.Service,
+		Title:     strings.Join(titleWords, " "),
+		Container: join(spec.Service, "-"),
+		Docker:    strings.ToLower(spec.Service),
+		Database:  upperFirst(spec.Service),
+		Metrics:   join(metrics, "_"),
+		Entity:    spec.Entity,
+		Var:       lowerFirst(spec.Entity),
+		File:      lowerFirst(spec.Entity),
+		Resource:  resource,
+		Port:      spec.Port,
+	}
+	for _, field := range spec.Fields {
+		sample := samples[field.Type]
+		if strings.Contains(sample, "%s") {
+			sample = fmt.Sprintf(sample, join(field.Name, " "))
+		}
+		d.Fields = append(d.Fields, fieldData{
+			Name:   field.Name,
+			GoName: goName(field.Name),
+			Type:   types[field.Type],
+			Rules:  field.Rules,
+			Sample: sample,
+		})
+		d.UsesTime = d.UsesTime || field.Type == "time"
+		d.UsesUUID = d.UsesUUID || field.Type == "uuid"
+	}
+	return d
+}
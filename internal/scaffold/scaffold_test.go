@@ -0,0 +1,159 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestScaffold(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scaffold Suits")
+}
+
+var sampleSpec = Spec{
+	Service: "serviceC",
+	Entity:  "ItemC",
+	Fields: []Field{
+		{Name: "name", Type: "string", Rules: "required,max=255"},
+		{Name: "ownerId", Type: "uuid"},
+		{Name: "dueDate", Type: "time"},
+	},
+}
+
+func contentOf(files []File, path string) string {
+	for _, file := range files {
+		if file.Path == path {
+			return string(file.Content)
+		}
+	}
+	Fail("no file generated at " + path)
+	return ""
+}
+
+var _ = Describe("Spec", func() {
+	Context("Parsing fields", func() {
+		When("The field has rules", func() {
+			It("Should keep the commas and colons of the rules", func() {
+				field, err := ParseField("name:string:required,max=255")
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(field).To(Equal(Field{Name: "name", Type: "string", Rules: "required,max=255"}))
+			})
+		})
+		When("The field has no type", func() {
+			It("Should fail", func() {
+				_, err := ParseField("name")
+
+				Expect(err).To(MatchError(ErrInvalidSpec))
+			})
+		})
+	})
+
+	Context("Validating", func() {
+		DescribeTable("Rejecting invalid specs",
+			func(change func(spec *Spec)) {
+				spec := sampleSpec
+				spec.Port = DefaultPort
+				spec.Fields = append([]Field(nil), sampleSpec.Fields...)
+				change(&spec)
+
+				Expect(spec.Validate()).To(MatchError(ErrInvalidSpec))
+			},
+			Entry("service not in lower camel case", func(spec *Spec) { spec.Service = "Service-C" }),
+			Entry("entity not in upper camel case", func(spec *Spec) { spec.Entity = "itemC" }),
+			Entry("resource not in kebab case", func(spec *Spec) { spec.Resource = "C_Items" }),
+			Entry("port out of range", func(spec *Spec) { spec.Port = 70000 }),
+			Entry("no fields", func(spec *Spec) { spec.Fields = nil }),
+			Entry("field held by entity.Base", func(spec *Spec) { spec.Fields[0].Name = "createdAt" }),
+			Entry("duplicate field", func(spec *Spec) { spec.Fields[1].Name = "name" }),
+			Entry("unknown type", func(spec *Spec) { spec.Fields[0].Type = "decimal" }),
+			Entry("rules closing the tag", func(spec *Spec) { spec.Fields[0].Rules = "required\" json:\"x" }),
+		)
+	})
+})
+
+var _ = Describe("Scaffolder", func() {
+	var root string
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+	})
+
+	Context("Generating", func() {
+		It("Should name the files and the routes after the service and the entity", func() {
+			files, err := New(Config{Root: root}).Generate(sampleSpec)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(files).To(HaveLen(len(layout)))
+			Expect(contentOf(files, "internal/serviceC/handler/constants.go")).To(ContainSubstring(`"/item-cs"`))
+			Expect(contentOf(files, "internal/serviceC/service/service.go")).To(ContainSubstring(`metricsName = "c"`))
+			Expect(contentOf(files, "build/services/serviceC/service.env")).To(ContainSubstring("SERVER_PORT=:8080"))
+			Expect(contentOf(files, "build/docker/servicec.yaml")).To(ContainSubstring("container_name: service-c-db"))
+		})
+		It("Should declare the fields on the entity", func() {
+			files, err := New(Config{Root: root}).Generate(sampleSpec)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			domain := contentOf(files, "internal/serviceC/domain/itemC.go")
+			Expect(domain).To(ContainSubstring("Name    string    `json:\"name\" validate:\"required,max=255\"`"))
+			Expect(domain).To(ContainSubstring("OwnerID uuid.UUID `json:\"ownerId\" gorm:\"type:uuid\"`"))
+			Expect(domain).To(ContainSubstring(`"time"`))
+		})
+		It("Should use the given resource", func() {
+			spec := sampleSpec
+			spec.Resource = "c-items"
+
+			files, err := New(Config{Root: root}).Generate(spec)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contentOf(files, "internal/serviceC/handler/constants.go")).To(ContainSubstring(`"/c-items"`))
+		})
+	})
+
+	Context("Writing", func() {
+		var files []File
+
+		BeforeEach(func() {
+			var err error
+			files, err = New(Config{Root: root}).Generate(sampleSpec)
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		When("Writing for real", func() {
+			It("Should create the files", func() {
+				err := New(Config{Root: root}).Write(files)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				for _, file := range files {
+					Expect(filepath.Join(root, file.Path)).To(BeARegularFile())
+				}
+			})
+		})
+		When("In dry-run mode", func() {
+			It("Should not write anything", func() {
+				err := New(Config{Root: root, DryRun: true}).Write(files)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(os.ReadDir(root)).To(BeEmpty())
+			})
+		})
+		When("A file already exists", func() {
+			It("Should fail without writing any file", func() {
+				existing := filepath.Join(root, files[1].Path)
+				Expect(os.MkdirAll(filepath.Dir(existing), 0o755)).To(Succeed())
+				Expect(os.WriteFile(existing, []byte("package domain\n"), 0o644)).To(Succeed())
+
+				err := New(Config{Root: root}).Write(files)
+
+				Expect(err).To(MatchError(ErrFileExists))
+				Expect(err.Error()).To(ContainSubstring(files[1].Path))
+				Expect(filepath.Join(root, files[0].Path)).ToNot(BeAnExistingFile())
+				Expect(os.ReadFile(existing)).To(Equal([]byte("package domain\n")))
+			})
+		})
+	})
+})
@@ -0,0 +1,198 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	DefaultPort = 8080
+
+	fieldSeparator = ":"
+
+	invalidServiceErr  = "service name %q must be a lower camel case identifier, like serviceC"
+	invalidEntityErr   = "entity name %q must be an upper camel case identifier, like ItemC"
+	invalidResourceErr = "resource %q must be kebab case, like c-items"
+	invalidFieldErr    = "field %q must be written name:type or name:type:rules"
+	invalidNameErr     = "field name %q must be a lower camel case identifier, like dueDate"
+	reservedFieldErr   = "field %q is already held by entity.Base"
+	duplicateFieldErr  = "field %q is declared more than once"
+	unknownTypeErr     = "field %q has the unknown type %q, expected one of %s"
+	invalidRulesErr    = "field %q has rules with quotes or backquotes: %s"
+	noFieldsErr        = "the entity needs at least one field"
+	invalidPortErr     = "port %d is out of range"
+)
+
+var (
+	ErrInvalidSpec = errors.New("invalid service specification")
+
+	lowerCamel = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	upperCamel = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	kebab      = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+)
+
+// types maps the field types accepted on the command line to their Go type
+var types = map[string]string{
+	"string":  "string",
+	"int":     "int",
+	"int64":   "int64",
+	"float64": "float64",
+	"bool":    "bool",
+	"time":    "time.Time",
+	"uuid":    "uuid.UUID",
+}
+
+// samples are the values the generated test fixtures give the fields of each type
+var samples = map[string]string{
+	"string":  `"sample %s"`,
+	"int":     "1",
+	"int64":   "1",
+	"float64": "1.5",
+	"bool":    "true",
+	"time":    "time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)",
+	"uuid":    `uuid.FromStringOrNil("0b6b8f3e-5b5c-4a8e-9d1a-6f3c2e1d4b7a")`,
+}
+
+// reserved are the JSON names of the fields of entity.Base
+var reserved = []string{"id", "createdAt", "updatedAt", "deletedAt", "createdBy", "updatedBy", "version"}
+
+// Spec describes the service to generate
+type Spec struct {
+	// Service names the packages and the directories of the service, like serviceC
+	Service string
+	// Entity names the domain type the service stores, like ItemC
+	Entity string
+	// Resource is the path of the items under /api/v1, the kebab case plural of Entity when empty
+	Resource string
+	Port     int
+	Fields   []Field
+}
+
+// Field is a field of the entity, declared on the command line as name:type or name:type:rules, rules being the
+// validate tag of the field, like title:string:required,max=255
+type Field struct {
+	// Name is the JSON name of the field
+	Name  string
+	Type  string
+	Rules string
+}
+
+// ParseField parses a field declared as name:type or name:type:rules
+func ParseField(s string) (Field, error) {
+	parts := strings.SplitN(s, fieldSeparator, 3)
+	if len(parts) < 2 {
+		return Field{}, fmt.Errorf("%w: "+invalidFieldErr, ErrInvalidSpec, s)
+	}
+	field := Field{
+		Name: parts[0],
+		Type: parts[1],
+	}
+	if len(parts) == 3 {
+		field.Rules = parts[2]
+	}
+	return field, nil
+}
+
+// TypeNames lists the field types, sorted
+func TypeNames() []string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks the names of the service, of the entity and of its fields, and the field types
+func (s Spec) Validate() error {
+	if !lowerCamel.MatchString(s.Service) {
+		return fmt.Errorf("%w: "+invalidServiceErr, ErrInvalidSpec, s.Service)
+	}
+	if !upperCamel.MatchString(s.Entity) {
+		return fmt.Errorf("%w: "+invalidEntityErr, ErrInvalidSpec, s.Entity)
+	}
+	if s.Resource != "" && !kebab.MatchString(s.Resource) {
+		return fmt.Errorf("%w: "+invalidResourceErr, ErrInvalidSpec, s.Resource)
+	}
+	if s.Port <= 0 || s.Port > 65535 {
+		return fmt.Errorf("%w: "+invalidPortErr, ErrInvalidSpec, s.Port)
+	}
+	if len(s.Fields) == 0 {
+		return fmt.Errorf("%w: "+noFieldsErr, ErrInvalidSpec)
+	}
+
+	seen := make(map[string]bool, len(s.Fields))
+	for _, field := range s.Fields {
+		if !lowerCamel.MatchString(field.Name) {
+			return fmt.Errorf("%w: "+invalidNameErr, ErrInvalidSpec, field.Name)
+		}
+		for _, name := range reserved {
+			if strings.EqualFold(field.Name, name) {
+				return fmt.Errorf("%w: "+reservedFieldErr, ErrInvalidSpec, field.Name)
+			}
+		}
+		if seen[goName(field.Name)] {
+			return fmt.Errorf("%w: "+duplicateFieldErr, ErrInvalidSpec, field.Name)
+		}
+		seen[goName(field.Name)] = true
+		if _, ok := types[field.Type]; !ok {
+			return fmt.Errorf("%w: "+unknownTypeErr, ErrInvalidSpec, field.Name, field.Type,
+				strings.Join(TypeNames(), ", "))
+		}
+		if strings.ContainsAny(field.Rules, "\"`") {
+			return fmt.Errorf("%w: "+invalidRulesErr, ErrInvalidSpec, field.Name, field.Rules)
+		}
+	}
+	return nil
+}
+
+// goName exports a JSON field name, spelling a trailing Id as ID like ItemBID
+func goName(name string) string {
+	if strings.HasSuffix(name, "Id") {
+		name = strings.TrimSuffix(name, "Id") + "ID"
+	}
+	return upperFirst(name)
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return string(unicode.ToUpper(rune(s[0]))) + s[1:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return string(unicode.ToLower(rune(s[0]))) + s[1:]
+}
+
+// words splits a camel case identifier, keeping runs of upper case letters and of digits together
+func words(s string) []string {
+	var result []string
+	start := 0
+	for i := 1; i < len(s); i++ {
+		prev, cur := rune(s[i-1]), rune(s[i])
+		boundary := unicode.IsUpper(cur) && !unicode.IsUpper(prev) ||
+			unicode.IsDigit(cur) != unicode.IsDigit(prev) && !unicode.IsUpper(prev) ||
+			unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(s) && unicode.IsLower(rune(s[i+1]))
+		if boundary {
+			result = append(result, s[start:i])
+			start = i
+		}
+	}
+	return append(result, s[start:])
+}
+
+func join(s string, separator string) string {
+	parts := words(s)
+	for i, part := range parts {
+		parts[i] = strings.ToLower(part)
+	}
+	return strings.Join(parts, separator)
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"app/internal/metric"
+)
+
+const (
+	NamespaceProperty = "scheduler"
+
+	RunsNameProperty        = "run_count"
+	RunsDescriptionProperty = "Task runs by outcome"
+
+	DurationNameProperty        = "run_duration_seconds"
+	DurationDescriptionProperty = "Duration of the task runs in seconds"
+
+	LastSuccessNameProperty        = "last_success_timestamp_seconds"
+	LastSuccessDescriptionProperty = "Unix time of the last successful run of a task"
+
+	LeaderNameProperty        = "leader"
+	LeaderDescriptionProperty = "Whether this replica is the leader running the tasks"
+
+	taskPropertyKey    = "task"
+	outcomePropertyKey = "outcome"
+)
+
+type Metrics struct {
+	Runs        metric.CounterVec
+	Duration    metric.HistogramVec
+	LastSuccess metric.GaugeVec
+	Leader      metric.GaugeVec
+}
+
+func Initialize() *Metrics {
+	return &Metrics{
+		Runs: metric.NewCounter(metricProperties(RunsNameProperty, RunsDescriptionProperty,
+			metric.CounterVecType, taskPropertyKey, outcomePropertyKey)),
+		Duration: metric.NewHistogram(metricProperties(DurationNameProperty, DurationDescriptionProperty,
+			metric.HistogramVecType, taskPropertyKey)),
+		LastSuccess: metric.NewGauge(metricProperties(LastSuccessNameProperty, LastSuccessDescriptionProperty,
+			metric.GaugeVecType, taskPropertyKey)),
+		Leader: metric.NewGauge(metricProperties(LeaderNameProperty, LeaderDescriptionProperty,
+			metric.GaugeVecType)),
+	}
+}
+
+func metricProperties(name, description, metricType string, properties ...string) metric.Properties {
+	return metric.Properties{
+		Name:        name,
+		Namespace:   NamespaceProperty,
+		Description: description,
+		Type:        metricType,
+		Properties:  properties,
+	}
+}
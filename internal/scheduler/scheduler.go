@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+
+	"app/internal/lock"
+	"app/internal/logger"
+	"app/internal/scheduler/metrics"
+)
+
+const (
+	DefaultElectionInterval = 10 * time.Second
+	DefaultLeaderKey        = "scheduler"
+	// NoJitter is the Jitter of a task running right when due, whatever the Jitter of the scheduler
+	NoJitter time.Duration = -1
+
+	FailedToElect = "failed to elect the scheduler leader"
+	FailedToRun   = "failed to run scheduled task"
+	Elected       = "elected scheduler leader"
+
+	invalidSpecErr = "invalid schedule %q of task %s: %w"
+	panicErr       = "task panicked: %v"
+
+	taskKey = "task"
+
+	succeededOutcome = "succeeded"
+	failedOutcome    = "failed"
+	skippedOutcome   = "skipped"
+)
+
+// Func is the work of a Task, ctx being cancelled when the replica stops leading
+type Func func(ctx context.Context) error
+
+type Task struct {
+	Name string
+	// Spec is a five fields cron expression or a descriptor like @daily or @every 1h
+	Spec string
+	// Jitter delays every run by a random duration up to Jitter, spreading the load of tasks sharing a schedule.
+	// Zero takes the Jitter of the scheduler, NoJitter turning it off
+	Jitter time.Duration
+	Run    Func
+
+	schedule cron.Schedule
+}
+
+// Scheduler runs the tasks of the replica elected leader, so a single replica runs each task
+type Scheduler interface {
+	// Schedule registers a task, failing when its Spec is invalid. It must be called before Run
+	Schedule(task Task) error
+	// Run elects a leader among the replicas until ctx is done, running the tasks while leading
+	Run(ctx context.Context)
+}
+
+type Config struct {
+	// LeaderKey names the lock the replicas of a service elect their leader with
+	LeaderKey string
+	// ElectionInterval is how often a replica that isn't the leader tries to become it
+	ElectionInterval time.Duration
+	// Jitter is the Jitter of the tasks not setting theirs
+	Jitter time.Duration
+}
+
+type DependenciesNode struct {
+	Locker lock.Locker
+	Log    logger.Logger
+}
+
+type scheduler struct {
+	deps    *DependenciesNode
+	config  Config
+	metrics *metrics.Metrics
+	tasks   []Task
+	now     func() time.Time
+	jitter  func(max time.Duration) time.Duration
+}
+
+// New returns a Scheduler where a run never overlaps the previous run of its task, the runs due meanwhile
+// being skipped
+func New(deps *DependenciesNode, config Config) Scheduler {
+	if config.LeaderKey == "" {
+		config.LeaderKey = DefaultLeaderKey
+	}
+	if config.ElectionInterval <= 0 {
+		config.ElectionInterval = DefaultElectionInterval
+	}
+
+	return &scheduler{
+		deps:    deps,
+		config:  config,
+		metrics: metrics.Initialize(),
+		now:     time.Now,
+		jitter:  jitter,
+	}
+}
+
+func (s *scheduler) Schedule(task Task) error {
+	schedule, err := cron.ParseStandard(task.Spec)
+	if err != nil {
+		return fmt.Errorf(invalidSpecErr, task.Spec, task.Name, err)
+	}
+
+	if task.Jitter == 0 {
+		task.Jitter = s.config.Jitter
+	}
+	task.schedule = schedule
+	s.tasks = append(s.tasks, task)
+	return nil
+}
+
+func (s *scheduler) Run(ctx context.Context) {
+	if len(s.tasks) == 0 {
+		return
+	}
+
+	for ctx.Err() == nil {
+		if _, err := s.deps.Locker.Hold(ctx, s.config.LeaderKey, s.lead); err != nil && ctx.Err() == nil {
+			s.deps.Log.Error(ctx, err, FailedToElect, nil)
+		}
+		wait(ctx, s.config.ElectionInterval)
+	}
+}
+
+// lead runs the tasks until ctx is done, on shutdown or when the leadership is lost
+func (s *scheduler) lead(ctx context.Context) {
+	s.deps.Log.Info(ctx, Elected, nil)
+	s.metrics.Leader.Set(1)
+	defer s.metrics.Leader.Set(0)
+
+	var wg sync.WaitGroup
+	for _, task := range s.tasks {
+		wg.Add(1)
+		go func(task Task) {
+			defer wg.Done()
+			s.loop(ctx, task)
+		}(task)
+	}
+	wg.Wait()
+}
+
+// loop runs task at every time due by its schedule until ctx is done
+func (s *scheduler) loop(ctx context.Context, task Task) {
+	next := task.schedule.Next(s.now())
+	for {
+		if !wait(ctx, next.Sub(s.now())+s.jitter(task.Jitter)) {
+			return
+		}
+		s.run(ctx, task)
+
+		following := task.schedule.Next(next)
+		for now := s.now(); !following.After(now); following = task.schedule.Next(following) {
+			s.metrics.Runs.Increment(task.Name, skippedOutcome)
+		}
+		next = following
+	}
+}
+
+func (s *scheduler) run(ctx context.Context, task Task) {
+	start := s.now()
+	err := safeRun(ctx, task.Run)
+	s.metrics.Duration.Observe(s.now().Sub(start).Seconds(), task.Name)
+
+	if err != nil {
+		s.deps.Log.Error(ctx, err, FailedToRun, logrus.Fields{taskKey: task.Name})
+		s.metrics.Runs.Increment(task.Name, failedOutcome)
+		return
+	}
+	s.metrics.Runs.Increment(task.Name, succeededOutcome)
+	s.metrics.LastSuccess.Set(float64(s.now().Unix()), task.Name)
+}
+
+// safeRun turns a panic of fn into an error, so a failing task doesn't stop the others
+func safeRun(ctx context.Context, fn Func) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf(panicErr, r)
+		}
+	}()
+	return fn(ctx)
+}
+
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// wait blocks for d, telling whether it elapsed before ctx was done
+func wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
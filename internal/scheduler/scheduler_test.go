@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	errorsAssertion "app/internal/test/assertion/errors"
+	pkgMock "app/internal/test/mocks/pkg"
+)
+
+func TestScheduler(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scheduler Suits")
+}
+
+// interval is a schedule shorter than the second cron expressions are limited to
+type interval time.Duration
+
+func (i interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// locker is a lock.Locker granting the lock when acquired is set, holding it for hold when set
+type locker struct {
+	acquired bool
+	hold     time.Duration
+	holds    int32
+}
+
+func (l *locker) Hold(ctx context.Context, _ string, fn func(ctx context.Context)) (bool, error) {
+	if !l.acquired {
+		return false, nil
+	}
+	atomic.AddInt32(&l.holds, 1)
+	if l.hold > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.hold)
+		defer cancel()
+	}
+	fn(ctx)
+	return true, nil
+}
+
+var _ = Describe("Scheduler", func() {
+	var (
+		ctx     context.Context
+		cancel  context.CancelFunc
+		logMock *pkgMock.Logger
+		lock    *locker
+		s       *scheduler
+		done    chan struct{}
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		logMock = pkgMock.NewLogger(GinkgoT())
+		logMock.On("Info", mock.Anything, mock.Anything, mock.Anything).Maybe()
+		logMock.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		lock = &locker{acquired: true}
+		s = New(&DependenciesNode{
+			Locker: lock,
+			Log:    logMock,
+		}, Config{ElectionInterval: 10 * time.Millisecond}).(*scheduler)
+		done = make(chan struct{})
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+
+	schedule := func(fn Func) {
+		s.tasks = append(s.tasks, Task{Name: "task", Run: fn, schedule: interval(20 * time.Millisecond)})
+	}
+
+	run := func() {
+		go func(ctx context.Context, done chan<- struct{}) {
+			defer close(done)
+			s.Run(ctx)
+		}(ctx, done)
+	}
+
+	Context("Scheduling a task", func() {
+		When("the schedule is a cron expression or a descriptor", func() {
+			It("Should register it", func() {
+				Expect(s.Schedule(Task{Name: "cron", Spec: "*/5 * * * *"})).To(Succeed())
+				Expect(s.Schedule(Task{Name: "descriptor", Spec: "@every 1h"})).To(Succeed())
+				Expect(s.tasks).To(HaveLen(2))
+				close(done)
+			})
+		})
+		When("the task sets no jitter", func() {
+			It("Should use the jitter of the scheduler", func() {
+				s.config.Jitter = time.Minute
+				Expect(s.Schedule(Task{Name: "default", Spec: "@hourly"})).To(Succeed())
+				Expect(s.Schedule(Task{Name: "own", Spec: "@hourly", Jitter: time.Second})).To(Succeed())
+				Expect(s.tasks[0].Jitter).To(Equal(time.Minute))
+				Expect(s.tasks[1].Jitter).To(Equal(time.Second))
+				close(done)
+			})
+		})
+		When("the task turns the jitter off", func() {
+			It("Should run it right when due", func() {
+				s.config.Jitter = time.Minute
+				Expect(s.Schedule(Task{Name: "punctual", Spec: "@hourly", Jitter: NoJitter})).To(Succeed())
+				Expect(s.tasks[0].Jitter).To(Equal(NoJitter))
+				Expect(s.jitter(s.tasks[0].Jitter)).To(BeZero())
+				close(done)
+			})
+		})
+		When("the schedule is invalid", func() {
+			It("Should return an error", func() {
+				Expect(s.Schedule(Task{Name: "invalid", Spec: "every minute"})).ToNot(Succeed())
+				Expect(s.tasks).To(BeEmpty())
+				close(done)
+			})
+		})
+	})
+
+	Context("Running tasks", func() {
+		When("the replica is the leader", func() {
+			It("Should run the task at every scheduled time", func() {
+				var runs int32
+				schedule(func(context.Context) error {
+					atomic.AddInt32(&runs, 1)
+					return nil
+				})
+
+				run()
+
+				Eventually(func() int32 { return atomic.LoadInt32(&runs) }).Should(BeNumerically(">=", 3))
+			})
+		})
+		When("another replica is the leader", func() {
+			It("Should not run the task", func() {
+				lock.acquired = false
+				var runs int32
+				schedule(func(context.Context) error {
+					atomic.AddInt32(&runs, 1)
+					return nil
+				})
+
+				run()
+
+				Consistently(func() int32 { return atomic.LoadInt32(&runs) }, 100*time.Millisecond).Should(BeZero())
+			})
+		})
+		When("a run lasts longer than the schedule interval", func() {
+			It("Should skip the runs due meanwhile instead of overlapping", func() {
+				var running, overlaps, runs int32
+				schedule(func(context.Context) error {
+					if atomic.AddInt32(&running, 1) > 1 {
+						atomic.AddInt32(&overlaps, 1)
+					}
+					time.Sleep(50 * time.Millisecond)
+					atomic.AddInt32(&running, -1)
+					atomic.AddInt32(&runs, 1)
+					return nil
+				})
+
+				run()
+
+				Eventually(func() int32 { return atomic.LoadInt32(&runs) }).Should(BeNumerically(">=", 2))
+				Expect(atomic.LoadInt32(&overlaps)).To(BeZero())
+			})
+		})
+		When("a task fails or panics", func() {
+			It("Should keep running it", func() {
+				var runs int32
+				schedule(func(context.Context) error {
+					if atomic.AddInt32(&runs, 1)%2 == 0 {
+						panic("task")
+					}
+					return errorsAssertion.ErrGeneric
+				})
+
+				run()
+
+				Eventually(func() int32 { return atomic.LoadInt32(&runs) }).Should(BeNumerically(">=", 3))
+			})
+		})
+		When("the leadership is lost", func() {
+			It("Should stop the running task and elect a leader again", func() {
+				lock.hold = 50 * time.Millisecond
+				stopped := make(chan struct{}, 10)
+				schedule(func(ctx context.Context) error {
+					<-ctx.Done()
+					stopped <- struct{}{}
+					return ctx.Err()
+				})
+
+				run()
+
+				Eventually(stopped).Should(Receive())
+				Eventually(func() int32 { return atomic.LoadInt32(&lock.holds) }).Should(BeNumerically(">=", 2))
+			})
+		})
+	})
+})
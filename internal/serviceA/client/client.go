@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/httpclient"
+	"app/internal/serviceA/domain"
+)
+
+const (
+	itemsPath         = "/api/v1/a-items"
+	itemPathFormat    = itemsPath + "/%s"
+	restorePathFormat = itemPathFormat + "/restore"
+)
+
+// Client is a typed client of the serviceA REST API, used by other services to read and write ItemAs
+type Client interface {
+	GetAll(ctx context.Context) ([]*domain.ItemA, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ItemA, error)
+	Exists(ctx context.Context, id uuid.UUID) (bool, error)
+	Create(ctx context.Context, item *domain.ItemA) (*domain.ItemA, error)
+	Update(ctx context.Context, id uuid.UUID, item *domain.ItemA) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
+}
+
+type client struct {
+	http *httpclient.Client
+}
+
+func New(http *httpclient.Client) Client {
+	return &client{
+		http: http,
+	}
+}
+
+func (c *client) GetAll(ctx context.Context) ([]*domain.ItemA, error) {
+	var items []*domain.ItemA
+	if err := c.http.Do(ctx, http.MethodGet, itemsPath, nil, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (c *client) GetByID(ctx context.Context, id uuid.UUID) (*domain.ItemA, error) {
+	item := &domain.ItemA{}
+	if err := c.http.Do(ctx, http.MethodGet, fmt.Sprintf(itemPathFormat, id), nil, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Exists tells whether an item with the given ID exists, soft deleted items don't
+func (c *client) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	_, err := c.GetByID(ctx, id)
+	if httpclient.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (c *client) Create(ctx context.Context, item *domain.ItemA) (*domain.ItemA, error) {
+	created := &domain.ItemA{}
+	if err := c.http.Do(ctx, http.MethodPost, itemsPath, item, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (c *client) Update(ctx context.Context, id uuid.UUID, item *domain.ItemA) error {
+	return c.http.Do(ctx, http.MethodPut, fmt.Sprintf(itemPathFormat, id), item, nil)
+}
+
+func (c *client) Delete(ctx context.Context, id uuid.UUID) error {
+	return c.http.Do(ctx, http.MethodDelete, fmt.Sprintf(itemPathFormat, id), nil, nil)
+}
+
+func (c *client) Restore(ctx context.Context, id uuid.UUID) error {
+	return c.http.Do(ctx, http.MethodPost, fmt.Sprintf(restorePathFormat, id), nil, nil)
+}
@@ -5,14 +5,23 @@ import (
 	"fmt"
 
 	uuid "github.com/satori/go.uuid"
+
+	"app/internal/entity"
 )
 
 const (
 	FailedToUnmarshal = "failed to unmarshal data to ItemA: %v"
+
+	ItemBIDField  = "itemBId"
+	ItemBIDColumn = "item_b_id"
 )
 
 type ItemA struct {
-	ID uuid.UUID `json:"id"`
+	entity.Base
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=1024"`
+	// ItemBID optionally references an ItemB of serviceB, checked through its client when set
+	ItemBID *uuid.UUID `json:"itemBId" gorm:"type:uuid"`
 }
 
 func NewFromBytes(b []byte) (*ItemA, error) {
@@ -1,5 +1,6 @@
 package handler
 
 const (
-	ParamID = "id"
+	itemsPath      = "/a-items"
+	exportFilename = "a-items"
 )
@@ -0,0 +1,216 @@
+package handler
+
+// The operations below are served by the generic handler the Handler embeds, they are declared here to document
+// them with the ItemA type for swag
+
+// Get godoc
+// @Summary     Show all items
+// @Description Return all stored items
+// @Tags        itemA
+// @Accept      json
+// @Produce     json
+// @Success     200 {array}  domain.ItemA
+// @Failure     500   {object} error
+// @Router      /a-items [get]
+func _() {}
+
+// Find godoc
+// @Summary     Show an item
+// @Description get item by ID
+// @Tags        itemA
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string true "Item ID"
+// @Success     200   {object} domain.ItemA
+// @Failure     400   {object} error
+// @Failure     404 {object} error
+// @Failure     500 {object} error
+// @Router      /a-items/{id} [get]
+func _() {}
+
+// Create godoc
+// @Summary     Creates an item
+// @Description creates an item with given data
+// @Tags        itemA
+// @Accept      json
+// @Produce     json
+// @Param       itemA body     domain.ItemA true "Item Properties"
+// @Success     201 {object} domain.ItemA
+// @Header      201 {string} Location "URL of the created item"
+// @Failure     400 {object} error
+// @Failure     422 {object} error
+// @Failure     500 {object} error
+// @Router      /a-items [post]
+func _() {}
+
+// Update godoc
+// @Summary     Updates an item
+// @Description Updates an item with given ID
+// @Tags        itemA
+// @Accept      json
+// @Produce     json
+// @Param       id    path string       true "Item ID"
+// @Param       itemA body domain.ItemA true "Item Properties"
+// @Success     200
+// @Failure     400 {object} error
+// @Failure     404 {object} error
+// @Failure     422 {object} error
+// @Failure     500 {object} error
+// @Router      /a-items/{id} [put]
+func _() {}
+
+// Patch godoc
+// @Summary     Patches an item
+// @Description Partially updates an item with given ID using a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) document
+// @Tags        itemA
+// @Accept      application/merge-patch+json,application/json-patch+json
+// @Produce     json
+// @Param       id    path     string true "Item ID"
+// @Param       patch body     object true "Patch document"
+// @Success     200   {object} domain.ItemA
+// @Failure     400   {object} error
+// @Failure     404   {object} error
+// @Failure     415   {object} error
+// @Failure     422   {object} error
+// @Failure     500   {object} error
+// @Router      /a-items/{id} [patch]
+func _() {}
+
+// Delete godoc
+// @Summary     Deletes an item
+// @Description Deletes an item with given ID
+// @Tags        itemA
+// @Accept      json
+// @Produce     json
+// @Param       string path     string true "Item ID"
+// @Success     200    {object} domain.ItemA
+// @Failure     400    {object} error
+// @Failure     404    {object} error
+// @Failure     500    {object} error
+// @Router      /a-items/{id} [delete]
+func _() {}
+
+// Restore godoc
+// @Summary     Restores an item
+// @Description Restores a soft deleted item with given ID
+// @Tags        itemA
+// @Accept      json
+// @Produce     json
+// @Param       id  path string true "Item ID"
+// @Success     204
+// @Failure     400 {object} error
+// @Failure     404 {object} error
+// @Failure     500 {object} error
+// @Router      /a-items/{id}/restore [post]
+func _() {}
+
+// Purge godoc
+// @Summary     Purges an item
+// @Description Permanently deletes an item with given ID, including soft deleted ones. Restricted to admins
+// @Tags        itemA
+// @Accept      json
+// @Produce     json
+// @Param       id  path string true "Item ID"
+// @Success     204
+// @Failure     400 {object} error
+// @Failure     401 {object} error
+// @Failure     403 {object} error
+// @Failure     404 {object} error
+// @Failure     500 {object} error
+// @Router      /a-items/{id}/purge [delete]
+func _() {}
+
+// CreateBatch godoc
+// @Summary     Creates items in batch
+// @Description Creates up to 1000 items. In atomic mode either every item is created or none, in best-effort mode each item succeeds or fails on its own
+// @Tags        itemA
+// @Accept      json
+// @Produce     json
+// @Param       mode  query    string         false "Batch mode" Enums(atomic, best-effort) default(atomic)
+// @Param       items body     []domain.ItemA true  "Items Properties"
+// @Success     201   {object} batch.Result
+// @Success     207   {object} batch.Result
+// @Failure     400   {object} error
+// @Failure     422   {object} error
+// @Failure     500   {object} error
+// @Router      /a-items/batch [post]
+func _() {}
+
+// UpsertBatch godoc
+// @Summary     Upserts items in batch
+// @Description Creates or replaces up to 1000 items by ID, items without ID are created with an ID generated by the server. An item with an ID that is not stored yet is created with it, so an export can be imported back, and one with the ID of a deleted item fails with 409 Conflict until the item is restored. In atomic mode either every item is applied or none, in best-effort mode each item succeeds or fails on its own
+// @Tags        itemA
+// @Accept      json
+// @Produce     json
+// @Param       mode  query    string         false "Batch mode" Enums(atomic, best-effort) default(atomic)
+// @Param       items body     []domain.ItemA true  "Items Properties"
+// @Success     200   {object} batch.Result
+// @Success     207   {object} batch.Result
+// @Failure     400   {object} error
+// @Failure     422   {object} error
+// @Failure     500   {object} error
+// @Router      /a-items/batch [put]
+func _() {}
+
+// DeleteBatch godoc
+// @Summary     Deletes items in batch
+// @Description Soft deletes up to 1000 items by ID. In atomic mode either every item is deleted or none, in best-effort mode each item succeeds or fails on its own
+// @Tags        itemA
+// @Accept      json
+// @Produce     json
+// @Param       mode query    string   false "Batch mode" Enums(atomic, best-effort) default(atomic)
+// @Param       ids  body     []string true  "Item IDs"
+// @Success     200  {object} batch.Result
+// @Success     207  {object} batch.Result
+// @Failure     400  {object} error
+// @Failure     404  {object} error
+// @Failure     422  {object} error
+// @Failure     500  {object} error
+// @Router      /a-items/batch [delete]
+func _() {}
+
+// Export godoc
+// @Summary     Exports items
+// @Description Streams every item as newline delimited JSON or CSV, reading them from the database with a cursor
+// @Tags        itemA
+// @Produce     application/x-ndjson,text/csv
+// @Param       format query string false "Export format" Enums(ndjson, csv) default(ndjson)
+// @Success     200
+// @Failure     415 {object} error
+// @Failure     500 {object} error
+// @Router      /a-items/export [get]
+func _() {}
+
+// Import godoc
+// @Summary     Imports items
+// @Description Streams an NDJSON or CSV file, sent as the request body or as the file field of a multipart form, and upserts its rows in batches. Rows that fail are listed in the report while the others are imported. With the Prefer: respond-async header the file is imported by a background job instead, answered with 202 Accepted and the job status URL as Location, the report being the job result
+// @Tags        itemA
+// @Accept      application/x-ndjson,text/csv,mpfd
+// @Produce     json
+// @Param       file   formData file   false "NDJSON or CSV file"
+// @Param       Prefer header   string false "respond-async to import in the background"
+// @Success     200  {object} transfer.ImportReport
+// @Success     202  {object} job.Job
+// @Header      202  {string} Location "URL of the job status"
+// @Success     207  {object} transfer.ImportReport
+// @Failure     400  {object} error
+// @Failure     413  {object} error
+// @Failure     415  {object} error
+// @Failure     500  {object} error
+// @Router      /a-items/import [post]
+func _() {}
+
+// Stream godoc
+// @Summary     Streams item changes
+// @Description Sends an event whenever an item is created, updated, deleted or restored, as Server-Sent Events or as WebSocket messages when the connection is upgraded. Events published after the Last-Event-ID header or the lastEventId parameter are replayed first
+// @Tags        itemA
+// @Produce     text/event-stream
+// @Param       types         query  string false "Comma separated event types" example(created,deleted)
+// @Param       ids           query  string false "Comma separated item IDs"
+// @Param       lastEventId   query  string false "ID of the last event received"
+// @Param       Last-Event-ID header string false "ID of the last event received"
+// @Success     200 {object} changefeed.Event
+// @Failure     400 {object} error
+// @Failure     503 {object} error
+// @Router      /a-items/stream [get]
+func _() {}
@@ -1,409 +1,115 @@
-package repository
+package repository_test
 
 import (
+	"context"
+	"io"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
 
+	cacheMemory "app/infra/cache/memory"
+	"app/infra/database/sqlite"
+	"app/internal/batch"
+	"app/internal/cacheaside"
+	"app/internal/errors"
 	"app/internal/serviceA/domain"
-	commonAssertion "app/internal/test/assertion/common"
+	"app/internal/serviceA/repository"
+	"app/internal/storage"
 	errorsAssertion "app/internal/test/assertion/errors"
 	assertion "app/internal/test/assertion/serviceA"
-	storageMock "app/internal/test/mocks/storage"
 )
 
-func TestService(t *testing.T) {
+func TestRepository(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Repository Suits")
 }
 
+// the specs run the repository on a sqlite database kept in memory, exercising the executors of postgresql offline
 var _ = Describe("Repository", func() {
 	var (
-		cacheMock    *storageMock.Cache
-		databaseMock *storageMock.Database
-		repo         Repository
+		ctx      context.Context
+		database storage.Database
+		repo     repository.Repository
 	)
 
 	BeforeEach(func() {
-		cacheMock = storageMock.NewCache(GinkgoT())
-		databaseMock = storageMock.NewDatabase(GinkgoT())
-		repo = New(
-			&DependenciesNode{
-				Database: databaseMock,
-				Cache:    cacheMock,
-			},
-		)
+		ctx = context.Background()
+
+		var err error
+		database, err = sqlite.New(sqlite.MemoryPath)
+		Expect(err).ShouldNot(HaveOccurred())
+		repo = repository.New(&repository.DependenciesNode{
+			Database: database,
+			Cache:    cacheMemory.New(cacheMemory.Config{}),
+		}, cacheaside.Config{})
 	})
 
-	Context("Testing CRUD operations", func() {
-		Context("Getting all items", func() {
-			When("Item is in cache", func() {
-				When("Succeeds", func() {
-					It("Should return an item from cache", func() {
-						expectedItemArr := assertion.ArrayOfItem
-						itemArrInBytes := assertion.ArrayOfItemAInBytes(expectedItemArr)
-						cacheMock.On("Get", AllItemsKey).
-							Return(itemArrInBytes, nil).
-							Once()
-
-						item, err := repo.GetAll(commonAssertion.EmptyCtx)
-
-						Expect(err).ShouldNot(HaveOccurred())
-						Expect(item).To(Equal(expectedItemArr))
-					})
-				})
-				When("Fails", func() {
-					It("Should return an error", func() {
-						cacheMock.On("Get", AllItemsKey).
-							Return(nil, errorsAssertion.ErrGeneric).
-							Once()
-
-						item, err := repo.GetAll(commonAssertion.EmptyCtx)
-
-						Expect(err).Should(HaveOccurred())
-						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-						Expect(item).To(BeNil())
-					})
-				})
-			})
-			When("Item is not in cache", func() {
-				When("Succeeds", func() {
-					It("Should return an item", func() {
-						var emptyArr []*domain.ItemA
-						cacheMock.On("Get", AllItemsKey).
-							Return(nil, nil).
-							Once()
-						databaseMock.On("Select", commonAssertion.EmptyCtx, &emptyArr).
-							Return(nil).
-							Once()
-						cacheMock.On("Set", AllItemsKey, emptyArr).
-							Return(nil).
-							Once()
-
-						_, err := repo.GetAll(commonAssertion.EmptyCtx)
-
-						Expect(err).ShouldNot(HaveOccurred())
-					})
-				})
-				When("Fails to get item from Database", func() {
-					It("Should return an error", func() {
-						var emptyArr []*domain.ItemA
-						cacheMock.On("Get", AllItemsKey).
-							Return(nil, nil).
-							Once()
-						databaseMock.On("Select", commonAssertion.EmptyCtx, &emptyArr).
-							Return(errorsAssertion.ErrGeneric).
-							Once()
-
-						item, err := repo.GetAll(commonAssertion.EmptyCtx)
-
-						Expect(err).Should(HaveOccurred())
-						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-						Expect(item).To(BeNil())
-					})
-				})
-				When("Fails to set cache", func() {
-					It("Should return an error", func() {
-						var emptyArr []*domain.ItemA
-						cacheMock.On("Get", AllItemsKey).
-							Return(nil, nil).
-							Once()
-						databaseMock.On("Select", commonAssertion.EmptyCtx, &emptyArr).
-							Return(nil).
-							Once()
-						cacheMock.On("Set", AllItemsKey, emptyArr).
-							Return(errorsAssertion.ErrGeneric).
-							Once()
-
-						item, err := repo.GetAll(commonAssertion.EmptyCtx)
-
-						Expect(err).Should(HaveOccurred())
-						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-						Expect(item).To(BeNil())
-					})
-				})
-			})
-		})
-
-		Context("Getting one item", func() {
-			When("Item is in cache", func() {
-				When("Succeeds", func() {
-					It("Should return an item from cache", func() {
-						idString := assertion.SampleID.String()
-						expectedItem := assertion.NewItemWithID(idString)
-						itemInBytes := assertion.ItemAInBytes(expectedItem)
-						cacheMock.On("Get", idString).
-							Return(itemInBytes, nil).
-							Once()
-
-						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
-
-						Expect(err).ShouldNot(HaveOccurred())
-						Expect(item).To(Equal(expectedItem))
-					})
-				})
-				When("Fails", func() {
-					It("Should return an error", func() {
-						idString := assertion.SampleID.String()
-						cacheMock.On("Get", idString).
-							Return(nil, errorsAssertion.ErrGeneric).
-							Once()
-
-						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
-
-						Expect(err).Should(HaveOccurred())
-						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-						Expect(item).To(BeNil())
-					})
-				})
-			})
-			When("Item is not in cache", func() {
-				When("Succeeds", func() {
-					It("Should return an item", func() {
-						idString := assertion.SampleID.String()
-						expectedItem := assertion.NewItemWithID(idString)
-						cacheMock.On("Get", idString).
-							Return(nil, nil).
-							Once()
-						databaseMock.On("Select", commonAssertion.EmptyCtx, assertion.NewItemWithID(idString)).
-							Return(nil).
-							Once()
-						cacheMock.On("Set", idString, expectedItem).
-							Return(nil).
-							Once()
-
-						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
-
-						Expect(err).ShouldNot(HaveOccurred())
-						Expect(item).To(Equal(expectedItem))
-					})
-				})
-				When("Fails to get item from Database", func() {
-					It("Should return an error", func() {
-						idString := assertion.SampleID.String()
-						cacheMock.On("Get", idString).
-							Return(nil, nil).
-							Once()
-						databaseMock.On("Select", commonAssertion.EmptyCtx, assertion.NewItemWithID(idString)).
-							Return(errorsAssertion.ErrGeneric).
-							Once()
-
-						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
-
-						Expect(err).Should(HaveOccurred())
-						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-						Expect(item).To(BeNil())
-					})
-				})
-				When("Fails to set cache", func() {
-					It("Should return an error", func() {
-						idString := assertion.SampleID.String()
-						expectedItem := assertion.NewItemWithID(idString)
-						cacheMock.On("Get", idString).
-							Return(nil, nil).
-							Once()
-						databaseMock.On("Select", commonAssertion.EmptyCtx, assertion.NewItemWithID(idString)).
-							Return(nil).
-							Once()
-						cacheMock.On("Set", idString, expectedItem).
-							Return(errorsAssertion.ErrGeneric).
-							Once()
-
-						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
-
-						Expect(err).Should(HaveOccurred())
-						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-						Expect(item).To(BeNil())
-					})
-				})
-			})
-		})
-
-		Context("Creating an item", func() {
-			When("Succeeds", func() {
-				It("Should return an error", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					expectedItem := assertion.NewItemFromInput(inputItem)
-					cacheMock.On("Remove", AllItemsKey).
-						Return(nil).
-						Once()
-					databaseMock.On("Create", commonAssertion.EmptyCtx, inputItem).
-						Return(nil).
-						Once()
-
-					item, err := repo.Insert(commonAssertion.EmptyCtx, inputItem)
-
-					Expect(err).ShouldNot(HaveOccurred())
-					Expect(item.ID).NotTo(BeNil())
-					Expect(item).To(Equal(expectedItem))
-				})
-			})
-			When("Fails to insert item", func() {
-				It("Should return an error", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					cacheMock.On("Remove", AllItemsKey).
-						Return(nil).
-						Once()
-					databaseMock.On("Create", commonAssertion.EmptyCtx, inputItem).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					item, err := repo.Insert(commonAssertion.EmptyCtx, inputItem)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-					Expect(item).To(BeNil())
-				})
-			})
-			When("Fail to remove all cached items", func() {
-				It("Should return an error", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					cacheMock.On("Remove", AllItemsKey).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					item, err := repo.Insert(commonAssertion.EmptyCtx, inputItem)
+	AfterEach(func() {
+		Expect(database.(io.Closer).Close()).To(Succeed())
+	})
 
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-					Expect(item).To(BeNil())
-				})
-			})
+	When("An item is inserted, patched and removed", func() {
+		It("Should store every change", func() {
+			item := assertion.NewItemReferencingItemB()
+			item.ID = assertion.SampleID
+			_, err := repo.Insert(ctx, item)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			stored, err := repo.GetByID(ctx, assertion.SampleID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(*stored.ItemBID).To(Equal(assertion.SampleItemBID))
+
+			columns := map[string]interface{}{domain.ItemBIDColumn: nil}
+			Expect(repo.Patch(ctx, assertion.SampleID, stored, columns)).To(Succeed())
+			stored, err = repo.GetByID(ctx, assertion.SampleID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stored.ItemBID).To(BeNil())
+			Expect(stored.Version).To(Equal(int64(2)))
+
+			Expect(repo.Remove(ctx, assertion.SampleID)).To(Succeed())
+			items, err := repo.GetAll(ctx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(items).To(BeEmpty())
+
+			Expect(repo.Restore(ctx, assertion.SampleID)).To(Succeed())
+			items, err = repo.GetAll(ctx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(items).To(HaveLen(1))
 		})
+	})
 
-		Context("Updating an item", func() {
-			When("Succeeds", func() {
-				It("Should return nothing", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(nil).
-						Once()
-					cacheMock.On("Remove", AllItemsKey).
-						Return(nil).
-						Once()
-					databaseMock.On("Update", commonAssertion.EmptyCtx, assertion.SampleID, inputItem).
-						Return(nil).
-						Once()
-
-					err := repo.Update(commonAssertion.EmptyCtx, assertion.SampleID, inputItem)
-
-					Expect(err).ShouldNot(HaveOccurred())
-				})
-			})
-			When("Fail to update item on DB", func() {
-				It("Should return an error", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(nil).
-						Once()
-					cacheMock.On("Remove", AllItemsKey).
-						Return(nil).
-						Once()
-					databaseMock.On("Update", commonAssertion.EmptyCtx, assertion.SampleID, inputItem).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					err := repo.Update(commonAssertion.EmptyCtx, assertion.SampleID, inputItem)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
-			})
-			When("Fail to remove cached item", func() {
-				It("Should return an error", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					err := repo.Update(commonAssertion.EmptyCtx, assertion.SampleID, inputItem)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
-			})
-			When("Fail to remove all cached items", func() {
-				It("Should return an error", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(nil).
-						Once()
-					cacheMock.On("Remove", AllItemsKey).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					err := repo.Update(commonAssertion.EmptyCtx, assertion.SampleID, inputItem)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
-			})
+	When("An item of an atomic batch is already stored", func() {
+		It("Should store none of them", func() {
+			_, err := repo.Insert(ctx, assertion.NewItemWithID(assertion.SampleID.String()))
+			Expect(err).ShouldNot(HaveOccurred())
+			items := []*domain.ItemA{
+				assertion.NewItemWithID(uuid.NewV4().String()),
+				assertion.NewItemWithID(assertion.SampleID.String()),
+			}
+
+			err = repo.InsertBatch(ctx, items, batch.Atomic)
+
+			Expect(err).To(MatchError(errors.ErrDuplicateKey))
+			stored, err := repo.GetAll(ctx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stored).To(HaveLen(1))
 		})
+	})
 
-		Context("Deleting an item", func() {
-			When("Succeeds", func() {
-				It("Should return nothing", func() {
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(nil).
-						Once()
-					cacheMock.On("Remove", AllItemsKey).
-						Return(nil).
-						Once()
-					databaseMock.On("Delete", commonAssertion.EmptyCtx, assertion.SampleID, domain.ItemA{}).
-						Return(nil).
-						Once()
-
-					err := repo.Remove(commonAssertion.EmptyCtx, assertion.SampleID)
-
-					Expect(err).ShouldNot(HaveOccurred())
-				})
-			})
-			When("Fail to remove cached item", func() {
-				It("Should return an error", func() {
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					err := repo.Remove(commonAssertion.EmptyCtx, assertion.SampleID)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
-			})
-			When("Fail to remove all cached items", func() {
-				It("Should return an error", func() {
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(nil).
-						Once()
-					cacheMock.On("Remove", AllItemsKey).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					err := repo.Remove(commonAssertion.EmptyCtx, assertion.SampleID)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
+	When("A transaction fails", func() {
+		It("Should roll back the items it inserted", func() {
+			err := repo.Transaction(ctx, func(ctx context.Context) error {
+				_, err := repo.Insert(ctx, assertion.NewItemWithID(assertion.SampleID.String()))
+				Expect(err).ShouldNot(HaveOccurred())
+				return errorsAssertion.ErrGeneric
 			})
-			When("Fail to delete item from DB", func() {
-				It("Should return an error", func() {
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(nil).
-						Once()
-					cacheMock.On("Remove", AllItemsKey).
-						Return(nil).
-						Once()
-					databaseMock.On("Delete", commonAssertion.EmptyCtx, assertion.SampleID, domain.ItemA{}).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					err := repo.Remove(commonAssertion.EmptyCtx, assertion.SampleID)
 
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
-			})
+			Expect(err).To(MatchError(errorsAssertion.ErrGeneric))
+			items, err := repo.GetAll(ctx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(items).To(BeEmpty())
 		})
 	})
 })
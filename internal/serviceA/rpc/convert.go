@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "app/api/proto/serviceA"
+	"app/internal/entity"
+	"app/internal/errors"
+	"app/internal/serviceA/domain"
+)
+
+func toProto(item *domain.ItemA) *pb.ItemA {
+	msg := &pb.ItemA{
+		Id:          item.ID.String(),
+		Name:        item.Name,
+		Description: item.Description,
+		Version:     item.Version,
+		CreatedAt:   timestamppb.New(item.CreatedAt),
+		UpdatedAt:   timestamppb.New(item.UpdatedAt),
+		CreatedBy:   item.CreatedBy,
+		UpdatedBy:   item.UpdatedBy,
+	}
+	if item.ItemBID != nil {
+		msg.ItemBId = item.ItemBID.String()
+	}
+	return msg
+}
+
+// fromProto returns the item sent by the client, the server managed fields are left for the service to set.
+// A nil message is returned as a nil item so the service reports the missing body
+func fromProto(msg *pb.ItemA) (*domain.ItemA, error) {
+	if msg == nil {
+		return nil, nil
+	}
+
+	item := &domain.ItemA{
+		Name:        msg.GetName(),
+		Description: msg.GetDescription(),
+	}
+	if msg.GetId() != "" {
+		id, err := uuid.FromString(msg.GetId())
+		if err != nil {
+			return nil, errors.ErrCreatingUUIDFromString
+		}
+		item.Base = entity.Base{ID: id}
+	}
+	if msg.GetItemBId() != "" {
+		itemBID, err := uuid.FromString(msg.GetItemBId())
+		if err != nil {
+			return nil, errors.ErrCreatingUUIDFromString
+		}
+		item.ItemBID = &itemBID
+	}
+	return item, nil
+}
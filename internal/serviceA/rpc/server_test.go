@@ -0,0 +1,162 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+
+	"app/api/interceptor"
+	pb "app/api/proto/serviceA"
+	"app/internal/serviceA/domain"
+	commonAssertion "app/internal/test/assertion/common"
+	assertion "app/internal/test/assertion/serviceA"
+	serviceMocks "app/internal/test/mocks/crud/service"
+	"app/internal/validation"
+)
+
+func TestRPC(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RPC Suits")
+}
+
+var _ = Describe("RPC Server", func() {
+	var (
+		serviceMock *serviceMocks.Service[domain.ItemA]
+		grpcServer  *grpc.Server
+		conn        *grpc.ClientConn
+		client      pb.ItemAServiceClient
+	)
+
+	BeforeEach(func() {
+		serviceMock = serviceMocks.NewService[domain.ItemA](GinkgoT())
+		grpcServer = grpc.NewServer(grpc.UnaryInterceptor(interceptor.NewErrorInterceptor().Unary()))
+		New(&DependenciesNode{Service: serviceMock}).Register(grpcServer)
+
+		listener := bufconn.Listen(1024 * 1024)
+		go func() {
+			_ = grpcServer.Serve(listener)
+		}()
+
+		var err error
+		conn, err = grpc.Dial("bufnet",
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+				return listener.Dial()
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		client = pb.NewItemAServiceClient(conn)
+	})
+
+	AfterEach(func() {
+		_ = conn.Close()
+		grpcServer.Stop()
+	})
+
+	Context("Listing items", func() {
+		When("Request succeeds", func() {
+			It("Should return all items", func() {
+				serviceMock.On("GetAll", mock.Anything).
+					Return(assertion.ArrayOfItem, nil).
+					Once()
+
+				resp, err := client.List(commonAssertion.EmptyCtx, &pb.ListItemsRequest{})
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Items).To(HaveLen(len(assertion.ArrayOfItem)))
+				Expect(resp.Items[0].Id).To(Equal(assertion.ArrayOfItem[0].ID.String()))
+			})
+		})
+	})
+
+	Context("Getting an item", func() {
+		When("Item is not found", func() {
+			It("Should return code not found", func() {
+				serviceMock.On("GetOneByID", mock.Anything, assertion.SampleID.String()).
+					Return(nil, gorm.ErrRecordNotFound).
+					Once()
+
+				resp, err := client.Get(commonAssertion.EmptyCtx, &pb.GetItemRequest{Id: assertion.SampleID.String()})
+
+				Expect(status.Code(err)).To(Equal(codes.NotFound))
+				Expect(resp).To(BeNil())
+			})
+		})
+	})
+
+	Context("Creating an item", func() {
+		When("Request succeeds", func() {
+			It("Should return the created item with its reference", func() {
+				input := assertion.NewItemReferencingItemB()
+				created := assertion.NewItemReferencingItemB()
+				created.ID = assertion.SampleID
+				serviceMock.On("Create", mock.Anything, input).
+					Return(created, nil).
+					Once()
+
+				resp, err := client.Create(commonAssertion.EmptyCtx, &pb.CreateItemRequest{Item: &pb.ItemA{
+					Name:    assertion.SampleName,
+					ItemBId: assertion.SampleItemBID.String(),
+				}})
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Id).To(Equal(assertion.SampleID.String()))
+				Expect(resp.ItemBId).To(Equal(assertion.SampleItemBID.String()))
+			})
+		})
+		When("Item is invalid", func() {
+			It("Should return code invalid argument with the invalid fields", func() {
+				serviceMock.On("Create", mock.Anything, &domain.ItemA{}).
+					Return(nil, validation.NewError(validation.FieldError{
+						Field:   "name",
+						Rule:    "required",
+						Message: "is required",
+					})).
+					Once()
+
+				_, err := client.Create(commonAssertion.EmptyCtx, &pb.CreateItemRequest{Item: &pb.ItemA{}})
+
+				st := status.Convert(err)
+				Expect(st.Code()).To(Equal(codes.InvalidArgument))
+				Expect(st.Details()).To(HaveLen(1))
+				Expect(st.Details()[0].(*errdetails.BadRequest).FieldViolations[0].Field).To(Equal("name"))
+			})
+		})
+		When("Reference is not a UUID", func() {
+			It("Should return code invalid argument without calling the service", func() {
+				_, err := client.Create(commonAssertion.EmptyCtx, &pb.CreateItemRequest{Item: &pb.ItemA{
+					Name:    assertion.SampleName,
+					ItemBId: assertion.InvalidIDString,
+				}})
+
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				serviceMock.AssertNotCalled(GinkgoT(), "Create", mock.Anything, mock.Anything)
+			})
+		})
+	})
+
+	Context("Deleting an item", func() {
+		When("Request succeeds", func() {
+			It("Should return an empty response", func() {
+				serviceMock.On("Delete", mock.Anything, assertion.SampleID.String()).
+					Return(nil).
+					Once()
+
+				_, err := client.Delete(commonAssertion.EmptyCtx, &pb.DeleteItemRequest{Id: assertion.SampleID.String()})
+
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+	})
+})
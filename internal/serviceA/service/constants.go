@@ -1,10 +0,0 @@
-package service
-
-const (
-	FailedToGetAll    = "failed to get all"
-	FailedToGetByID   = "failed to get by id"
-	FailedToCreate    = "failed to create"
-	FailedToUpdate    = "failed to update"
-	FailedToDelete    = "failed to delete"
-	FailedToParseUUID = "failed to parse id to UUID"
-)
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/errors"
+	"app/internal/serviceA/domain"
+	serviceBClient "app/internal/serviceB/client"
+	"app/internal/validation"
+)
+
+type references struct {
+	itemBClient serviceBClient.Client
+}
+
+// NewReferences returns the References checking the ItemB referenced by an item through the client of serviceB
+func NewReferences(itemBClient serviceBClient.Client) References {
+	return &references{
+		itemBClient: itemBClient,
+	}
+}
+
+func (r *references) Columns() []string {
+	return []string{domain.ItemBIDColumn}
+}
+
+func (r *references) Check(ctx context.Context, item *domain.ItemA, checked map[uuid.UUID]bool) error {
+	if item.ItemBID == nil {
+		return nil
+	}
+
+	exists, ok := checked[*item.ItemBID]
+	if !ok {
+		var err error
+		exists, err = r.itemBClient.Exists(ctx, *item.ItemBID)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errors.ErrDependencyUnavailable, err)
+		}
+		if checked != nil {
+			checked[*item.ItemBID] = exists
+		}
+	}
+	if !exists {
+		return validation.Reference(domain.ItemBIDField)
+	}
+	return nil
+}
@@ -1,18 +1,25 @@
 package service
 
 import (
+	"net/http"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/mock"
 
+	"app/internal/batch"
+	crud "app/internal/crud/service"
+	"app/internal/errors"
+	"app/internal/serviceA/domain"
 	commonAssertion "app/internal/test/assertion/common"
 	errorsAssertion "app/internal/test/assertion/errors"
 	assertion "app/internal/test/assertion/serviceA"
+	repositoryMock "app/internal/test/mocks/crud/repository"
+	identifierMock "app/internal/test/mocks/identifier"
 	pkgMock "app/internal/test/mocks/pkg"
-	repositoryMock "app/internal/test/mocks/serviceA/repository"
+	serviceBClientMock "app/internal/test/mocks/serviceB/client"
+	"app/internal/validation"
 )
 
 func TestService(t *testing.T) {
@@ -22,254 +29,109 @@ func TestService(t *testing.T) {
 
 var _ = Describe("Service", func() {
 	var (
-		logMock  *pkgMock.Logger
-		repoMock *repositoryMock.Repository
-		s        Service
+		logMock       *pkgMock.Logger
+		repoMock      *repositoryMock.Repository[domain.ItemA]
+		generatorMock *identifierMock.Generator
+		itemBMock     *serviceBClientMock.Client
+		s             Service
 	)
 
 	BeforeEach(func() {
 		logMock = pkgMock.NewLogger(GinkgoT())
-		repoMock = repositoryMock.NewRepository(GinkgoT())
+		repoMock = repositoryMock.NewRepository[domain.ItemA](GinkgoT())
+		generatorMock = identifierMock.NewGenerator(GinkgoT())
+		itemBMock = serviceBClientMock.NewClient(GinkgoT())
 		s = New(
 			&DependenciesNode{
-				Log:        logMock,
-				Repository: repoMock,
+				Log:         logMock,
+				Repository:  repoMock,
+				IDGenerator: generatorMock,
+				References:  NewReferences(itemBMock),
 			},
 		)
 	})
 
-	Context("Testing CRUD Operations", func() {
-		Context("Getting All items", func() {
-			When("Request succeeds", func() {
-				It("Should return all items from DB", func() {
-					expectedItems := assertion.ArrayOfItem
-					repoMock.On("GetAll", commonAssertion.EmptyCtx).
-						Return(expectedItems, nil).
-						Once()
-
-					resp, err := s.GetAll(commonAssertion.EmptyCtx)
-
-					Expect(err).ShouldNot(HaveOccurred())
-					Expect(resp).To(Equal(expectedItems))
-				})
-			})
-			When("DB is empty", func() {
-				It("Should an empty array", func() {
-					repoMock.On("GetAll", commonAssertion.EmptyCtx).
-						Return(nil, nil).
-						Once()
-
-					resp, err := s.GetAll(commonAssertion.EmptyCtx)
-
-					Expect(err).ShouldNot(HaveOccurred())
-					Expect(resp).To(BeNil())
-				})
-			})
-			When("Request fails", func() {
-				It("Should return an error", func() {
-					repoMock.On("GetAll", commonAssertion.EmptyCtx).
-						Return(nil, errorsAssertion.ErrGeneric).
-						Once()
-					logMock.On(
-						"Error",
-						commonAssertion.EmptyCtx,
-						errorsAssertion.ErrGeneric,
-						FailedToGetAll,
-						mock.Anything,
-					).Once()
-
-					resp, err := s.GetAll(commonAssertion.EmptyCtx)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-					Expect(resp).To(BeNil())
-				})
+	Context("Validating item references", func() {
+		When("The referenced ItemB exists", func() {
+			It("Should create the item", func() {
+				itemInput := assertion.NewItemReferencingItemB()
+				itemBMock.On("Exists", commonAssertion.EmptyCtx, assertion.SampleItemBID).
+					Return(true, nil).
+					Once()
+				generatorMock.On("NewID").
+					Return(assertion.SampleID).
+					Once()
+				repoMock.On("Insert", commonAssertion.EmptyCtx, itemInput).
+					Return(itemInput, nil).
+					Once()
+
+				resp, err := s.Create(commonAssertion.EmptyCtx, itemInput)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(*resp.ItemBID).To(Equal(assertion.SampleItemBID))
 			})
 		})
-
-		Context("Getting one item by ID", func() {
-			When("Request succeeds", func() {
-				It("Should return an item with given ID", func() {
-					idString := assertion.SampleID.String()
-					expectedItem := assertion.NewItemWithID(idString)
-					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
-						Return(expectedItem, nil).
-						Once()
-
-					resp, err := s.GetOneByID(commonAssertion.EmptyCtx, assertion.SampleID.String())
-
-					Expect(err).ShouldNot(HaveOccurred())
-					Expect(resp).To(Equal(expectedItem))
-				})
-			})
-			When("Item is not found", func() {
-				It("Should return a not found error", func() {
-					repoMock.On("GetByID", commonAssertion.EmptyCtx, assertion.SampleID).
-						Return(nil, errorsAssertion.ErrNotFound).
-						Once()
-					logMock.On("Error",
-						commonAssertion.EmptyCtx,
-						errorsAssertion.ErrNotFound,
-						FailedToGetByID,
-						logrus.Fields{itemIDKey: assertion.SampleID},
-					).Once()
-
-					resp, err := s.GetOneByID(commonAssertion.EmptyCtx, assertion.SampleID.String())
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrNotFound))
-					Expect(resp).To(BeNil())
-				})
-			})
-			When("Fails to parse UUID from string", func() {
-				It("Should return an error", func() {
-					logMock.On("Error",
-						commonAssertion.EmptyCtx,
-						assertion.NewErrIncorrectIDLength(assertion.InvalidIDString),
-						FailedToParseUUID,
-						logrus.Fields{requestIDKey: assertion.InvalidIDString},
-					).Once()
-
-					resp, err := s.GetOneByID(commonAssertion.EmptyCtx, assertion.InvalidIDString)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrCreatingUUID))
-					Expect(resp).To(BeNil())
-				})
+		When("The referenced ItemB doesn't exist", func() {
+			It("Should return a validation error on itemBId", func() {
+				itemInput := assertion.NewItemReferencingItemB()
+				itemBMock.On("Exists", commonAssertion.EmptyCtx, assertion.SampleItemBID).
+					Return(false, nil).
+					Once()
+				logMock.On("Error",
+					commonAssertion.EmptyCtx,
+					mock.Anything,
+					crud.FailedToValidateReferences,
+					mock.Anything,
+				).Once()
+
+				resp, err := s.Create(commonAssertion.EmptyCtx, itemInput)
+
+				Expect(err).To(MatchError(errors.ErrValidation))
+				Expect(err.(*validation.Error).Fields).To(ConsistOf(validation.FieldError{
+					Field:   domain.ItemBIDField,
+					Rule:    validation.ExistsRule,
+					Message: "must reference an existing item",
+				}))
+				Expect(resp).To(BeNil())
+				repoMock.AssertNotCalled(GinkgoT(), "Insert", mock.Anything, mock.Anything)
 			})
 		})
-
-		Context("Creating an item", func() {
-			When("Request succeeds", func() {
-				It("Should return the created object", func() {
-					itemInput := assertion.NewItemWithoutID()
-					expectedItem := assertion.NewItemFromInput(itemInput)
-					repoMock.On("Insert", commonAssertion.EmptyCtx, itemInput).
-						Return(expectedItem, nil).
-						Once()
-
-					resp, err := s.Create(commonAssertion.EmptyCtx, itemInput)
-
-					Expect(err).ShouldNot(HaveOccurred())
-					Expect(resp).To(Equal(expectedItem))
-					Expect(resp.ID).NotTo(BeEmpty())
-				})
-			})
-			When("Request fails", func() {
-				It("Should return an error", func() {
-					itemInput := assertion.NewItemWithoutID()
-					repoMock.On("Insert", commonAssertion.EmptyCtx, itemInput).
-						Return(nil, errorsAssertion.ErrGeneric).
-						Once()
-					logMock.On("Error",
-						commonAssertion.EmptyCtx,
-						errorsAssertion.ErrGeneric,
-						FailedToCreate,
-						logrus.Fields{itemObjKey: itemInput},
-					).Once()
-
-					resp, err := s.Create(commonAssertion.EmptyCtx, itemInput)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-					Expect(resp).To(BeNil())
-				})
+		When("ServiceB is unavailable", func() {
+			It("Should return a dependency error", func() {
+				itemInput := assertion.NewItemReferencingItemB()
+				itemBMock.On("Exists", commonAssertion.EmptyCtx, assertion.SampleItemBID).
+					Return(false, errorsAssertion.ErrGeneric).
+					Once()
+				logMock.On("Error",
+					commonAssertion.EmptyCtx,
+					mock.Anything,
+					crud.FailedToValidateReferences,
+					mock.Anything,
+				).Once()
+
+				err := s.Update(commonAssertion.EmptyCtx, assertion.SampleID.String(), itemInput)
+
+				Expect(err).To(MatchError(errors.ErrDependencyUnavailable))
+				Expect(errors.GetStatus(err)).To(Equal(http.StatusServiceUnavailable))
 			})
 		})
-
-		Context("Updating an item", func() {
-			When("Request succeeds", func() {
-				It("Should return nothing", func() {
-					idString := assertion.SampleID.String()
-					inputItem := assertion.NewItemWithID(idString)
-					repoMock.On("Update", commonAssertion.EmptyCtx, assertion.SampleID, inputItem).
-						Return(nil).
-						Once()
-
-					err := s.Update(commonAssertion.EmptyCtx, idString, inputItem)
-					Expect(err).ShouldNot(HaveOccurred())
-				})
-			})
-			When("Request fails", func() {
-				It("Should return an error", func() {
-					idString := assertion.SampleID.String()
-					inputItem := assertion.NewItemWithID(idString)
-					repoMock.On("Update", commonAssertion.EmptyCtx, assertion.SampleID, inputItem).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-					logMock.On("Error",
-						commonAssertion.EmptyCtx,
-						errorsAssertion.ErrGeneric,
-						FailedToUpdate,
-						logrus.Fields{itemIDKey: assertion.SampleID, itemObjKey: inputItem},
-					).Once()
-
-					err := s.Update(commonAssertion.EmptyCtx, idString, inputItem)
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
-			})
-			When("Fails to parse UUID from string", func() {
-				It("Should return an error", func() {
-					idString := assertion.InvalidIDString
-					inputItem := assertion.NewItemWithID(idString)
-					logMock.On("Error",
-						commonAssertion.EmptyCtx,
-						assertion.NewErrIncorrectIDLength(assertion.InvalidIDString),
-						FailedToParseUUID,
-						logrus.Fields{requestIDKey: idString},
-					).Once()
-
-					err := s.Update(commonAssertion.EmptyCtx, assertion.InvalidIDString, inputItem)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrCreatingUUID))
-				})
-			})
-		})
-
-		Context("Deleting an item", func() {
-			When("Request succeeds", func() {
-				It("Should return nothing", func() {
-					repoMock.On("Remove", commonAssertion.EmptyCtx, assertion.SampleID).
-						Return(nil).
-						Once()
-
-					err := s.Delete(commonAssertion.EmptyCtx, assertion.SampleID.String())
-					Expect(err).ShouldNot(HaveOccurred())
-				})
-			})
-			When("Request fails", func() {
-				It("Should return an error", func() {
-					repoMock.On("Remove", commonAssertion.EmptyCtx, assertion.SampleID).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-					logMock.On("Error",
-						commonAssertion.EmptyCtx,
-						errorsAssertion.ErrGeneric,
-						FailedToDelete,
-						logrus.Fields{itemIDKey: assertion.SampleID},
-					).Once()
-
-					err := s.Delete(commonAssertion.EmptyCtx, assertion.SampleID.String())
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
-			})
-			When("Fails to parse UUID from string", func() {
-				It("Should return an error", func() {
-					logMock.On("Error",
-						commonAssertion.EmptyCtx,
-						assertion.NewErrIncorrectIDLength(assertion.InvalidIDString),
-						FailedToParseUUID,
-						logrus.Fields{requestIDKey: assertion.InvalidIDString},
-					).Once()
-
-					err := s.Delete(commonAssertion.EmptyCtx, assertion.InvalidIDString)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrCreatingUUID))
-				})
+		When("Items of a batch reference the same ItemB", func() {
+			It("Should look it up once", func() {
+				items := []*domain.ItemA{assertion.NewItemReferencingItemB(), assertion.NewItemReferencingItemB()}
+				itemBMock.On("Exists", commonAssertion.EmptyCtx, assertion.SampleItemBID).
+					Return(true, nil).
+					Once()
+				generatorMock.On("NewID").
+					Return(assertion.SampleID).
+					Twice()
+				repoMock.On("InsertBatch", commonAssertion.EmptyCtx, items, batch.Atomic).
+					Return(nil).
+					Once()
+
+				result, err := s.CreateBatch(commonAssertion.EmptyCtx, items, batch.Atomic)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(result.Succeeded).To(Equal(2))
 			})
 		})
 	})
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/entity"
+	"app/internal/httpclient"
+	"app/internal/serviceB/domain"
+)
+
+func TestClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Client Suits")
+}
+
+var sampleID = uuid.FromStringOrNil("15664c2f-d5bf-4922-8d19-39c6886bce90")
+
+var _ = Describe("Client", func() {
+	var (
+		server  *httptest.Server
+		handler http.HandlerFunc
+		c       Client
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler(w, r)
+		}))
+		c = New(httpclient.New(httpclient.Config{BaseURL: server.URL}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("Getting an item by ID", func() {
+		When("Item exists", func() {
+			It("Should return it", func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					Expect(r.URL.Path).To(Equal("/api/v1/b-items/" + sampleID.String()))
+					_ = json.NewEncoder(w).Encode(domain.ItemB{Base: entity.Base{ID: sampleID}, Name: "sample"})
+				}
+
+				item, err := c.GetByID(context.Background(), sampleID)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(item.ID).To(Equal(sampleID))
+				Expect(item.Name).To(Equal("sample"))
+			})
+		})
+	})
+
+	Context("Checking an item exists", func() {
+		When("Item exists", func() {
+			It("Should return true", func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					_ = json.NewEncoder(w).Encode(domain.ItemB{Base: entity.Base{ID: sampleID}})
+				}
+
+				exists, err := c.Exists(context.Background(), sampleID)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(exists).To(BeTrue())
+			})
+		})
+		When("Item is not found", func() {
+			It("Should return false without error", func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				}
+
+				exists, err := c.Exists(context.Background(), sampleID)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(exists).To(BeFalse())
+			})
+		})
+		When("Service fails", func() {
+			It("Should return the error", func() {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+
+				exists, err := c.Exists(context.Background(), sampleID)
+
+				Expect(httpclient.HasStatus(err, http.StatusInternalServerError)).To(BeTrue())
+				Expect(exists).To(BeFalse())
+			})
+		})
+	})
+})
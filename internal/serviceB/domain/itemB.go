@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
-	uuid "github.com/satori/go.uuid"
+	"app/internal/entity"
 )
 
 const (
@@ -12,7 +12,9 @@ const (
 )
 
 type ItemB struct {
-	ID uuid.UUID `json:"id"`
+	entity.Base
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=1024"`
 }
 
 func NewFromBytes(b []byte) (*ItemB, error) {
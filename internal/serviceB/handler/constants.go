@@ -1,5 +1,6 @@
 package handler
 
 const (
-	ParamID = "id"
+	itemsPath      = "/b-items"
+	exportFilename = "b-items"
 )
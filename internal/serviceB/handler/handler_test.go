@@ -3,7 +3,6 @@ package handler
 import (
 	"bytes"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,10 +10,13 @@ import (
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
 
-	errorsAssertion "app/internal/test/assertion/errors"
+	crud "app/internal/crud/handler"
+	"app/internal/serviceB/domain"
 	assertion "app/internal/test/assertion/serviceB"
-	serviceMocks "app/internal/test/mocks/serviceB/service"
+	serviceMocks "app/internal/test/mocks/crud/service"
+	"app/internal/transfer"
 )
 
 func TestHandler(t *testing.T) {
@@ -22,337 +24,68 @@ func TestHandler(t *testing.T) {
 	RunSpecs(t, "Handler Suits")
 }
 
-func ginCtxParam(key, value string) gin.Param {
-	return gin.Param{
-		Key:   key,
-		Value: value,
-	}
-}
-
 var _ = Describe("Handler", func() {
 	var (
 		router      *gin.Engine
 		w           *httptest.ResponseRecorder
-		ginCtx      *gin.Context
-		serviceMock *serviceMocks.Service
-		deps        *DependenciesNode
+		serviceMock *serviceMocks.Service[domain.ItemB]
 	)
 
 	BeforeEach(func() {
 		gin.SetMode(gin.TestMode)
 		w = httptest.NewRecorder()
-		ginCtx, router = gin.CreateTestContext(w)
-		serviceMock = serviceMocks.NewService(GinkgoT())
-		deps = &DependenciesNode{
+		_, router = gin.CreateTestContext(w)
+		serviceMock = serviceMocks.NewService[domain.ItemB](GinkgoT())
+		New(&DependenciesNode{
 			Service: serviceMock,
 			Router:  router,
-		}
+		}, Config{})
 	})
 
-	Context("CRUD Operations", func() {
-		Context("GET", func() {
-			When("Succeed", func() {
-				It("Return an array of item from DB", func() {
-					arrayOfItemBInBytes := assertion.ArrayOfItemBInBytes(assertion.ArrayOfItem)
-					serviceMock.On("GetAll", ginCtx).
-						Return(assertion.ArrayOfItem, nil)
-
-					New(deps)
-
-					request, err := http.NewRequestWithContext(ginCtx, http.MethodGet, "/api/v1/b-items", nil)
-					Expect(err).ToNot(HaveOccurred())
-
-					router.ServeHTTP(w, request)
-
-					respInBytes, err := ioutil.ReadAll(w.Body)
-					Expect(err).ToNot(HaveOccurred())
-
-					Expect(w.Code).To(Equal(http.StatusOK))
-					Expect(respInBytes).To(Equal(arrayOfItemBInBytes))
-				})
-			})
-			When("Fails", func() {
-				It("Return an Internal Server Error", func() {
-					serviceMock.On("GetAll", ginCtx).
-						Return(nil, errorsAssertion.ErrGeneric)
-
-					New(deps)
+	Context("Mounting the items API", func() {
+		When("Listing the items", func() {
+			It("Should serve them under /api/v1/b-items", func() {
+				serviceMock.On("GetAll", mock.Anything).
+					Return(assertion.ArrayOfItem, nil)
 
-					request, err := http.NewRequestWithContext(ginCtx, http.MethodGet, "/api/v1/b-items", nil)
-					Expect(err).ToNot(HaveOccurred())
+				request, err := http.NewRequest(http.MethodGet, "/api/v1/b-items", nil)
+				Expect(err).ToNot(HaveOccurred())
+				router.ServeHTTP(w, request)
 
-					router.ServeHTTP(w, request)
-
-					_, err = ioutil.ReadAll(w.Body)
-					Expect(err).NotTo(HaveOccurred())
-
-					Expect(w.Code).To(Equal(http.StatusInternalServerError))
-				})
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Body.Bytes()).To(MatchJSON(assertion.ArrayOfItemBInBytes(assertion.ArrayOfItem)))
 			})
 		})
-
-		Context("FIND", func() {
-			When("Succeed", func() {
-				It("Return an item from DB", func() {
-					itemID := assertion.SampleID.String()
-					item := assertion.NewItemWithID(itemID)
-					itemBInBytes := assertion.ItemBInBytes(item)
-					serviceMock.On("GetOneByID", ginCtx, itemID).
-						Return(item, nil)
-					ginCtx.Params = []gin.Param{
-						ginCtxParam("id", itemID),
-					}
-
-					New(deps)
-
-					request, err := http.NewRequestWithContext(
-						ginCtx,
-						http.MethodGet,
-						fmt.Sprintf("/api/v1/b-items/%s", itemID),
-						nil,
-					)
-					Expect(err).ToNot(HaveOccurred())
-
-					router.ServeHTTP(w, request)
-
-					respInBytes, err := ioutil.ReadAll(w.Body)
-					Expect(err).ToNot(HaveOccurred())
-
-					Expect(w.Code).To(Equal(http.StatusOK))
-					Expect(respInBytes).To(Equal(itemBInBytes))
-				})
-			})
-			When("Fails", func() {
-				It("Return a Not Found error", func() {
-					itemID := assertion.SampleID.String()
-					serviceMock.On("GetOneByID", ginCtx, itemID).
-						Return(nil, errorsAssertion.ErrNotFound)
-					ginCtx.Params = []gin.Param{
-						ginCtxParam("id", itemID),
-					}
-
-					New(deps)
-
-					request, err := http.NewRequestWithContext(
-						ginCtx,
-						http.MethodGet,
-						fmt.Sprintf("/api/v1/b-items/%s", itemID),
-						nil,
-					)
-
-					router.ServeHTTP(w, request)
-
-					_, err = ioutil.ReadAll(w.Body)
-					Expect(err).NotTo(HaveOccurred())
-
-					Expect(w.Code).To(Equal(http.StatusNotFound))
-				})
+		When("Creating an item", func() {
+			It("Should locate it under /api/v1/b-items", func() {
+				item := assertion.NewItemWithID(assertion.SampleID.String())
+				serviceMock.On("Create", mock.Anything, mock.Anything).
+					Return(item, nil)
+
+				request, err := http.NewRequest(
+					http.MethodPost,
+					"/api/v1/b-items",
+					bytes.NewBuffer(assertion.ItemBInBytes(assertion.NewItemWithoutID())),
+				)
+				Expect(err).ToNot(HaveOccurred())
+				router.ServeHTTP(w, request)
+
+				Expect(w.Code).To(Equal(http.StatusCreated))
+				Expect(w.Header().Get(crud.HeaderLocation)).
+					To(Equal(fmt.Sprintf("/api/v1/b-items/%s", assertion.SampleID)))
 			})
 		})
+		When("Exporting the items", func() {
+			It("Should name the file after the items", func() {
+				serviceMock.On("Export", mock.Anything, transfer.CSV, mock.Anything).
+					Return(nil)
 
-		Context("CREATE", func() {
-			When("Succeed", func() {
-				It("Creates a new item", func() {
-					itemInput := assertion.NewItemWithoutID()
-					inputInBytes := assertion.ItemBInBytes(itemInput)
-					expectedOutput := *itemInput
-					expectedOutput.ID = assertion.SampleID
-					serviceMock.On("Create", ginCtx, itemInput).
-						Return(&expectedOutput, nil)
-
-					New(deps)
-
-					request, err := http.NewRequestWithContext(
-						ginCtx,
-						http.MethodPost,
-						"/api/v1/b-items",
-						bytes.NewBuffer(inputInBytes),
-					)
-					Expect(err).ToNot(HaveOccurred())
-
-					router.ServeHTTP(w, request)
-
-					respInBytes, err := ioutil.ReadAll(w.Body)
-					Expect(err).ToNot(HaveOccurred())
-
-					Expect(w.Code).To(Equal(http.StatusOK))
-					Expect(respInBytes).To(Equal(assertion.ItemBInBytes(&expectedOutput)))
-				})
-			})
-			When("Fails", func() {
-				It("Return Bad Request when fails to Bind Input JSON", func() {
-					New(deps)
-
-					request, err := http.NewRequestWithContext(
-						ginCtx,
-						http.MethodPost,
-						"/api/v1/b-items",
-						nil,
-					)
-					Expect(err).ToNot(HaveOccurred())
-
-					router.ServeHTTP(w, request)
-
-					_, err = ioutil.ReadAll(w.Body)
-					Expect(err).ToNot(HaveOccurred())
-
-					Expect(w.Code).To(Equal(http.StatusBadRequest))
-				})
-				It("Return Internal Server Error when fails to create item", func() {
-					itemInput := assertion.NewItemWithoutID()
-					inputInBytes := assertion.ItemBInBytes(itemInput)
-					expectedOutput := *itemInput
-					expectedOutput.ID = assertion.SampleID
-					serviceMock.On("Create", ginCtx, itemInput).
-						Return(nil, errorsAssertion.ErrCreatingUUID)
-
-					New(deps)
-
-					request, err := http.NewRequestWithContext(
-						ginCtx,
-						http.MethodPost,
-						"/api/v1/b-items",
-						bytes.NewBuffer(inputInBytes),
-					)
-					Expect(err).ToNot(HaveOccurred())
-
-					router.ServeHTTP(w, request)
-
-					_, err = ioutil.ReadAll(w.Body)
-					Expect(err).ToNot(HaveOccurred())
-
-					Expect(w.Code).To(Equal(http.StatusInternalServerError))
-				})
-			})
-		})
-
-		Context("UPDATE", func() {
-			When("Succeed", func() {
-				It("Return no content", func() {
-					itemID := assertion.SampleID.String()
-					itemInput := assertion.NewItemWithoutID()
-					inputInBytes := assertion.ItemBInBytes(itemInput)
-					serviceMock.On("Update", ginCtx, itemID, itemInput).
-						Return(nil)
-
-					New(deps)
-
-					request, err := http.NewRequestWithContext(
-						ginCtx,
-						http.MethodPut,
-						fmt.Sprintf("/api/v1/b-items/%s", itemID),
-						bytes.NewBuffer(inputInBytes),
-					)
-					Expect(err).ToNot(HaveOccurred())
-
-					router.ServeHTTP(w, request)
-
-					_, err = ioutil.ReadAll(w.Body)
-					Expect(err).ToNot(HaveOccurred())
-
-					Expect(w.Code).To(Equal(http.StatusNoContent))
-				})
-			})
-			When("Fails", func() {
-				It("Return a Bad Request Error when fails to Bind Input JSON", func() {
-					itemID := assertion.SampleID.String()
-					New(deps)
-
-					request, err := http.NewRequestWithContext(
-						ginCtx,
-						http.MethodPut,
-						fmt.Sprintf("/api/v1/b-items/%s", itemID),
-						nil,
-					)
-					Expect(err).ToNot(HaveOccurred())
-
-					router.ServeHTTP(w, request)
-
-					_, err = ioutil.ReadAll(w.Body)
-					Expect(err).ToNot(HaveOccurred())
-
-					Expect(w.Code).To(Equal(http.StatusBadRequest))
-				})
-				It("Return a Not Found Error when fails to Update item", func() {
-					itemID := assertion.SampleID.String()
-					itemInput := assertion.NewItemWithoutID()
-					inputInBytes := assertion.ItemBInBytes(itemInput)
-					serviceMock.On("Update", ginCtx, itemID, itemInput).
-						Return(errorsAssertion.ErrNotFound)
-
-					New(deps)
-
-					request, err := http.NewRequestWithContext(
-						ginCtx,
-						http.MethodPut,
-						fmt.Sprintf("/api/v1/b-items/%s", itemID),
-						bytes.NewBuffer(inputInBytes),
-					)
-					Expect(err).ToNot(HaveOccurred())
-
-					router.ServeHTTP(w, request)
-
-					_, err = ioutil.ReadAll(w.Body)
-					Expect(err).ToNot(HaveOccurred())
-
-					Expect(w.Code).To(Equal(http.StatusNotFound))
-				})
-			})
-		})
-
-		Context("DELETE", func() {
-			When("Succeed", func() {
-				It("Return an item from DB", func() {
-					itemID := assertion.SampleID.String()
-					serviceMock.On("Delete", ginCtx, itemID).
-						Return(nil)
-					ginCtx.Params = []gin.Param{
-						ginCtxParam("id", itemID),
-					}
-
-					New(deps)
-
-					request, err := http.NewRequestWithContext(
-						ginCtx,
-						http.MethodDelete,
-						fmt.Sprintf("/api/v1/b-items/%s", itemID),
-						nil,
-					)
-					Expect(err).ToNot(HaveOccurred())
-
-					router.ServeHTTP(w, request)
-
-					_, err = ioutil.ReadAll(w.Body)
-					Expect(err).ToNot(HaveOccurred())
-
-					Expect(w.Code).To(Equal(http.StatusNoContent))
-				})
-			})
-			When("Fails", func() {
-				It("Return a Not Found error", func() {
-					itemID := assertion.SampleID.String()
-					serviceMock.On("Delete", ginCtx, itemID).
-						Return(errorsAssertion.ErrNotFound)
-					ginCtx.Params = []gin.Param{
-						ginCtxParam("id", itemID),
-					}
-
-					New(deps)
-
-					request, err := http.NewRequestWithContext(
-						ginCtx,
-						http.MethodDelete,
-						fmt.Sprintf("/api/v1/b-items/%s", itemID),
-						nil,
-					)
-
-					router.ServeHTTP(w, request)
-
-					_, err = ioutil.ReadAll(w.Body)
-					Expect(err).NotTo(HaveOccurred())
+				request, err := http.NewRequest(http.MethodGet, "/api/v1/b-items/export?format=csv", nil)
+				Expect(err).ToNot(HaveOccurred())
+				router.ServeHTTP(w, request)
 
-					Expect(w.Code).To(Equal(http.StatusNotFound))
-				})
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Header().Get(crud.HeaderContentDisposition)).To(Equal(`attachment; filename="b-items.csv"`))
 			})
 		})
 	})
@@ -369,9 +102,10 @@ var _ = Describe("Api", func() {
 		_, r = gin.CreateTestContext(httptest.NewRecorder())
 		apiHandler = New(
 			&DependenciesNode{
-				Service: serviceMocks.NewService(GinkgoT()),
+				Service: serviceMocks.NewService[domain.ItemB](GinkgoT()),
 				Router:  r,
 			},
+			Config{},
 		)
 	})
 	Context("Testing API", func() {
@@ -1,409 +1,90 @@
-package repository
+package repository_test
 
 import (
+	"context"
+	"io"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	cacheMemory "app/infra/cache/memory"
+	"app/infra/database/sqlite"
+	"app/internal/batch"
+	"app/internal/cacheaside"
 	"app/internal/serviceB/domain"
-	commonAssertion "app/internal/test/assertion/common"
-	errorsAssertion "app/internal/test/assertion/errors"
+	"app/internal/serviceB/repository"
+	"app/internal/storage"
 	assertion "app/internal/test/assertion/serviceB"
-	storageMock "app/internal/test/mocks/storage"
 )
 
-func TestService(t *testing.T) {
+func TestRepository(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Repository Suits")
 }
 
+// the specs run the repository on a sqlite database kept in memory, exercising the executors of postgresql offline
 var _ = Describe("Repository", func() {
 	var (
-		cacheMock    *storageMock.Cache
-		databaseMock *storageMock.Database
-		repo         Repository
+		ctx      context.Context
+		database storage.Database
+		repo     repository.Repository
 	)
 
 	BeforeEach(func() {
-		cacheMock = storageMock.NewCache(GinkgoT())
-		databaseMock = storageMock.NewDatabase(GinkgoT())
-		repo = New(
-			&DependenciesNode{
-				Database: databaseMock,
-				Cache:    cacheMock,
-			},
-		)
-	})
-
-	Context("Testing CRUD operations", func() {
-		Context("Getting all items", func() {
-			When("Item is in cache", func() {
-				When("Succeeds", func() {
-					It("Should return an item from cache", func() {
-						expectedItemArr := assertion.ArrayOfItem
-						itemBrrInBytes := assertion.ArrayOfItemBInBytes(expectedItemArr)
-						cacheMock.On("Get", AllItemsKey).
-							Return(itemBrrInBytes, nil).
-							Once()
-
-						item, err := repo.GetAll(commonAssertion.EmptyCtx)
-
-						Expect(err).ShouldNot(HaveOccurred())
-						Expect(item).To(Equal(expectedItemArr))
-					})
-				})
-				When("Fails", func() {
-					It("Should return an error", func() {
-						cacheMock.On("Get", AllItemsKey).
-							Return(nil, errorsAssertion.ErrGeneric).
-							Once()
-
-						item, err := repo.GetAll(commonAssertion.EmptyCtx)
-
-						Expect(err).Should(HaveOccurred())
-						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-						Expect(item).To(BeNil())
-					})
-				})
-			})
-			When("Item is not in cache", func() {
-				When("Succeeds", func() {
-					It("Should return an item", func() {
-						var emptyArr []*domain.ItemB
-						cacheMock.On("Get", AllItemsKey).
-							Return(nil, nil).
-							Once()
-						databaseMock.On("Select", commonAssertion.EmptyCtx, &emptyArr).
-							Return(nil).
-							Once()
-						cacheMock.On("Set", AllItemsKey, emptyArr).
-							Return(nil).
-							Once()
-
-						_, err := repo.GetAll(commonAssertion.EmptyCtx)
-
-						Expect(err).ShouldNot(HaveOccurred())
-					})
-				})
-				When("Fails to get item from Database", func() {
-					It("Should return an error", func() {
-						var emptyArr []*domain.ItemB
-						cacheMock.On("Get", AllItemsKey).
-							Return(nil, nil).
-							Once()
-						databaseMock.On("Select", commonAssertion.EmptyCtx, &emptyArr).
-							Return(errorsAssertion.ErrGeneric).
-							Once()
-
-						item, err := repo.GetAll(commonAssertion.EmptyCtx)
-
-						Expect(err).Should(HaveOccurred())
-						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-						Expect(item).To(BeNil())
-					})
-				})
-				When("Fails to set cache", func() {
-					It("Should return an error", func() {
-						var emptyArr []*domain.ItemB
-						cacheMock.On("Get", AllItemsKey).
-							Return(nil, nil).
-							Once()
-						databaseMock.On("Select", commonAssertion.EmptyCtx, &emptyArr).
-							Return(nil).
-							Once()
-						cacheMock.On("Set", AllItemsKey, emptyArr).
-							Return(errorsAssertion.ErrGeneric).
-							Once()
-
-						item, err := repo.GetAll(commonAssertion.EmptyCtx)
-
-						Expect(err).Should(HaveOccurred())
-						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-						Expect(item).To(BeNil())
-					})
-				})
-			})
-		})
-
-		Context("Getting one item", func() {
-			When("Item is in cache", func() {
-				When("Succeeds", func() {
-					It("Should return an item from cache", func() {
-						idString := assertion.SampleID.String()
-						expectedItem := assertion.NewItemWithID(idString)
-						itemInBytes := assertion.ItemBInBytes(expectedItem)
-						cacheMock.On("Get", idString).
-							Return(itemInBytes, nil).
-							Once()
-
-						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
-
-						Expect(err).ShouldNot(HaveOccurred())
-						Expect(item).To(Equal(expectedItem))
-					})
-				})
-				When("Fails", func() {
-					It("Should return an error", func() {
-						idString := assertion.SampleID.String()
-						cacheMock.On("Get", idString).
-							Return(nil, errorsAssertion.ErrGeneric).
-							Once()
-
-						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
-
-						Expect(err).Should(HaveOccurred())
-						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-						Expect(item).To(BeNil())
-					})
-				})
-			})
-			When("Item is not in cache", func() {
-				When("Succeeds", func() {
-					It("Should return an item", func() {
-						idString := assertion.SampleID.String()
-						expectedItem := assertion.NewItemWithID(idString)
-						cacheMock.On("Get", idString).
-							Return(nil, nil).
-							Once()
-						databaseMock.On("Select", commonAssertion.EmptyCtx, assertion.NewItemWithID(idString)).
-							Return(nil).
-							Once()
-						cacheMock.On("Set", idString, expectedItem).
-							Return(nil).
-							Once()
-
-						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
-
-						Expect(err).ShouldNot(HaveOccurred())
-						Expect(item).To(Equal(expectedItem))
-					})
-				})
-				When("Fails to get item from Database", func() {
-					It("Should return an error", func() {
-						idString := assertion.SampleID.String()
-						cacheMock.On("Get", idString).
-							Return(nil, nil).
-							Once()
-						databaseMock.On("Select", commonAssertion.EmptyCtx, assertion.NewItemWithID(idString)).
-							Return(errorsAssertion.ErrGeneric).
-							Once()
-
-						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
-
-						Expect(err).Should(HaveOccurred())
-						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-						Expect(item).To(BeNil())
-					})
-				})
-				When("Fails to set cache", func() {
-					It("Should return an error", func() {
-						idString := assertion.SampleID.String()
-						expectedItem := assertion.NewItemWithID(idString)
-						cacheMock.On("Get", idString).
-							Return(nil, nil).
-							Once()
-						databaseMock.On("Select", commonAssertion.EmptyCtx, assertion.NewItemWithID(idString)).
-							Return(nil).
-							Once()
-						cacheMock.On("Set", idString, expectedItem).
-							Return(errorsAssertion.ErrGeneric).
-							Once()
-
-						item, err := repo.GetByID(commonAssertion.EmptyCtx, assertion.SampleID)
-
-						Expect(err).Should(HaveOccurred())
-						Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-						Expect(item).To(BeNil())
-					})
-				})
-			})
-		})
-
-		Context("Creating an item", func() {
-			When("Succeeds", func() {
-				It("Should return an error", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					expectedItem := assertion.NewItemFromInput(inputItem)
-					cacheMock.On("Remove", AllItemsKey).
-						Return(nil).
-						Once()
-					databaseMock.On("Create", commonAssertion.EmptyCtx, inputItem).
-						Return(nil).
-						Once()
-
-					item, err := repo.Insert(commonAssertion.EmptyCtx, inputItem)
+		ctx = context.Background()
 
-					Expect(err).ShouldNot(HaveOccurred())
-					Expect(item.ID).NotTo(BeNil())
-					Expect(item).To(Equal(expectedItem))
-				})
-			})
-			When("Fails to insert item", func() {
-				It("Should return an error", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					cacheMock.On("Remove", AllItemsKey).
-						Return(nil).
-						Once()
-					databaseMock.On("Create", commonAssertion.EmptyCtx, inputItem).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					item, err := repo.Insert(commonAssertion.EmptyCtx, inputItem)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-					Expect(item).To(BeNil())
-				})
-			})
-			When("Fail to remove all cached items", func() {
-				It("Should return an error", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					cacheMock.On("Remove", AllItemsKey).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					item, err := repo.Insert(commonAssertion.EmptyCtx, inputItem)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-					Expect(item).To(BeNil())
-				})
-			})
-		})
-
-		Context("Updating an item", func() {
-			When("Succeeds", func() {
-				It("Should return nothing", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(nil).
-						Once()
-					cacheMock.On("Remove", AllItemsKey).
-						Return(nil).
-						Once()
-					databaseMock.On("Update", commonAssertion.EmptyCtx, assertion.SampleID, inputItem).
-						Return(nil).
-						Once()
-
-					err := repo.Update(commonAssertion.EmptyCtx, assertion.SampleID, inputItem)
-
-					Expect(err).ShouldNot(HaveOccurred())
-				})
-			})
-			When("Fail to update item on DB", func() {
-				It("Should return an error", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(nil).
-						Once()
-					cacheMock.On("Remove", AllItemsKey).
-						Return(nil).
-						Once()
-					databaseMock.On("Update", commonAssertion.EmptyCtx, assertion.SampleID, inputItem).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					err := repo.Update(commonAssertion.EmptyCtx, assertion.SampleID, inputItem)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
-			})
-			When("Fail to remove cached item", func() {
-				It("Should return an error", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					err := repo.Update(commonAssertion.EmptyCtx, assertion.SampleID, inputItem)
+		var err error
+		database, err = sqlite.New(sqlite.MemoryPath)
+		Expect(err).ShouldNot(HaveOccurred())
+		repo = repository.New(&repository.DependenciesNode{
+			Database: database,
+			Cache:    cacheMemory.New(cacheMemory.Config{}),
+		}, cacheaside.Config{})
 
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
-			})
-			When("Fail to remove all cached items", func() {
-				It("Should return an error", func() {
-					inputItem := assertion.NewItemWithID(assertion.SampleID.String())
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(nil).
-						Once()
-					cacheMock.On("Remove", AllItemsKey).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
+		Expect(repo.InsertBatch(ctx, assertion.ArrayOfItem, batch.Atomic)).To(Succeed())
+	})
 
-					err := repo.Update(commonAssertion.EmptyCtx, assertion.SampleID, inputItem)
+	AfterEach(func() {
+		Expect(database.(io.Closer).Close()).To(Succeed())
+	})
 
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
-			})
+	When("Upserting items", func() {
+		It("Should overwrite the stored ones and insert the others", func() {
+			updated := assertion.NewItemWithID(assertion.ArrayOfItem[0].ID.String())
+			updated.Name = "upserted"
+			items := []*domain.ItemB{updated, assertion.NewItemWithID(assertion.SampleID.String())}
+
+			Expect(repo.UpsertBatch(ctx, items, batch.Atomic)).To(Succeed())
+
+			stored, err := repo.GetByID(ctx, updated.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stored.Name).To(Equal("upserted"))
+			Expect(stored.Version).To(Equal(int64(2)))
+			all, err := repo.GetAll(ctx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(all).To(HaveLen(len(assertion.ArrayOfItem) + 1))
 		})
+	})
 
-		Context("Deleting an item", func() {
-			When("Succeeds", func() {
-				It("Should return nothing", func() {
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(nil).
-						Once()
-					cacheMock.On("Remove", AllItemsKey).
-						Return(nil).
-						Once()
-					databaseMock.On("Delete", commonAssertion.EmptyCtx, assertion.SampleID, domain.ItemB{}).
-						Return(nil).
-						Once()
-
-					err := repo.Remove(commonAssertion.EmptyCtx, assertion.SampleID)
-
-					Expect(err).ShouldNot(HaveOccurred())
-				})
-			})
-			When("Fail to remove cached item", func() {
-				It("Should return an error", func() {
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					err := repo.Remove(commonAssertion.EmptyCtx, assertion.SampleID)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
-			})
-			When("Fail to remove all cached items", func() {
-				It("Should return an error", func() {
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(nil).
-						Once()
-					cacheMock.On("Remove", AllItemsKey).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					err := repo.Remove(commonAssertion.EmptyCtx, assertion.SampleID)
-
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
+	When("Purging the removed items", func() {
+		It("Should stream the others", func() {
+			removed := assertion.ArrayOfItem[0].ID
+			Expect(repo.Remove(ctx, removed)).To(Succeed())
+			Expect(repo.PurgeDeleted(ctx, time.Now().Add(time.Hour))).To(Succeed())
+
+			item := &domain.ItemB{}
+			var streamed int
+			err := repo.Stream(ctx, item, func() error {
+				Expect(item.ID).ShouldNot(Equal(removed))
+				streamed++
+				return nil
 			})
-			When("Fail to delete item from DB", func() {
-				It("Should return an error", func() {
-					cacheMock.On("Remove", assertion.SampleID.String()).
-						Return(nil).
-						Once()
-					cacheMock.On("Remove", AllItemsKey).
-						Return(nil).
-						Once()
-					databaseMock.On("Delete", commonAssertion.EmptyCtx, assertion.SampleID, domain.ItemB{}).
-						Return(errorsAssertion.ErrGeneric).
-						Once()
-
-					err := repo.Remove(commonAssertion.EmptyCtx, assertion.SampleID)
 
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(errorsAssertion.ErrGeneric))
-				})
-			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(streamed).To(Equal(len(assertion.ArrayOfItem) - 1))
+			Expect(repo.Restore(ctx, removed)).ShouldNot(Succeed())
 		})
 	})
 })
@@ -0,0 +1,46 @@
+package rpc
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "app/api/proto/serviceB"
+	"app/internal/entity"
+	"app/internal/errors"
+	"app/internal/serviceB/domain"
+)
+
+func toProto(item *domain.ItemB) *pb.ItemB {
+	msg := &pb.ItemB{
+		Id:          item.ID.String(),
+		Name:        item.Name,
+		Description: item.Description,
+		Version:     item.Version,
+		CreatedAt:   timestamppb.New(item.CreatedAt),
+		UpdatedAt:   timestamppb.New(item.UpdatedAt),
+		CreatedBy:   item.CreatedBy,
+		UpdatedBy:   item.UpdatedBy,
+	}
+	return msg
+}
+
+// fromProto returns the item sent by the client, the server managed fields are left for the service to set.
+// A nil message is returned as a nil item so the service reports the missing body
+func fromProto(msg *pb.ItemB) (*domain.ItemB, error) {
+	if msg == nil {
+		return nil, nil
+	}
+
+	item := &domain.ItemB{
+		Name:        msg.GetName(),
+		Description: msg.GetDescription(),
+	}
+	if msg.GetId() != "" {
+		id, err := uuid.FromString(msg.GetId())
+		if err != nil {
+			return nil, errors.ErrCreatingUUIDFromString
+		}
+		item.Base = entity.Base{ID: id}
+	}
+	return item, nil
+}
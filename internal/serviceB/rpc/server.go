@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "app/api/proto/serviceB"
+	"app/internal/serviceB/service"
+)
+
+type DependenciesNode struct {
+	Service service.Service
+}
+
+// Server implements the ItemBService gRPC API on top of the same service as the REST handler.
+// Errors are returned as is and turned into statuses by the error interceptor
+type Server struct {
+	pb.UnimplementedItemBServiceServer
+	deps *DependenciesNode
+}
+
+func New(deps *DependenciesNode) *Server {
+	return &Server{
+		deps: deps,
+	}
+}
+
+// Register adds the ItemBService to the gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	pb.RegisterItemBServiceServer(registrar, s)
+}
+
+func (s *Server) List(ctx context.Context, _ *pb.ListItemsRequest) (*pb.ListItemsResponse, error) {
+	items, err := s.deps.Service.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListItemsResponse{Items: make([]*pb.ItemB, 0, len(items))}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toProto(item))
+	}
+	return resp, nil
+}
+
+func (s *Server) Get(ctx context.Context, req *pb.GetItemRequest) (*pb.ItemB, error) {
+	item, err := s.deps.Service.GetOneByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toProto(item), nil
+}
+
+func (s *Server) Create(ctx context.Context, req *pb.CreateItemRequest) (*pb.ItemB, error) {
+	input, err := fromProto(req.GetItem())
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.deps.Service.Create(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return toProto(item), nil
+}
+
+func (s *Server) Update(ctx context.Context, req *pb.UpdateItemRequest) (*emptypb.Empty, error) {
+	input, err := fromProto(req.GetItem())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.deps.Service.Update(ctx, req.GetId(), input); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) Delete(ctx context.Context, req *pb.DeleteItemRequest) (*emptypb.Empty, error) {
+	if err := s.deps.Service.Delete(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) Restore(ctx context.Context, req *pb.RestoreItemRequest) (*emptypb.Empty, error) {
+	if err := s.deps.Service.Restore(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
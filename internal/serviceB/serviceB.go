@@ -0,0 +1,102 @@
+// Package serviceB wires the components of Service B, for cmd/serviceB and for cmd/all hosting it with the other
+// services
+package serviceB
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"app/build/config"
+	"app/internal/container"
+	jobHandler "app/internal/job/handler"
+	"app/internal/scheduler"
+	"app/internal/serviceB/handler"
+	"app/internal/serviceB/repository"
+	"app/internal/serviceB/rpc"
+	"app/internal/serviceB/service"
+)
+
+// Name is the name the service is hosted under by cmd/all
+const Name = "serviceB"
+
+var (
+	repositoryKey = container.NewKey[repository.Repository]("serviceB.repository")
+	serviceKey    = container.NewKey[service.Service]("serviceB.service")
+	handlerKey    = container.NewKey[*handler.Handler]("serviceB.handler")
+)
+
+// Provide declares the components of the service on top of the shared ones of config
+func Provide(c *container.Container) {
+	container.Provide(c, repositoryKey, func(c *container.Container) (repository.Repository, error) {
+		return repository.New(
+			&repository.DependenciesNode{
+				Database: container.MustResolve(c, config.Database),
+				Cache:    container.MustResolve(c, config.Cache),
+				Locker:   container.MustResolve(c, config.RecomputeLocker),
+			},
+			container.MustResolve(c, config.CacheAside),
+		), nil
+	})
+
+	container.Provide(c, serviceKey, func(c *container.Container) (service.Service, error) {
+		api := service.New(
+			&service.DependenciesNode{
+				Log:         container.MustResolve(c, config.Logger),
+				Repository:  container.MustResolve(c, repositoryKey),
+				IDGenerator: container.MustResolve(c, config.IDGenerator),
+				Events:      container.MustResolve(c, config.ChangeFeed),
+				Messages:    container.MustResolve(c, config.Outbox),
+				Jobs:        container.MustResolve(c, config.Jobs),
+			},
+		)
+
+		container.MustResolve(c, config.JobPool).Handle(service.ImportJob, api.RunImportJob)
+		purge := container.MustResolve(c, config.Purge)
+		err := container.MustResolve(c, config.Scheduler).Schedule(scheduler.Task{
+			Name: service.PurgeTask,
+			Spec: purge.Schedule,
+			Run: func(ctx context.Context) error {
+				return api.PurgeDeleted(ctx, purge.Retention)
+			},
+		})
+		return api, err
+	})
+
+	container.Provide(c, handlerKey, func(c *container.Container) (*handler.Handler, error) {
+		api := container.MustResolve(c, serviceKey)
+		router := container.MustResolve(c, config.Router)
+
+		jobHandler.New(
+			&jobHandler.DependenciesNode{
+				Queue:  container.MustResolve(c, config.Jobs),
+				Router: router,
+			},
+		)
+		rpc.New(
+			&rpc.DependenciesNode{
+				Service: api,
+			},
+		).Register(container.MustResolve(c, config.GRPCServer))
+
+		return handler.New(
+			&handler.DependenciesNode{
+				Service: api,
+				Router:  router,
+			},
+			handler.Config{
+				Origins: container.MustResolve(c, config.ChangeFeedOrigins),
+			},
+		), nil
+	})
+}
+
+// Router resolves the handler of the service, building the components it depends on, and returns the router
+// serving its routes
+func Router(c *container.Container) (*gin.Engine, error) {
+	h, err := container.Resolve(c, handlerKey)
+	if err != nil {
+		return nil, err
+	}
+	return h.GetRouter(), nil
+}
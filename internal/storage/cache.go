@@ -1,7 +1,12 @@
 package storage
 
+import "time"
+
 type Cache interface {
 	Set(key string, value interface{}) error
+	// SetWithTTL sets key like Set, the entry expiring once ttl elapsed
+	SetWithTTL(key string, value interface{}, ttl time.Duration) error
+	// Get returns nil without an error when key isn't cached, an error meaning the cache couldn't be read
 	Get(key string) ([]byte, error)
-	Remove(key string) error
+	Remove(keys ...string) error
 }
@@ -2,15 +2,29 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	uuid "github.com/satori/go.uuid"
+
+	"app/internal/batch"
 )
 
 type Database interface {
 	Create(ctx context.Context, obj interface{}) error
 	Update(ctx context.Context, id uuid.UUID, obj interface{}) error
 	Set(ctx context.Context, obj interface{}, field string, value interface{}) error
+	SetColumns(ctx context.Context, obj interface{}, columns map[string]interface{}) error
 	Select(ctx context.Context, obj interface{}) error
 	Raw(ctx context.Context, query string, obj interface{}) error
+	Stream(ctx context.Context, obj interface{}, each func() error) error
 	Delete(ctx context.Context, id uuid.UUID, obj interface{}) error
+	Restore(ctx context.Context, id uuid.UUID, obj interface{}) error
+	Purge(ctx context.Context, id uuid.UUID, obj interface{}) error
+	PurgeDeleted(ctx context.Context, obj interface{}, deletedBefore time.Time) error
+	CreateBatch(ctx context.Context, objs interface{}, mode batch.Mode) error
+	UpsertBatch(ctx context.Context, objs interface{}, mode batch.Mode) error
+	DeleteBatch(ctx context.Context, ids []uuid.UUID, obj interface{}, mode batch.Mode) error
+	// Transaction runs fn in a transaction joined by every call made with the ctx given to fn. It's committed
+	// when fn returns nil and rolled back otherwise, a nested call joining the outer transaction
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -0,0 +1,24 @@
+package crud
+
+import (
+	"encoding/json"
+
+	"app/internal/entity"
+)
+
+// Item is the entity the generic layers are tested with
+type Item struct {
+	entity.Base
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=1024"`
+}
+
+func ItemInBytes(item *Item) []byte {
+	b, _ := json.Marshal(item)
+	return b
+}
+
+func ArrayOfItemInBytes(arr []*Item) []byte {
+	b, _ := json.Marshal(arr)
+	return b
+}
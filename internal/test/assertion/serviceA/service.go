@@ -5,6 +5,7 @@ import (
 
 	uuid "github.com/satori/go.uuid"
 
+	"app/internal/entity"
 	"app/internal/serviceA/domain"
 )
 
@@ -17,12 +18,26 @@ var (
 	}
 
 	SampleID        = uuid.FromStringOrNil("15664c2f-d5bf-4922-8d19-39c6886bce90")
+	SampleItemBID   = uuid.FromStringOrNil("0b6c47a5-e6f4-4b59-8f2e-4c04d3c5f1a7")
 	InvalidIDString = "15664c2f"
+	SampleName      = "sample item"
 )
 
 func NewItemWithID(id string) *domain.ItemA {
 	return &domain.ItemA{
-		ID: uuid.FromStringOrNil(id),
+		Base: entity.Base{
+			ID: uuid.FromStringOrNil(id),
+		},
+		Name: SampleName,
+	}
+}
+
+// NewItemReference returns an item holding only its ID, as used to look it up in the database
+func NewItemReference(id string) *domain.ItemA {
+	return &domain.ItemA{
+		Base: entity.Base{
+			ID: uuid.FromStringOrNil(id),
+		},
 	}
 }
 
@@ -34,8 +49,18 @@ func NewItemFromInput(input *domain.ItemA) *domain.ItemA {
 	return newItem
 }
 
+// NewItemReferencingItemB returns an item without ID referencing SampleItemBID
+func NewItemReferencingItemB() *domain.ItemA {
+	item := NewItemWithoutID()
+	itemBID := SampleItemBID
+	item.ItemBID = &itemBID
+	return item
+}
+
 func NewItemWithoutID() *domain.ItemA {
-	return &domain.ItemA{}
+	return &domain.ItemA{
+		Name: SampleName,
+	}
 }
 
 func NewErrIncorrectIDLength(id string) error {
@@ -5,6 +5,7 @@ import (
 
 	uuid "github.com/satori/go.uuid"
 
+	"app/internal/entity"
 	"app/internal/serviceB/domain"
 )
 
@@ -18,11 +19,24 @@ var (
 
 	SampleID        = uuid.FromStringOrNil("15664c2f-d5bf-4922-8d19-39c6886bce90")
 	InvalidIDString = "15664c2f"
+	SampleName      = "sample item"
 )
 
 func NewItemWithID(id string) *domain.ItemB {
 	return &domain.ItemB{
-		ID: uuid.FromStringOrNil(id),
+		Base: entity.Base{
+			ID: uuid.FromStringOrNil(id),
+		},
+		Name: SampleName,
+	}
+}
+
+// NewItemReference returns an item holding only its ID, as used to look it up in the database
+func NewItemReference(id string) *domain.ItemB {
+	return &domain.ItemB{
+		Base: entity.Base{
+			ID: uuid.FromStringOrNil(id),
+		},
 	}
 }
 
@@ -35,7 +49,9 @@ func NewItemFromInput(input *domain.ItemB) *domain.ItemB {
 }
 
 func NewItemWithoutID() *domain.ItemB {
-	return &domain.ItemB{}
+	return &domain.ItemB{
+		Name: SampleName,
+	}
 }
 
 func NewErrIncorrectIDLength(id string) error {
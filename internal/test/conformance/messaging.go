@@ -0,0 +1,121 @@
+package conformance
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"app/internal/messaging"
+)
+
+const (
+	receiveTimeout = 5 * time.Second
+	pollInterval   = 10 * time.Millisecond
+	// settleTimeout covers the time the adapters may take to notice a new message
+	settleTimeout = time.Second
+)
+
+// DescribeBroker declares the specs every messaging.Broker adapter must pass, newBroker returning an adapter
+// connected to a fresh local stand-in and topic a new topic name for every spec
+func DescribeBroker(newBroker func() messaging.Broker, topic func() string) {
+	Context("Conforming to messaging.Broker", func() {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+			broker messaging.Broker
+			name   string
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			broker = newBroker()
+			name = topic()
+		})
+
+		AfterEach(func() {
+			cancel()
+		})
+
+		newEvent := func() messaging.Event {
+			event, err := messaging.NewEvent("/test", messaging.ItemCreated, "subject", map[string]string{"name": "sample"})
+			Expect(err).ShouldNot(HaveOccurred())
+			return event
+		}
+
+		When("An event is published", func() {
+			It("Should deliver it to every group", func() {
+				first, err := broker.Subscribe(ctx, name, "first")
+				Expect(err).ShouldNot(HaveOccurred())
+				second, err := broker.Subscribe(ctx, name, "second")
+				Expect(err).ShouldNot(HaveOccurred())
+				event := newEvent()
+
+				Expect(broker.Publish(ctx, name, event)).To(Succeed())
+
+				for _, messages := range []<-chan *messaging.Message{first, second} {
+					var msg *messaging.Message
+					Eventually(messages, receiveTimeout, pollInterval).Should(Receive(&msg))
+					Expect(msg.Topic).To(Equal(name))
+					Expect(msg.Event.ID).To(Equal(event.ID))
+					Expect(msg.Event.Type).To(Equal(messaging.ItemCreated))
+					Expect(msg.Event.Data).To(MatchJSON(`{"name":"sample"}`))
+					Expect(msg.Attempt).To(Equal(1))
+					Expect(msg.Ack(ctx)).To(Succeed())
+				}
+			})
+		})
+		When("A group has several members", func() {
+			It("Should deliver the event to a single one", func() {
+				first, err := broker.Subscribe(ctx, name, "group")
+				Expect(err).ShouldNot(HaveOccurred())
+				second, err := broker.Subscribe(ctx, name, "group")
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(broker.Publish(ctx, name, newEvent())).To(Succeed())
+
+				var msg *messaging.Message
+				Eventually(func() bool {
+					select {
+					case msg = <-first:
+					case msg = <-second:
+					default:
+					}
+					return msg != nil
+				}, receiveTimeout, pollInterval).Should(BeTrue())
+				Expect(msg.Ack(ctx)).To(Succeed())
+				Consistently(first, settleTimeout).ShouldNot(Receive())
+				Consistently(second, settleTimeout).ShouldNot(Receive())
+			})
+		})
+		When("A message is negatively acknowledged", func() {
+			It("Should deliver it again with the next attempt", func() {
+				messages, err := broker.Subscribe(ctx, name, "group")
+				Expect(err).ShouldNot(HaveOccurred())
+				event := newEvent()
+				Expect(broker.Publish(ctx, name, event)).To(Succeed())
+
+				var msg *messaging.Message
+				Eventually(messages, receiveTimeout, pollInterval).Should(Receive(&msg))
+				Expect(msg.Nack(ctx)).To(Succeed())
+
+				var retried *messaging.Message
+				Eventually(messages, receiveTimeout, pollInterval).Should(Receive(&retried))
+				Expect(retried.Event.ID).To(Equal(event.ID))
+				Expect(retried.Attempt).To(Equal(2))
+				Expect(retried.Ack(ctx)).To(Succeed())
+			})
+		})
+		When("Context is done", func() {
+			It("Should close the channel", func() {
+				messages, err := broker.Subscribe(ctx, name, "group")
+				Expect(err).ShouldNot(HaveOccurred())
+
+				cancel()
+
+				Eventually(messages, receiveTimeout, pollInterval).Should(BeClosed())
+			})
+		})
+	})
+}
@@ -2,7 +2,11 @@
 
 package storage
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Cache is an autogenerated mock type for the Cache type
 type Cache struct {
@@ -32,13 +36,19 @@ func (_m *Cache) Get(key string) ([]byte, error) {
 	return r0, r1
 }
 
-// Remove provides a mock function with given fields: key
-func (_m *Cache) Remove(key string) error {
-	ret := _m.Called(key)
+// Remove provides a mock function with given fields: keys
+func (_m *Cache) Remove(keys ...string) error {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(key)
+	if rf, ok := ret.Get(0).(func(...string) error); ok {
+		r0 = rf(keys...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -60,6 +70,20 @@ func (_m *Cache) Set(key string, value interface{}) error {
 	return r0
 }
 
+// SetWithTTL provides a mock function with given fields: key, value, ttl
+func (_m *Cache) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	ret := _m.Called(key, value, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, interface{}, time.Duration) error); ok {
+		r0 = rf(key, value, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewCache interface {
 	mock.TestingT
 	Cleanup(func())
@@ -3,10 +3,13 @@
 package storage
 
 import (
+	batch "app/internal/batch"
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -29,6 +32,20 @@ func (_m *Database) Create(ctx context.Context, obj interface{}) error {
 	return r0
 }
 
+// CreateBatch provides a mock function with given fields: ctx, objs, mode
+func (_m *Database) CreateBatch(ctx context.Context, objs interface{}, mode batch.Mode) error {
+	ret := _m.Called(ctx, objs, mode)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, batch.Mode) error); ok {
+		r0 = rf(ctx, objs, mode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, id, obj
 func (_m *Database) Delete(ctx context.Context, id uuid.UUID, obj interface{}) error {
 	ret := _m.Called(ctx, id, obj)
@@ -43,6 +60,48 @@ func (_m *Database) Delete(ctx context.Context, id uuid.UUID, obj interface{}) e
 	return r0
 }
 
+// DeleteBatch provides a mock function with given fields: ctx, ids, obj, mode
+func (_m *Database) DeleteBatch(ctx context.Context, ids []uuid.UUID, obj interface{}, mode batch.Mode) error {
+	ret := _m.Called(ctx, ids, obj, mode)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID, interface{}, batch.Mode) error); ok {
+		r0 = rf(ctx, ids, obj, mode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Purge provides a mock function with given fields: ctx, id, obj
+func (_m *Database) Purge(ctx context.Context, id uuid.UUID, obj interface{}) error {
+	ret := _m.Called(ctx, id, obj)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, interface{}) error); ok {
+		r0 = rf(ctx, id, obj)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeDeleted provides a mock function with given fields: ctx, obj, deletedBefore
+func (_m *Database) PurgeDeleted(ctx context.Context, obj interface{}, deletedBefore time.Time) error {
+	ret := _m.Called(ctx, obj, deletedBefore)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, time.Time) error); ok {
+		r0 = rf(ctx, obj, deletedBefore)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Raw provides a mock function with given fields: ctx, query, obj
 func (_m *Database) Raw(ctx context.Context, query string, obj interface{}) error {
 	ret := _m.Called(ctx, query, obj)
@@ -57,6 +116,20 @@ func (_m *Database) Raw(ctx context.Context, query string, obj interface{}) erro
 	return r0
 }
 
+// Restore provides a mock function with given fields: ctx, id, obj
+func (_m *Database) Restore(ctx context.Context, id uuid.UUID, obj interface{}) error {
+	ret := _m.Called(ctx, id, obj)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, interface{}) error); ok {
+		r0 = rf(ctx, id, obj)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Select provides a mock function with given fields: ctx, obj
 func (_m *Database) Select(ctx context.Context, obj interface{}) error {
 	ret := _m.Called(ctx, obj)
@@ -85,6 +158,48 @@ func (_m *Database) Set(ctx context.Context, obj interface{}, field string, valu
 	return r0
 }
 
+// SetColumns provides a mock function with given fields: ctx, obj, columns
+func (_m *Database) SetColumns(ctx context.Context, obj interface{}, columns map[string]interface{}) error {
+	ret := _m.Called(ctx, obj, columns)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, map[string]interface{}) error); ok {
+		r0 = rf(ctx, obj, columns)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Stream provides a mock function with given fields: ctx, obj, each
+func (_m *Database) Stream(ctx context.Context, obj interface{}, each func() error) error {
+	ret := _m.Called(ctx, obj, each)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, func() error) error); ok {
+		r0 = rf(ctx, obj, each)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Transaction provides a mock function with given fields: ctx, fn
+func (_m *Database) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, id, obj
 func (_m *Database) Update(ctx context.Context, id uuid.UUID, obj interface{}) error {
 	ret := _m.Called(ctx, id, obj)
@@ -99,6 +214,20 @@ func (_m *Database) Update(ctx context.Context, id uuid.UUID, obj interface{}) e
 	return r0
 }
 
+// UpsertBatch provides a mock function with given fields: ctx, objs, mode
+func (_m *Database) UpsertBatch(ctx context.Context, objs interface{}, mode batch.Mode) error {
+	ret := _m.Called(ctx, objs, mode)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, batch.Mode) error); ok {
+		r0 = rf(ctx, objs, mode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewDatabase interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,54 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	RequestIDHeader   = "X-Request-ID"
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+type contextKey struct{}
+
+// Info holds the request ID and the W3C trace context of the request being served,
+// so they can be propagated to the requests sent to other services
+type Info struct {
+	RequestID   string
+	TraceParent string
+	TraceState  string
+}
+
+// FromHeader reads the propagation headers of an incoming request
+func FromHeader(header http.Header) Info {
+	return Info{
+		RequestID:   header.Get(RequestIDHeader),
+		TraceParent: header.Get(TraceParentHeader),
+		TraceState:  header.Get(TraceStateHeader),
+	}
+}
+
+// Inject writes the non empty propagation headers into an outgoing request
+func (i Info) Inject(header http.Header) {
+	for key, value := range map[string]string{
+		RequestIDHeader:   i.RequestID,
+		TraceParentHeader: i.TraceParent,
+		TraceStateHeader:  i.TraceState,
+	} {
+		if value != "" {
+			header.Set(key, value)
+		}
+	}
+}
+
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext returns the Info stored in ctx, if any
+func FromContext(ctx context.Context) (Info, bool) {
+	info, ok := ctx.Value(contextKey{}).(Info)
+	return info, ok
+}
@@ -0,0 +1,109 @@
+package transfer
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	jsonTag          = "json"
+	jsonTagSeparator = ","
+	jsonIgnoreTag    = "-"
+	jsonNull         = "null"
+)
+
+// column is a CSV column mapped to a possibly embedded struct field, named after its JSON name
+type column struct {
+	name  string
+	index []int
+}
+
+// columnsOf lists the exported fields of t in declaration order, flattening embedded structs like encoding/json does
+func columnsOf(t reflect.Type) []column {
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get(jsonTag), jsonTagSeparator)[0]
+		if name == jsonIgnoreTag {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for _, embedded := range columnsOf(field.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				columns = append(columns, embedded)
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		columns = append(columns, column{name: name, index: []int{i}})
+	}
+	return columns
+}
+
+// formatCell returns the CSV representation of a field, using its text or JSON encoding when it has one.
+// Nil pointers are written as empty cells
+func formatCell(field reflect.Value) (string, error) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return "", nil
+		}
+		field = field.Elem()
+	}
+
+	if marshaler, ok := field.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), nil
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return fmt.Sprint(field.Interface()), nil
+	}
+
+	data, err := json.Marshal(field.Interface())
+	if err != nil {
+		return "", err
+	}
+	if string(data) == jsonNull {
+		return "", nil
+	}
+	if unquoted, err := strconv.Unquote(string(data)); err == nil {
+		return unquoted, nil
+	}
+	return string(data), nil
+}
+
+// parseCell sets field from its CSV representation, leaving it untouched when value is empty
+func parseCell(field reflect.Value, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	ptr := field.Addr().Interface()
+	if unmarshaler, ok := ptr.(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+		return nil
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return json.Unmarshal([]byte(value), ptr)
+	}
+	return json.Unmarshal([]byte(strconv.Quote(value)), ptr)
+}
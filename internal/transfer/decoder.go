@@ -0,0 +1,161 @@
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+const (
+	maxLineSize = 1024 * 1024
+
+	unknownColumnFormat = "unknown column %q"
+	invalidCellFormat   = "invalid value for %s: %w"
+)
+
+// LineError is a row that can't be decoded. The decoder can keep reading the following rows after it
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Decoder reads items one at a time. Decode returns io.EOF once every row has been read,
+// a *LineError for a malformed row and any other error when the input can't be read anymore
+type Decoder interface {
+	Decode(obj interface{}) error
+	// Line returns the line of the input holding the last decoded row
+	Line() int
+}
+
+func NewDecoder(format Format, r io.Reader) Decoder {
+	if format == CSV {
+		reader := csv.NewReader(r)
+		reader.ReuseRecord = true
+		return &csvDecoder{reader: reader}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+	return &ndjsonDecoder{scanner: scanner}
+}
+
+type ndjsonDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// Decode reads the next non blank line into obj
+func (d *ndjsonDecoder) Decode(obj interface{}) error {
+	for d.scanner.Scan() {
+		d.line++
+		data := bytes.TrimSpace(d.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(data, obj); err != nil {
+			return &LineError{Line: d.line, Err: err}
+		}
+		return nil
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+func (d *ndjsonDecoder) Line() int {
+	return d.line
+}
+
+type csvDecoder struct {
+	reader  *csv.Reader
+	header  []column
+	columns []column
+	line    int
+}
+
+// Decode reads the next record into obj, matching the columns by the JSON names of its fields
+func (d *csvDecoder) Decode(obj interface{}) error {
+	value := reflect.Indirect(reflect.ValueOf(obj))
+	if d.header == nil {
+		if err := d.readHeader(value.Type()); err != nil {
+			return err
+		}
+	}
+
+	record, err := d.reader.Read()
+	if err != nil {
+		return d.readError(err)
+	}
+	d.line, _ = d.reader.FieldPos(0)
+
+	for i, cell := range record {
+		if i >= len(d.header) {
+			break
+		}
+		col := d.header[i]
+		if col.index == nil {
+			continue
+		}
+		if err = parseCell(value.FieldByIndex(col.index), cell); err != nil {
+			return &LineError{Line: d.line, Err: fmt.Errorf(invalidCellFormat, col.name, err)}
+		}
+	}
+	return nil
+}
+
+func (d *csvDecoder) Line() int {
+	return d.line
+}
+
+// readHeader maps every header column to a field of t. The header is kept even when a column is unknown,
+// so the error is reported once and the following records can still be decoded
+func (d *csvDecoder) readHeader(t reflect.Type) error {
+	record, err := d.reader.Read()
+	if err != nil {
+		return d.readError(err)
+	}
+	d.line, _ = d.reader.FieldPos(0)
+
+	fields := make(map[string]column)
+	for _, col := range columnsOf(t) {
+		fields[col.name] = col
+	}
+
+	d.header = make([]column, 0, len(record))
+	var unknown error
+	for _, name := range record {
+		col, ok := fields[name]
+		if !ok && unknown == nil {
+			unknown = fmt.Errorf(unknownColumnFormat, name)
+		}
+		d.header = append(d.header, column{name: name, index: col.index})
+	}
+	if unknown != nil {
+		return &LineError{Line: d.line, Err: unknown}
+	}
+	return nil
+}
+
+func (d *csvDecoder) readError(err error) error {
+	var parseErr *csv.ParseError
+	if stdErrors.As(err, &parseErr) {
+		d.line = parseErr.Line
+		return &LineError{Line: parseErr.Line, Err: parseErr.Err}
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// Encoder writes items one at a time, so an export never holds more than one row in memory
+type Encoder interface {
+	Encode(obj interface{}) error
+	Flush() error
+}
+
+func NewEncoder(format Format, w io.Writer) Encoder {
+	if format == CSV {
+		return &csvEncoder{writer: csv.NewWriter(w)}
+	}
+	return &ndjsonEncoder{encoder: json.NewEncoder(w)}
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+// Encode writes obj as a single JSON line
+func (e *ndjsonEncoder) Encode(obj interface{}) error {
+	return e.encoder.Encode(obj)
+}
+
+func (e *ndjsonEncoder) Flush() error {
+	return nil
+}
+
+type csvEncoder struct {
+	writer  *csv.Writer
+	columns []column
+}
+
+// Encode writes obj as a CSV record, preceded by the header when it's the first one
+func (e *csvEncoder) Encode(obj interface{}) error {
+	value := reflect.Indirect(reflect.ValueOf(obj))
+	if e.columns == nil {
+		e.columns = columnsOf(value.Type())
+		header := make([]string, 0, len(e.columns))
+		for _, col := range e.columns {
+			header = append(header, col.name)
+		}
+		if err := e.writer.Write(header); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, 0, len(e.columns))
+	for _, col := range e.columns {
+		cell, err := formatCell(value.FieldByIndex(col.index))
+		if err != nil {
+			return err
+		}
+		record = append(record, cell)
+	}
+	return e.writer.Write(record)
+}
+
+func (e *csvEncoder) Flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
@@ -0,0 +1,74 @@
+package transfer
+
+import (
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"app/internal/errors"
+)
+
+// Format is a line oriented encoding used to export and import items
+type Format string
+
+const (
+	NDJSON Format = "ndjson"
+	CSV    Format = "csv"
+
+	NDJSONContentType = "application/x-ndjson"
+	CSVContentType    = "text/csv"
+
+	jsonLinesExtension = ".jsonl"
+
+	// ImportBatchSize is the number of rows stored at once while importing
+	ImportBatchSize = 500
+	// MaxImportJobSize bounds the file of an import run in the background, which is held whole in the job
+	MaxImportJobSize = 32 << 20
+)
+
+// ParseFormat returns the Format named by name, defaulting to NDJSON when it's empty
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case "", NDJSON:
+		return NDJSON, nil
+	case CSV:
+		return CSV, nil
+	}
+	return "", errors.ErrUnsupportedFormat
+}
+
+// FormatFromContentType returns the Format of a media type, ignoring its parameters
+func FormatFromContentType(contentType string) (Format, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", errors.ErrUnsupportedFormat
+	}
+
+	switch mediaType {
+	case NDJSONContentType:
+		return NDJSON, nil
+	case CSVContentType:
+		return CSV, nil
+	}
+	return "", errors.ErrUnsupportedFormat
+}
+
+// FormatFromFilename returns the Format matching the extension of filename
+func FormatFromFilename(filename string) (Format, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == jsonLinesExtension {
+		return NDJSON, nil
+	}
+	return ParseFormat(strings.TrimPrefix(ext, "."))
+}
+
+func (f Format) ContentType() string {
+	if f == CSV {
+		return CSVContentType
+	}
+	return NDJSONContentType
+}
+
+func (f Format) Extension() string {
+	return "." + string(f)
+}
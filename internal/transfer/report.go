@@ -0,0 +1,68 @@
+package transfer
+
+import (
+	stdErrors "errors"
+	"net/http"
+
+	"app/internal/batch"
+	"app/internal/validation"
+)
+
+// RowError tells why the row at Line wasn't imported
+type RowError struct {
+	Line   int                     `json:"line"`
+	Error  string                  `json:"error"`
+	Fields []validation.FieldError `json:"fields,omitempty"`
+}
+
+// ImportReport is the outcome of an import, listing only the rows that failed
+type ImportReport struct {
+	Imported int        `json:"imported"`
+	Failed   int        `json:"failed"`
+	Errors   []RowError `json:"errors"`
+}
+
+func NewImportReport() *ImportReport {
+	return &ImportReport{
+		Errors: []RowError{},
+	}
+}
+
+// Status returns 200 OK when every row was imported and 207 Multi-Status otherwise
+func (r *ImportReport) Status() int {
+	if r.Failed > 0 {
+		return http.StatusMultiStatus
+	}
+	return http.StatusOK
+}
+
+// AddError records a row that failed before reaching the database
+func (r *ImportReport) AddError(line int, err error) {
+	rowErr := RowError{
+		Line:  line,
+		Error: err.Error(),
+	}
+	var validationErr *validation.Error
+	if stdErrors.As(err, &validationErr) {
+		rowErr.Fields = validationErr.Fields
+	}
+
+	r.Failed++
+	r.Errors = append(r.Errors, rowErr)
+}
+
+// AddResult records the outcome of a batch, where lines holds the input line of every batch item
+func (r *ImportReport) AddResult(lines []int, result *batch.Result) {
+	r.Imported += result.Succeeded
+	r.Failed += result.Failed
+	for _, item := range result.Items {
+		if item.Error == "" {
+			continue
+		}
+		r.Errors = append(r.Errors, RowError{
+			Line:   lines[item.Index],
+			Error:  item.Error,
+			Fields: item.Fields,
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package transfer
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"app/internal/errors"
+)
+
+const (
+	FileField = "file"
+	// RespondAsync is the preference of a client asking for a long import to run in the background
+	RespondAsync = "respond-async"
+
+	contentTypeHeader  = "Content-Type"
+	preferHeader       = "Prefer"
+	preferenceSep      = ","
+	multipartMediaType = "multipart/"
+)
+
+// FromRequest returns the uploaded file of a request and its format. Multipart requests are read part by part,
+// so the file is streamed instead of being buffered in memory or on disk. Any other body is the file itself
+func FromRequest(req *http.Request) (io.Reader, Format, error) {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get(contentTypeHeader))
+	if err != nil {
+		return nil, "", errors.ErrUnsupportedFormat
+	}
+	if !strings.HasPrefix(mediaType, multipartMediaType) {
+		format, err := FormatFromContentType(mediaType)
+		return req.Body, format, err
+	}
+
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return nil, "", err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, "", errors.ErrMissingFile
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if part.FormName() != FileField {
+			continue
+		}
+
+		format, err := FormatFromContentType(part.Header.Get(contentTypeHeader))
+		if err != nil {
+			format, err = FormatFromFilename(part.FileName())
+		}
+		return part, format, err
+	}
+}
+
+// PrefersAsync tells whether the Prefer header of a request holds the RespondAsync preference
+func PrefersAsync(req *http.Request) bool {
+	for _, preference := range strings.Split(req.Header.Get(preferHeader), preferenceSep) {
+		if strings.EqualFold(strings.TrimSpace(preference), RespondAsync) {
+			return true
+		}
+	}
+	return false
+}
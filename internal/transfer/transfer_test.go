@@ -0,0 +1,218 @@
+package transfer
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+
+	"app/internal/batch"
+	"app/internal/entity"
+	"app/internal/errors"
+)
+
+func TestTransfer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Transfer Suits")
+}
+
+type item struct {
+	entity.Base
+	Name     string `json:"name"`
+	Internal string `json:"-"`
+}
+
+var (
+	sampleID   = uuid.FromStringOrNil("15664c2f-d5bf-4922-8d19-39c6886bce90")
+	sampleTime = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+)
+
+func sampleItem() *item {
+	return &item{
+		Base: entity.Base{
+			ID:        sampleID,
+			CreatedAt: sampleTime,
+			UpdatedAt: sampleTime,
+			CreatedBy: "anonymous",
+			Version:   2,
+		},
+		Name:     "name, with comma",
+		Internal: "internal",
+	}
+}
+
+var _ = Describe("Transfer", func() {
+	Context("Parsing formats", func() {
+		When("Format is empty", func() {
+			It("Should default to NDJSON", func() {
+				format, err := ParseFormat("")
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(format).To(Equal(NDJSON))
+			})
+		})
+		When("Format is unknown", func() {
+			It("Should return an unsupported format error", func() {
+				_, err := FormatFromContentType("application/xml")
+
+				Expect(err).To(Equal(errors.ErrUnsupportedFormat))
+			})
+		})
+		When("Filename has a JSON lines extension", func() {
+			It("Should return NDJSON", func() {
+				format, err := FormatFromFilename("items.JSONL")
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(format).To(Equal(NDJSON))
+			})
+		})
+	})
+
+	Context("Encoding and decoding items", func() {
+		for _, format := range []Format{NDJSON, CSV} {
+			format := format
+			When("Format is "+string(format), func() {
+				It("Should read back what was written", func() {
+					var buf bytes.Buffer
+					encoder := NewEncoder(format, &buf)
+					Expect(encoder.Encode(sampleItem())).To(Succeed())
+					Expect(encoder.Encode(sampleItem())).To(Succeed())
+					Expect(encoder.Flush()).To(Succeed())
+
+					expected := sampleItem()
+					expected.Internal = ""
+					decoder := NewDecoder(format, &buf)
+					for i := 0; i < 2; i++ {
+						decoded := &item{}
+						Expect(decoder.Decode(decoded)).To(Succeed())
+						Expect(decoded).To(Equal(expected))
+					}
+					Expect(decoder.Decode(&item{})).To(Equal(io.EOF))
+				})
+			})
+		}
+		When("CSV header is written", func() {
+			It("Should use the JSON names of the flattened fields", func() {
+				var buf bytes.Buffer
+				encoder := NewEncoder(CSV, &buf)
+				Expect(encoder.Encode(sampleItem())).To(Succeed())
+				Expect(encoder.Flush()).To(Succeed())
+
+				header := strings.SplitN(buf.String(), "\n", 2)[0]
+				Expect(header).To(Equal("id,createdAt,updatedAt,deletedAt,createdBy,updatedBy,version,name"))
+			})
+		})
+	})
+
+	Context("Decoding malformed rows", func() {
+		When("An NDJSON line is not JSON", func() {
+			It("Should report its line and keep reading", func() {
+				decoder := NewDecoder(NDJSON, strings.NewReader("{\"name\":\"first\"}\n\nnot json\n{\"name\":\"last\"}\n"))
+
+				Expect(decoder.Decode(&item{})).To(Succeed())
+				err := decoder.Decode(&item{})
+				Expect(err).To(BeAssignableToTypeOf(&LineError{}))
+				Expect(err.(*LineError).Line).To(Equal(3))
+
+				last := &item{}
+				Expect(decoder.Decode(last)).To(Succeed())
+				Expect(last.Name).To(Equal("last"))
+				Expect(decoder.Line()).To(Equal(4))
+			})
+		})
+		When("A CSV cell has the wrong type", func() {
+			It("Should report its line and keep reading", func() {
+				decoder := NewDecoder(CSV, strings.NewReader("name,version\nfirst,one\nlast,3\n"))
+
+				err := decoder.Decode(&item{})
+				Expect(err).To(BeAssignableToTypeOf(&LineError{}))
+				Expect(err.(*LineError).Line).To(Equal(2))
+
+				last := &item{}
+				Expect(decoder.Decode(last)).To(Succeed())
+				Expect(last.Name).To(Equal("last"))
+				Expect(last.Version).To(Equal(int64(3)))
+			})
+		})
+	})
+
+	Context("Reading the uploaded file", func() {
+		When("File is the request body", func() {
+			It("Should use the request content type", func() {
+				req, err := http.NewRequest(http.MethodPost, "/import", strings.NewReader("name\n"))
+				Expect(err).ShouldNot(HaveOccurred())
+				req.Header.Set(contentTypeHeader, "text/csv; charset=utf-8")
+
+				_, format, err := FromRequest(req)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(format).To(Equal(CSV))
+			})
+		})
+		When("File is a multipart field", func() {
+			It("Should use the file extension", func() {
+				var body bytes.Buffer
+				writer := multipart.NewWriter(&body)
+				Expect(writer.WriteField("comment", "ignored")).To(Succeed())
+				part, err := writer.CreateFormFile(FileField, "items.ndjson")
+				Expect(err).ShouldNot(HaveOccurred())
+				_, err = part.Write([]byte("{\"name\":\"first\"}\n"))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(writer.Close()).To(Succeed())
+
+				req, err := http.NewRequest(http.MethodPost, "/import", &body)
+				Expect(err).ShouldNot(HaveOccurred())
+				req.Header.Set(contentTypeHeader, writer.FormDataContentType())
+
+				file, format, err := FromRequest(req)
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(format).To(Equal(NDJSON))
+				content, err := io.ReadAll(file)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(string(content)).To(Equal("{\"name\":\"first\"}\n"))
+			})
+		})
+	})
+
+	Context("Reading the preferences of a request", func() {
+		It("Should find respond-async among the preferences", func() {
+			req, err := http.NewRequest(http.MethodPost, "/import", nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(PrefersAsync(req)).To(BeFalse())
+
+			req.Header.Set(preferHeader, "wait=10, Respond-Async")
+			Expect(PrefersAsync(req)).To(BeTrue())
+		})
+	})
+
+	Context("Reporting an import", func() {
+		It("Should map the failed batch items to their lines", func() {
+			report := NewImportReport()
+			report.AddError(2, errors.ErrInvalidParameter)
+			report.AddResult([]int{3, 4}, &batch.Result{
+				Succeeded: 1,
+				Failed:    1,
+				Items: []batch.ItemResult{
+					{Index: 0, Status: http.StatusOK},
+					{Index: 1, Status: http.StatusUnprocessableEntity, Error: "failed"},
+				},
+			})
+
+			Expect(report.Imported).To(Equal(1))
+			Expect(report.Failed).To(Equal(2))
+			Expect(report.Status()).To(Equal(http.StatusMultiStatus))
+			Expect(report.Errors).To(Equal([]RowError{
+				{Line: 2, Error: errors.ErrInvalidParameter.Error()},
+				{Line: 4, Error: "failed"},
+			}))
+		})
+	})
+})
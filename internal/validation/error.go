@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"app/internal/errors"
+)
+
+const (
+	validationFailedMessage = "validation failed"
+	errorFormat             = "%s: %s"
+	fieldErrorFormat        = "%s %s"
+	fieldErrorSeparator     = ", "
+)
+
+// FieldError describes why a single field or parameter is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error lists every invalid field of a payload or every invalid parameter of a request
+type Error struct {
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields"`
+
+	kind error
+}
+
+// NewError returns a validation error for an invalid payload, mapped to 422 Unprocessable Entity
+func NewError(fields ...FieldError) *Error {
+	return &Error{
+		Message: validationFailedMessage,
+		Fields:  fields,
+		kind:    errors.ErrValidation,
+	}
+}
+
+// NewParamError returns a validation error for invalid request parameters, mapped to 400 Bad Request
+func NewParamError(fields ...FieldError) *Error {
+	return &Error{
+		Message: validationFailedMessage,
+		Fields:  fields,
+		kind:    errors.ErrInvalidParameter,
+	}
+}
+
+func (e *Error) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		fields = append(fields, fmt.Sprintf(fieldErrorFormat, field.Field, field.Message))
+	}
+	return fmt.Sprintf(errorFormat, e.Message, strings.Join(fields, fieldErrorSeparator))
+}
+
+func (e *Error) Unwrap() error {
+	return e.kind
+}
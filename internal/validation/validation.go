@@ -0,0 +1,163 @@
+package validation
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// UUIDRule validates that a value is a canonical UUID
+	UUIDRule = "required,uuid"
+
+	tagName          = "validate"
+	jsonTag          = "json"
+	jsonTagSeparator = ","
+	jsonIgnoreTag    = "-"
+	namespaceSep     = "."
+
+	bodyField = "body"
+
+	requiredRule = "required"
+	// ExistsRule fails when a field references an item that doesn't exist
+	ExistsRule = "exists"
+)
+
+var (
+	validate = newValidator()
+
+	ruleMessages = map[string]string{
+		"required": "is required",
+		"uuid":     "must be a valid UUID",
+		"email":    "must be a valid email",
+		"url":      "must be a valid URL",
+		ExistsRule: "must reference an existing item",
+	}
+	ruleParamMessages = map[string]string{
+		"max":   "must be at most %s characters long",
+		"min":   "must be at least %s characters long",
+		"len":   "must be exactly %s characters long",
+		"oneof": "must be one of: %s",
+		"lte":   "must be less than or equal to %s",
+		"gte":   "must be greater than or equal to %s",
+	}
+)
+
+// Validator is implemented by payloads with rules that can't be expressed with tags
+type Validator interface {
+	Validate() error
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName(tagName)
+	v.RegisterTagNameFunc(jsonFieldName)
+	return v
+}
+
+// Struct validates obj against its `validate` tags and its Validate method, listing every invalid field
+func Struct(obj interface{}) error {
+	if isNil(obj) {
+		return NewError(FieldError{Field: bodyField, Rule: requiredRule, Message: ruleMessages[requiredRule]})
+	}
+
+	var fields []FieldError
+	if err := validate.Struct(obj); err != nil {
+		var validationErrs validator.ValidationErrors
+		if !stdErrors.As(err, &validationErrs) {
+			return err
+		}
+		fields = append(fields, toFieldErrors(validationErrs)...)
+	}
+
+	if v, ok := obj.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			var validationErr *Error
+			if !stdErrors.As(err, &validationErr) {
+				return err
+			}
+			fields = append(fields, validationErr.Fields...)
+		}
+	}
+
+	if len(fields) > 0 {
+		return NewError(fields...)
+	}
+	return nil
+}
+
+// Reference returns the validation error of a field referencing an item that doesn't exist
+func Reference(field string) *Error {
+	return NewError(FieldError{Field: field, Rule: ExistsRule, Message: ruleMessages[ExistsRule]})
+}
+
+// Param validates a single request parameter against the given rules
+func Param(name string, value interface{}, rules string) error {
+	err := validate.Var(value, rules)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !stdErrors.As(err, &validationErrs) {
+		return err
+	}
+
+	fields := toFieldErrors(validationErrs)
+	for i := range fields {
+		fields[i].Field = name
+	}
+	return NewParamError(fields...)
+}
+
+func toFieldErrors(validationErrs validator.ValidationErrors) []FieldError {
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fieldErr.Namespace()),
+			Rule:    fieldErr.Tag(),
+			Message: message(fieldErr.Tag(), fieldErr.Param()),
+		})
+	}
+	return fields
+}
+
+// fieldPath removes the struct name from the namespace, e.g. ItemA.name becomes name
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, namespaceSep); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+func message(rule, param string) string {
+	if msg, ok := ruleMessages[rule]; ok {
+		return msg
+	}
+	if msg, ok := ruleParamMessages[rule]; ok {
+		return fmt.Sprintf(msg, param)
+	}
+	return fmt.Sprintf("failed on the '%s' rule", rule)
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get(jsonTag), jsonTagSeparator)[0]
+	if name == jsonIgnoreTag {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func isNil(obj interface{}) bool {
+	if obj == nil {
+		return true
+	}
+	value := reflect.ValueOf(obj)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
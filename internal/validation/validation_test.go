@@ -0,0 +1,85 @@
+package validation
+
+import (
+	stdErrors "errors"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"app/internal/errors"
+)
+
+func TestValidation(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Validation Suits")
+}
+
+type payload struct {
+	Name  string `json:"name" validate:"required,max=5"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+func (p payload) Validate() error {
+	if p.End < p.Start {
+		return NewError(FieldError{Field: "end", Rule: "gtefield", Message: "must not be before start"})
+	}
+	return nil
+}
+
+var _ = Describe("Validation", func() {
+	Context("Validating a struct", func() {
+		When("Struct is valid", func() {
+			It("Should not return an error", func() {
+				err := Struct(&payload{Name: "name", Start: 1, End: 2})
+
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+		When("Struct breaks tag and custom rules", func() {
+			It("Should list every invalid field using its JSON name", func() {
+				err := Struct(&payload{Name: "too long", Start: 2, End: 1})
+
+				Expect(err).To(MatchError(errors.ErrValidation))
+				var validationErr *Error
+				Expect(stdErrors.As(err, &validationErr)).To(BeTrue())
+				Expect(validationErr.Fields).To(Equal([]FieldError{
+					{Field: "name", Rule: "max", Message: "must be at most 5 characters long"},
+					{Field: "end", Rule: "gtefield", Message: "must not be before start"},
+				}))
+			})
+		})
+		When("Struct is nil", func() {
+			It("Should report the body as required", func() {
+				var p *payload
+
+				err := Struct(p)
+
+				Expect(err).To(MatchError(errors.ErrValidation))
+				Expect(err.(*Error).Fields).To(ConsistOf(
+					FieldError{Field: "body", Rule: "required", Message: "is required"},
+				))
+			})
+		})
+	})
+	Context("Validating a parameter", func() {
+		When("Parameter is valid", func() {
+			It("Should not return an error", func() {
+				err := Param("id", "6ba7b810-9dad-11d1-80b4-00c04fd430c8", UUIDRule)
+
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+		When("Parameter is invalid", func() {
+			It("Should return an invalid parameter error", func() {
+				err := Param("id", "not-a-uuid", UUIDRule)
+
+				Expect(err).To(MatchError(errors.ErrInvalidParameter))
+				Expect(err.(*Error).Fields).To(ConsistOf(
+					FieldError{Field: "id", Rule: "uuid", Message: "must be a valid UUID"},
+				))
+			})
+		})
+	})
+})
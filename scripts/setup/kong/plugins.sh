@@ -14,6 +14,7 @@ curl --location --request POST 'http://localhost:8001/plugins' \
 --data-urlencode 'config.methods=GET' \
 --data-urlencode 'config.methods=POST' \
 --data-urlencode 'config.methods=PUT' \
+--data-urlencode 'config.methods=PATCH' \
 --data-urlencode 'config.methods=DELETE' \
 --data-urlencode 'config.methods=OPTIONS' \
 --data-urlencode 'config.headers=Content-Type' \
@@ -22,3 +23,26 @@ curl --location --request POST 'http://localhost:8001/plugins' \
 --data-urlencode 'config.credentials=true' \
 --data-urlencode 'config.preflight_continue=true' \
 --data-urlencode 'config.max_age=3600'
+
+echo "Trusting the consumer headers set by Kong only"
+# the client can't send the headers the services trust: they're cleared before the jwt and acl plugins set them, and
+# the secret proving the request went through Kong is replaced by the one shared with the services
+curl --location --request POST 'http://localhost:8001/plugins' \
+--header 'Content-Type: application/x-www-form-urlencoded' \
+--data-urlencode 'name=pre-function' \
+--data-urlencode 'config.access[1]=kong.service.request.clear_header("X-Consumer-Username")
+kong.service.request.clear_header("X-Consumer-Custom-ID")
+kong.service.request.clear_header("X-Consumer-Groups")'
+curl --location --request POST 'http://localhost:8001/plugins' \
+--header 'Content-Type: application/x-www-form-urlencoded' \
+--data-urlencode 'name=request-transformer' \
+--data-urlencode 'config.remove.headers=X-Gateway-Secret' \
+--data-urlencode "config.add.headers=X-Gateway-Secret:${GATEWAY_SECRET:?GATEWAY_SECRET must be set}"
+
+echo "Forwarding the consumer groups"
+# every consumer but the blocked ones gets through, with its groups in X-Consumer-Groups. The routes restricted to a
+# group, like the purge routes, override it
+curl --location --request POST 'http://localhost:8001/plugins' \
+--header 'Content-Type: application/x-www-form-urlencoded' \
+--data-urlencode 'name=acl' \
+--data-urlencode 'config.deny=blocked'
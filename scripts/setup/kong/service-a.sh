@@ -14,5 +14,27 @@ curl --location --request POST 'http://localhost:8001/services/service-a/routes'
 --data-urlencode 'methods=GET' \
 --data-urlencode 'methods=POST' \
 --data-urlencode 'methods=PUT' \
+--data-urlencode 'methods=PATCH' \
 --data-urlencode 'methods=OPTIONS' \
 --data-urlencode 'methods=DELETE'
+
+echo "Restricting the Service A purge route to the admin group"
+# the route takes precedence over the service-a prefix, forwarding the path captured without the prefix. Its
+# request-transformer replaces the global one, so it adds the gateway secret too
+curl --location --request POST 'http://localhost:8001/services/service-a/routes' \
+--header 'Content-Type: application/x-www-form-urlencoded' \
+--data-urlencode 'name=service-a-purge' \
+--data-urlencode 'paths=~/service-a(?<path>/api/v1/a-items/[^/]+/purge)$' \
+--data-urlencode 'regex_priority=10' \
+--data-urlencode 'strip_path=false' \
+--data-urlencode 'methods=DELETE'
+curl --location --request POST 'http://localhost:8001/routes/service-a-purge/plugins' \
+--header 'Content-Type: application/x-www-form-urlencoded' \
+--data-urlencode 'name=acl' \
+--data-urlencode 'config.allow=admin'
+curl --location --request POST 'http://localhost:8001/routes/service-a-purge/plugins' \
+--header 'Content-Type: application/x-www-form-urlencoded' \
+--data-urlencode 'name=request-transformer' \
+--data-urlencode 'config.replace.uri=$(uri_captures["path"])' \
+--data-urlencode 'config.remove.headers=X-Gateway-Secret' \
+--data-urlencode "config.add.headers=X-Gateway-Secret:${GATEWAY_SECRET:?GATEWAY_SECRET must be set}"